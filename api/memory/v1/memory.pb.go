@@ -0,0 +1,506 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: api/memory/v1/memory.proto
+
+// Memory's gRPC API, served by `memory serve --grpc-addr` alongside the HTTP sync
+// endpoint. Calls carry the same bearer token as sync, in the authorization metadata.
+
+package memoryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QueryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Project name or ID
+	Project string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	// Case-insensitive text to match; empty matches everything
+	Search string `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	// Breadcrumb types to return: finding, unknown, dead_end. Empty means all three.
+	Types []string `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`
+	// Most breadcrumbs of each type; 50 when unset
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
+	mi := &file_api_memory_v1_memory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRequest) ProtoMessage() {}
+
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_memory_v1_memory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return file_api_memory_v1_memory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueryRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *QueryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Breadcrumb struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// finding, unknown or dead_end
+	Type      string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ProjectId string `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	SessionId string `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// The finding, question or failed approach
+	Text string `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	// Why a dead end failed
+	WhyFailed string `protobuf:"bytes,6,opt,name=why_failed,json=whyFailed,proto3" json:"why_failed,omitempty"`
+	// File, directory or URL the breadcrumb is about
+	Scope            string   `protobuf:"bytes,7,opt,name=scope,proto3" json:"scope,omitempty"`
+	Impact           float64  `protobuf:"fixed64,8,opt,name=impact,proto3" json:"impact,omitempty"`
+	CreatedTimestamp float64  `protobuf:"fixed64,9,opt,name=created_timestamp,json=createdTimestamp,proto3" json:"created_timestamp,omitempty"`
+	Tags             []string `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Time-decayed confidence of a finding
+	Confidence    float64 `protobuf:"fixed64,11,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Breadcrumb) Reset() {
+	*x = Breadcrumb{}
+	mi := &file_api_memory_v1_memory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Breadcrumb) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breadcrumb) ProtoMessage() {}
+
+func (x *Breadcrumb) ProtoReflect() protoreflect.Message {
+	mi := &file_api_memory_v1_memory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breadcrumb.ProtoReflect.Descriptor instead.
+func (*Breadcrumb) Descriptor() ([]byte, []int) {
+	return file_api_memory_v1_memory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Breadcrumb) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetWhyFailed() string {
+	if x != nil {
+		return x.WhyFailed
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetImpact() float64 {
+	if x != nil {
+		return x.Impact
+	}
+	return 0
+}
+
+func (x *Breadcrumb) GetCreatedTimestamp() float64 {
+	if x != nil {
+		return x.CreatedTimestamp
+	}
+	return 0
+}
+
+func (x *Breadcrumb) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Breadcrumb) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only events of this project, by name or ID; empty watches every readable project
+	Project string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	// Resume after this event seq; when unset, only events recorded from now on are sent
+	After         *int64 `protobuf:"varint,2,opt,name=after,proto3,oneof" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_api_memory_v1_memory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_memory_v1_memory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_memory_v1_memory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WatchRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetAfter() int64 {
+	if x != nil && x.After != nil {
+		return *x.After
+	}
+	return 0
+}
+
+type BreadcrumbEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position in the server's event log; pass it as after to resume
+	Seq        int64  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Id         string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	EntityType string `protobuf:"bytes,3,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId   string `protobuf:"bytes,4,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	// finding_created, finding_verified, unknown_resolved, moved, ...
+	Kind      string `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`
+	ProjectId string `protobuf:"bytes,6,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	// The event's JSON payload
+	Payload       string  `protobuf:"bytes,7,opt,name=payload,proto3" json:"payload,omitempty"`
+	Timestamp     float64 `protobuf:"fixed64,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	DeviceId      string  `protobuf:"bytes,9,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Lamport       int64   `protobuf:"varint,10,opt,name=lamport,proto3" json:"lamport,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BreadcrumbEvent) Reset() {
+	*x = BreadcrumbEvent{}
+	mi := &file_api_memory_v1_memory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BreadcrumbEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BreadcrumbEvent) ProtoMessage() {}
+
+func (x *BreadcrumbEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_memory_v1_memory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BreadcrumbEvent.ProtoReflect.Descriptor instead.
+func (*BreadcrumbEvent) Descriptor() ([]byte, []int) {
+	return file_api_memory_v1_memory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BreadcrumbEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *BreadcrumbEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BreadcrumbEvent) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *BreadcrumbEvent) GetLamport() int64 {
+	if x != nil {
+		return x.Lamport
+	}
+	return 0
+}
+
+var File_api_memory_v1_memory_proto protoreflect.FileDescriptor
+
+const file_api_memory_v1_memory_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapi/memory/v1/memory.proto\x12\tmemory.v1\"l\n" +
+	"\fQueryRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x16\n" +
+	"\x06search\x18\x02 \x01(\tR\x06search\x12\x14\n" +
+	"\x05types\x18\x03 \x03(\tR\x05types\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xb0\x02\n" +
+	"\n" +
+	"Breadcrumb\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x04 \x01(\tR\tsessionId\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x1d\n" +
+	"\n" +
+	"why_failed\x18\x06 \x01(\tR\twhyFailed\x12\x14\n" +
+	"\x05scope\x18\a \x01(\tR\x05scope\x12\x16\n" +
+	"\x06impact\x18\b \x01(\x01R\x06impact\x12+\n" +
+	"\x11created_timestamp\x18\t \x01(\x01R\x10createdTimestamp\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\v \x01(\x01R\n" +
+	"confidence\"M\n" +
+	"\fWatchRequest\x12\x18\n" +
+	"\aproject\x18\x01 \x01(\tR\aproject\x12\x19\n" +
+	"\x05after\x18\x02 \x01(\x03H\x00R\x05after\x88\x01\x01B\b\n" +
+	"\x06_after\"\x93\x02\n" +
+	"\x0fBreadcrumbEvent\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1f\n" +
+	"\ventity_type\x18\x03 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x04 \x01(\tR\bentityId\x12\x12\n" +
+	"\x04kind\x18\x05 \x01(\tR\x04kind\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x06 \x01(\tR\tprojectId\x12\x18\n" +
+	"\apayload\x18\a \x01(\tR\apayload\x12\x1c\n" +
+	"\ttimestamp\x18\b \x01(\x01R\ttimestamp\x12\x1b\n" +
+	"\tdevice_id\x18\t \x01(\tR\bdeviceId\x12\x18\n" +
+	"\alamport\x18\n" +
+	" \x01(\x03R\alamport2\x8a\x01\n" +
+	"\rMemoryService\x129\n" +
+	"\x05Query\x12\x17.memory.v1.QueryRequest\x1a\x15.memory.v1.Breadcrumb0\x01\x12>\n" +
+	"\x05Watch\x12\x17.memory.v1.WatchRequest\x1a\x1a.memory.v1.BreadcrumbEvent0\x01B1Z/github.com/AbdouB/memory/api/memory/v1;memoryv1b\x06proto3"
+
+var (
+	file_api_memory_v1_memory_proto_rawDescOnce sync.Once
+	file_api_memory_v1_memory_proto_rawDescData []byte
+)
+
+func file_api_memory_v1_memory_proto_rawDescGZIP() []byte {
+	file_api_memory_v1_memory_proto_rawDescOnce.Do(func() {
+		file_api_memory_v1_memory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_memory_v1_memory_proto_rawDesc), len(file_api_memory_v1_memory_proto_rawDesc)))
+	})
+	return file_api_memory_v1_memory_proto_rawDescData
+}
+
+var file_api_memory_v1_memory_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_api_memory_v1_memory_proto_goTypes = []any{
+	(*QueryRequest)(nil),    // 0: memory.v1.QueryRequest
+	(*Breadcrumb)(nil),      // 1: memory.v1.Breadcrumb
+	(*WatchRequest)(nil),    // 2: memory.v1.WatchRequest
+	(*BreadcrumbEvent)(nil), // 3: memory.v1.BreadcrumbEvent
+}
+var file_api_memory_v1_memory_proto_depIdxs = []int32{
+	0, // 0: memory.v1.MemoryService.Query:input_type -> memory.v1.QueryRequest
+	2, // 1: memory.v1.MemoryService.Watch:input_type -> memory.v1.WatchRequest
+	1, // 2: memory.v1.MemoryService.Query:output_type -> memory.v1.Breadcrumb
+	3, // 3: memory.v1.MemoryService.Watch:output_type -> memory.v1.BreadcrumbEvent
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_memory_v1_memory_proto_init() }
+func file_api_memory_v1_memory_proto_init() {
+	if File_api_memory_v1_memory_proto != nil {
+		return
+	}
+	file_api_memory_v1_memory_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_memory_v1_memory_proto_rawDesc), len(file_api_memory_v1_memory_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_memory_v1_memory_proto_goTypes,
+		DependencyIndexes: file_api_memory_v1_memory_proto_depIdxs,
+		MessageInfos:      file_api_memory_v1_memory_proto_msgTypes,
+	}.Build()
+	File_api_memory_v1_memory_proto = out.File
+	file_api_memory_v1_memory_proto_goTypes = nil
+	file_api_memory_v1_memory_proto_depIdxs = nil
+}
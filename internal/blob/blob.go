@@ -0,0 +1,161 @@
+// Package blob keeps large evidence and attachments as content-addressed files next to
+// the database, so only their hashes are stored in it
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DirName is the blob directory inside the memory directory
+const DirName = "blobs"
+
+// RefPrefix starts a reference to a blob stored in place of text
+const RefPrefix = "blob:sha256:"
+
+// Store reads and writes blobs under a directory, named by the SHA-256 of their content
+type Store struct {
+	dir string
+}
+
+// New returns the store of a memory directory
+func New(memoryDir string) *Store {
+	return &Store{dir: filepath.Join(memoryDir, DirName)}
+}
+
+// Put stores data and returns its hash. Storing the same content again is a no-op.
+func (s *Store) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	path := s.Path(hash)
+	if _, err := os.Stat(path); err == nil {
+		// Refreshed so Collect spares it until the new reference is recorded
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	// Write then rename so a reader never sees a partial blob under its hash
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// Get reads a blob, checking its content still matches the hash
+func (s *Store) Get(hash string) ([]byte, error) {
+	if !validHash(hash) {
+		return nil, fmt.Errorf("invalid blob hash %q", hash)
+	}
+	data, err := os.ReadFile(s.Path(hash))
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("blob %s is corrupt", hash[:12])
+	}
+	return data, nil
+}
+
+// Path returns where a blob is stored: blobs/<first two hex digits>/<hash>
+func (s *Store) Path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(s.dir, hash)
+	}
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Ref returns the reference stored in place of a blob's content
+func Ref(hash string) string {
+	return RefPrefix + hash
+}
+
+// ParseRef returns the hash a reference points to, if text is one
+func ParseRef(text string) (string, bool) {
+	hash, ok := strings.CutPrefix(text, RefPrefix)
+	if !ok || !validHash(hash) {
+		return "", false
+	}
+	return hash, true
+}
+
+// refPattern matches blob references inside larger text, such as event payloads
+var refPattern = regexp.MustCompile(regexp.QuoteMeta(RefPrefix) + `[0-9a-f]{64}`)
+
+// FindRefs returns the hashes of the blobs referred to anywhere in text
+func FindRefs(text string) []string {
+	var hashes []string
+	for _, ref := range refPattern.FindAllString(text, -1) {
+		hashes = append(hashes, strings.TrimPrefix(ref, RefPrefix))
+	}
+	return hashes
+}
+
+// Collect removes the blobs not in keep that were last written before cutoff, returning
+// how many were removed and the bytes freed. The cutoff spares blobs stored for a write
+// that hasn't recorded its reference yet. With dryRun nothing is removed.
+func (s *Store) Collect(keep map[string]bool, cutoff time.Time, dryRun bool) (removed int, freed int64, err error) {
+	shards, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, shard := range shards {
+		if !shard.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(s.dir, shard.Name()))
+		if err != nil {
+			return removed, freed, err
+		}
+		for _, entry := range entries {
+			hash := entry.Name()
+			if !validHash(hash) || keep[hash] {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if !dryRun {
+				if err := os.Remove(s.Path(hash)); err != nil {
+					return removed, freed, err
+				}
+			}
+			removed++
+			freed += info.Size()
+		}
+	}
+	return removed, freed, nil
+}
+
+// validHash reports whether s is a hex-encoded SHA-256
+func validHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
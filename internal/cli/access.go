@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// hashToken is how API tokens are stored and compared
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// syncAccess is what a sync request's token may touch: everything, or the projects
+// an API token was granted
+type syncAccess struct {
+	token *config.APIToken // nil for the --token given to serve, which has full access
+	names map[string]string
+}
+
+// requestAccess matches a request's bearer token against the serve token and the
+// configured API tokens; nil means it matched neither
+func requestAccess(r *http.Request, serveToken string, tokens []config.APIToken) *syncAccess {
+	return tokenAccess(r.Header.Get("Authorization"), serveToken, tokens)
+}
+
+// tokenAccess matches an authorization value ("Bearer <token>") against the serve token
+// and the configured API tokens; nil means it matched neither, or had no Bearer scheme
+func tokenAccess(authorization, serveToken string, tokens []config.APIToken) *syncAccess {
+	given, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || given == "" {
+		return nil
+	}
+	if serveToken != "" && subtle.ConstantTimeCompare([]byte(given), []byte(serveToken)) == 1 {
+		return &syncAccess{}
+	}
+	hash := hashToken(given)
+	for i := range tokens {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(tokens[i].Hash)) == 1 {
+			return &syncAccess{token: &tokens[i], names: map[string]string{}}
+		}
+	}
+	return nil
+}
+
+// allows reports whether the request may access a project at a level
+func (a *syncAccess) allows(ctx context.Context, projectID, access string) bool {
+	if a.token == nil {
+		return true
+	}
+	name, ok := a.names[projectID]
+	if !ok {
+		if p, err := stores.Projects.Get(ctx, projectID); err == nil {
+			name = p.Name
+		}
+		a.names[projectID] = name
+	}
+	return a.token.Allows(projectID, name, access)
+}
+
+// filterSyncBatch drops the events and projects a pull may not read. The cursor is
+// kept, so the client still moves past the events it wasn't sent.
+func (a *syncAccess) filterSyncBatch(ctx context.Context, batch *models.SyncBatch) error {
+	if a.token == nil {
+		return nil
+	}
+	eventProjects, err := stores.Sync.EventProjectIDs(ctx, batch.Events)
+	if err != nil {
+		return err
+	}
+	events := batch.Events[:0]
+	for _, ev := range batch.Events {
+		if projectID, ok := eventProjects[ev.EntityID]; ok && a.allows(ctx, projectID, config.AccessRead) {
+			events = append(events, ev)
+		}
+	}
+	batch.Events = events
+
+	projects := batch.Projects[:0]
+	for _, p := range batch.Projects {
+		if a.allows(ctx, p.ID, config.AccessRead) {
+			projects = append(projects, p)
+		}
+	}
+	batch.Projects = projects
+	return nil
+}
+
+// checkSyncWrite fails unless a push only adds to projects the token may write.
+// Events and projects the server already holds are skipped, since clients push back
+// what they pulled from projects they may only read.
+func (a *syncAccess) checkSyncWrite(ctx context.Context, batch *models.SyncBatch) error {
+	if a.token == nil {
+		return nil
+	}
+	for _, p := range batch.Projects {
+		if _, err := stores.Projects.Get(ctx, p.ID); err == nil {
+			continue
+		}
+		// A project pushed for the first time is known only by the batch
+		a.names[p.ID] = p.Name
+		if !a.allows(ctx, p.ID, config.AccessWrite) {
+			return fmt.Errorf("token %s may not write project %s", a.token.Name, p.Name)
+		}
+	}
+
+	events, err := stores.Sync.NewSyncEvents(ctx, batch.Events)
+	if err != nil {
+		return err
+	}
+	eventProjects, err := stores.Sync.EventProjectIDs(ctx, events)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		projectID, ok := eventProjects[ev.EntityID]
+		if !ok {
+			return fmt.Errorf("token %s may not write %s %s of an unknown project", a.token.Name, ev.EntityType, ev.EntityID)
+		}
+		if !a.allows(ctx, projectID, config.AccessWrite) {
+			return fmt.Errorf("token %s may not write project %s", a.token.Name, projectID)
+		}
+		// A move also writes the project it files the breadcrumb under
+		if ev.Kind == models.EventBreadcrumbMoved {
+			var moved models.BreadcrumbMovedPayload
+			if err := json.Unmarshal([]byte(ev.Payload), &moved); err != nil {
+				return fmt.Errorf("%w event %s: %v", db.ErrInvalid, ev.ID, err)
+			}
+			if !a.allows(ctx, moved.ProjectID, config.AccessWrite) {
+				return fmt.Errorf("token %s may not write project %s", a.token.Name, moved.ProjectID)
+			}
+		}
+	}
+	return nil
+}
+
+// serveTokenCmd groups API token commands
+var serveTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage API tokens for the sync server",
+	Long: `API tokens let a shared sync server give each agent or teammate access to some
+projects only. A token is granted read or write access per project (by ID or name, or *
+for every project); write includes read. Pulls only return what the token may read,
+and pushes touching a project it may not write are rejected.
+
+Tokens are stored hashed in the served database's config and checked on every request,
+so changes apply without restarting 'memory serve'. The --token given to serve keeps
+full access.`,
+}
+
+// serveTokenCreateCmd creates an API token
+var serveTokenCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an API token",
+	Long: `Create an API token and print it. It is shown only once.
+
+Examples:
+  memory serve token create ci-bot --read payments --read auth
+  memory serve token create alice --write payments --read '*'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		read, _ := cmd.Flags().GetStringArray("read")
+		write, _ := cmd.Flags().GetStringArray("write")
+		if len(read) == 0 && len(write) == 0 {
+			return fmt.Errorf("%w: grant at least one --read or --write project", db.ErrInvalid)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		for _, t := range cfg.Tokens {
+			if t.Name == name {
+				return fmt.Errorf("%w: token %q already exists; revoke it first", db.ErrInvalid, name)
+			}
+		}
+
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			return fmt.Errorf("failed to generate token: %w", err)
+		}
+		token := "mem_" + hex.EncodeToString(secret)
+
+		apiToken := config.APIToken{Name: name, Hash: hashToken(token)}
+		for _, p := range read {
+			apiToken.Grants = append(apiToken.Grants, config.ProjectGrant{Project: p, Access: config.AccessRead})
+		}
+		for _, p := range write {
+			apiToken.Grants = append(apiToken.Grants, config.ProjectGrant{Project: p, Access: config.AccessWrite})
+		}
+		cfg.Tokens = append(cfg.Tokens, apiToken)
+		if err := cfg.Save(memoryDir()); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Token %s: %s\n", name, token)
+			fmt.Println("  Store it now; it can't be shown again.")
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "created",
+				"name":   name,
+				"token":  token,
+				"grants": apiToken.Grants,
+			})
+		}
+		return nil
+	},
+}
+
+// serveTokenListCmd lists API tokens and their grants
+var serveTokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API tokens and what they may access",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if !outputText {
+			type tokenInfo struct {
+				Name   string                `json:"name"`
+				Grants []config.ProjectGrant `json:"grants"`
+			}
+			tokens := make([]tokenInfo, 0, len(cfg.Tokens))
+			for _, t := range cfg.Tokens {
+				tokens = append(tokens, tokenInfo{Name: t.Name, Grants: t.Grants})
+			}
+			outputResult(map[string]interface{}{
+				"tokens": tokens,
+				"count":  len(tokens),
+			})
+			return nil
+		}
+		if len(cfg.Tokens) == 0 {
+			fmt.Println("No API tokens.")
+			return nil
+		}
+		for _, t := range cfg.Tokens {
+			grants := make([]string, 0, len(t.Grants))
+			for _, g := range t.Grants {
+				grants = append(grants, g.Access+" "+g.Project)
+			}
+			fmt.Printf("  %s: %s\n", t.Name, strings.Join(grants, ", "))
+		}
+		return nil
+	},
+}
+
+// serveTokenRevokeCmd deletes an API token
+var serveTokenRevokeCmd = &cobra.Command{
+	Use:   "revoke [name]",
+	Short: "Revoke an API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		kept := cfg.Tokens[:0]
+		for _, t := range cfg.Tokens {
+			if t.Name != args[0] {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(cfg.Tokens) {
+			return fmt.Errorf("token %q: %w", args[0], db.ErrNotFound)
+		}
+		cfg.Tokens = kept
+		if err := cfg.Save(memoryDir()); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Revoked token %s\n", args[0])
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "revoked",
+				"name":   args[0],
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveTokenCreateCmd.Flags().StringArray("read", nil, "Project ID or name the token may read, or * (repeatable)")
+	serveTokenCreateCmd.Flags().StringArray("write", nil, "Project ID or name the token may read and write, or * (repeatable)")
+
+	serveTokenCmd.AddCommand(serveTokenCreateCmd, serveTokenListCmd, serveTokenRevokeCmd)
+	serveCmd.AddCommand(serveTokenCmd)
+}
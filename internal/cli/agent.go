@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+)
+
+// defaultAIID is the AI identifier used when the calling agent can't be detected
+const defaultAIID = "claude-code"
+
+// aiIDEnvVar names the calling agent explicitly, overriding detection
+const aiIDEnvVar = "MEMORY_AI_ID"
+
+// agentSignature recognizes an agent by the environment variables it sets
+type agentSignature struct {
+	aiID     string
+	vars     []string // Any of these set identifies the agent
+	prefixes []string // As does any variable starting with one of these
+}
+
+// agentSignatures are checked in order. Coding agents come before CI systems so an
+// agent running inside a CI job is still told apart from the job itself.
+var agentSignatures = []agentSignature{
+	{aiID: "claude-code", vars: []string{"CLAUDECODE"}, prefixes: []string{"CLAUDE_CODE_"}},
+	{aiID: "cursor", vars: []string{"CURSOR_TRACE_ID"}, prefixes: []string{"CURSOR_"}},
+	{aiID: "codex", prefixes: []string{"CODEX_"}},
+	{aiID: "aider", prefixes: []string{"AIDER_"}},
+	{aiID: "windsurf", prefixes: []string{"WINDSURF_"}},
+	{aiID: "github-actions", vars: []string{"GITHUB_ACTIONS"}},
+	{aiID: "gitlab-ci", vars: []string{"GITLAB_CI"}},
+}
+
+// detectAIID names the calling agent from MEMORY_AI_ID, the profile in effect or the
+// variables agents and CI systems set, and reports whether one was found
+func detectAIID() (string, bool) {
+	if id := os.Getenv(aiIDEnvVar); id != "" {
+		return id, true
+	}
+	if activeProfile != nil && activeProfile.AIID != "" {
+		return activeProfile.AIID, true
+	}
+	environ := os.Environ()
+	for _, sig := range agentSignatures {
+		for _, v := range sig.vars {
+			if os.Getenv(v) != "" {
+				return sig.aiID, true
+			}
+		}
+		for _, kv := range environ {
+			name, _, _ := strings.Cut(kv, "=")
+			for _, prefix := range sig.prefixes {
+				if strings.HasPrefix(name, prefix) {
+					return sig.aiID, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// agentOverfetch multiplies list limits while ranking an agent family first, so its
+// breadcrumbs older than the newest few still make the cut
+const agentOverfetch = 5
+
+// agentFamily is the vendor part of an AI ID: "claude" for claude-code and
+// claude-reviewer, "user" for user:alice
+func agentFamily(aiID string) string {
+	family, _, _ := strings.Cut(strings.ToLower(aiID), "-")
+	family, _, _ = strings.Cut(family, ":")
+	family, _, _ = strings.Cut(family, "_")
+	return family
+}
+
+// agentFilter narrows context and query results to breadcrumbs from some agents, or
+// ranks one agent family's breadcrumbs first
+type agentFilter struct {
+	aiIDs        []string          // Keep only breadcrumbs logged by these AI IDs or agent families
+	preferFamily string            // Rank this agent family's breadcrumbs first
+	sessionAIIDs map[string]string // AI ID of each session looked up so far
+}
+
+// newAgentFilter builds a filter; a nil filter keeps everything in order
+func newAgentFilter(aiIDs []string, preferSameFamilyAs string) *agentFilter {
+	if len(aiIDs) == 0 && preferSameFamilyAs == "" {
+		return nil
+	}
+	f := &agentFilter{aiIDs: aiIDs, sessionAIIDs: map[string]string{}}
+	if preferSameFamilyAs != "" {
+		f.preferFamily = agentFamily(preferSameFamilyAs)
+	}
+	return f
+}
+
+// page selects a page of breadcrumbs with the --ai-id filter applied in the query.
+// When ranking a family first the page is overfetched for agentScoped to trim.
+func (f *agentFilter) page(size int) db.Page {
+	if f == nil {
+		return db.Page{Size: size}
+	}
+	if f.preferFamily != "" {
+		size *= agentOverfetch
+	}
+	return db.Page{Size: size, AIIDs: f.aiIDs}
+}
+
+// sessionAIID returns the AI ID that ran a session
+func (f *agentFilter) sessionAIID(ctx context.Context, sessionID string) string {
+	aiID, ok := f.sessionAIIDs[sessionID]
+	if !ok {
+		if session, err := stores.Sessions.Get(ctx, sessionID); err == nil {
+			aiID = session.AIID
+		}
+		f.sessionAIIDs[sessionID] = aiID
+	}
+	return aiID
+}
+
+// allows reports whether an AI ID passes the --ai-id filter
+func (f *agentFilter) allows(aiID string) bool {
+	if len(f.aiIDs) == 0 {
+		return true
+	}
+	for _, want := range f.aiIDs {
+		if strings.EqualFold(want, aiID) || strings.EqualFold(want, agentFamily(aiID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// agentScoped drops items logged by agents the filter excludes and, when preferring a
+// family, moves that family's items first. Lists fetched with the filter's page are
+// already narrowed in SQL; searches are narrowed here. It keeps at most limit items and returns
+// how many were dropped by the filter.
+func agentScoped[T any](ctx context.Context, f *agentFilter, items []T, limit int, itemSession func(T) string) ([]T, int) {
+	if f == nil {
+		return items, 0
+	}
+	kept := items[:0]
+	hidden := 0
+	for _, item := range items {
+		if !f.allows(f.sessionAIID(ctx, itemSession(item))) {
+			hidden++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if f.preferFamily != "" {
+		sort.SliceStable(kept, func(i, j int) bool {
+			return f.preferred(ctx, itemSession(kept[i])) && !f.preferred(ctx, itemSession(kept[j]))
+		})
+	}
+	if len(kept) > limit {
+		kept = kept[:limit]
+	}
+	return kept, hidden
+}
+
+// preferred reports whether a session was run by the preferred agent family
+func (f *agentFilter) preferred(ctx context.Context, sessionID string) bool {
+	return agentFamily(f.sessionAIID(ctx, sessionID)) == f.preferFamily
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+)
+
+// shellAliasCommands maps the shell function names printed by --emit-aliases to the
+// commands they run, in the order they are printed
+var shellAliasCommands = [][2]string{
+	{"ml", "learned"},
+	{"mu", "uncertain"},
+	{"mt", "tried"},
+	{"ms", "status"},
+}
+
+// shellAliases renders shell functions for the most frequently typed commands, for
+// sh-compatible shells (bash, zsh) or fish
+func shellAliases(shell string) (string, error) {
+	var b strings.Builder
+	if shell == "fish" {
+		b.WriteString("# memory short commands; add to config.fish: memory --emit-aliases=fish | source\n")
+	} else {
+		b.WriteString("# memory short commands; add to your shell profile: eval \"$(memory --emit-aliases)\"\n")
+	}
+	for _, a := range shellAliasCommands {
+		switch shell {
+		case "sh", "bash", "zsh":
+			fmt.Fprintf(&b, "%s() { memory %s \"$@\"; }\n", a[0], a[1])
+		case "fish":
+			fmt.Fprintf(&b, "function %s; memory %s $argv; end\n", a[0], a[1])
+		default:
+			return "", fmt.Errorf("%w shell %q (use sh, bash, zsh or fish)", db.ErrInvalid, shell)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// archiveCmd moves old sessions' breadcrumbs out of the working database
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Move old sessions and their breadcrumbs to an archive database",
+	Long: `Move the findings, unknowns and dead ends of sessions that started before a date
+into an archive database, keeping the working database small and session context
+focused on current knowledge. Handoffs are copied to the archive too.
+
+The sessions themselves and their handoff reports stay behind as summaries, so
+'memory sessions' and 'memory timeline' still show what was done. Breadcrumbs still
+in use stay as well: findings verified since the cutoff and open unknowns. The active
+session is never archived.
+
+The archive defaults to archive.db next to the database. Archiving again adds to it.
+A target ending in .gz is written as a gzip-compressed database. Evidence blobs of
+archived findings are copied into the archive and leave .memory/blobs.
+
+Restore archived breadcrumbs with 'memory db merge', after gunzip for a compressed
+archive; their evidence blobs come back with them. Until then, events for them that
+'db merge' or 'sync pull' bring in from other copies of the database are skipped, so
+archived breadcrumbs don't come back on their own.
+
+Examples:
+  memory archive --before 2024-01-01
+  memory archive --before 180d --dry-run
+  memory archive --before 2024-01-01 --to ~/memory-2023.db.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		beforeStr, _ := cmd.Flags().GetString("before")
+		if beforeStr == "" {
+			return fmt.Errorf("%w: --before is required", db.ErrInvalid)
+		}
+		before, err := parseTimePoint(beforeStr)
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		target, _ := cmd.Flags().GetString("to")
+		if target == "" {
+			target = filepath.Join(filepath.Dir(database.Path()), "archive.db")
+		}
+		if info, err := os.Stat(target); err == nil {
+			if self, err := os.Stat(database.Path()); err == nil && os.SameFile(info, self) {
+				return fmt.Errorf("%s is the current database", target)
+			}
+		}
+
+		exclude := ""
+		if active, err := loadActiveSession(ctx); err == nil {
+			exclude = active.SessionID
+		}
+
+		var result *db.ArchiveResult
+		if dryRun {
+			result, err = database.ArchiveSessions(ctx, target, before, exclude, true)
+		} else if strings.HasSuffix(target, ".gz") {
+			result, err = archiveCompressed(ctx, target, before, exclude)
+		} else {
+			result, err = archiveTo(ctx, target, before, exclude)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to archive: %w", err)
+		}
+
+		if outputText {
+			counts := fmt.Sprintf("%d sessions (%d findings, %d unknowns, %d dead ends)",
+				result.Sessions, result.Findings, result.Unknowns, result.DeadEnds)
+			if dryRun {
+				fmt.Printf("Dry run: would archive %s to %s\n", counts, target)
+			} else {
+				fmt.Printf("✓ Archived %s to %s\n", counts, target)
+			}
+			if result.Kept > 0 {
+				fmt.Printf("  %d breadcrumbs still in use stay in place\n", result.Kept)
+			}
+			return nil
+		}
+		status := "archived"
+		if dryRun {
+			status = "dry_run"
+		}
+		outputResult(map[string]interface{}{
+			"status":    status,
+			"target":    target,
+			"before":    before,
+			"sessions":  result.Sessions,
+			"findings":  result.Findings,
+			"unknowns":  result.Unknowns,
+			"dead_ends": result.DeadEnds,
+			"events":    result.Events,
+			"kept":      result.Kept,
+		})
+		return nil
+	},
+}
+
+// archiveTo archives into a database file, creating it or bringing it up to the
+// current schema first
+func archiveTo(ctx context.Context, path string, before time.Time, exclude string) (*db.ArchiveResult, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	archive, err := db.Open(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	archive.Close()
+	result, err := database.ArchiveSessions(ctx, path, before, exclude, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The archived findings' evidence blobs go into the archive, then leave the blob
+	// store once nothing left in the working database refers to them
+	if archive, err = db.Open(ctx, path); err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	_, err = packEvidenceBlobs(ctx, archive)
+	archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := collectEvidenceBlobs(ctx, false); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// archiveCompressed archives into a gzip-compressed database: an existing archive is
+// unpacked to a temporary file, added to, and compressed back in place
+func archiveCompressed(ctx context.Context, path string, before time.Time, exclude string) (*db.ArchiveResult, error) {
+	dir, err := os.MkdirTemp("", "memory-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	unpacked := filepath.Join(dir, "archive.db")
+	if _, err := os.Stat(path); err == nil {
+		if err := gunzipFile(path, unpacked); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	result, err := archiveTo(ctx, unpacked, before, exclude)
+	if err != nil {
+		return nil, err
+	}
+	if result.Findings+result.Unknowns+result.DeadEnds == 0 {
+		return result, nil
+	}
+	if err := gzipFile(unpacked, path); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// gunzipFile decompresses src into dst
+func gunzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, zr); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// gzipFile compresses src into dst, replacing dst only once the copy is complete
+func gzipFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+func init() {
+	archiveCmd.Flags().String("before", "", "Archive sessions started before this date (2024-01-01, RFC 3339 or a window such as 180d)")
+	archiveCmd.Flags().String("to", "", "Archive database, .gz to compress (default: archive.db next to the database)")
+	archiveCmd.Flags().Bool("dry-run", false, "Report what would be archived without writing")
+	rootCmd.AddCommand(archiveCmd)
+}
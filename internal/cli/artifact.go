@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// artifactCmd groups commands tracking what a session produced
+var artifactCmd = &cobra.Command{
+	Use:   "artifact",
+	Short: "Track files and URLs produced in the current session",
+}
+
+// artifactAddCmd records artifacts on the active session
+var artifactAddCmd = &cobra.Command{
+	Use:   "add [path...]",
+	Short: "Record files or URLs produced in this session",
+	Long: `Record files or URLs produced in the current session. They are listed in the
+handoff written by 'memory done'. Local paths are stored relative to the repository root.
+
+With --git, every file added or modified in the working tree, including untracked
+files, is recorded; deleted files and .memory/ are skipped.
+
+Examples:
+  memory artifact add internal/auth/refresh.go docs/auth.md
+  memory artifact add https://github.com/org/repo/pull/42
+  memory artifact add --git`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		fromGit, _ := cmd.Flags().GetBool("git")
+		if len(args) == 0 && !fromGit {
+			return fmt.Errorf("a path or --git is required")
+		}
+
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+
+		var paths []string
+		for _, arg := range args {
+			paths = append(paths, normalizeRepoPath(ctx, arg))
+		}
+		if fromGit {
+			changed, err := workingTreeChanges(ctx)
+			if err != nil {
+				return err
+			}
+			for _, p := range changed {
+				if !strings.HasPrefix(p, ".memory/") {
+					paths = append(paths, p)
+				}
+			}
+		}
+
+		seen := make(map[string]bool, len(active.Artifacts))
+		for _, a := range active.Artifacts {
+			seen[a] = true
+		}
+		var added []string
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				added = append(added, p)
+			}
+		}
+		active.Artifacts = append(active.Artifacts, added...)
+		if err := saveActiveSession(ctx, active); err != nil {
+			return fmt.Errorf("failed to save active session: %w", err)
+		}
+
+		if outputText {
+			if len(added) == 0 {
+				fmt.Println("No new artifacts.")
+			}
+			for _, a := range added {
+				fmt.Printf("✓ Artifact: %s\n", a)
+			}
+		} else {
+			if added == nil {
+				added = []string{}
+			}
+			outputResult(map[string]interface{}{
+				"status":    "added",
+				"added":     added,
+				"artifacts": active.Artifacts,
+			})
+		}
+		return nil
+	},
+}
+
+// artifactListCmd lists the active session's artifacts
+var artifactListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List artifacts recorded in this session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !outputText {
+			artifacts := active.Artifacts
+			if artifacts == nil {
+				artifacts = []string{}
+			}
+			outputResult(map[string]interface{}{
+				"artifacts": artifacts,
+				"count":     len(artifacts),
+			})
+			return nil
+		}
+
+		if len(active.Artifacts) == 0 {
+			fmt.Println("No artifacts.")
+			return nil
+		}
+		for _, a := range active.Artifacts {
+			fmt.Printf("  + %s\n", a)
+		}
+		return nil
+	},
+}
+
+func init() {
+	artifactAddCmd.Flags().Bool("git", false, "Record files added or modified according to git status")
+
+	artifactCmd.AddCommand(artifactAddCmd, artifactListCmd)
+	rootCmd.AddCommand(artifactCmd)
+}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// backupCmd uploads a snapshot of the database to object storage
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the database to S3",
+	Long: `Take an online snapshot of the database and stream it to S3 or any S3-compatible
+store. Agents on ephemeral machines can restore it with 'memory backup restore'.
+Evidence blobs the findings refer to are copied into the snapshot and unpacked into
+.memory/blobs on restore.
+
+A destination ending in / (or just a bucket) gets a timestamped object name. The
+destination can also be set with MEMORY_BACKUP_S3.
+
+Credentials come from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN,
+the region from AWS_REGION. Set AWS_ENDPOINT_URL_S3 for MinIO, R2 and other stores.
+
+'memory backup schedule' has the daemon back up regularly.
+
+Examples:
+  memory backup --s3 s3://my-bucket/memory/
+  memory backup --s3 s3://my-bucket/memory/latest.db`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		loc, creds, err := resolveBackupTarget(cmd)
+		if err != nil {
+			return err
+		}
+		timestampBackupKey(loc, time.Now())
+
+		size, err := uploadBackup(ctx, creds, loc)
+		if err != nil {
+			return err
+		}
+
+		target := "s3://" + loc.Bucket + "/" + loc.Key
+		if outputText {
+			fmt.Printf("✓ Backed up %s (%d bytes) to %s\n", database.Path(), size, target)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "backed_up",
+				"target": target,
+				"bytes":  size,
+			})
+		}
+		return nil
+	},
+}
+
+// backupRestoreCmd replaces the database with a backup from object storage
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the database from an S3 backup",
+	Long: `Download a backup and replace the current database with it. A destination ending
+in / restores the most recent timestamped backup under that prefix. The current
+database is kept next to it as sessions.db.bak-<time>.
+
+Examples:
+  memory backup restore --s3 s3://my-bucket/memory/
+  memory backup restore --s3 s3://my-bucket/memory/sessions-20260101T000000Z.db`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		loc, creds, err := resolveBackupTarget(cmd)
+		if err != nil {
+			return err
+		}
+		if loc.Key == "" || strings.HasSuffix(loc.Key, "/") {
+			key, err := latestBackupKey(ctx, creds, loc)
+			if err != nil {
+				return err
+			}
+			loc.Key = key
+		}
+
+		// Download next to the database so the final rename stays on one filesystem
+		dbPath := database.Path()
+		tmp, err := os.CreateTemp(filepath.Dir(dbPath), "sessions.db.restore-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temp file: %w", err)
+		}
+		tmpPath := tmp.Name()
+		defer os.Remove(tmpPath)
+		size, err := creds.s3Download(ctx, loc, tmp)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to download backup: %w", err)
+		}
+
+		// Opening migrates older backups to the current schema
+		restored, err := db.Open(ctx, tmpPath)
+		if err != nil {
+			return fmt.Errorf("backup is not a usable database: %w", err)
+		}
+		err = restored.IntegrityCheck(ctx)
+		if err != nil {
+			restored.Close()
+			return fmt.Errorf("backup is damaged: %w", err)
+		}
+		// The blobs travel inside the backup; once in the blob store they're dropped from it
+		_, err = unpackEvidenceBlobs(ctx, restored)
+		if err == nil {
+			err = restored.DropCarriedBlobs(ctx)
+		}
+		restored.Close()
+		if err != nil {
+			return fmt.Errorf("failed to restore evidence blobs: %w", err)
+		}
+
+		database.Close()
+		previous := dbPath + ".bak-" + time.Now().UTC().Format("20060102T150405Z")
+		if err := os.Rename(dbPath, previous); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to keep current database: %w", err)
+		}
+		for _, suffix := range []string{"-wal", "-shm"} {
+			os.Remove(dbPath + suffix)
+		}
+		if err := os.Rename(tmpPath, dbPath); err != nil {
+			return fmt.Errorf("failed to install backup (previous database is at %s): %w", previous, err)
+		}
+
+		source := "s3://" + loc.Bucket + "/" + loc.Key
+		if outputText {
+			fmt.Printf("✓ Restored %s (%d bytes) from %s\n", dbPath, size, source)
+			fmt.Printf("  Previous database kept at %s\n", previous)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":   "restored",
+				"source":   source,
+				"bytes":    size,
+				"previous": previous,
+			})
+		}
+		return nil
+	},
+}
+
+// resolveBackupTarget reads --s3 (or MEMORY_BACKUP_S3) and the AWS credentials
+func resolveBackupTarget(cmd *cobra.Command) (*S3Location, *s3Credentials, error) {
+	target, _ := cmd.Flags().GetString("s3")
+	if target == "" {
+		target = os.Getenv("MEMORY_BACKUP_S3")
+	}
+	if target == "" {
+		return nil, nil, fmt.Errorf("no destination; pass --s3 s3://bucket/path or set MEMORY_BACKUP_S3")
+	}
+	loc, err := parseS3URL(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	creds, err := loadS3Credentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	return loc, creds, nil
+}
+
+// uploadBackup snapshots the open database to a temp file and streams it to S3
+func uploadBackup(ctx context.Context, creds *s3Credentials, loc *S3Location) (int64, error) {
+	dir, err := os.MkdirTemp("", "memory-backup-")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	snapshot := filepath.Join(dir, "sessions.db")
+	if err := database.BackupTo(ctx, snapshot); err != nil {
+		return 0, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+	// Evidence blobs live outside the database, so the snapshot carries copies
+	snap, err := db.Open(ctx, snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	_, err = packEvidenceBlobs(ctx, snap)
+	snap.Close()
+	if err != nil {
+		return 0, err
+	}
+	size, err := creds.s3Upload(ctx, loc, snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upload backup: %w", err)
+	}
+	return size, nil
+}
+
+// timestampBackupKey names the backup object when the destination is a prefix
+func timestampBackupKey(loc *S3Location, now time.Time) {
+	if loc.Key == "" || strings.HasSuffix(loc.Key, "/") {
+		loc.Key += "sessions-" + now.UTC().Format("20060102T150405Z") + ".db"
+	}
+}
+
+// backupUploadedKey is the meta key holding when the scheduled backup last ran
+const backupUploadedKey = "backup_uploaded_at"
+
+// backupCheckInterval is how often the daemon checks whether a scheduled backup is due
+const backupCheckInterval = 10 * time.Minute
+
+// uploadScheduledBackup uploads the backup scheduled with 'memory backup schedule'
+// when a full period has passed since the last one. It returns where the backup went,
+// empty when none was due.
+func uploadScheduledBackup(ctx context.Context, now time.Time) (string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Backup == nil || cfg.Backup.Every == "" {
+		return "", nil
+	}
+	every, err := parseWindow(cfg.Backup.Every)
+	if err != nil {
+		return "", err
+	}
+	last, err := stores.Sync.GetMeta(ctx, backupUploadedKey)
+	if err != nil {
+		return "", err
+	}
+	if last != "" {
+		if uploaded, err := time.Parse(time.RFC3339, last); err == nil && now.Sub(uploaded) < every {
+			return "", nil
+		}
+	}
+
+	loc, err := parseS3URL(cfg.Backup.S3)
+	if err != nil {
+		return "", err
+	}
+	creds, err := loadS3Credentials()
+	if err != nil {
+		return "", err
+	}
+	timestampBackupKey(loc, now)
+	if _, err := uploadBackup(ctx, creds, loc); err != nil {
+		return "", err
+	}
+	if err := stores.Sync.SetMeta(ctx, backupUploadedKey, now.UTC().Format(time.RFC3339)); err != nil {
+		return "", fmt.Errorf("failed to record backup: %w", err)
+	}
+	return "s3://" + loc.Bucket + "/" + loc.Key, nil
+}
+
+// backupScheduleCmd shows or sets the backups the daemon uploads
+var backupScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Have the daemon back up the database regularly",
+	Long: `Set how often 'memory daemon' uploads a backup, and where to. The first backup is
+uploaded when the daemon next checks, within ten minutes; later ones a period apart.
+A destination ending in / gets timestamped object names, so every backup is kept.
+Credentials are read from the daemon's environment, as for 'memory backup'.
+
+Without flags the current schedule is shown. The schedule is stored in config.json
+next to the database.
+
+Examples:
+  memory backup schedule --every 1d --s3 s3://my-bucket/memory/
+  memory backup schedule --every 6h
+  memory backup schedule --off`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		every, _ := cmd.Flags().GetString("every")
+		target, _ := cmd.Flags().GetString("s3")
+		off, _ := cmd.Flags().GetBool("off")
+
+		changed := off || every != "" || target != ""
+		switch {
+		case off:
+			cfg.Backup = nil
+		case changed:
+			schedule := &config.BackupConfig{}
+			if cfg.Backup != nil {
+				*schedule = *cfg.Backup
+			}
+			if every != "" {
+				if _, err := parseWindow(every); err != nil {
+					return err
+				}
+				schedule.Every = every
+			}
+			if target != "" {
+				if _, err := parseS3URL(target); err != nil {
+					return err
+				}
+				schedule.S3 = target
+			}
+			if schedule.Every == "" || schedule.S3 == "" {
+				return fmt.Errorf("%w: --every and --s3 are required", db.ErrInvalid)
+			}
+			cfg.Backup = schedule
+		}
+		if changed {
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		if !outputText {
+			status := "current"
+			if changed {
+				status = "saved"
+			}
+			result := map[string]interface{}{"status": status, "scheduled": cfg.Backup != nil}
+			if cfg.Backup != nil {
+				result["every"] = cfg.Backup.Every
+				result["s3"] = cfg.Backup.S3
+			}
+			outputResult(result)
+			return nil
+		}
+		if cfg.Backup == nil {
+			fmt.Println("No backup scheduled")
+			return nil
+		}
+		fmt.Printf("Backup every %s to %s\n", cfg.Backup.Every, cfg.Backup.S3)
+		return nil
+	},
+}
+
+// latestBackupKey finds the newest timestamped backup under a prefix
+func latestBackupKey(ctx context.Context, creds *s3Credentials, loc *S3Location) (string, error) {
+	keys, err := creds.s3List(ctx, loc.Bucket, loc.Key+"sessions-")
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups: %w", err)
+	}
+	var backups []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, ".db") {
+			backups = append(backups, key)
+		}
+	}
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no backups under s3://%s/%s", loc.Bucket, loc.Key)
+	}
+	sort.Strings(backups) // Timestamps sort chronologically
+	return backups[len(backups)-1], nil
+}
+
+func init() {
+	backupCmd.PersistentFlags().String("s3", "", "S3 destination (s3://bucket/path)")
+	backupScheduleCmd.Flags().String("every", "", "Back up this often, e.g. 6h or 1d")
+	backupScheduleCmd.Flags().Bool("off", false, "Stop scheduled backups")
+	backupCmd.AddCommand(backupRestoreCmd, backupScheduleCmd)
+	rootCmd.AddCommand(backupCmd)
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// BlameEntry is a single breadcrumb related to a file
+type BlameEntry struct {
+	Type          string  `json:"type"` // finding, unknown, dead_end
+	ID            string  `json:"id"`
+	Text          string  `json:"text"`
+	SecondaryText string  `json:"secondary_text,omitempty"` // why_failed for dead ends
+	Status        string  `json:"status"`                   // fresh/aging/stale for findings, open/resolved for unknowns
+	Freshness     float64 `json:"freshness"`
+	DaysOld       int     `json:"days_old"`
+	FileChanged   bool    `json:"file_changed,omitempty"`
+	Match         string  `json:"match"` // scope or citation
+	Scope         string  `json:"scope,omitempty"`
+
+	Snippet *models.CodeSnippet `json:"snippet,omitempty"` // The code as it read when the finding was logged
+}
+
+// resolveTargetPath normalizes a user-supplied path for comparison with stored scopes
+func resolveTargetPath(ctx context.Context, path string) (target, repoRoot string) {
+	if root, err := gitRepoRoot(ctx); err == nil {
+		return normalizeScopePath(path, root), root
+	}
+	return filepath.ToSlash(filepath.Clean(path)), ""
+}
+
+// matchFileScope reports how a breadcrumb relates to the target file:
+// "scope" when scoped to the file (or a directory containing it), "citation" when
+// its text mentions the file, or empty string when unrelated
+func matchFileScope(subject *string, texts []string, target, repoRoot string) string {
+	if subject != nil && *subject != "" {
+		s := filepath.ToSlash(filepath.Clean(*subject))
+		if repoRoot != "" {
+			s = normalizeScopePath(*subject, repoRoot)
+		}
+		if s == target || strings.HasPrefix(target, strings.TrimSuffix(s, "/")+"/") {
+			return "scope"
+		}
+	}
+	for _, t := range texts {
+		if strings.Contains(t, target) {
+			return "citation"
+		}
+	}
+	return ""
+}
+
+// ageFreshness decays a creation timestamp with the same half-life as findings
+func ageFreshness(createdTimestamp float64) float64 {
+	days := ageDays(createdTimestamp)
+	return math.Exp(-math.Log(2) / models.DecayHalfLifeDays * days)
+}
+
+// ageDays returns the number of days since a timestamp
+func ageDays(timestamp float64) float64 {
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	return (now - timestamp) / (24 * 60 * 60)
+}
+
+// collectFileKnowledge gathers every breadcrumb related to a file, freshest first
+func collectFileKnowledge(ctx context.Context, projectID, path string, includeResolved bool) ([]BlameEntry, error) {
+	target, repoRoot := resolveTargetPath(ctx, path)
+	// Scoped breadcrumbs all come back and are matched below, so a directory scope
+	// covers the files under it; the needle only narrows the citations
+	needle := filepath.Base(target)
+	repo := stores.Breadcrumbs
+
+	var entries []BlameEntry
+
+	findings, err := repo.FindFindingsTouching(ctx, projectID, needle)
+	if err != nil {
+		return nil, err
+	}
+	primeFindingHashes(ctx, findings)
+	for _, f := range findings {
+		match := matchFileScope(f.Subject, []string{f.Finding}, target, repoRoot)
+		if match == "" {
+			continue
+		}
+		fileChanged := findingFileChanged(ctx, f)
+		freshness := f.CalculateConfidence()
+		if fileChanged {
+			freshness *= models.FileChangeConfidenceMultiplier
+		}
+		entry := BlameEntry{
+			Type:        "finding",
+			ID:          f.ID,
+			Text:        f.Finding,
+			Status:      string(f.GetStalenessStatus(fileChanged)),
+			Freshness:   freshness,
+			DaysOld:     int(f.DaysSinceVerified()),
+			FileChanged: fileChanged,
+			Match:       match,
+			Snippet:     f.Snippet,
+		}
+		if f.Subject != nil {
+			entry.Scope = *f.Subject
+		}
+		entries = append(entries, entry)
+	}
+
+	var resolvedFilter *bool
+	if !includeResolved {
+		open := false
+		resolvedFilter = &open
+	}
+	unknowns, err := repo.FindUnknownsTouching(ctx, projectID, needle, resolvedFilter)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unknowns {
+		match := matchFileScope(u.Subject, []string{u.Unknown}, target, repoRoot)
+		if match == "" {
+			continue
+		}
+		status := "open"
+		if u.IsResolved {
+			status = "resolved"
+		}
+		entry := BlameEntry{
+			Type:      "unknown",
+			ID:        u.ID,
+			Text:      u.Unknown,
+			Status:    status,
+			Freshness: ageFreshness(u.CreatedTimestamp),
+			DaysOld:   int(ageDays(u.CreatedTimestamp)),
+			Match:     match,
+		}
+		if u.Subject != nil {
+			entry.Scope = *u.Subject
+		}
+		entries = append(entries, entry)
+	}
+
+	deadEnds, err := repo.FindDeadEndsTouching(ctx, projectID, needle)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deadEnds {
+		match := matchFileScope(d.Subject, []string{d.Approach, d.WhyFailed}, target, repoRoot)
+		if match == "" {
+			continue
+		}
+		entry := BlameEntry{
+			Type:          "dead_end",
+			ID:            d.ID,
+			Text:          d.Approach,
+			SecondaryText: d.WhyFailed,
+			Status:        "dead_end",
+			Freshness:     ageFreshness(d.CreatedTimestamp),
+			DaysOld:       int(ageDays(d.CreatedTimestamp)),
+			Match:         match,
+		}
+		if d.Subject != nil {
+			entry.Scope = *d.Subject
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Freshness > entries[j].Freshness
+	})
+	return entries, nil
+}
+
+// blameCmd lists everything known about a file
+var blameCmd = &cobra.Command{
+	Use:   "blame [path]",
+	Short: "Show everything known about a file",
+	Long: `List every finding, open question and dead end scoped to a file (or a directory
+containing it) or citing it in their text, sorted by freshness.
+
+Run this before editing a file to see what is already known about it.
+
+Examples:
+  memory blame internal/auth/jwt.go
+  memory blame src/api.ts --resolved   # Include resolved questions`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		path := args[0]
+		includeResolved, _ := cmd.Flags().GetBool("resolved")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		entries, err := collectFileKnowledge(ctx, project.ID, path, includeResolved)
+		if err != nil {
+			return fmt.Errorf("failed to collect knowledge: %w", err)
+		}
+
+		if !outputText {
+			if entries == nil {
+				entries = []BlameEntry{}
+			}
+			outputResult(map[string]interface{}{
+				"path":    path,
+				"entries": entries,
+				"count":   len(entries),
+			})
+			return nil
+		}
+
+		fmt.Printf("Blame: %s\n", path)
+		fmt.Println(strings.Repeat("─", 50))
+		if len(entries) == 0 {
+			fmt.Println("Nothing known about this file yet.")
+			return nil
+		}
+		for _, e := range entries {
+			icon := stalenessIcon(models.StalenessStatus(e.Status))
+			switch e.Type {
+			case "unknown":
+				icon = "?"
+			case "dead_end":
+				icon = paint(ansiRed, "✗")
+			}
+			extra := ""
+			if e.Match == "citation" {
+				extra = " [cited]"
+			}
+			if e.FileChanged {
+				extra += " [file changed]"
+			}
+			fmt.Printf("  %s %s (%dd, %.0f%%)%s\n", icon, e.Text, e.DaysOld, e.Freshness*100, extra)
+			if e.SecondaryText != "" {
+				fmt.Printf("    Why: %s\n", e.SecondaryText)
+			}
+			if e.Snippet != nil {
+				printSnippet(e.Snippet, "    ")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	blameCmd.Flags().Bool("resolved", false, "Include resolved questions")
+	rootCmd.AddCommand(blameCmd)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// calibrateAgents groups calibration samples by agent
+func calibrateAgents(samples []*models.CalibrationSample) []*models.AgentCalibration {
+	byAgent := map[string]*models.AgentCalibration{}
+	for _, s := range samples {
+		a := byAgent[s.AIID]
+		if a == nil {
+			a = &models.AgentCalibration{AIID: s.AIID}
+			byAgent[s.AIID] = a
+		}
+		a.Sessions++
+		if s.Completed {
+			a.Completed++
+		}
+		a.Findings += s.Findings
+		a.Invalidated += s.Invalidated
+		a.MeanPredicted += s.Predicted
+		a.MeanOutcome += s.Outcome()
+	}
+
+	agents := make([]*models.AgentCalibration, 0, len(byAgent))
+	for _, a := range byAgent {
+		a.MeanPredicted = math.Round(a.MeanPredicted/float64(a.Sessions)*100) / 100
+		a.MeanOutcome = math.Round(a.MeanOutcome/float64(a.Sessions)*100) / 100
+		a.Gap = math.Round((a.MeanPredicted-a.MeanOutcome)*100) / 100
+		a.Status = models.CalibrationStatusFor(a.MeanPredicted, a.MeanOutcome)
+		agents = append(agents, a)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].AIID < agents[j].AIID })
+	return agents
+}
+
+// calibrationCmd reports how well each agent's confidence predicts its outcomes
+var calibrationCmd = &cobra.Command{
+	Use:   "calibration",
+	Short: "Report over- and underconfidence per agent",
+	Long: `Compare the confidence decision guidance gave each agent at the start of a session
+with how the session turned out. A session completed with done scores 1, less the share
+of its findings later deleted as wrong; a session its agent abandoned for a new one
+scores 0. Agents whose average confidence exceeds their average outcome by more than
+0.1 are overconfident; those below it by more than 0.1 are underconfident.
+
+Each session's handoff records its own calibration status, refreshed by this command.
+Only sessions started since snapshots were recorded can be scored.
+
+Examples:
+  memory calibration --text`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		samples, err := stores.Reflexes.CalibrationSamples(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to collect calibration samples: %w", err)
+		}
+
+		// Findings are invalidated long after done, so session statuses are revised here
+		for _, s := range samples {
+			if s.Completed {
+				status := models.CalibrationStatusFor(s.Predicted, s.Outcome())
+				if err := stores.Handoffs.SetCalibrationStatus(ctx, s.SessionID, status); err != nil {
+					return fmt.Errorf("failed to update calibration status: %w", err)
+				}
+			}
+		}
+		agents := calibrateAgents(samples)
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"agents":   agents,
+				"sessions": len(samples),
+			})
+			return nil
+		}
+
+		if len(agents) == 0 {
+			fmt.Println("No finished sessions with a recorded start confidence yet.")
+			return nil
+		}
+		fmt.Printf("%-20s %8s %10s %9s %6s  %s\n", "Agent", "Sessions", "Predicted", "Outcome", "Gap", "Status")
+		fmt.Println(strings.Repeat("─", 70))
+		for _, a := range agents {
+			fmt.Printf("%-20s %8d %9.0f%% %8.0f%% %+6.0f  %s\n", truncateText(a.AIID, 20), a.Sessions,
+				a.MeanPredicted*100, a.MeanOutcome*100, a.Gap*100, a.Status)
+			if a.Invalidated > 0 || a.Completed < a.Sessions {
+				fmt.Printf("%-20s %d abandoned, %d of %d findings invalidated\n", "",
+					a.Sessions-a.Completed, a.Invalidated, a.Findings)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(calibrationCmd)
+}
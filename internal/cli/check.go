@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// maxCheckEvidence caps how much check output is shown and stored inline as evidence;
+// longer output is kept whole in a blob
+const maxCheckEvidence = 4000
+
+// verifyCheckTimeout bounds how long a verification check may run unless verify is
+// given --timeout
+const verifyCheckTimeout = 5 * time.Minute
+
+// CheckResult captures the outcome of running a finding's verification check
+type CheckResult struct {
+	Command  string `json:"command"`
+	Passed   bool   `json:"passed"`
+	ExitCode int    `json:"exit_code"`
+	Duration string `json:"duration"`
+	Output   string `json:"output"`
+	Blob     string `json:"blob,omitempty"` // Hash of the blob holding output longer than Output shows
+	TimedOut bool   `json:"timed_out,omitempty"`
+
+	full []byte // The whole output, recorded as evidence
+}
+
+// runVerificationCheck executes a check command through the platform shell, killing
+// it after timeout. A zero exit status means the finding still holds.
+func runVerificationCheck(ctx context.Context, command string, timeout time.Duration) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	// A child of the shell can outlive it and hold the output open
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+
+	result := &CheckResult{
+		Command:  command,
+		Passed:   err == nil,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+		Output:   truncateText(string(output), maxCheckEvidence),
+		full:     output,
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.ExitCode = -1
+		result.full = append(output, fmt.Sprintf("\n(timed out after %s)", timeout)...)
+		result.Output = truncateText(string(result.full), maxCheckEvidence)
+	} else if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		// Command could not be started at all
+		result.ExitCode = -1
+		result.Output = err.Error()
+		result.full = []byte(result.Output)
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// Checklist item kinds, in the order they are worked through
+const (
+	ChecklistPrerequisite = "prerequisite"
+	ChecklistVerify       = "verify"
+	ChecklistAnswer       = "answer"
+	ChecklistSubtask      = "subtask"
+)
+
+// ChecklistItem is one step of a checklist
+type ChecklistItem struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text"`
+	Command string `json:"command,omitempty"` // Memory command that completes or records the step
+}
+
+// Checklist is an ordered list of steps to take before and while working
+type Checklist struct {
+	Objective string           `json:"objective,omitempty"`
+	Goal      string           `json:"goal,omitempty"`
+	Action    string           `json:"action"`
+	Items     []*ChecklistItem `json:"items"`
+}
+
+// buildChecklist turns decision prerequisites, stale findings, open questions and the next
+// subtask into steps, in that order
+func buildChecklist(sessionCtx *models.SessionContext) *Checklist {
+	checklist := &Checklist{Objective: sessionCtx.Objective, Items: []*ChecklistItem{}}
+	if sessionCtx.Goal != nil {
+		checklist.Goal = sessionCtx.Goal.Objective
+	}
+	decision := sessionCtx.Decision
+	if decision != nil {
+		checklist.Action = decision.Action
+		for _, p := range decision.Prerequisites {
+			checklist.Items = append(checklist.Items, &ChecklistItem{Kind: ChecklistPrerequisite, Text: p})
+		}
+	}
+	for _, q := range sessionCtx.AssignedQuestions {
+		checklist.Items = append(checklist.Items, &ChecklistItem{
+			Kind:    ChecklistAnswer,
+			Text:    fmt.Sprintf("Answer: %s (assigned to %s)", q.Unknown, q.AssignedTo),
+			Command: `memory learned "<answer>"`,
+		})
+	}
+	for _, v := range sessionCtx.RequiresVerification {
+		text := fmt.Sprintf("Verify: %s (%dd old", v.Finding, v.DaysStale)
+		if v.FileChanged {
+			text += ", file changed"
+		}
+		text += ")"
+		checklist.Items = append(checklist.Items, &ChecklistItem{Kind: ChecklistVerify, Text: text, Command: v.VerifyCommand})
+	}
+	for _, q := range sessionCtx.OpenQuestions {
+		checklist.Items = append(checklist.Items, &ChecklistItem{
+			Kind:    ChecklistAnswer,
+			Text:    "Answer: " + q,
+			Command: `memory learned "<answer>"`,
+		})
+	}
+	// Critical subtasks are already prerequisites
+	if decision != nil && decision.NextSubtask != nil && decision.NextSubtask.Importance != models.ImportanceCritical {
+		s := decision.NextSubtask
+		checklist.Items = append(checklist.Items, &ChecklistItem{
+			Kind:    ChecklistSubtask,
+			Text:    "Work on subtask: " + s.Description,
+			Command: fmt.Sprintf("memory subtask done %s", s.ID[:8]),
+		})
+	}
+	return checklist
+}
+
+// renderMarkdownChecklist formats a checklist as Markdown task lists, one section per kind
+func renderMarkdownChecklist(c *Checklist) string {
+	var b strings.Builder
+	title := c.Objective
+	if title == "" {
+		title = "project"
+	}
+	fmt.Fprintf(&b, "## Checklist: %s\n\n", title)
+	if c.Goal != "" {
+		fmt.Fprintf(&b, "_Goal: %s_\n\n", c.Goal)
+	}
+	if len(c.Items) == 0 {
+		b.WriteString("Nothing to check first. Proceed with the task.\n")
+		return b.String()
+	}
+
+	sections := []struct{ kind, title string }{
+		{ChecklistPrerequisite, "Before proceeding"},
+		{ChecklistVerify, "Verify stale findings"},
+		{ChecklistAnswer, "Answer open questions"},
+		{ChecklistSubtask, "Then"},
+	}
+	for _, s := range sections {
+		var lines []string
+		for _, item := range c.Items {
+			if item.Kind != s.kind {
+				continue
+			}
+			line := "- [ ] " + item.Text
+			if item.Command != "" {
+				line += fmt.Sprintf(" — `%s`", item.Command)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "### %s\n\n%s\n\n", s.title, strings.Join(lines, "\n"))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// checklistCmd prints the steps to take before acting on the current objective
+var checklistCmd = &cobra.Command{
+	Use:   "checklist",
+	Short: "Turn open questions and stale findings into an ordered checklist",
+	Long: `Build an ordered checklist an agent can work through top to bottom: the decision
+guidance prerequisites first, then stale findings to verify, open questions to answer,
+and finally the active goal's next subtask.
+
+Uses the active session, and its goal, when there is one; otherwise the whole project.
+
+Examples:
+  memory checklist --text
+  memory checklist --format markdown > CHECKLIST.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "markdown" {
+			return fmt.Errorf("%w format %q (use json or markdown)", db.ErrInvalid, format)
+		}
+
+		var sessionCtx *models.SessionContext
+		if active, err := loadActiveSession(ctx); err == nil {
+			var inheritFrom []string
+			if active.InheritParent {
+				if project, _ := stores.Projects.Get(ctx, active.ProjectID); project != nil {
+					inheritFrom = projectAncestorIDs(ctx, project)
+				}
+			}
+			inheritFrom = withGlobalKnowledge(ctx, active.ProjectID, inheritFrom)
+			sessionCtx = buildSessionContext(ctx, active.SessionID, active.ProjectID, active.Objective, active.AIID, active.StartedAt, inheritFrom, currentGoal(ctx, active), active.contextAgents())
+		} else {
+			project, err := getOrCreateDefaultProject(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			sessionCtx = buildSessionContext(ctx, "", project.ID, "", "", time.Now(), withGlobalKnowledge(ctx, project.ID, nil), nil, nil)
+		}
+		checklist := buildChecklist(sessionCtx)
+
+		if format == "markdown" || outputText {
+			fmt.Print(renderMarkdownChecklist(checklist))
+		} else {
+			outputResult(checklist)
+		}
+		return nil
+	},
+}
+
+func init() {
+	checklistCmd.Flags().String("format", "json", "Output format: json or markdown")
+	rootCmd.AddCommand(checklistCmd)
+}
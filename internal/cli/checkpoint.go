@@ -0,0 +1,407 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// sessionBreadcrumbCounts counts the breadcrumbs a session recorded, findings by staleness
+func sessionBreadcrumbCounts(ctx context.Context, projectID, sessionID string) models.BreadcrumbCounts {
+	bcRepo := stores.Breadcrumbs
+	var counts models.BreadcrumbCounts
+
+	findings, _ := bcRepo.ListFindingsWithStaleness(ctx, projectID, sessionID, 1000)
+	primeFindingHashes(ctx, findings)
+	for _, f := range findings {
+		counts.Findings++
+		switch f.GetStalenessStatus(findingFileChanged(ctx, f)) {
+		case models.StatusFresh:
+			counts.FindingsFresh++
+		case models.StatusAging:
+			counts.FindingsAging++
+		default:
+			counts.FindingsStale++
+		}
+	}
+	resolved := true
+	resolvedUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &resolved, 1000)
+	unresolved := false
+	openUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &unresolved, 1000)
+	deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, sessionID, 1000)
+	counts.UnknownsResolved = len(resolvedUnknowns)
+	counts.UnknownsOpen = len(openUnknowns)
+	counts.DeadEnds = len(deadEnds)
+	return counts
+}
+
+// createCheckpoint snapshots the active session's epistemic state and breadcrumb counts
+// under a label
+func createCheckpoint(ctx context.Context, active *ActiveSession, label string) (*models.Checkpoint, error) {
+	snapshot := sessionEpistemicState(ctx, active.ProjectID, active.SessionID, time.Since(active.StartedAt)).Snapshot()
+	counts := sessionBreadcrumbCounts(ctx, active.ProjectID, active.SessionID)
+
+	data, err := json.Marshal(models.CheckpointData{Label: label, Counts: counts})
+	if err != nil {
+		return nil, err
+	}
+	reflex := models.NewSnapshotReflex(active.SessionID, models.PhaseCheckpoint, snapshot)
+	reflexData := string(data)
+	reflex.ReflexData = &reflexData
+	if err := stores.Reflexes.Create(ctx, reflex); err != nil {
+		return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return checkpointFromReflex(reflex), nil
+}
+
+// maybeAutoCheckpoint counts newly recorded breadcrumbs and checkpoints the active session
+// once enough breadcrumbs or time have passed since its last checkpoint, so trend and drift
+// have data without agents checkpointing explicitly. Failures are logged, never returned.
+func maybeAutoCheckpoint(ctx context.Context, active *ActiveSession, added int) {
+	// Checkpoints track the active session's own progress
+	if active.detached {
+		return
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		slog.Warn("failed to load config", "err", err)
+		return
+	}
+	if cfg.Checkpoints != nil && cfg.Checkpoints.Off {
+		return
+	}
+	everyBreadcrumbs, everyInterval := cfg.Checkpoints.Intervals()
+
+	active.BreadcrumbsSinceCheckpoint += added
+	last := active.LastCheckpointAt
+	if last.IsZero() {
+		last = active.StartedAt
+	}
+	var label string
+	switch {
+	case active.BreadcrumbsSinceCheckpoint >= everyBreadcrumbs:
+		label = fmt.Sprintf("auto (%d breadcrumbs)", active.BreadcrumbsSinceCheckpoint)
+	case active.BreadcrumbsSinceCheckpoint > 0 && time.Since(last) >= everyInterval:
+		label = fmt.Sprintf("auto (%s)", time.Since(last).Round(time.Minute))
+	}
+	if label != "" {
+		if _, err := createCheckpoint(ctx, active, label); err != nil {
+			slog.Warn("automatic checkpoint failed", "err", err)
+		} else {
+			resetCheckpointProgress(active)
+		}
+	}
+	if err := saveActiveSession(ctx, active); err != nil {
+		slog.Warn("failed to save session", "err", err)
+	}
+}
+
+// resetCheckpointProgress restarts the automatic checkpoint counters
+func resetCheckpointProgress(active *ActiveSession) {
+	active.LastCheckpointAt = time.Now()
+	active.BreadcrumbsSinceCheckpoint = 0
+}
+
+// checkpointFromReflex reads a checkpoint back out of its reflex
+func checkpointFromReflex(r *models.Reflex) *models.Checkpoint {
+	var data models.CheckpointData
+	if r.ReflexData != nil {
+		json.Unmarshal([]byte(*r.ReflexData), &data)
+	}
+	return &models.Checkpoint{
+		ID:        r.ID,
+		SessionID: r.SessionID,
+		Label:     data.Label,
+		Timestamp: r.Timestamp,
+		Vectors:   snapshotFromReflex(r),
+		Counts:    data.Counts,
+	}
+}
+
+// listCheckpoints returns a session's checkpoints, newest first
+func listCheckpoints(ctx context.Context, sessionID string) ([]*models.Checkpoint, error) {
+	reflexes, err := stores.Reflexes.ListBySession(ctx, sessionID, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	checkpoints := []*models.Checkpoint{}
+	for _, r := range reflexes {
+		if r.Phase == string(models.PhaseCheckpoint) {
+			checkpoints = append(checkpoints, checkpointFromReflex(r))
+		}
+	}
+	return checkpoints, nil
+}
+
+// countDeltas returns how far each breadcrumb count moved
+func countDeltas(from, to models.BreadcrumbCounts) map[string]int {
+	return map[string]int{
+		"findings":          to.Findings - from.Findings,
+		"findings_fresh":    to.FindingsFresh - from.FindingsFresh,
+		"findings_aging":    to.FindingsAging - from.FindingsAging,
+		"findings_stale":    to.FindingsStale - from.FindingsStale,
+		"unknowns_resolved": to.UnknownsResolved - from.UnknownsResolved,
+		"unknowns_open":     to.UnknownsOpen - from.UnknownsOpen,
+		"dead_ends":         to.DeadEnds - from.DeadEnds,
+	}
+}
+
+// checkpointCmd records a labeled progress marker in the active session
+var checkpointCmd = &cobra.Command{
+	Use:   "checkpoint [label]",
+	Short: "Mark progress within the current session",
+	Long: `Snapshot the current session's epistemic state and breadcrumb counts under a label,
+then compare against it later with 'memory checkpoint diff'.
+
+Examples:
+  memory checkpoint "before refactor"
+  memory checkpoint diff                      # Against the latest checkpoint
+  memory checkpoint diff "before refactor" --text
+  memory checkpoint list --text`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		label := strings.TrimSpace(args[0])
+		if label == "" {
+			return fmt.Errorf("%w: checkpoint label is empty", db.ErrInvalid)
+		}
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+
+		checkpoint, err := createCheckpoint(ctx, active, label)
+		if err != nil {
+			return err
+		}
+		resetCheckpointProgress(active)
+		if err := saveActiveSession(ctx, active); err != nil {
+			slog.Warn("failed to save session", "err", err)
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"status":     "created",
+				"checkpoint": checkpoint,
+			})
+			return nil
+		}
+		c := checkpoint.Counts
+		fmt.Printf("◆ Checkpoint %q: %.0f%% confidence, %d findings, %d open / %d resolved unknowns, %d dead ends\n",
+			label, checkpoint.Vectors.Overall*100, c.Findings, c.UnknownsOpen, c.UnknownsResolved, c.DeadEnds)
+		return nil
+	},
+}
+
+// checkpointListCmd lists the active session's checkpoints
+var checkpointListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the current session's checkpoints",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+		checkpoints, err := listCheckpoints(ctx, active.SessionID)
+		if err != nil {
+			return err
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"checkpoints": checkpoints,
+				"count":       len(checkpoints),
+			})
+			return nil
+		}
+		if len(checkpoints) == 0 {
+			fmt.Println("No checkpoints in this session.")
+			return nil
+		}
+		for _, c := range checkpoints {
+			fmt.Printf("%s  %3.0f%%  %s\n", time.UnixMilli(int64(c.Timestamp*1000)).Format("15:04:05"), c.Vectors.Overall*100, c.Label)
+		}
+		return nil
+	},
+}
+
+// checkpointDiffCmd compares the active session against a checkpoint
+var checkpointDiffCmd = &cobra.Command{
+	Use:   "diff [label]",
+	Short: "Compare the current session against a checkpoint",
+	Long: `Compare the current session's epistemic state and breadcrumb counts against its
+latest checkpoint, or the latest one with the given label, and list what was recorded
+since.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+		checkpoints, err := listCheckpoints(ctx, active.SessionID)
+		if err != nil {
+			return err
+		}
+
+		var checkpoint *models.Checkpoint
+		for _, c := range checkpoints {
+			if len(args) == 0 || c.Label == args[0] {
+				checkpoint = c
+				break
+			}
+		}
+		if checkpoint == nil {
+			if len(args) == 0 {
+				return fmt.Errorf("checkpoints in session %s: %w", active.SessionID, db.ErrNotFound)
+			}
+			return fmt.Errorf("checkpoint %q: %w", args[0], db.ErrNotFound)
+		}
+
+		vectors := sessionEpistemicState(ctx, active.ProjectID, active.SessionID, time.Since(active.StartedAt)).Snapshot()
+		counts := sessionBreadcrumbCounts(ctx, active.ProjectID, active.SessionID)
+		since, err := stores.Timeline.List(ctx, db.TimelineFilter{
+			ProjectID: active.ProjectID,
+			SessionID: active.SessionID,
+			Since:     checkpoint.Timestamp,
+		}, 0)
+		if err != nil {
+			return err
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"checkpoint":       checkpoint,
+				"current":          map[string]interface{}{"vectors": vectors, "counts": counts},
+				"vector_deltas":    vectorDeltas(&checkpoint.Vectors, vectors),
+				"count_deltas":     countDeltas(checkpoint.Counts, counts),
+				"since_checkpoint": since,
+			})
+			return nil
+		}
+
+		age := time.Since(time.UnixMilli(int64(checkpoint.Timestamp * 1000))).Round(time.Minute)
+		fmt.Printf("Since checkpoint %q (%s ago)\n", checkpoint.Label, age)
+		fmt.Println(strings.Repeat("─", 50))
+		deltas := vectorDeltas(&checkpoint.Vectors, vectors)
+		for _, vec := range trendVectors {
+			fmt.Printf("  %-12s %3.0f%% → %3.0f%%  (%+.0f)\n", vec.name,
+				vec.value(&checkpoint.Vectors)*100, vec.value(vectors)*100, deltas[vec.name]*100)
+		}
+		c := countDeltas(checkpoint.Counts, counts)
+		fmt.Printf("\nFindings %+d, unknowns opened %+d, resolved %+d, dead ends %+d\n",
+			c["findings"], c["unknowns_open"], c["unknowns_resolved"], c["dead_ends"])
+		if len(since) > 0 {
+			fmt.Println()
+			for _, e := range since {
+				line := fmt.Sprintf("  %s  %-18s %s", time.UnixMilli(int64(e.Timestamp*1000)).Format("15:04:05"), e.Kind, truncateText(e.Text, 70))
+				if e.Detail != "" {
+					line += " — " + truncateText(e.Detail, 50)
+				}
+				fmt.Println(line)
+			}
+		}
+		return nil
+	},
+}
+
+// checkpointAutoCmd configures automatic checkpoints
+var checkpointAutoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Configure automatic checkpoints",
+	Long: `Sessions are checkpointed automatically once enough breadcrumbs have been recorded
+or enough time has passed since the last checkpoint, whichever comes first. The defaults
+are every 10 breadcrumbs or 30 minutes. Run without flags to show the current settings.
+
+Examples:
+  memory checkpoint auto --text
+  memory checkpoint auto --every 20 --minutes 60
+  memory checkpoint auto --off
+  memory checkpoint auto --on`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("off") && flags.Changed("on") {
+			return fmt.Errorf("%w: give either --on or --off", db.ErrInvalid)
+		}
+		changed := flags.Changed("every") || flags.Changed("minutes") || flags.Changed("off") || flags.Changed("on")
+		if changed {
+			if cfg.Checkpoints == nil {
+				cfg.Checkpoints = &config.CheckpointConfig{}
+			}
+			if flags.Changed("every") {
+				every, _ := flags.GetInt("every")
+				if every < 1 {
+					return fmt.Errorf("%w: --every must be at least 1", db.ErrInvalid)
+				}
+				cfg.Checkpoints.Breadcrumbs = every
+			}
+			if flags.Changed("minutes") {
+				minutes, _ := flags.GetInt("minutes")
+				if minutes < 1 {
+					return fmt.Errorf("%w: --minutes must be at least 1", db.ErrInvalid)
+				}
+				cfg.Checkpoints.Minutes = minutes
+			}
+			if flags.Changed("off") {
+				cfg.Checkpoints.Off = true
+			}
+			if flags.Changed("on") {
+				cfg.Checkpoints.Off = false
+			}
+			if *cfg.Checkpoints == (config.CheckpointConfig{}) {
+				cfg.Checkpoints = nil
+			}
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		enabled := cfg.Checkpoints == nil || !cfg.Checkpoints.Off
+		everyBreadcrumbs, everyInterval := cfg.Checkpoints.Intervals()
+		if !outputText {
+			status := "current"
+			if changed {
+				status = "saved"
+			}
+			outputResult(map[string]interface{}{
+				"status":            status,
+				"enabled":           enabled,
+				"every_breadcrumbs": everyBreadcrumbs,
+				"every_minutes":     int(everyInterval.Minutes()),
+			})
+			return nil
+		}
+		if !enabled {
+			fmt.Println("Automatic checkpoints are off")
+			return nil
+		}
+		fmt.Printf("Automatic checkpoints every %d breadcrumbs or %s\n", everyBreadcrumbs, everyInterval)
+		return nil
+	},
+}
+
+func init() {
+	checkpointAutoCmd.Flags().Int("every", 0, "Checkpoint after this many breadcrumbs")
+	checkpointAutoCmd.Flags().Int("minutes", 0, "Checkpoint after this many minutes")
+	checkpointAutoCmd.Flags().Bool("off", false, "Turn automatic checkpoints off")
+	checkpointAutoCmd.Flags().Bool("on", false, "Turn automatic checkpoints back on")
+	checkpointCmd.AddCommand(checkpointAutoCmd)
+	checkpointCmd.AddCommand(checkpointDiffCmd)
+	checkpointCmd.AddCommand(checkpointListCmd)
+	rootCmd.AddCommand(checkpointCmd)
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+var noColor bool // --no-color; the NO_COLOR environment variable does the same
+
+// daemonTerminal is set while the daemon runs a command whose client writes to a terminal
+var daemonTerminal bool
+
+// ANSI colors used in --text output
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+)
+
+// colorEnabled reports whether --text output goes to a terminal that wants color.
+// JSON output is never colored.
+func colorEnabled() bool {
+	if !outputText || noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if daemonTerminal {
+		return true
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// paint wraps s in an ANSI color when color is enabled
+func paint(color, s string) string {
+	if !colorEnabled() {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// stalenessIcon is the colored icon for a finding's staleness: a green ✓ for fresh, a
+// yellow ○ for aging and a red ⚠ for stale
+func stalenessIcon(status models.StalenessStatus) string {
+	switch status {
+	case models.StatusAging:
+		return paint(ansiYellow, "○")
+	case models.StatusStale:
+		return paint(ansiRed, "⚠")
+	}
+	return paint(ansiGreen, "✓")
+}
+
+// paintAction colors a decision action: green to proceed, yellow to look closer first,
+// red to stop
+func paintAction(action string) string {
+	color := ansiYellow
+	switch action {
+	case "proceed":
+		color = ansiGreen
+	case "stop", "reset":
+		color = ansiRed
+	}
+	return paint(ansiBold+color, action)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors in --text output (also set by NO_COLOR)")
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// commitLinkCmd links a finding to the commit that produced or validated it
+var commitLinkCmd = &cobra.Command{
+	Use:   "commit-link [finding-id] [sha]",
+	Short: "Link a finding to a git commit",
+	Long: `Record that a git commit produced or validated a finding.
+
+Linked commits are shown by 'memory query' so you can trace knowledge back
+to the code changes it came from.
+
+Examples:
+  memory commit-link 3f2a9c1e-... HEAD
+  memory commit-link 3f2a9c1e-... a1b2c3d --relation produced`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		findingID := args[0]
+		relation, _ := cmd.Flags().GetString("relation")
+
+		switch models.CommitRelation(relation) {
+		case models.CommitProduced, models.CommitValidated:
+		default:
+			return fmt.Errorf("invalid relation %q (use produced or validated)", relation)
+		}
+
+		repo := stores.Breadcrumbs
+		finding, err := repo.GetFinding(ctx, findingID)
+		if err != nil {
+			return fmt.Errorf("failed to get finding: %w", err)
+		}
+
+		sha, err := resolveCommit(ctx, args[1])
+		if err != nil {
+			return err
+		}
+
+		link := models.NewCommitLink(finding.ID, sha, models.CommitRelation(relation))
+		if err := stores.CommitLinks.Create(ctx, link); err != nil {
+			return fmt.Errorf("failed to link commit: %w", err)
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"status":     "linked",
+				"finding_id": finding.ID,
+				"finding":    finding.Finding,
+				"commit":     sha,
+				"relation":   relation,
+			})
+		} else {
+			fmt.Printf("✓ Linked %s (%s) to: %s\n", shortSHA(sha), relation, finding.Finding)
+		}
+		return nil
+	},
+}
+
+// findingCommits loads linked commits for a set of findings, keyed by finding ID
+func findingCommits(ctx context.Context, findings []*models.Finding) map[string][]*models.CommitLink {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	links, err := stores.CommitLinks.ListByFindings(ctx, ids)
+	if err != nil {
+		return map[string][]*models.CommitLink{}
+	}
+	return links
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+func init() {
+	commitLinkCmd.Flags().String("relation", string(models.CommitValidated), "How the commit relates to the finding: produced or validated")
+	rootCmd.AddCommand(commitLinkCmd)
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// Default cosine similarity above which findings are clustered: model embeddings
+// place paraphrases close together, word counts only when they share most words
+const (
+	compactThreshold     = 0.85
+	compactTermThreshold = 0.5
+)
+
+// compactMaxCluster caps how many findings one synthesis call consolidates
+const compactMaxCluster = 12
+
+// compactScanLimit caps how many findings a compaction considers
+const compactScanLimit = 2000
+
+// compactSubject is the subject of the session that records consolidated findings
+const compactSubject = "Knowledge compaction"
+
+// CompactCluster is a group of related findings and what they were consolidated into
+type CompactCluster struct {
+	Scope        string            `json:"scope,omitempty"`
+	Originals    []*models.Finding `json:"originals"`
+	Consolidated []*models.Finding `json:"consolidated"`
+}
+
+// compactPrompt tells the model how to consolidate a cluster
+const compactPrompt = `You maintain a knowledge base of findings about a codebase, logged by AI agents
+over many sessions. You are given a group of related findings. Rewrite them as fewer
+self-contained findings that keep every distinct fact, version, number, name and
+caveat. Merge duplicates, drop repetition, and where findings contradict each other
+keep the later one (they are listed oldest first). Don't add anything not stated.
+Reply with only a JSON object: {"findings": ["...", "..."]}`
+
+// clusterFindings groups findings whose vectors are at least threshold similar. Each
+// cluster is seeded by the highest-impact finding left and takes every remaining one
+// close to the seed, so a cluster can't drift from its topic through a chain of
+// neighbours. Findings left alone are dropped.
+func clusterFindings(findings []*models.Finding, vectors [][]float64, threshold float64) [][]*models.Finding {
+	order := make([]int, len(findings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return findings[order[a]].Impact > findings[order[b]].Impact
+	})
+
+	taken := make([]bool, len(findings))
+	var clusters [][]*models.Finding
+	for _, seed := range order {
+		if taken[seed] {
+			continue
+		}
+		taken[seed] = true
+		cluster := []*models.Finding{findings[seed]}
+		for _, i := range order {
+			if len(cluster) == compactMaxCluster {
+				break
+			}
+			if !taken[i] && cosineSimilarity(vectors[seed], vectors[i]) >= threshold {
+				taken[i] = true
+				cluster = append(cluster, findings[i])
+			}
+		}
+		if len(cluster) > 1 {
+			sort.Slice(cluster, func(a, b int) bool {
+				return cluster[a].CreatedTimestamp < cluster[b].CreatedTimestamp
+			})
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters
+}
+
+// synthesizeFindings asks the model to consolidate a cluster into fewer findings
+func synthesizeFindings(ctx context.Context, llm *LLMConfig, cluster []*models.Finding) ([]string, error) {
+	var prompt strings.Builder
+	for _, f := range cluster {
+		fmt.Fprintf(&prompt, "- [%s] %s\n", time.Unix(int64(f.CreatedTimestamp), 0).Format("2006-01-02"), f.Finding)
+	}
+	reply, err := llm.complete(ctx, compactPrompt, prompt.String())
+	if err != nil {
+		return nil, err
+	}
+	reply = strings.TrimSpace(reply)
+	if start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}"); start >= 0 && end > start {
+		reply = reply[start : end+1]
+	}
+	var out struct {
+		Findings []string `json:"findings"`
+	}
+	if err := json.Unmarshal([]byte(reply), &out); err != nil {
+		return nil, fmt.Errorf("%s returned unreadable findings: %w", llm.Provider, err)
+	}
+	texts := make([]string, 0, len(out.Findings))
+	for _, text := range out.Findings {
+		if text = strings.TrimSpace(text); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts, nil
+}
+
+// consolidatedFinding builds a finding replacing a cluster. It takes the highest impact
+// and every tag, and stays as stale as the least recently verified original with its
+// file hash, so consolidating doesn't pass old knowledge off as fresh.
+func consolidatedFinding(projectID, sessionID, text string, cluster []*models.Finding) *models.Finding {
+	finding := models.NewFinding(projectID, sessionID, text, 0)
+	finding.Subject = cluster[0].Subject
+	verified := math.Inf(1)
+	for _, f := range cluster {
+		finding.Impact = math.Max(finding.Impact, f.Impact)
+		for _, tag := range f.Tags {
+			if !slices.Contains(finding.Tags, tag) {
+				finding.Tags = append(finding.Tags, tag)
+			}
+		}
+		at := f.CreatedTimestamp
+		if f.LastVerifiedTimestamp != nil {
+			at = *f.LastVerifiedTimestamp
+		}
+		if at < verified {
+			verified = at
+			finding.SubjectGitHash = f.SubjectGitHash
+			finding.SubjectKind = f.SubjectKind // The hash was taken as its kind takes it
+		}
+		finding.Relations = append(finding.Relations, models.BreadcrumbRelation{TargetID: f.ID, Kind: models.RelationSupersedes})
+	}
+	sort.Strings(finding.Tags)
+	sort.Slice(finding.Relations, func(i, j int) bool {
+		return finding.Relations[i].TargetID < finding.Relations[j].TargetID
+	})
+	finding.LastVerifiedTimestamp = &verified
+	return finding
+}
+
+// compactCmd consolidates clusters of related old findings with the configured LLM
+var compactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Consolidate related old findings into fewer ones with an LLM",
+	Long: `Cluster related findings older than --older-than by embedding similarity and have
+the configured LLM rewrite each cluster as fewer consolidated findings, so years of
+sessions don't bloat the start context. Only findings with the same scope are
+clustered together, and the consolidated ones keep that scope.
+
+Each consolidated finding supersedes its originals, which are archived: deleted with
+a reason naming it, hidden from every command, and restorable with
+'memory forget <id> --restore'. A consolidated finding is only as fresh as the least
+recently verified of its originals. The new findings are logged to a "Knowledge
+compaction" session.
+
+The LLM is configured as for 'memory done --summarize'. Embeddings come from the
+provider (MEMORY_LLM_EMBED_MODEL, by default text-embedding-3-small for OpenAI and
+nomic-embed-text for Ollama); with Anthropic, which has no embeddings API, findings are
+compared by their words instead and the default --threshold is 0.5 rather than 0.85.
+
+Use --dry-run to see the proposed consolidation without changing anything.
+
+Examples:
+  memory compact --dry-run --text
+  memory compact --older-than 180d --threshold 0.9`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		window, err := parseWindow(olderThan)
+		if err != nil {
+			return err
+		}
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("%w threshold %v (use a similarity between 0 and 1)", db.ErrInvalid, threshold)
+		}
+		llm, err := loadLLMConfig()
+		if err != nil {
+			return err
+		}
+		if llm.EmbedModel == "" && !cmd.Flags().Changed("threshold") {
+			threshold = compactTermThreshold
+		}
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		findings, err := stores.Breadcrumbs.ListFindings(ctx, project.ID, "", compactScanLimit)
+		if err != nil {
+			return fmt.Errorf("failed to list findings: %w", err)
+		}
+		cutoff := float64(time.Now().Add(-window).Unix())
+		byScope := map[string][]*models.Finding{}
+		var scopes []string
+		for _, f := range findings {
+			if f.CreatedTimestamp > cutoff {
+				continue
+			}
+			scope := ""
+			if f.Subject != nil {
+				scope = *f.Subject
+			}
+			if _, ok := byScope[scope]; !ok {
+				scopes = append(scopes, scope)
+			}
+			byScope[scope] = append(byScope[scope], f)
+		}
+		sort.Strings(scopes)
+
+		var clusters []*CompactCluster
+		for _, scope := range scopes {
+			group := byScope[scope]
+			if len(group) < 2 {
+				continue
+			}
+			texts := make([]string, len(group))
+			for i, f := range group {
+				texts[i] = f.Finding
+			}
+			vectors, err := llm.embed(ctx, texts)
+			if err != nil {
+				return fmt.Errorf("failed to embed findings: %w", err)
+			}
+			for _, cluster := range clusterFindings(group, vectors, threshold) {
+				clusters = append(clusters, &CompactCluster{Scope: scope, Originals: cluster})
+			}
+		}
+
+		// Synthesize everything before writing, so a failing LLM leaves nothing half done
+		var session *models.Session
+		sessionID := ""
+		if !dryRun {
+			subject := compactSubject
+			session = models.NewSession(currentActor(ctx))
+			session.ProjectID = &project.ID
+			session.Subject = &subject
+			sessionID = session.SessionID
+		}
+		compacted := clusters[:0]
+		for _, c := range clusters {
+			texts, err := synthesizeFindings(ctx, llm, c.Originals)
+			if err != nil {
+				return fmt.Errorf("failed to consolidate findings: %w", err)
+			}
+			if len(texts) == 0 || len(texts) >= len(c.Originals) {
+				continue // Nothing gained
+			}
+			for _, text := range texts {
+				c.Consolidated = append(c.Consolidated, consolidatedFinding(project.ID, sessionID, text, c.Originals))
+			}
+			compacted = append(compacted, c)
+		}
+		clusters = compacted
+
+		archived, created := 0, 0
+		for _, c := range clusters {
+			archived += len(c.Originals)
+			created += len(c.Consolidated)
+		}
+		if !dryRun && len(clusters) > 0 {
+			err = stores.InTx(ctx, func(tx *db.Stores) error {
+				if err := tx.Sessions.Create(ctx, session); err != nil {
+					return fmt.Errorf("failed to create compaction session: %w", err)
+				}
+				for _, c := range clusters {
+					ids := make([]string, 0, len(c.Consolidated))
+					for _, f := range c.Consolidated {
+						if err := tx.Breadcrumbs.CreateFinding(ctx, f); err != nil {
+							return fmt.Errorf("failed to log consolidated finding: %w", err)
+						}
+						ids = append(ids, f.ID)
+					}
+					reason := "Compacted into " + strings.Join(ids, ", ")
+					for _, f := range c.Originals {
+						if err := tx.Breadcrumbs.DeleteBreadcrumb(ctx, models.EntityFinding, f.ID, reason); err != nil {
+							return fmt.Errorf("failed to archive finding %s: %w", f.ID, err)
+						}
+					}
+				}
+				return tx.Sessions.End(ctx, session.SessionID)
+			})
+			if err != nil {
+				return err
+			}
+			tidyEvidenceBlobs(ctx)
+		} else {
+			sessionID = "" // Nothing written
+		}
+
+		status := "compacted"
+		if dryRun {
+			status = "dry_run"
+		}
+		if !outputText {
+			if clusters == nil {
+				clusters = []*CompactCluster{}
+			}
+			outputResult(map[string]interface{}{
+				"status":     status,
+				"project":    project.Name,
+				"session_id": sessionID, // Empty when nothing was written
+				"clusters":   clusters,
+				"archived":   archived,
+				"created":    created,
+			})
+			return nil
+		}
+		if len(clusters) == 0 {
+			fmt.Println("No related findings to compact")
+			return nil
+		}
+		for _, c := range clusters {
+			if c.Scope != "" {
+				fmt.Printf("%s\n", c.Scope)
+			} else {
+				fmt.Println("(no scope)")
+			}
+			for _, f := range c.Originals {
+				fmt.Printf("  - %s\n", f.Finding)
+			}
+			for _, f := range c.Consolidated {
+				fmt.Printf("  + %s\n", f.Finding)
+			}
+			fmt.Println()
+		}
+		if dryRun {
+			fmt.Printf("Dry run: would consolidate %d findings into %d\n", archived, created)
+		} else {
+			fmt.Printf("✓ Consolidated %d findings into %d (originals archived; restore with 'memory forget <id> --restore')\n", archived, created)
+		}
+		return nil
+	},
+}
+
+func init() {
+	compactCmd.Flags().String("older-than", "90d", "Only compact findings logged longer ago than this, e.g. 90d or 26w")
+	compactCmd.Flags().Float64("threshold", compactThreshold, "Cosine similarity above which findings are clustered")
+	compactCmd.Flags().Bool("dry-run", false, "Show the proposed consolidation without changing anything")
+	rootCmd.AddCommand(compactCmd)
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// contextCmd prints the context an agent should start from
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Print the knowledge an agent should start from",
+	Long: `Print the same context start returns without starting a session: decision guidance,
+findings to verify, dead ends, knowledge and open questions. Uses the active session
+when there is one, otherwise the current directory's project. Nothing is recorded, so
+it is safe to call from agent hooks on every session start.
+
+Examples:
+  memory context
+  memory context --text   # From a SessionStart hook`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var sessionCtx *models.SessionContext
+		title := ""
+		if active, err := loadActiveSession(ctx); err == nil {
+			var inheritFrom []string
+			if active.InheritParent {
+				if project, _ := stores.Projects.Get(ctx, active.ProjectID); project != nil {
+					inheritFrom = projectAncestorIDs(ctx, project)
+				}
+			}
+			inheritFrom = withGlobalKnowledge(ctx, active.ProjectID, inheritFrom)
+			sessionCtx = buildSessionContext(ctx, active.SessionID, active.ProjectID, active.Objective, active.AIID, active.StartedAt, inheritFrom, currentGoal(ctx, active), active.contextAgents())
+			title = "Session: " + active.Objective
+		} else {
+			project, err := getOrCreateDefaultProject(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			sessionCtx = buildSessionContext(ctx, "", project.ID, "", "", time.Now(), withGlobalKnowledge(ctx, project.ID, nil), nil, nil)
+			title = "Project: " + project.Name
+		}
+
+		if !outputText {
+			outputResult(sessionCtx)
+			return nil
+		}
+		fmt.Printf("Memory context — %s\n", title)
+		fmt.Println(strings.Repeat("─", 50))
+		if d := sessionCtx.Decision; d != nil {
+			fmt.Printf("\n%s (%.0f%% confidence): %s\n", strings.ToUpper(d.Action), d.Confidence*100, d.Reason)
+			for _, p := range d.Prerequisites {
+				fmt.Printf("  → %s\n", p)
+			}
+		}
+		printContextSections(sessionCtx)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+}
@@ -0,0 +1,397 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// daemonSocketName is the daemon's socket, in daemonSocketDir next to the database it serves
+const daemonSocketName = "daemon.sock"
+
+// daemonSocketDir holds the daemon's socket. Only its owner may enter it, so nobody else
+// can connect in the moment between the socket being created and restricted.
+const daemonSocketDir = "daemon"
+
+// daemonDialTimeout bounds how long the CLI waits for a daemon before running the command itself
+const daemonDialTimeout = 200 * time.Millisecond
+
+// noDaemonEnvVar makes the CLI run every command itself even when a daemon is up
+const noDaemonEnvVar = "MEMORY_NO_DAEMON"
+
+// inDaemon is set while the daemon runs commands, which read the client's stdin
+var inDaemon bool
+
+// localCommands always run in the CLI process, with their subcommands: servers,
+// interactive loops, commands that don't touch the database and backup, whose restore
+// replaces the database file under the daemon's open handle
+var localCommands = map[string]bool{
+	"daemon": true, "repl": true, "serve": true, "help": true, "version": true,
+	"mergetool": true, "completion": true, "backup": true,
+}
+
+// isLocalCommand reports whether cmd or a command it belongs to is in localCommands
+func isLocalCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil && cmd != rootCmd; cmd = cmd.Parent() {
+		if localCommands[cmd.Name()] {
+			return true
+		}
+	}
+	return false
+}
+
+// daemonRequest is one command a client forwards. The client's stdin follows the
+// request line on the connection until the client closes its side.
+type daemonRequest struct {
+	Args     []string `json:"args"`
+	Dir      string   `json:"dir"`
+	Env      []string `json:"env"`
+	Terminal bool     `json:"terminal"` // The client's stdout is a terminal
+}
+
+// daemonResponse is what the forwarded command wrote and how it exited
+type daemonResponse struct {
+	Stdout []byte `json:"stdout"`
+	Stderr []byte `json:"stderr"`
+	Exit   int    `json:"exit"`
+}
+
+// daemonExit is the exit status of a command the daemon ran; its output is already printed
+type daemonExit int
+
+func (e daemonExit) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// daemonSocketPath returns the socket of the daemon serving the current directory's database
+func daemonSocketPath(ctx context.Context) string {
+	return filepath.Join(filepath.Dir(databasePath(ctx)), daemonSocketDir, daemonSocketName)
+}
+
+// forwardToDaemon runs the command line through the daemon serving this directory's
+// database, if one is listening. Reports the command's exit status and whether it was
+// forwarded; when it wasn't, the caller runs the command itself.
+func forwardToDaemon() (int, bool) {
+	args := os.Args[1:]
+	if len(args) == 0 || os.Getenv(noDaemonEnvVar) != "" {
+		return 0, false
+	}
+	if cmd, _, err := rootCmd.Find(args); err != nil || cmd == rootCmd || isLocalCommand(cmd) {
+		return 0, false
+	}
+
+	socket := daemonSocketPath(context.Background())
+	conn, err := net.DialTimeout("unix", socket, daemonDialTimeout)
+	if err != nil {
+		return 0, false
+	}
+	defer conn.Close()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return 0, false
+	}
+	info, err := os.Stdout.Stat()
+	req := daemonRequest{
+		Args:     args,
+		Dir:      dir,
+		Env:      os.Environ(),
+		Terminal: err == nil && info.Mode()&os.ModeCharDevice != 0,
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return 0, false
+	}
+
+	// Stream stdin for commands that read it; a terminal is left alone, since the daemon
+	// can't prompt through it
+	go func() {
+		if !stdinIsTerminal() {
+			io.Copy(conn, os.Stdin)
+		}
+		conn.(*net.UnixConn).CloseWrite()
+	}()
+
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		// The command may have run, so running it again here could log it twice
+		outputError(fmt.Errorf("lost the daemon at %s: %w", socket, err))
+		return ExitError, true
+	}
+	os.Stdout.Write(resp.Stdout)
+	os.Stderr.Write(resp.Stderr)
+	return resp.Exit, true
+}
+
+// daemonServer runs forwarded commands one at a time against the open database
+type daemonServer struct {
+	mu sync.Mutex // Commands swap the process's working directory, environment and stdio
+}
+
+// every runs task now and at every interval until ctx is done, between forwarded
+// commands
+func (s *daemonServer) every(ctx context.Context, interval time.Duration, task func(context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		task(ctx)
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneDue applies the retention periods
+func pruneDue(ctx context.Context) {
+	expired, err := enforceRetention(ctx, false)
+	if err != nil {
+		slog.Warn("retention failed", "err", err)
+	} else if len(expired) > 0 {
+		slog.Info("pruned breadcrumbs past retention", "count", len(expired))
+	}
+}
+
+// digestDue posts the scheduled digest when one is due
+func digestDue(ctx context.Context) {
+	digest, err := postScheduledDigest(ctx, time.Now())
+	if err != nil {
+		slog.Warn("scheduled digest failed", "err", err)
+	} else if digest != nil {
+		slog.Info("posted scheduled digest", "sessions", len(digest.Sessions), "newly_stale", len(digest.NewlyStale))
+	}
+}
+
+// backupDue uploads the scheduled backup when one is due
+func backupDue(ctx context.Context) {
+	target, err := uploadScheduledBackup(ctx, time.Now())
+	if err != nil {
+		slog.Warn("scheduled backup failed", "err", err)
+	} else if target != "" {
+		slog.Info("uploaded scheduled backup", "target", target)
+	}
+}
+
+// handle runs the command a client forwards and replies with its output
+func (s *daemonServer) handle(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var req daemonRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		slog.Warn("daemon: malformed request", "err", err)
+		return
+	}
+
+	s.mu.Lock()
+	resp := s.run(ctx, req, r)
+	s.mu.Unlock()
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// run executes a request in the client's directory and environment, with stdin read
+// from the connection and stdout and stderr captured
+func (s *daemonServer) run(ctx context.Context, req daemonRequest, stdin io.Reader) daemonResponse {
+	env := os.Environ()
+	wd, _ := os.Getwd()
+	defer func() {
+		os.Clearenv()
+		for _, kv := range env {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				os.Setenv(k, v)
+			}
+		}
+		os.Chdir(wd)
+	}()
+	os.Clearenv()
+	for _, kv := range req.Env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			os.Setenv(k, v)
+		}
+	}
+	if err := os.Chdir(req.Dir); err != nil {
+		var stderr bytes.Buffer
+		fmt.Fprintf(&stderr, "Error: %v\n", err)
+		return daemonResponse{Stderr: stderr.Bytes(), Exit: ExitError}
+	}
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		return daemonResponse{Stderr: []byte(err.Error() + "\n"), Exit: ExitError}
+	}
+	defer inR.Close()
+	go func() {
+		io.Copy(inW, stdin)
+		inW.Close()
+	}()
+	var stdout, stderr bytes.Buffer
+	outW, outDone, err := capture(&stdout)
+	if err != nil {
+		return daemonResponse{Stderr: []byte(err.Error() + "\n"), Exit: ExitError}
+	}
+	errW, errDone, err := capture(&stderr)
+	if err != nil {
+		outW.Close()
+		<-outDone
+		return daemonResponse{Stderr: []byte(err.Error() + "\n"), Exit: ExitError}
+	}
+
+	origIn, origOut, origErr := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = inR, outW, errW
+	daemonTerminal = req.Terminal
+	database.SetActor(currentActor(ctx))
+	err = runInProcess(ctx, req.Args, false)
+	if err != nil {
+		outputError(err)
+		slog.Info("command failed", "args", req.Args, "err", err)
+	}
+	daemonTerminal = false
+	os.Stdin, os.Stdout, os.Stderr = origIn, origOut, origErr
+
+	outW.Close()
+	errW.Close()
+	<-outDone
+	<-errDone
+	return daemonResponse{Stdout: stdout.Bytes(), Stderr: stderr.Bytes(), Exit: ExitCode(err)}
+}
+
+// capture returns a file whose writes collect into buf until it is closed and done fires
+func capture(buf *bytes.Buffer) (*os.File, <-chan struct{}, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		io.Copy(buf, r)
+		r.Close()
+		close(done)
+	}()
+	return w, done, nil
+}
+
+// daemonCmd holds the database open and runs commands forwarded by the CLI
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep the database open and serve CLI calls over a local socket",
+	Long: `Hold the database open and listen on a unix socket next to it (daemon/daemon.sock in
+the .memory directory, which only its owner can enter). While it runs, every memory command using that database is
+forwarded to it transparently, saving the process start work of opening the
+database and checking migrations on each call. Useful for agents issuing many
+calls per minute.
+
+Commands run one at a time in the caller's directory and environment, with its
+stdin, and print and exit exactly as they would have. If no daemon answers, the CLI
+runs the command itself; set MEMORY_NO_DAEMON=1 to always do so. The repl, serve,
+daemon and backup commands are never forwarded: backup restore replaces the database
+file the daemon holds open.
+
+With --metrics-addr it serves Prometheus metrics for the commands it runs on
+/metrics: breadcrumbs logged, sessions started and completed, verifications, query
+latency and context build time.
+
+It applies the retention periods set with 'memory prune policy' at start and every
+hour. It posts the Slack digest scheduled with 'memory notify schedule' and uploads
+the backups scheduled with 'memory backup schedule' when they're due.
+
+Stop it with Ctrl-C or SIGTERM; the socket is removed on exit.
+
+Examples:
+  memory daemon &
+  memory daemon -v --log-file .memory/daemon.log
+  memory daemon --metrics-addr 127.0.0.1:9464`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		dbPath := database.Path()
+		socketDir := filepath.Join(filepath.Dir(dbPath), daemonSocketDir)
+		if err := os.MkdirAll(socketDir, 0700); err != nil {
+			return fmt.Errorf("failed to create %s: %w", socketDir, err)
+		}
+		// MkdirAll leaves an existing directory's mode as it was
+		if err := os.Chmod(socketDir, 0700); err != nil {
+			return fmt.Errorf("failed to restrict %s: %w", socketDir, err)
+		}
+		socket := filepath.Join(socketDir, daemonSocketName)
+		if conn, err := net.DialTimeout("unix", socket, daemonDialTimeout); err == nil {
+			conn.Close()
+			return fmt.Errorf("%w: a daemon is already serving %s", db.ErrConflict, dbPath)
+		}
+		// Left behind by a daemon that didn't exit cleanly
+		os.Remove(socket)
+
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", socket, err)
+		}
+		defer os.Remove(socket)
+		if err := os.Chmod(socket, 0600); err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to restrict %s: %w", socket, err)
+		}
+		go func() {
+			<-ctx.Done()
+			listener.Close()
+		}()
+		if metricsAddr != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler())
+			metricsListener, err := net.Listen("tcp", metricsAddr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", metricsAddr, err)
+			}
+			defer metricsListener.Close()
+			fmt.Fprintf(os.Stderr, "Serving metrics on http://%s/metrics\n", metricsAddr)
+			go http.Serve(metricsListener, mux)
+		}
+
+		inRepl, inDaemon = true, true
+		defer func() { inRepl, inDaemon = false, false }()
+
+		fmt.Fprintf(os.Stderr, "Serving %s on %s\n", dbPath, socket)
+		server := &daemonServer{}
+		go server.every(ctx, retentionInterval, pruneDue)
+		go server.every(ctx, digestCheckInterval, digestDue)
+		go server.every(ctx, backupCheckInterval, backupDue)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to accept: %w", err)
+			}
+			go server.handle(ctx, conn)
+		}
+	},
+}
+
+func init() {
+	daemonCmd.Flags().String("metrics-addr", "", "Serve Prometheus metrics on this address")
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd groups database maintenance commands
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database maintenance",
+}
+
+// dbRebuildCmd replays the breadcrumb event stream into the read tables
+var dbRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild findings, unknowns and dead ends from the event stream",
+	Long: `Findings, unknowns and dead ends are stored as an append-only event stream
+(created, verified, file changed, resolved, ...). The tables queried by every other
+command are projections of that stream. Rebuild discards them and replays the stream,
+repairing any drift.
+
+Example:
+  memory db rebuild`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		replayed, err := database.RebuildBreadcrumbs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to rebuild: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Replayed %d events\n", replayed)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":   "rebuilt",
+				"replayed": replayed,
+			})
+		}
+		return nil
+	},
+}
+
+// dbMergeCmd merges another database's sessions and breadcrumbs into this one
+var dbMergeCmd = &cobra.Command{
+	Use:   "merge [other.db]",
+	Short: "Merge sessions, findings, unknowns and dead ends from another database",
+	Long: `Merge the sessions and breadcrumb event stream of another memory database, for
+example one edited offline on another machine, or a project-local database being
+consolidated with the home directory one. Events are ordered by per-device Lamport
+clocks, so merging is deterministic: merging A into B and B into A gives the same
+breadcrumbs, and merging twice changes nothing. Verifications, tags and relations are
+combined; for other fields the latest edit wins. Sessions are matched by ID.
+
+Projects referenced by merged breadcrumbs are copied over when missing. A project with
+the same name as a local one is mapped onto it instead: its sessions are filed under
+the local project and its breadcrumbs moved there. --map-project other=local maps
+projects whose names differ. The other database is upgraded to the current schema
+before merging.
+
+With --dry-run the number of new events, sessions and projects is reported and neither
+database is modified; the other database must already be on the current schema.
+
+Examples:
+  memory db merge /mnt/laptop/.memory/sessions.db
+  memory db merge ./.memory/sessions.db --map-project api=auth-service`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		path := args[0]
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if self, err := os.Stat(database.Path()); err == nil && os.SameFile(info, self) {
+			return fmt.Errorf("%s is the current database", path)
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		mappings, _ := cmd.Flags().GetStringArray("map-project")
+		projectMap := make(map[string]string, len(mappings))
+		for _, m := range mappings {
+			from, to, ok := strings.Cut(m, "=")
+			if !ok || from == "" || to == "" {
+				return fmt.Errorf("%w --map-project %q (use other=local)", db.ErrInvalid, m)
+			}
+			projectMap[from] = to
+		}
+
+		blobs := 0
+		if !dryRun {
+			// Bring the other database up to the current schema
+			other, err := db.Open(ctx, path)
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", path, err)
+			}
+			// Evidence blobs it carries, or keeps in its own blob store, come along
+			blobs, err = unpackEvidenceBlobs(ctx, other)
+			other.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err := database.MergeBreadcrumbs(ctx, path, projectMap, dryRun)
+		if err != nil {
+			return fmt.Errorf("failed to merge: %w", err)
+		}
+
+		status := "merged"
+		if dryRun {
+			status = "dry_run"
+		}
+		if outputText {
+			counts := fmt.Sprintf("%d events, %d sessions and %d projects", result.Events, result.Sessions, result.Projects)
+			if dryRun {
+				fmt.Printf("Dry run: would merge %s from %s\n", counts, path)
+			} else {
+				fmt.Printf("✓ Merged %s from %s\n", counts, path)
+			}
+			if result.Remapped > 0 {
+				fmt.Printf("  %d breadcrumbs filed under mapped local projects\n", result.Remapped)
+			}
+			if result.Skipped > 0 {
+				fmt.Printf("  %d events of archived breadcrumbs skipped\n", result.Skipped)
+			}
+			if blobs > 0 {
+				fmt.Printf("  %d evidence blobs copied\n", blobs)
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status":   status,
+				"events":   result.Events,
+				"sessions": result.Sessions,
+				"projects": result.Projects,
+				"remapped": result.Remapped,
+				"skipped":  result.Skipped,
+				"blobs":    blobs,
+			})
+		}
+		return nil
+	},
+}
+
+// dbGCCmd removes evidence blobs nothing refers to any more
+var dbGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove evidence blobs no finding refers to",
+	Long: `Remove the blobs in .memory/blobs that no finding's evidence refers to: evidence
+replaced by a newer verification, and the evidence of findings deleted more than 30
+days ago. Blobs written in the last hour are kept, as their finding may still be
+being recorded. 'memory forget', 'prune', 'compact' and 'archive' collect blobs too.
+
+Examples:
+  memory db gc --dry-run --text
+  memory db gc`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		removed, freed, err := collectEvidenceBlobs(ctx, dryRun)
+		if err != nil {
+			return err
+		}
+
+		if outputText {
+			if dryRun {
+				fmt.Printf("Dry run: would remove %d blobs (%d bytes)\n", removed, freed)
+			} else {
+				fmt.Printf("✓ Removed %d blobs (%d bytes)\n", removed, freed)
+			}
+			return nil
+		}
+		status := "collected"
+		if dryRun {
+			status = "dry_run"
+		}
+		outputResult(map[string]interface{}{
+			"status":  status,
+			"removed": removed,
+			"bytes":   freed,
+		})
+		return nil
+	},
+}
+
+func init() {
+	dbMergeCmd.Flags().Bool("dry-run", false, "Report what would be merged without writing")
+	dbMergeCmd.Flags().StringArray("map-project", nil, "Merge the other database's project into a local one: other=local (name or ID, repeatable)")
+	dbCmd.AddCommand(dbRebuildCmd)
+	dbCmd.AddCommand(dbMergeCmd)
+	dbGCCmd.Flags().Bool("dry-run", false, "Count the blobs that would be removed without removing them")
+	dbCmd.AddCommand(dbGCCmd)
+	rootCmd.AddCommand(dbCmd)
+}
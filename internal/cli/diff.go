@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// KnowledgeDiff lists how project knowledge changed within a window
+type KnowledgeDiff struct {
+	Since               time.Time               `json:"since"`
+	Until               time.Time               `json:"until"`
+	FindingsAdded       []*models.TimelineEntry `json:"findings_added"`
+	FindingsInvalidated []*models.TimelineEntry `json:"findings_invalidated"`
+	UnknownsOpened      []*models.TimelineEntry `json:"unknowns_opened"`
+	UnknownsResolved    []*models.TimelineEntry `json:"unknowns_resolved"`
+	DeadEnds            []*models.TimelineEntry `json:"dead_ends"`
+}
+
+// parseTimePoint parses a point in time: a date, an RFC 3339 timestamp, or a window
+// such as 7d counted back from now
+func parseTimePoint(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	window, err := parseWindow(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w time %q (use a date such as 2024-06-01, an RFC 3339 timestamp or a window such as 7d)", db.ErrInvalid, s)
+	}
+	return time.Now().Add(-window), nil
+}
+
+// diffCmd shows how project knowledge changed between two points in time
+var diffCmd = &cobra.Command{
+	Use:   "diff [from-ref [to-ref]]",
+	Short: "Show how project knowledge changed over a window",
+	Long: `Show the findings added and invalidated, unknowns opened and resolved and dead ends
+recorded between two points in time, so reviewers can see how understanding of the
+project evolved.
+
+The window is given either with --since and --until, or as two git refs whose commit
+times bound it. A single ref runs from that commit until now.
+
+Examples:
+  memory diff --since 2024-06-01 --text
+  memory diff --since 2w --until 1w
+  memory diff v1.2.0 v1.3.0 --text
+  memory diff main~20`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		untilFlag, _ := cmd.Flags().GetString("until")
+
+		until := time.Now()
+		var since time.Time
+		var err error
+		switch {
+		case len(args) > 0 && (sinceFlag != "" || untilFlag != ""):
+			return fmt.Errorf("%w: give either git refs or --since/--until, not both", db.ErrInvalid)
+		case len(args) > 0:
+			if since, err = commitTime(ctx, args[0]); err != nil {
+				return err
+			}
+			if len(args) == 2 {
+				if until, err = commitTime(ctx, args[1]); err != nil {
+					return err
+				}
+			}
+		case sinceFlag == "":
+			return fmt.Errorf("%w: give --since or a git ref to start from", db.ErrInvalid)
+		default:
+			if since, err = parseTimePoint(sinceFlag); err != nil {
+				return err
+			}
+			if untilFlag != "" {
+				if until, err = parseTimePoint(untilFlag); err != nil {
+					return err
+				}
+			}
+		}
+		if !until.After(since) {
+			return fmt.Errorf("%w: the window ends before it starts", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		entries, err := stores.Timeline.List(ctx, db.TimelineFilter{
+			ProjectID: project.ID,
+			Since:     float64(since.UnixMilli()) / 1000.0,
+			Until:     float64(until.UnixMilli()) / 1000.0,
+		}, 0)
+		if err != nil {
+			return err
+		}
+
+		diff := &KnowledgeDiff{
+			Since:               since,
+			Until:               until,
+			FindingsAdded:       []*models.TimelineEntry{},
+			FindingsInvalidated: []*models.TimelineEntry{},
+			UnknownsOpened:      []*models.TimelineEntry{},
+			UnknownsResolved:    []*models.TimelineEntry{},
+			DeadEnds:            []*models.TimelineEntry{},
+		}
+		for _, e := range entries {
+			switch models.BreadcrumbEventKind(e.Kind) {
+			case models.EventFindingCreated:
+				diff.FindingsAdded = append(diff.FindingsAdded, e)
+			case models.EventFindingDeleted:
+				diff.FindingsInvalidated = append(diff.FindingsInvalidated, e)
+			case models.EventUnknownCreated:
+				diff.UnknownsOpened = append(diff.UnknownsOpened, e)
+			case models.EventUnknownResolved:
+				diff.UnknownsResolved = append(diff.UnknownsResolved, e)
+			case models.EventDeadEndCreated:
+				diff.DeadEnds = append(diff.DeadEnds, e)
+			}
+		}
+
+		if !outputText {
+			outputResult(diff)
+			return nil
+		}
+
+		fmt.Printf("Project %s: %s to %s\n", project.Name, since.Format("2006-01-02 15:04"), until.Format("2006-01-02 15:04"))
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Printf("%d findings added, %d invalidated; %d unknowns opened, %d resolved; %d dead ends\n",
+			len(diff.FindingsAdded), len(diff.FindingsInvalidated), len(diff.UnknownsOpened),
+			len(diff.UnknownsResolved), len(diff.DeadEnds))
+		printTimelineSection("Findings added", "+", diff.FindingsAdded)
+		printTimelineSection("Findings invalidated", "-", diff.FindingsInvalidated)
+		printTimelineSection("Unknowns opened", "?", diff.UnknownsOpened)
+		printTimelineSection("Unknowns resolved", "✓", diff.UnknownsResolved)
+		printTimelineSection("Dead ends", "✗", diff.DeadEnds)
+		return nil
+	},
+}
+
+// printTimelineSection prints a titled list of timeline entries, skipping empty ones
+func printTimelineSection(title, bullet string, entries []*models.TimelineEntry) {
+	items := make([]string, 0, len(entries))
+	for _, e := range entries {
+		item := e.Text
+		if e.Detail != "" {
+			item += " — " + e.Detail
+		}
+		items = append(items, item)
+	}
+	printBriefingList(title, bullet, items)
+}
+
+func init() {
+	diffCmd.Flags().String("since", "", "Start of the window: a date, RFC 3339 timestamp or window such as 7d")
+	diffCmd.Flags().String("until", "", "End of the window (default now)")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// DigestStats counts knowledge activity within a digest window
+type DigestStats struct {
+	Sessions         int `json:"sessions"`
+	FindingsAdded    int `json:"findings_added"`
+	UnknownsOpened   int `json:"unknowns_opened"`
+	UnknownsResolved int `json:"unknowns_resolved"`
+	DeadEnds         int `json:"dead_ends"`
+}
+
+// ConfidencePoint is a session's confidence when it ended
+type ConfidencePoint struct {
+	SessionID  string  `json:"session_id"`
+	Date       string  `json:"date"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ConfidenceTrend follows session confidence across a digest window, oldest first
+type ConfidenceTrend struct {
+	Points    []ConfidencePoint `json:"points"`
+	Change    float64           `json:"change"`
+	Direction string            `json:"direction"` // rising, falling or steady
+}
+
+// trendSteadyBand is the confidence change below which a trend counts as steady
+const trendSteadyBand = 0.05
+
+// parseWindow parses a look-back window: a Go duration or a number of days or weeks
+// such as 7d or 2w
+func parseWindow(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err == nil && count > 0 {
+			unit := 24 * time.Hour
+			if s[n-1] == 'w' {
+				unit *= 7
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w window %q (use e.g. 7d, 2w or 36h)", db.ErrInvalid, s)
+	}
+	return d, nil
+}
+
+// listBetween pages through a newest-first list and returns the entries created
+// after from and up to to, stopping at the first older page
+func listBetween[T any](from, to float64, fetch func(db.Page) ([]T, *db.Cursor, error), created func(T) float64) ([]T, error) {
+	var matched []T
+	page := db.Page{Size: 200}
+	for {
+		items, next, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			ts := created(item)
+			if ts <= from {
+				return matched, nil
+			}
+			if ts <= to {
+				matched = append(matched, item)
+			}
+		}
+		if next == nil {
+			return matched, nil
+		}
+		page.After = next
+	}
+}
+
+// countSince counts the entries of a newest-first list created at or after since
+func countSince[T any](since float64, fetch func(db.Page) ([]T, *db.Cursor, error), created func(T) float64) (int, error) {
+	items, err := listBetween(math.Nextafter(since, math.Inf(-1)), math.Inf(1), fetch, created)
+	return len(items), err
+}
+
+// sessionEpistemicState recomputes a session's epistemic state from its breadcrumbs,
+// as done reported it after running for duration
+func sessionEpistemicState(ctx context.Context, projectID, sessionID string, duration time.Duration) *EpistemicState {
+	bcRepo := stores.Breadcrumbs
+	findings, _ := bcRepo.ListFindingsWithStaleness(ctx, projectID, sessionID, 100)
+	resolved := true
+	resolvedUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &resolved, 100)
+	unresolved := false
+	openUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &unresolved, 100)
+	deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, sessionID, 100)
+
+	// Engagement decays over the session's length, so measure it as at the end
+	return calculateEpistemicState(ctx, findings, openUnknowns, resolvedUnknowns, deadEnds, time.Now().Add(-duration))
+}
+
+// sessionConfidence returns a finished session's confidence as done recorded it,
+// recomputing it from its breadcrumbs for sessions that predate recorded snapshots
+func sessionConfidence(ctx context.Context, projectID string, h *models.HandoffReport) float64 {
+	if reflex, err := stores.Reflexes.GetLatestByPhase(ctx, h.SessionID, string(models.PhasePostflight)); err == nil && reflex.Confidence != nil {
+		return *reflex.Confidence
+	}
+	var duration time.Duration
+	if h.DurationSeconds != nil {
+		duration = time.Duration(*h.DurationSeconds) * time.Second
+	}
+	return sessionEpistemicState(ctx, projectID, h.SessionID, duration).Confidence
+}
+
+// collectDigestStats counts breadcrumbs created or resolved since the digest's start
+// and follows the confidence of its sessions
+func collectDigestStats(ctx context.Context, project *models.Project, digest *SessionDigest) error {
+	sinceTS := float64(digest.Since.UnixMilli()) / 1000.0
+	bcRepo := stores.Breadcrumbs
+	stats := &DigestStats{Sessions: len(digest.Sessions)}
+
+	var err error
+	stats.FindingsAdded, err = countSince(sinceTS, func(p db.Page) ([]*models.Finding, *db.Cursor, error) {
+		return bcRepo.ListFindingsPage(ctx, project.ID, "", p)
+	}, func(f *models.Finding) float64 { return f.CreatedTimestamp })
+	if err != nil {
+		return fmt.Errorf("failed to count findings: %w", err)
+	}
+	stats.UnknownsOpened, err = countSince(sinceTS, func(p db.Page) ([]*models.Unknown, *db.Cursor, error) {
+		return bcRepo.ListUnknownsPage(ctx, project.ID, "", nil, p)
+	}, func(u *models.Unknown) float64 { return u.CreatedTimestamp })
+	if err != nil {
+		return fmt.Errorf("failed to count unknowns: %w", err)
+	}
+	stats.DeadEnds, err = countSince(sinceTS, func(p db.Page) ([]*models.DeadEnd, *db.Cursor, error) {
+		return bcRepo.ListDeadEndsPage(ctx, project.ID, "", p)
+	}, func(d *models.DeadEnd) float64 { return d.CreatedTimestamp })
+	if err != nil {
+		return fmt.Errorf("failed to count dead ends: %w", err)
+	}
+
+	// Old questions can be answered in the window, so resolution time decides
+	resolved := true
+	resolvedUnknowns, err := bcRepo.ListUnknowns(ctx, project.ID, "", &resolved, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to list resolved unknowns: %w", err)
+	}
+	for _, u := range resolvedUnknowns {
+		if u.ResolvedTimestamp != nil && *u.ResolvedTimestamp >= sinceTS {
+			stats.UnknownsResolved++
+		}
+	}
+	digest.Stats = stats
+	digest.AgingUnknowns = agingUnknowns(ctx, project.ID)
+
+	// Digest sessions are newest first; the trend reads oldest first
+	trend := &ConfidenceTrend{Points: []ConfidencePoint{}, Direction: "steady"}
+	for i := len(digest.Sessions) - 1; i >= 0; i-- {
+		h, err := stores.Handoffs.Get(ctx, digest.Sessions[i].SessionID)
+		if err != nil {
+			continue
+		}
+		trend.Points = append(trend.Points, ConfidencePoint{
+			SessionID:  h.SessionID,
+			Date:       time.Unix(int64(h.CreatedAt), 0).Format("2006-01-02"),
+			Confidence: math.Round(sessionConfidence(ctx, project.ID, h)*100) / 100,
+		})
+	}
+	if n := len(trend.Points); n >= 2 {
+		trend.Change = math.Round((trend.Points[n-1].Confidence-trend.Points[0].Confidence)*100) / 100
+		if trend.Change >= trendSteadyBand {
+			trend.Direction = "rising"
+		} else if trend.Change <= -trendSteadyBand {
+			trend.Direction = "falling"
+		}
+	}
+	digest.Trend = trend
+	return nil
+}
+
+// renderMarkdownDigest formats a digest as Markdown for standup and retro notes
+func renderMarkdownDigest(d *SessionDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Memory digest: %s\n\n", d.Project)
+	fmt.Fprintf(&b, "_%s to %s_\n\n", d.Since.Format("Jan 2 15:04"), time.Now().Format("Jan 2 15:04"))
+
+	if s := d.Stats; s != nil {
+		fmt.Fprintf(&b, "| Sessions | Findings added | Unknowns opened | Unknowns resolved | Dead ends |\n")
+		fmt.Fprintf(&b, "|---|---|---|---|---|\n")
+		fmt.Fprintf(&b, "| %d | %d | %d | %d | %d |\n\n", s.Sessions, s.FindingsAdded, s.UnknownsOpened, s.UnknownsResolved, s.DeadEnds)
+	}
+
+	if t := d.Trend; t != nil && len(t.Points) > 0 {
+		points := make([]string, 0, len(t.Points))
+		for _, p := range t.Points {
+			points = append(points, fmt.Sprintf("%.0f%%", p.Confidence*100))
+		}
+		fmt.Fprintf(&b, "**Confidence:** %s (%s", strings.Join(points, " → "), t.Direction)
+		if len(t.Points) >= 2 {
+			fmt.Fprintf(&b, ", %+.0f pts", t.Change*100)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	if len(d.Sessions) > 0 {
+		b.WriteString("### Sessions\n\n")
+		for _, s := range d.Sessions {
+			title := s.Objective
+			if title == "" {
+				title = s.Summary
+			}
+			fmt.Fprintf(&b, "- **%s** (%s", title, s.AIID)
+			if s.Duration != "" {
+				fmt.Fprintf(&b, ", %s", s.Duration)
+			}
+			b.WriteString(")")
+			if s.Summary != "" && s.Summary != title {
+				fmt.Fprintf(&b, " — %s", s.Summary)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.NewlyStale) > 0 {
+		b.WriteString("### Went stale\n\n")
+		for _, f := range d.NewlyStale {
+			line := f.Finding
+			if f.Scope != "" {
+				line += fmt.Sprintf(" (`%s`)", f.Scope)
+			}
+			fmt.Fprintf(&b, "- %s\n", line)
+		}
+		b.WriteString("\n")
+	}
+	if len(d.AgingUnknowns) > 0 {
+		b.WriteString("### Open too long — escalate or close\n\n")
+		for _, a := range d.AgingUnknowns {
+			fmt.Fprintf(&b, "- %s (%dd, %s: `%s`)\n", a.Unknown, a.AgeDays, a.Recommendation, a.Command)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// digestCmd summarizes recent activity for standups and retros
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize recent sessions and knowledge activity",
+	Long: `Summarize a time window: sessions run, findings added, unknowns opened and
+resolved, dead ends hit, findings that went stale, and how session confidence moved.
+
+Examples:
+  memory digest                      # Last 7 days as JSON
+  memory digest --since 14d --format markdown > retro.md
+  memory digest --since 24h --text   # Markdown for a standup`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "markdown" {
+			return fmt.Errorf("%w format %q (use json or markdown)", db.ErrInvalid, format)
+		}
+		window, err := parseWindow(sinceFlag)
+		if err != nil {
+			return err
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		digest, err := buildSessionDigest(ctx, project, time.Now().Add(-window))
+		if err != nil {
+			return err
+		}
+		if err := collectDigestStats(ctx, project, digest); err != nil {
+			return err
+		}
+
+		if format == "markdown" || outputText {
+			fmt.Print(renderMarkdownDigest(digest))
+		} else {
+			outputResult(digest)
+		}
+		return nil
+	},
+}
+
+func init() {
+	digestCmd.Flags().String("since", "7d", "Window to summarize, e.g. 7d, 2w or 36h")
+	digestCmd.Flags().String("format", "json", "Output format: json or markdown")
+	rootCmd.AddCommand(digestCmd)
+}
@@ -0,0 +1,389 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// maxContextDocs caps the reference docs included in the start context
+const maxContextDocs = 5
+
+// docKeywordStopWords are objective words too common to pick out a document
+var docKeywordStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"add": true, "fix": true, "use": true, "make": true, "update": true, "support": true,
+}
+
+// docKeywords splits text into lowercase words worth matching
+func docKeywords(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) >= 3 && !docKeywordStopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// keywordMatches reports whether two words name the same thing, allowing one to
+// abbreviate the other ("auth" and "authentication")
+func keywordMatches(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 4 || len(b) < 4 {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// scoreReferenceDoc rates a doc's relevance: one point per objective word found in
+// its path, type or description, and two when it sits beside a file in scope
+func scoreReferenceDoc(doc *models.ReferenceDoc, objectiveWords, scopes []string) int {
+	text := doc.DocPath
+	if doc.DocType != nil {
+		text += " " + *doc.DocType
+	}
+	if doc.Description != nil {
+		text += " " + *doc.Description
+	}
+	docWords := docKeywords(text)
+
+	score := 0
+	for _, ow := range objectiveWords {
+		for _, dw := range docWords {
+			if keywordMatches(ow, dw) {
+				score++
+				break
+			}
+		}
+	}
+
+	if !isURLScope(doc.DocPath) {
+		if dir := path.Dir(doc.DocPath); dir != "." {
+			for _, s := range scopes {
+				if s == doc.DocPath || strings.HasPrefix(s, dir+"/") {
+					score += 2
+					break
+				}
+			}
+		}
+	}
+	return score
+}
+
+// relevantReferenceDocs picks the project's docs that match the objective or sit
+// next to the given file scopes, most relevant first
+func relevantReferenceDocs(ctx context.Context, projectIDs []string, objective string, scopes []string) []*models.ReferenceDoc {
+	objectiveWords := docKeywords(objective)
+
+	type scored struct {
+		doc   *models.ReferenceDoc
+		score int
+	}
+	var matches []scored
+	for _, projectID := range projectIDs {
+		docs, err := stores.Docs.List(ctx, projectID, "")
+		if err != nil {
+			continue
+		}
+		for _, d := range docs {
+			if score := scoreReferenceDoc(d, objectiveWords, scopes); score > 0 {
+				matches = append(matches, scored{d, score})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	for i, m := range matches {
+		msg := "included reference doc"
+		if i >= maxContextDocs {
+			msg = "left out lower-scoring reference doc"
+		}
+		traceContext(ctx, msg, "path", m.doc.DocPath, "score", m.score)
+	}
+	if len(matches) > maxContextDocs {
+		matches = matches[:maxContextDocs]
+	}
+	docs := make([]*models.ReferenceDoc, 0, len(matches))
+	for _, m := range matches {
+		docs = append(docs, m.doc)
+	}
+	return docs
+}
+
+// resolveReferenceDoc finds a doc by ID or by the path it was registered with
+func resolveReferenceDoc(ctx context.Context, projectID, ref string) (*models.ReferenceDoc, error) {
+	doc, err := stores.Docs.Get(ctx, ref)
+	if err == nil || !errors.Is(err, db.ErrNotFound) {
+		return doc, err
+	}
+	return stores.Docs.GetByPath(ctx, projectID, normalizeRepoPath(ctx, ref))
+}
+
+// normalizeRepoPath makes local paths relative to the repository root so they
+// resolve from any directory; URLs are kept as given
+func normalizeRepoPath(ctx context.Context, target string) string {
+	if isURLScope(target) {
+		return target
+	}
+	if root, err := gitRepoRoot(ctx); err == nil {
+		return normalizeScopePath(target, root)
+	}
+	return filepath.ToSlash(filepath.Clean(target))
+}
+
+// openCommand returns the platform command that opens a file or URL in its default viewer
+func openCommand(ctx context.Context, target string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "open", target)
+	case "windows":
+		return exec.CommandContext(ctx, "cmd", "/C", "start", "", target)
+	default:
+		return exec.CommandContext(ctx, "xdg-open", target)
+	}
+}
+
+// docsCmd groups reference doc commands
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Register the documents agents should consult",
+	Long: `Register design docs, specs, runbooks and URLs for the current project.
+
+Docs whose path, type or description match a session's objective, or that sit in a
+directory the session's findings are scoped to, are listed in the start context.`,
+}
+
+// docsAddCmd registers a reference doc
+var docsAddCmd = &cobra.Command{
+	Use:   "add [path-or-url]",
+	Short: "Register a reference doc",
+	Long: `Register a file or URL as a reference doc for the current project. Local paths are
+stored relative to the repository root.
+
+Examples:
+  memory docs add docs/auth.md --type design --description "Token lifecycle and refresh"
+  memory docs add https://datatracker.ietf.org/doc/html/rfc6749 --type spec`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		docType, _ := cmd.Flags().GetString("type")
+		description, _ := cmd.Flags().GetString("description")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		var typePtr, descPtr *string
+		if docType != "" {
+			typePtr = &docType
+		}
+		if description != "" {
+			descPtr = &description
+		}
+		doc := models.NewReferenceDoc(project.ID, normalizeRepoPath(ctx, args[0]), typePtr, descPtr)
+		if err := stores.Docs.Create(ctx, doc); err != nil {
+			return fmt.Errorf("failed to add doc: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Doc: %s\n", doc.DocPath)
+			fmt.Printf("  ID: %s\n", doc.ID)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "created",
+				"doc_id": doc.ID,
+				"doc":    doc,
+			})
+		}
+		return nil
+	},
+}
+
+// docsListCmd lists the project's reference docs
+var docsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List reference docs in the current project",
+	Long: `List reference docs in the current project by path. With --objective, list only
+the docs the start context would include for that objective.
+
+Examples:
+  memory docs list
+  memory docs list --type design
+  memory docs list --objective "Add token refresh"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		docType, _ := cmd.Flags().GetString("type")
+		objective, _ := cmd.Flags().GetString("objective")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		var docs []*models.ReferenceDoc
+		if objective != "" {
+			docs = relevantReferenceDocs(ctx, []string{project.ID}, objective, nil)
+		} else if docs, err = stores.Docs.List(ctx, project.ID, docType); err != nil {
+			return fmt.Errorf("failed to list docs: %w", err)
+		}
+
+		if !outputText {
+			if docs == nil {
+				docs = []*models.ReferenceDoc{}
+			}
+			outputResult(map[string]interface{}{
+				"docs":  docs,
+				"count": len(docs),
+			})
+			return nil
+		}
+
+		if len(docs) == 0 {
+			fmt.Println("No reference docs.")
+			return nil
+		}
+		for _, d := range docs {
+			line := fmt.Sprintf("  %s  %s", d.ID[:8], d.DocPath)
+			if d.DocType != nil {
+				line += fmt.Sprintf(" [%s]", *d.DocType)
+			}
+			fmt.Println(line)
+			if d.Description != nil {
+				fmt.Printf("      %s\n", truncateText(*d.Description, 70))
+			}
+		}
+		return nil
+	},
+}
+
+// docsOpenCmd opens a reference doc in the default viewer or prints it
+var docsOpenCmd = &cobra.Command{
+	Use:   "open [id-or-path]",
+	Short: "Open a reference doc",
+	Long: `Open a reference doc in the system's default viewer or browser. With --print, a
+local doc's contents are written to stdout instead, for agents to read.
+
+Examples:
+  memory docs open docs/auth.md
+  memory docs open 3f2a9c1e-... --print`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		printDoc, _ := cmd.Flags().GetBool("print")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		doc, err := resolveReferenceDoc(ctx, project.ID, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get doc: %w", err)
+		}
+
+		target := doc.DocPath
+		if !isURLScope(target) && !filepath.IsAbs(target) {
+			if root, err := gitRepoRoot(ctx); err == nil {
+				target = filepath.Join(root, filepath.FromSlash(target))
+			}
+		}
+
+		if printDoc {
+			if isURLScope(target) {
+				return fmt.Errorf("%w: --print only reads local docs, %s is a URL", db.ErrInvalid, target)
+			}
+			content, err := os.ReadFile(target)
+			if err != nil {
+				return fmt.Errorf("failed to read doc: %w", err)
+			}
+			if outputText {
+				fmt.Print(string(content))
+			} else {
+				outputResult(map[string]interface{}{
+					"doc":     doc,
+					"content": string(content),
+				})
+			}
+			return nil
+		}
+
+		if err := openCommand(ctx, target).Start(); err != nil {
+			return fmt.Errorf("failed to open %s: %w", target, err)
+		}
+		if outputText {
+			fmt.Printf("Opened %s\n", target)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "opened",
+				"doc":    doc,
+				"target": target,
+			})
+		}
+		return nil
+	},
+}
+
+// docsRemoveCmd unregisters a reference doc
+var docsRemoveCmd = &cobra.Command{
+	Use:   "remove [id-or-path]",
+	Short: "Unregister a reference doc",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		doc, err := resolveReferenceDoc(ctx, project.ID, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get doc: %w", err)
+		}
+		if err := stores.Docs.Delete(ctx, doc.ID); err != nil {
+			return fmt.Errorf("failed to remove doc: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Removed %s\n", doc.DocPath)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "removed",
+				"doc_id": doc.ID,
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	docsAddCmd.Flags().String("type", "", "Kind of document (design, spec, runbook, ...)")
+	docsAddCmd.Flags().String("description", "", "What the document covers")
+
+	docsListCmd.Flags().String("type", "", "Only list docs of this type")
+	docsListCmd.Flags().String("objective", "", "Only list docs relevant to this objective")
+
+	docsOpenCmd.Flags().Bool("print", false, "Print a local doc instead of opening it")
+
+	docsCmd.AddCommand(docsAddCmd, docsListCmd, docsOpenCmd, docsRemoveCmd)
+	rootCmd.AddCommand(docsCmd)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AbdouB/memory/internal/db"
+)
+
+// Process exit codes, stable so scripts and agents can branch on the kind of failure
+const (
+	ExitError    = 1 // Any other failure
+	ExitNotFound = 3 // The session, project or breadcrumb doesn't exist
+	ExitConflict = 4 // The record changed concurrently or already exists
+	ExitInvalid  = 5 // The input was rejected
+	ExitTimeout  = 6 // --timeout expired
+)
+
+// errorKinds maps repository and context errors to their code and exit status, most specific first
+var errorKinds = []struct {
+	err  error
+	code string
+	exit int
+}{
+	{context.DeadlineExceeded, "timeout", ExitTimeout},
+	{db.ErrNotFound, "not_found", ExitNotFound},
+	{db.ErrConflict, "conflict", ExitConflict},
+	{db.ErrInvalid, "invalid", ExitInvalid},
+}
+
+// errorCode returns the stable code reported with an error in JSON output
+func errorCode(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.code
+		}
+	}
+	return "error"
+}
+
+// ExitCode returns the process exit status for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var forwarded daemonExit
+	if errors.As(err, &forwarded) {
+		return int(forwarded)
+	}
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.exit
+		}
+	}
+	return ExitError
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+)
+
+// Events emitted to webhooks
+const (
+	EventSessionStarted = "session_started"
+	EventFindingLogged  = "finding_logged"
+	EventUnknownLogged  = "unknown_logged"
+	EventDeadEndLogged  = "dead_end_logged"
+	EventSessionDone    = "session_done"
+)
+
+// knownEvents lists every event name, for validating subscriptions
+var knownEvents = []string{
+	EventSessionStarted,
+	EventFindingLogged,
+	EventUnknownLogged,
+	EventDeadEndLogged,
+	EventSessionDone,
+}
+
+// webhookTimeout bounds each webhook delivery so a slow endpoint can't stall the CLI
+const webhookTimeout = 5 * time.Second
+
+// Event is the envelope delivered to webhooks and project hooks
+type Event struct {
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	ProjectID string      `json:"project_id,omitempty"`
+	Data      interface{} `json:"data"` // Same payload the command prints as JSON
+}
+
+// memoryDir returns the directory holding the open database and its config
+func memoryDir() string {
+	return filepath.Dir(database.Path())
+}
+
+// loadConfig reads the config for the open database
+func loadConfig() (*config.Config, error) {
+	return config.Load(memoryDir())
+}
+
+// emitEvent runs the matching project hook and delivers the event to every subscribed
+// webhook. Failures are logged as warnings but never fail the command that produced the event.
+func emitEvent(ctx context.Context, name, projectID string, data interface{}) {
+	countEvent(name)
+	event := Event{
+		Event:     name,
+		Timestamp: time.Now().UTC(),
+		ProjectID: projectID,
+		Data:      data,
+	}
+
+	if hook, ok := eventHookNames[name]; ok {
+		if err := runExecHook(ctx, hook, projectID, event); err != nil {
+			slog.Warn("event hook failed", "event", name, "err", err)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		slog.Warn("failed to load config", "err", err)
+		return
+	}
+	if len(cfg.Webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	for _, hook := range cfg.Webhooks {
+		if !hook.Matches(name) {
+			continue
+		}
+		if err := deliverWebhook(ctx, hook, name, body); err != nil {
+			slog.Warn("webhook failed", "url", hook.URL, "event", name, "err", err)
+		}
+	}
+}
+
+// deliverWebhook POSTs an event body to a webhook, signing it when a secret is configured
+func deliverWebhook(ctx context.Context, hook config.Webhook, name string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Memory-Event", name)
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Memory-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AbdouB/memory/internal/blob"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// storeEvidence returns what to record as a finding's evidence: short text as is,
+// anything longer than maxCheckEvidence, binary or attached as a reference to a blob
+// holding it. Blobs bypass the database, so text is scrubbed before it is stored.
+func storeEvidence(data []byte, attached bool) (evidence, hash string, err error) {
+	text := utf8.Valid(data)
+	if text && len(data) <= maxCheckEvidence && !attached {
+		return string(data), "", nil
+	}
+	if text {
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return "", "", err
+		}
+		if scrubber != nil {
+			data = []byte(scrubber.String(string(data)))
+		}
+	}
+	hash, err = blob.New(memoryDir()).Put(data)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to store evidence: %w", err)
+	}
+	return blob.Ref(hash), hash, nil
+}
+
+// readEvidence returns a finding's evidence, reading it from its blob when stored there
+func readEvidence(evidence string) ([]byte, error) {
+	hash, ok := blob.ParseRef(evidence)
+	if !ok {
+		return []byte(evidence), nil
+	}
+	data, err := blob.New(memoryDir()).Get(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evidence blob: %w", err)
+	}
+	return data, nil
+}
+
+// printEvidence shows a finding's evidence. As text the content is written as is, so
+// an attached file can be saved with a redirect.
+func printEvidence(finding *models.Finding) error {
+	if finding.VerificationEvidence == nil || *finding.VerificationEvidence == "" {
+		return fmt.Errorf("%w: finding %s has no evidence", db.ErrNotFound, finding.ID[:8])
+	}
+	stored := *finding.VerificationEvidence
+	data, err := readEvidence(stored)
+	if err != nil {
+		return err
+	}
+
+	if outputText {
+		os.Stdout.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' && utf8.Valid(data) {
+			fmt.Println()
+		}
+		return nil
+	}
+	result := map[string]interface{}{
+		"id":      finding.ID,
+		"finding": finding.Finding,
+		"bytes":   len(data),
+	}
+	if hash, ok := blob.ParseRef(stored); ok {
+		result["blob"] = hash
+		result["path"] = blob.New(memoryDir()).Path(hash)
+	}
+	if utf8.Valid(data) {
+		result["evidence"] = string(data)
+	}
+	outputResult(result)
+	return nil
+}
+
+// blobGracePeriod spares new blobs from collection while the breadcrumb write that
+// refers to them is still in flight
+const blobGracePeriod = time.Hour
+
+// blobRestoreWindow is how long a deleted finding keeps its evidence blob, so
+// 'memory forget --restore' brings the evidence back too
+const blobRestoreWindow = 30 * 24 * time.Hour
+
+// collectEvidenceBlobs removes the blobs no finding's evidence refers to any more,
+// counting findings deleted within blobRestoreWindow. With dryRun they're only counted.
+func collectEvidenceBlobs(ctx context.Context, dryRun bool) (removed int, freed int64, err error) {
+	now := time.Now()
+	hashes, err := database.EvidenceBlobs(ctx, now.Add(-blobRestoreWindow))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list evidence blobs: %w", err)
+	}
+	keep := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		keep[hash] = true
+	}
+	removed, freed, err = blob.New(memoryDir()).Collect(keep, now.Add(-blobGracePeriod), dryRun)
+	if err != nil {
+		return removed, freed, fmt.Errorf("failed to collect blobs: %w", err)
+	}
+	return removed, freed, nil
+}
+
+// tidyEvidenceBlobs collects unreferenced blobs after a command has deleted or moved
+// breadcrumbs. A failure is logged as a warning rather than failing the command, whose
+// change is already made.
+func tidyEvidenceBlobs(ctx context.Context) {
+	removed, freed, err := collectEvidenceBlobs(ctx, false)
+	if err != nil {
+		slog.Warn("blob collection failed", "err", err)
+	} else if removed > 0 {
+		slog.Info("collected unreferenced blobs", "count", removed, "bytes", freed)
+	}
+}
+
+// packEvidenceBlobs copies the blobs a database's findings refer to into it, so a
+// snapshot or archive leaving the memory directory carries them. Blobs missing from
+// the local store are left out.
+func packEvidenceBlobs(ctx context.Context, d *db.DB) (int, error) {
+	hashes, err := d.EvidenceBlobs(ctx, time.Time{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list evidence blobs: %w", err)
+	}
+	store := blob.New(memoryDir())
+	packed := 0
+	for _, hash := range hashes {
+		data, err := store.Get(hash)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return packed, err
+		}
+		if err := d.CarryBlob(ctx, hash, data); err != nil {
+			return packed, fmt.Errorf("failed to pack blob %s: %w", hash[:12], err)
+		}
+		packed++
+	}
+	return packed, nil
+}
+
+// unpackEvidenceBlobs writes the blobs a database carries into the local store, along
+// with any its findings refer to that sit in the blob store of its own directory
+func unpackEvidenceBlobs(ctx context.Context, d *db.DB) (int, error) {
+	store := blob.New(memoryDir())
+	unpacked := 0
+	err := d.CarriedBlobs(ctx, func(hash string, data []byte) error {
+		if err := putBlob(store, hash, data); err != nil {
+			return err
+		}
+		unpacked++
+		return nil
+	})
+	if err != nil {
+		return unpacked, fmt.Errorf("failed to unpack blobs: %w", err)
+	}
+
+	peerDir := filepath.Dir(d.Path())
+	if sameDir(peerDir, memoryDir()) {
+		return unpacked, nil
+	}
+	peer := blob.New(peerDir)
+	hashes, err := d.EvidenceBlobs(ctx, time.Time{})
+	if err != nil {
+		return unpacked, fmt.Errorf("failed to list evidence blobs: %w", err)
+	}
+	for _, hash := range hashes {
+		if _, err := os.Stat(store.Path(hash)); err == nil {
+			continue
+		}
+		data, err := peer.Get(hash)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return unpacked, err
+		}
+		if err := putBlob(store, hash, data); err != nil {
+			return unpacked, err
+		}
+		unpacked++
+	}
+	return unpacked, nil
+}
+
+// attachBatchBlobs adds the blobs an outgoing sync batch's events refer to, so the
+// peer can read their evidence. Blobs missing from the local store are left out.
+func attachBatchBlobs(batch *models.SyncBatch) error {
+	store := blob.New(memoryDir())
+	for _, ev := range batch.Events {
+		for _, hash := range blob.FindRefs(ev.Payload) {
+			if _, ok := batch.Blobs[hash]; ok {
+				continue
+			}
+			data, err := store.Get(hash)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if batch.Blobs == nil {
+				batch.Blobs = make(map[string][]byte)
+			}
+			batch.Blobs[hash] = data
+		}
+	}
+	return nil
+}
+
+// storeBatchBlobs writes the blobs an incoming sync batch carries into the local store
+func storeBatchBlobs(batch *models.SyncBatch) error {
+	store := blob.New(memoryDir())
+	for hash, data := range batch.Blobs {
+		if err := putBlob(store, hash, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putBlob stores a blob received under a hash, refusing content that doesn't match it
+func putBlob(store *blob.Store, hash string, data []byte) error {
+	if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != hash {
+		return fmt.Errorf("%w: blob %.12s does not match its content", db.ErrInvalid, hash)
+	}
+	if _, err := store.Put(data); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	return nil
+}
+
+// sameDir reports whether two paths name the same directory
+func sameDir(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	return err == nil && os.SameFile(ai, bi)
+}
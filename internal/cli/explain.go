@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// explainDecision breaks an epistemic state's recommended action down into the
+// threshold checks that decided it and the breadcrumbs that moved the vectors
+func explainDecision(
+	ctx context.Context,
+	epistemic *EpistemicState,
+	findings []*models.Finding,
+	openUnknowns []*models.Unknown,
+	resolvedUnknowns []*models.Unknown,
+	deadEnds []*models.DeadEnd,
+) *models.DecisionExplanation {
+	explanation := &models.DecisionExplanation{
+		Action: epistemic.RecommendedAction,
+		Checks: []models.VectorCheck{
+			{Vector: "engagement", Value: epistemic.Engagement, Threshold: engagementGate, Passes: ">=", OnFail: "stop"},
+			{Vector: "coherence", Value: epistemic.Coherence, Threshold: minCoherence, Passes: ">=", OnFail: "reset"},
+			{Vector: "clarity", Value: epistemic.Clarity, Threshold: minClarity, Passes: ">=", OnFail: "verify"},
+			{Vector: "know", Value: epistemic.Know, Threshold: minKnow, Passes: ">=", OnFail: "investigate"},
+			{Vector: "uncertainty", Value: epistemic.Uncertainty, Threshold: maxUncertainty, Passes: "<=", OnFail: "investigate"},
+		},
+		OpenUnknowns: len(openUnknowns),
+	}
+	for i := range explanation.Checks {
+		c := &explanation.Checks[i]
+		if c.Passes == "<=" {
+			c.Passed = c.Value <= c.Threshold
+		} else {
+			c.Passed = c.Value >= c.Threshold
+		}
+		if !c.Passed && explanation.Deciding == "" {
+			relation := "below"
+			if c.Passes == "<=" {
+				relation = "above"
+			}
+			explanation.Deciding = fmt.Sprintf("%s %.2f is %s %.2f", c.Vector, c.Value, relation, c.Threshold)
+		}
+	}
+	if explanation.Deciding == "" {
+		explanation.Deciding = "every check passed"
+	}
+
+	// Weighted as calculateEpistemicState weighs them, so each cost is what the
+	// breadcrumb takes off its vector
+	findingWeight := weightedFindings(findings)
+	for _, f := range findings {
+		fileChanged := findingFileChanged(ctx, f)
+		status := f.GetStalenessStatus(fileChanged)
+		if status == models.StatusFresh {
+			continue
+		}
+		cause := models.StaleFindingCause{
+			ID:          f.ID,
+			Finding:     f.Finding,
+			Status:      status,
+			Confidence:  f.CalculateConfidence(),
+			DaysStale:   int(f.DaysSinceVerified()),
+			FileChanged: fileChanged,
+			Weight:      impactWeight(f.Impact) / findingWeight,
+		}
+		if f.Subject != nil {
+			cause.Scope = *f.Subject
+		}
+		explanation.StaleFindings = append(explanation.StaleFindings, cause)
+	}
+
+	totalWeight := findingWeight + weightedUnknowns(openUnknowns) + weightedUnknowns(resolvedUnknowns) + weightedDeadEnds(deadEnds)
+	for _, d := range deadEnds {
+		explanation.DeadEnds = append(explanation.DeadEnds, models.DeadEndCause{
+			ID:        d.ID,
+			Approach:  d.Approach,
+			WhyFailed: d.WhyFailed,
+			Cost:      impactWeight(d.Impact) / totalWeight,
+		})
+	}
+	return explanation
+}
+
+// explainCmd shows why the context recommends the action it does
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain the recommended action",
+	Long: `Break down the action start and context recommend: each epistemic vector against
+the threshold it's checked by, in the order the checks are made, and which one decided
+the action. Lists the findings counted as not fresh, which lower clarity, and the dead
+ends, which lower coherence, with how much each costs.
+
+Guidance built from wrong breadcrumbs is wrong too. Verify a finding that's still true
+with 'memory verify --id', and delete one that isn't, or a dead end recorded by
+mistake, with 'memory forget'.
+
+Uses the active session when there is one, otherwise the current directory's project.
+
+Examples:
+  memory explain --text
+  memory explain | jq '.stale_findings[].id'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		var sessionCtx *models.SessionContext
+		if active, err := loadActiveSession(ctx); err == nil {
+			var inheritFrom []string
+			if active.InheritParent {
+				if project, _ := stores.Projects.Get(ctx, active.ProjectID); project != nil {
+					inheritFrom = projectAncestorIDs(ctx, project)
+				}
+			}
+			inheritFrom = withGlobalKnowledge(ctx, active.ProjectID, inheritFrom)
+			sessionCtx = buildSessionContext(ctx, active.SessionID, active.ProjectID, active.Objective, active.AIID, active.StartedAt, inheritFrom, currentGoal(ctx, active), active.contextAgents())
+		} else {
+			project, err := getOrCreateDefaultProject(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			sessionCtx = buildSessionContext(ctx, "", project.ID, "", "", time.Now(), withGlobalKnowledge(ctx, project.ID, nil), nil, nil)
+		}
+		e := sessionCtx.Explanation
+		d := sessionCtx.Decision
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"action":         e.Action,
+				"confidence":     d.Confidence,
+				"reason":         d.Reason,
+				"deciding":       e.Deciding,
+				"checks":         e.Checks,
+				"stale_findings": e.StaleFindings,
+				"dead_ends":      e.DeadEnds,
+				"open_unknowns":  e.OpenUnknowns,
+				"vectors":        sessionCtx.Vectors,
+			})
+			return nil
+		}
+
+		fmt.Printf("%s (%.0f%% confidence): %s\n", strings.ToUpper(e.Action), d.Confidence*100, d.Reason)
+		fmt.Printf("Decided by: %s\n\n", e.Deciding)
+		fmt.Println("Checks, in order:")
+		for _, c := range e.Checks {
+			mark, relation := "✓", "≥"
+			if !c.Passed {
+				mark = "✗"
+			}
+			if c.Passes == "<=" {
+				relation = "≤"
+			}
+			fmt.Printf("  %s %-12s %.2f (needs %s %.2f, else %s)\n", mark, c.Vector, c.Value, relation, c.Threshold, c.OnFail)
+		}
+
+		if len(e.StaleFindings) > 0 {
+			fmt.Println("\nFindings counted as not fresh (lower clarity):")
+			for _, f := range e.StaleFindings {
+				detail := fmt.Sprintf("%s, %d days", f.Status, f.DaysStale)
+				if f.FileChanged {
+					detail += ", file changed"
+				}
+				fmt.Printf("  %s  -%.2f  %s  (%s)\n", f.ID[:8], f.Weight, truncateText(f.Finding, 60), detail)
+			}
+		}
+		if len(e.DeadEnds) > 0 {
+			fmt.Println("\nDead ends (lower coherence):")
+			for _, de := range e.DeadEnds {
+				fmt.Printf("  %s  -%.2f  %s\n", de.ID[:8], de.Cost, truncateText(de.Approach, 60))
+			}
+		}
+		if e.OpenUnknowns > 0 {
+			fmt.Printf("\n%d open unknown(s) raise uncertainty\n", e.OpenUnknowns)
+		}
+		if len(e.StaleFindings) > 0 || len(e.DeadEnds) > 0 {
+			fmt.Println("\nTo correct: memory verify --id <id> if still true, memory forget <id> --reason \"...\" if not")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
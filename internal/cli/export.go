@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/AbdouB/memory/internal/scrub"
+	"github.com/spf13/cobra"
+)
+
+// Markers around the section export writes, so re-exporting replaces it in place
+const (
+	exportBeginMarker = "<!-- memory:begin -->"
+	exportEndMarker   = "<!-- memory:end -->"
+)
+
+// exportScanLimit caps how many breadcrumbs of each type export ranks
+const exportScanLimit = 500
+
+// ExportedKnowledge is what export renders: a project's freshest high-impact findings,
+// the ones tagged as conventions, decisions or constraints, and the dead ends not to
+// repeat, global knowledge included
+type ExportedKnowledge struct {
+	Project     string            `json:"project"`
+	Conventions []*models.Finding `json:"conventions"`
+	Decisions   []*models.Finding `json:"decisions"`
+	Constraints []*models.Finding `json:"constraints"`
+	Findings    []*models.Finding `json:"findings"`
+	DeadEnds    []*models.DeadEnd `json:"dead_ends"`
+}
+
+// exportFormats render exported knowledge as the file content a format targets
+var exportFormats = map[string]func(k *ExportedKnowledge) string{
+	"claude-md":    renderClaudeMD,
+	"cursor-rules": renderCursorRules,
+}
+
+// gatherExportKnowledge collects the project's and global live findings and dead ends.
+// Stale findings are left out; the rest are ranked by impact, then freshness, and each
+// list is cut to limit.
+func gatherExportKnowledge(ctx context.Context, project *models.Project, limit int) (*ExportedKnowledge, error) {
+	k := &ExportedKnowledge{
+		Project:     project.Name,
+		Conventions: []*models.Finding{},
+		Decisions:   []*models.Finding{},
+		Constraints: []*models.Finding{},
+		Findings:    []*models.Finding{},
+		DeadEnds:    []*models.DeadEnd{},
+	}
+	repo := stores.Breadcrumbs
+	for _, projectID := range withGlobalKnowledge(ctx, project.ID, []string{project.ID}) {
+		findings, err := repo.ListFindingsWithStaleness(ctx, projectID, "", exportScanLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list findings: %w", err)
+		}
+		for _, f := range findings {
+			if f.GetStalenessStatus(f.FileChangedDetectedAt != nil) == models.StatusStale {
+				continue
+			}
+			switch {
+			case slices.Contains(f.Tags, models.TagConvention):
+				k.Conventions = append(k.Conventions, f)
+			case slices.Contains(f.Tags, models.TagDecision):
+				k.Decisions = append(k.Decisions, f)
+			case slices.Contains(f.Tags, models.TagConstraint):
+				k.Constraints = append(k.Constraints, f)
+			default:
+				k.Findings = append(k.Findings, f)
+			}
+		}
+		deadEnds, err := repo.ListDeadEnds(ctx, projectID, "", exportScanLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dead ends: %w", err)
+		}
+		k.DeadEnds = append(k.DeadEnds, deadEnds...)
+	}
+
+	rankFindings := func(findings []*models.Finding) []*models.Finding {
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].Impact != findings[j].Impact {
+				return findings[i].Impact > findings[j].Impact
+			}
+			return findings[i].CalculateConfidence() > findings[j].CalculateConfidence()
+		})
+		return findings[:min(len(findings), limit)]
+	}
+	k.Conventions = rankFindings(k.Conventions)
+	k.Decisions = rankFindings(k.Decisions)
+	k.Constraints = rankFindings(k.Constraints)
+	k.Findings = rankFindings(k.Findings)
+	sort.SliceStable(k.DeadEnds, func(i, j int) bool {
+		if k.DeadEnds[i].Impact != k.DeadEnds[j].Impact {
+			return k.DeadEnds[i].Impact > k.DeadEnds[j].Impact
+		}
+		return k.DeadEnds[i].CreatedTimestamp > k.DeadEnds[j].CreatedTimestamp
+	})
+	k.DeadEnds = k.DeadEnds[:min(len(k.DeadEnds), limit)]
+	return k, nil
+}
+
+// renderClaudeMD renders exported knowledge as a CLAUDE.md or AGENTS.md section
+// between the export markers
+func renderClaudeMD(k *ExportedKnowledge) string {
+	var b strings.Builder
+	b.WriteString(exportBeginMarker + "\n")
+	fmt.Fprintf(&b, "## Project memory: %s\n\n", k.Project)
+	b.WriteString("_Generated by `memory export --format claude-md`; edits between the markers are overwritten._\n\n")
+
+	sections := []struct {
+		title    string
+		findings []*models.Finding
+	}{
+		{"Conventions", k.Conventions},
+		{"Decisions", k.Decisions},
+		{"Constraints", k.Constraints},
+		{"Key knowledge", k.Findings},
+	}
+	for _, s := range sections {
+		if len(s.findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n\n", s.title)
+		for _, f := range s.findings {
+			fmt.Fprintf(&b, "- %s\n", exportLine(f.Finding, f.Subject))
+		}
+		b.WriteString("\n")
+	}
+	if len(k.DeadEnds) > 0 {
+		b.WriteString("### Dead ends (do not repeat)\n\n")
+		for _, d := range k.DeadEnds {
+			fmt.Fprintf(&b, "- %s — %s\n", exportLine(d.Approach, d.Subject), d.WhyFailed)
+		}
+		b.WriteString("\n")
+	}
+	if k.empty() {
+		b.WriteString("Nothing recorded yet.\n\n")
+	}
+	b.WriteString(exportEndMarker + "\n")
+	return b.String()
+}
+
+// renderCursorRules renders the decisions, constraints, conventions and dead ends as
+// editor agent rules, the content of .cursorrules or .windsurfrules. Plain findings
+// are left out: rules files hold guardrails, not background.
+func renderCursorRules(k *ExportedKnowledge) string {
+	var b strings.Builder
+	b.WriteString(exportBeginMarker + "\n")
+	fmt.Fprintf(&b, "# Rules recorded in memory for %s\n", k.Project)
+	b.WriteString("# Generated by `memory export --format cursor-rules`; edits between the markers are overwritten.\n\n")
+
+	sections := []struct {
+		title    string
+		findings []*models.Finding
+	}{
+		{"Follow these decisions:", k.Decisions},
+		{"Respect these constraints:", k.Constraints},
+		{"Follow these conventions:", k.Conventions},
+	}
+	rules := 0
+	for _, s := range sections {
+		if len(s.findings) == 0 {
+			continue
+		}
+		b.WriteString(s.title + "\n")
+		for _, f := range s.findings {
+			fmt.Fprintf(&b, "- %s\n", exportLine(f.Finding, f.Subject))
+		}
+		b.WriteString("\n")
+		rules += len(s.findings)
+	}
+	if len(k.DeadEnds) > 0 {
+		b.WriteString("Do not:\n")
+		for _, d := range k.DeadEnds {
+			fmt.Fprintf(&b, "- %s (failed before: %s)\n", exportLine(d.Approach, d.Subject), d.WhyFailed)
+		}
+		b.WriteString("\n")
+		rules += len(k.DeadEnds)
+	}
+	if rules == 0 {
+		b.WriteString("No rules recorded yet. Tag findings decision, constraint or convention to add some.\n\n")
+	}
+	b.WriteString(exportEndMarker + "\n")
+	return b.String()
+}
+
+// scrub removes personal data from the text export renders
+func (k *ExportedKnowledge) scrub(s *scrub.Scrubber) {
+	if s == nil {
+		return
+	}
+	scrubSubject := func(subject *string) *string {
+		if subject == nil {
+			return nil
+		}
+		scrubbed := s.String(*subject)
+		return &scrubbed
+	}
+	for _, findings := range [][]*models.Finding{k.Conventions, k.Decisions, k.Constraints, k.Findings} {
+		for _, f := range findings {
+			f.Finding = s.String(f.Finding)
+			f.Subject = scrubSubject(f.Subject)
+		}
+	}
+	for _, d := range k.DeadEnds {
+		d.Approach = s.String(d.Approach)
+		d.WhyFailed = s.String(d.WhyFailed)
+		d.Subject = scrubSubject(d.Subject)
+	}
+}
+
+// empty reports whether there is nothing to export
+func (k *ExportedKnowledge) empty() bool {
+	return len(k.Conventions)+len(k.Decisions)+len(k.Constraints)+len(k.Findings)+len(k.DeadEnds) == 0
+}
+
+// exportLine flattens a breadcrumb's text to one line, with its scope when it has one
+func exportLine(text string, subject *string) string {
+	line := strings.Join(strings.Fields(text), " ")
+	if subject != nil && *subject != "" {
+		line += fmt.Sprintf(" (`%s`)", *subject)
+	}
+	return line
+}
+
+// writeMarkedSection replaces the section between the export markers in the file at
+// path, or appends it when the file has none. The file is created if missing.
+func writeMarkedSection(path, section string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(data)
+	begin := strings.Index(content, exportBeginMarker)
+	end := strings.Index(content, exportEndMarker)
+	switch {
+	case begin >= 0 && end > begin:
+		end += len(exportEndMarker)
+		if end < len(content) && content[end] == '\n' {
+			end++
+		}
+		content = content[:begin] + section + content[end:]
+	case content == "":
+		content = section
+	default:
+		content = strings.TrimRight(content, "\n") + "\n\n" + section
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// exportCmd renders recorded knowledge as a file other agents read
+var exportCmd = &cobra.Command{
+	Use:   "export --format [format]",
+	Short: "Render recorded knowledge as a section for agent instruction files",
+	Long: `Render the project's freshest high-impact findings, the findings tagged convention,
+decision or constraint, and the dead ends not to repeat as a bounded section for files
+other agents read. Global knowledge is included and stale findings are left out. The
+section is wrapped in begin/end markers, so --write replaces it in place on every
+export and leaves the rest of the file alone. The 'memory scrub' rules are applied to
+the text, as for 'memory share export'.
+
+Formats:
+  claude-md     Markdown section for CLAUDE.md or AGENTS.md
+  cursor-rules  Editor agent rules for .cursorrules or .windsurfrules: decisions,
+                constraints, conventions and dead ends, without plain findings
+
+Examples:
+  memory export --format claude-md
+  memory export --format claude-md --write CLAUDE.md --limit 15
+  memory export --format cursor-rules --write .cursorrules`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		format, _ := cmd.Flags().GetString("format")
+		target, _ := cmd.Flags().GetString("write")
+		limit, _ := cmd.Flags().GetInt("limit")
+		projectName, _ := cmd.Flags().GetString("project")
+		render, ok := exportFormats[format]
+		if !ok {
+			return fmt.Errorf("%w format %q (use claude-md or cursor-rules)", db.ErrInvalid, format)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("%w: --limit must be positive", db.ErrInvalid)
+		}
+
+		var project *models.Project
+		var err error
+		if projectName != "" {
+			project, err = stores.Projects.GetByName(ctx, projectName)
+		} else {
+			project, err = getOrCreateDefaultProject(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		knowledge, err := gatherExportKnowledge(ctx, project, limit)
+		if err != nil {
+			return err
+		}
+		// Rules added since the entries were stored still apply to what leaves the database
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+		knowledge.scrub(scrubber)
+		section := render(knowledge)
+		if target == "" {
+			fmt.Print(section)
+			return nil
+		}
+
+		if err := writeMarkedSection(target, section); err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		if outputText {
+			fmt.Printf("✓ Wrote %d conventions, %d decisions, %d constraints, %d findings and %d dead ends to %s\n",
+				len(knowledge.Conventions), len(knowledge.Decisions), len(knowledge.Constraints),
+				len(knowledge.Findings), len(knowledge.DeadEnds), target)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":      "exported",
+				"format":      format,
+				"path":        target,
+				"conventions": len(knowledge.Conventions),
+				"decisions":   len(knowledge.Decisions),
+				"constraints": len(knowledge.Constraints),
+				"findings":    len(knowledge.Findings),
+				"dead_ends":   len(knowledge.DeadEnds),
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().String("format", "claude-md", "Output format: claude-md or cursor-rules")
+	exportCmd.Flags().String("write", "", "Update this file's memory section in place instead of printing it")
+	exportCmd.Flags().Int("limit", 10, "Most entries per section")
+	exportCmd.Flags().String("project", "", "Project name (defaults to the current directory's project)")
+	rootCmd.AddCommand(exportCmd)
+}
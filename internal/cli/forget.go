@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// forgetCmd soft-deletes a breadcrumb
+var forgetCmd = &cobra.Command{
+	Use:   "forget [id]",
+	Short: "Delete a finding, unknown or dead end",
+	Long: `Delete a finding, unknown or dead end by ID. Deletes are soft: the entry is hidden
+from every command but its history is kept and it can be brought back with --restore.
+A deleted finding's evidence blob is kept for 30 days, then collected.
+
+Examples:
+  memory forget 3f2a9c1e-... --reason "Wrong, auth moved to middleware" --dry-run
+  memory forget 3f2a9c1e-... --reason "Wrong, auth moved to middleware"
+  memory forget 3f2a9c1e-... --restore`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		id := args[0]
+		reason, _ := cmd.Flags().GetString("reason")
+		restore, _ := cmd.Flags().GetBool("restore")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		repo := stores.Breadcrumbs
+		entityType, err := lookupBreadcrumb(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return printTombstone(entityType, id, reason, restore)
+		}
+
+		action, status := "delete", "deleted"
+		if restore {
+			action, status = "restore", "restored"
+			err = repo.RestoreBreadcrumb(ctx, entityType, id)
+		} else {
+			err = repo.DeleteBreadcrumb(ctx, entityType, id, reason)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %w", action, entityType, err)
+		}
+		if !restore {
+			tidyEvidenceBlobs(ctx)
+		}
+
+		if outputText {
+			fmt.Printf("✓ %s %s: %s\n", entityType, status, id)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": status,
+				"type":   entityType,
+				"id":     id,
+			})
+		}
+		return nil
+	},
+}
+
+// printTombstone shows the event forget would record, without recording it
+func printTombstone(entityType, id, reason string, restore bool) error {
+	kind, ok := db.TombstoneKind(entityType, restore)
+	if !ok {
+		return fmt.Errorf("%w: cannot delete %s", db.ErrInvalid, entityType)
+	}
+	var payload interface{} = struct{}{}
+	if !restore {
+		payload = models.BreadcrumbDeletedPayload{
+			DeletedAt: float64(time.Now().UnixMilli()) / 1000.0,
+			Reason:    reason,
+		}
+	}
+
+	if outputText {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dry run: would record %s on %s %s\n", kind, entityType, id)
+		fmt.Printf("  payload: %s\n", data)
+		return nil
+	}
+	outputResult(map[string]interface{}{
+		"status":  "dry_run",
+		"type":    entityType,
+		"id":      id,
+		"event":   kind,
+		"payload": payload,
+	})
+	return nil
+}
+
+func init() {
+	forgetCmd.Flags().String("reason", "", "Why the entry is being deleted")
+	forgetCmd.Flags().Bool("restore", false, "Restore a deleted entry")
+	forgetCmd.Flags().Bool("dry-run", false, "Print the tombstone event that would be recorded without recording it")
+	rootCmd.AddCommand(forgetCmd)
+}
@@ -0,0 +1,515 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scopeHashCache memoizes scope fingerprints for a single command run,
+// so a finding checked by several context builders only hits git once
+var scopeHashCache = struct {
+	sync.Mutex
+	hashes map[string]string
+}{hashes: make(map[string]string)}
+
+// cachedScopeHash returns a previously computed fingerprint for a scope
+func cachedScopeHash(scope string) (string, bool) {
+	scopeHashCache.Lock()
+	defer scopeHashCache.Unlock()
+	hash, ok := scopeHashCache.hashes[scope]
+	return hash, ok
+}
+
+// resetScopeHashCache drops all memoized fingerprints so each command run sees fresh hashes
+func resetScopeHashCache() {
+	scopeHashCache.Lock()
+	defer scopeHashCache.Unlock()
+	scopeHashCache.hashes = make(map[string]string)
+}
+
+// storeScopeHash records a computed fingerprint for a scope
+func storeScopeHash(scope, hash string) {
+	scopeHashCache.Lock()
+	defer scopeHashCache.Unlock()
+	scopeHashCache.hashes[scope] = hash
+}
+
+// getFileGitHash returns the git blob hash for a file
+// Returns empty string if not in a git repo or file doesn't exist
+func getFileGitHash(ctx context.Context, filePath string) string {
+	// Try to get git hash for the file
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", "hash-object", filePath))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// resolveCommit expands a commit-ish (short SHA, HEAD, branch) to a full commit SHA
+func resolveCommit(ctx context.Context, ref string) (string, error) {
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}"))
+	if err != nil {
+		return "", fmt.Errorf("not a valid commit: %s", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// commitTime returns when a commit was made
+func commitTime(ctx context.Context, ref string) (time.Time, error) {
+	sha, err := resolveCommit(ctx, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	output, err := gitOutput(ctx, "log", "-1", "--format=%ct", sha)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read commit time of %s: %w", ref, err)
+	}
+	seconds, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected commit time %q for %s", output, ref)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// gitOutput runs a git command and returns its trimmed stdout
+func gitOutput(ctx context.Context, args ...string) (string, error) {
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", args...))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitRun runs a git command, returning its stdout, and logs how long it took
+func gitRun(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
+	output, err := cmd.Output()
+	logGitCall(ctx, cmd.Args[1:], start, err)
+	return output, err
+}
+
+// logGitCall records a git invocation at debug level, or at info level when it failed,
+// and as a span of the command's trace
+func logGitCall(ctx context.Context, args []string, start time.Time, err error) {
+	if trace.SpanFromContext(ctx).IsRecording() {
+		name := "git"
+		if len(args) > 0 {
+			name += " " + args[0]
+		}
+		_, span := tracer().Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(
+			attribute.StringSlice("git.args", args),
+		))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+	if err != nil {
+		slog.Info("git call failed", "args", strings.Join(args, " "), "duration", time.Since(start), "err", err)
+		return
+	}
+	slog.Debug("git call", "args", strings.Join(args, " "), "duration", time.Since(start))
+}
+
+// gitRepoRoot returns the top-level directory of the current working tree
+func gitRepoRoot(ctx context.Context) (string, error) {
+	return gitOutput(ctx, "rev-parse", "--show-toplevel")
+}
+
+// WorktreeInfo describes the git checkout the CLI is running in
+type WorktreeInfo struct {
+	Root     string // Top-level directory of the current worktree
+	MainRoot string // Top-level directory of the main worktree sharing the same common git dir
+	Branch   string // Checked-out branch, empty when detached
+	IsLinked bool   // True for worktrees created with `git worktree add`
+}
+
+// currentWorktree inspects the current checkout, returning nil outside a git repository
+func currentWorktree(ctx context.Context) *WorktreeInfo {
+	output, err := gitOutput(ctx, "rev-parse", "--show-toplevel", "--git-dir", "--git-common-dir")
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) != 3 {
+		return nil
+	}
+
+	// git-dir and common-dir may be reported relative to the working directory
+	gitDir, err1 := filepath.Abs(lines[1])
+	commonDir, err2 := filepath.Abs(lines[2])
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+
+	info := &WorktreeInfo{
+		Root:     lines[0],
+		MainRoot: lines[0],
+		IsLinked: filepath.Clean(gitDir) != filepath.Clean(commonDir),
+	}
+	if info.IsLinked {
+		// The main worktree's .git directory is the common dir
+		info.MainRoot = filepath.Dir(commonDir)
+	}
+	if branch, err := gitOutput(ctx, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		info.Branch = branch
+	}
+	return info
+}
+
+// sharedMemoryDir returns the repository's .memory directory when running in a
+// subdirectory or linked worktree that has none of its own, so every checkout and
+// sub-project shares one database. Returns empty string otherwise.
+func sharedMemoryDir(ctx context.Context) string {
+	if _, err := os.Stat(".memory"); err == nil {
+		return ""
+	}
+	wt := currentWorktree(ctx)
+	if wt == nil {
+		return ""
+	}
+	for _, root := range []string{wt.Root, wt.MainRoot} {
+		dir := filepath.Join(root, ".memory")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// changedFilesBetween lists repo-relative paths that differ between two revisions
+func changedFilesBetween(ctx context.Context, from, to string) ([]string, error) {
+	output, err := gitOutput(ctx, "diff", "--name-only", from, to)
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s %s failed: %w", from, to, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// stagedFiles lists repo-relative paths staged for the next commit
+func stagedFiles(ctx context.Context) ([]string, error) {
+	output, err := gitOutput(ctx, "diff", "--cached", "--name-only")
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached failed: %w", err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// changedFilesInCommit lists repo-relative paths touched by a single commit
+func changedFilesInCommit(ctx context.Context, ref string) ([]string, error) {
+	output, err := gitOutput(ctx, "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", ref)
+	if err != nil {
+		return nil, fmt.Errorf("git diff-tree %s failed: %w", ref, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// workingTreeChanges lists repo-relative paths added or modified in the working tree
+// or index, including untracked files. Deleted files are left out.
+func workingTreeChanges(ctx context.Context) ([]string, error) {
+	// Not trimmed: the first record may start with a blank status column
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", "status", "--porcelain", "-z", "--untracked-files=all"))
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	var paths []string
+	records := strings.Split(string(output), "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 4 {
+			continue
+		}
+		status, path := record[:2], record[3:]
+		// Renames and copies are followed by their original path
+		if status[0] == 'R' || status[0] == 'C' {
+			i++
+		}
+		if strings.Contains(status, "D") {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// normalizeScopePath converts a file scope to a path relative to the repository root
+// so it can be compared with paths reported by git
+func normalizeScopePath(scope, repoRoot string) string {
+	if isURLScope(scope) {
+		return scope
+	}
+	if filepath.IsAbs(scope) {
+		if rel, err := filepath.Rel(repoRoot, scope); err == nil {
+			scope = rel
+		}
+	} else if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(repoRoot, filepath.Join(cwd, scope)); err == nil {
+			scope = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(scope))
+}
+
+// recheckFindingsForFiles re-hashes scoped findings whose files appear in paths,
+// flags every changed one with a single bulk update and returns them
+func recheckFindingsForFiles(ctx context.Context, projectID string, paths []string) ([]*models.Finding, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	repoRoot, err := gitRepoRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+
+	touched := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		touched[filepath.ToSlash(p)] = true
+	}
+
+	repo := stores.Breadcrumbs
+	findings, err := repo.ListFindingsWithStaleness(ctx, projectID, "", 10000)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*models.Finding
+	for _, f := range findings {
+		if f.SubjectGitHash != nil && scopeTouched(f, touched, repoRoot) {
+			candidates = append(candidates, f)
+		}
+	}
+	primeFindingHashes(ctx, candidates)
+
+	var changed []*models.Finding
+	var newlyChanged []string
+	for _, f := range candidates {
+		if f.FileChangedDetectedAt != nil {
+			changed = append(changed, f)
+		} else if checkFileChanged(ctx, f.ScopeKind(), *f.Subject, *f.SubjectGitHash) {
+			changed = append(changed, f)
+			newlyChanged = append(newlyChanged, f.ID)
+		}
+	}
+
+	if len(newlyChanged) > 0 {
+		now := float64(time.Now().UnixMilli()) / 1000.0
+		if _, err := repo.MarkFindingsFileChanged(ctx, newlyChanged, now); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
+
+// scopeTouched reports whether any of the touched paths is a finding's file, or lies
+// under its directory
+func scopeTouched(f *models.Finding, touched map[string]bool, repoRoot string) bool {
+	switch f.ScopeKind() {
+	case models.ScopeFile:
+		return touched[normalizeScopePath(*f.Subject, repoRoot)]
+	case models.ScopeDir:
+		dir := normalizeScopePath(*f.Subject, repoRoot)
+		if dir == "." {
+			return len(touched) > 0
+		}
+		for p := range touched {
+			if strings.HasPrefix(p, dir+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SessionTrailerKey is the commit trailer that records which memory session produced a commit
+const SessionTrailerKey = "Memory-Session"
+
+// parseSessionTrailers extracts Memory-Session trailer values from a commit message
+func parseSessionTrailers(message string) []string {
+	var sessions []string
+	prefix := strings.ToLower(SessionTrailerKey) + ":"
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(line), prefix) {
+			if value := strings.TrimSpace(line[len(prefix):]); value != "" {
+				sessions = append(sessions, value)
+			}
+		}
+	}
+	return sessions
+}
+
+// SessionCommit is a commit annotated with the memory sessions that produced it
+type SessionCommit struct {
+	SHA        string   `json:"sha"`
+	Subject    string   `json:"subject"`
+	SessionIDs []string `json:"session_ids"`
+}
+
+// listSessionCommits returns commits in a revision range that carry a Memory-Session trailer
+func listSessionCommits(ctx context.Context, revRange string, limit int) ([]SessionCommit, error) {
+	args := []string{"log", "--format=%H%x1f%s%x1f%B%x1e", fmt.Sprintf("--max-count=%d", limit)}
+	if revRange != "" {
+		args = append(args, revRange)
+	}
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", args...))
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var commits []SessionCommit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		sessions := parseSessionTrailers(fields[2])
+		if len(sessions) == 0 {
+			continue
+		}
+		commits = append(commits, SessionCommit{SHA: fields[0], Subject: fields[1], SessionIDs: sessions})
+	}
+	return commits, nil
+}
+
+// primeFileGitHashes computes blob hashes for many files with a single
+// `git hash-object --stdin-paths` call and stores them in the cache
+func primeFileGitHashes(ctx context.Context, paths []string) {
+	var pending []string
+	seen := make(map[string]bool)
+	for _, p := range paths {
+		if p == "" || isURLScope(p) || seen[p] {
+			continue
+		}
+		seen[p] = true
+		if _, ok := cachedScopeHash(p); ok {
+			continue
+		}
+		// hash-object aborts the whole batch on a missing file, so filter first
+		info, err := os.Stat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			storeScopeHash(p, "")
+			continue
+		}
+		pending = append(pending, p)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "hash-object", "--stdin-paths")
+	cmd.Stdin = strings.NewReader(strings.Join(pending, "\n") + "\n")
+	output, err := gitRun(ctx, cmd)
+	if err != nil {
+		return // Leave uncached; callers fall back to per-file hashing
+	}
+
+	hashes := strings.Fields(string(output))
+	if len(hashes) != len(pending) {
+		return
+	}
+	for i, p := range pending {
+		storeScopeHash(p, hashes[i])
+	}
+}
+
+// primeFindingHashes batches hash computation for every scoped finding, and records
+// the findings whose scope has changed in one write rather than one per finding
+func primeFindingHashes(ctx context.Context, findings []*models.Finding) {
+	changed, detectedAt := flagChangedScopes(ctx, findings)
+	if len(changed) > 0 && stores != nil {
+		if _, err := stores.Breadcrumbs.MarkFindingsFileChanged(ctx, changed, detectedAt); err != nil {
+			slog.Warn("failed to flag changed files", "findings", len(changed), "err", err)
+		}
+	}
+}
+
+// flagChangedScopes marks the scoped findings whose scope has changed since they were
+// verified, in memory only, and returns their IDs and the detection time. Previews that mustn't write use it
+// in place of primeFindingHashes; findingFileChanged then has nothing left to record.
+func flagChangedScopes(ctx context.Context, findings []*models.Finding) ([]string, float64) {
+	var paths, urls []string
+	for _, f := range findings {
+		// Findings already flagged as changed never need re-hashing
+		if f.SubjectGitHash == nil || f.FileChangedDetectedAt != nil {
+			continue
+		}
+		switch f.ScopeKind() {
+		case models.ScopeFile:
+			paths = append(paths, *f.Subject)
+		case models.ScopeURL:
+			urls = append(urls, *f.Subject)
+		}
+	}
+	primeFileGitHashes(ctx, paths)
+	primeURLFingerprints(ctx, urls)
+
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	var changed []string
+	for _, f := range findings {
+		if f.Subject != nil && f.SubjectGitHash != nil && f.FileChangedDetectedAt == nil &&
+			checkFileChanged(ctx, f.ScopeKind(), *f.Subject, *f.SubjectGitHash) {
+			f.FileChangedDetectedAt = &now
+			changed = append(changed, f.ID)
+		}
+	}
+	return changed, now
+}
+
+// findingFileChanged reports whether a finding's scope has changed since it was verified.
+// The first detection is persisted so subsequent runs skip hashing until the finding is re-verified.
+func findingFileChanged(ctx context.Context, f *models.Finding) bool {
+	if f.FileChangedDetectedAt != nil {
+		return true
+	}
+	if f.Subject == nil || f.SubjectGitHash == nil {
+		return false
+	}
+	if !checkFileChanged(ctx, f.ScopeKind(), *f.Subject, *f.SubjectGitHash) {
+		return false
+	}
+
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	f.FileChangedDetectedAt = &now
+	if stores != nil {
+		if err := stores.Breadcrumbs.MarkFindingFileChanged(ctx, f.ID, now); err != nil {
+			slog.Warn("failed to flag changed file", "finding", f.ID, "err", err)
+		}
+	}
+	return true
+}
+
+// checkFileChanged compares a stored scope hash with the scope's current fingerprint,
+// taken as getScopeHash takes it for the kind of scope
+func checkFileChanged(ctx context.Context, kind models.ScopeKind, filePath string, storedHash string) bool {
+	if storedHash == "" || filePath == "" {
+		return false // Can't determine change without both values
+	}
+	currentHash := getScopeHash(ctx, kind, filePath)
+	if currentHash == "" {
+		return false // File not in git or URL unreachable, can't determine
+	}
+	return currentHash != storedHash
+}
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// gitCmd groups git integration helpers
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Git integration helpers",
+	Long: `Helpers that connect memory sessions with git history.
+
+Examples:
+  memory git prepare-commit-msg .git/COMMIT_EDITMSG
+  memory git commits main..HEAD
+  memory git sync`,
+}
+
+// gitPrepareCommitMsgCmd appends the active session as a commit trailer
+var gitPrepareCommitMsgCmd = &cobra.Command{
+	Use:   "prepare-commit-msg [msg-file] [source] [sha]",
+	Short: "Append a Memory-Session trailer to a commit message",
+	Long: `Append "Memory-Session: <id>" to a commit message file.
+
+Designed to be called from git's prepare-commit-msg hook, which passes the
+message file path as the first argument. Does nothing when no session is
+active so commits are never blocked.
+
+Example (.git/hooks/prepare-commit-msg):
+  #!/bin/sh
+  memory git prepare-commit-msg "$@"`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		trailer, err := addSessionTrailer(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if outputText && trailer != "" {
+			fmt.Printf("✓ Added trailer: %s\n", trailer)
+		}
+		return nil
+	},
+}
+
+// addSessionTrailer appends the active session trailer to a commit message file.
+// Returns the trailer added, or empty string when no session is active.
+func addSessionTrailer(ctx context.Context, msgFile string) (string, error) {
+	active, err := loadActiveSession(ctx)
+	if err != nil {
+		return "", nil // No session, nothing to record
+	}
+
+	trailer := fmt.Sprintf("%s: %s", SessionTrailerKey, active.SessionID)
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "git", "interpret-trailers", "--in-place",
+		"--if-exists", "addIfDifferent", "--trailer", trailer, msgFile)
+	out, err := cmd.CombinedOutput()
+	logGitCall(ctx, cmd.Args[1:], start, err)
+	if err != nil {
+		return "", fmt.Errorf("failed to add trailer: %s", out)
+	}
+	return trailer, nil
+}
+
+// gitHookCmd is the entry point invoked by hooks written by `memory hooks install`
+var gitHookCmd = &cobra.Command{
+	Use:   "hook [name] [args...]",
+	Short: "Handle a git hook event",
+	Long: `Handle a git hook event. Called by hooks installed with 'memory hooks install'.
+
+Supported hooks:
+  pre-commit          List findings scoped to staged files whose content changed
+  prepare-commit-msg  Append the Memory-Session trailer
+  post-commit         Re-check findings scoped to files in the new commit
+  post-merge          Re-check findings scoped to files changed by the merge
+  post-checkout       Re-check findings scoped to files that differ between branches`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		hook := args[0]
+		hookArgs := args[1:]
+
+		var files []string
+		var err error
+		switch hook {
+		case "pre-commit":
+			return reportStagedFindings(ctx)
+		case "prepare-commit-msg":
+			if len(hookArgs) == 0 {
+				return fmt.Errorf("prepare-commit-msg requires the message file path")
+			}
+			_, err := addSessionTrailer(ctx, hookArgs[0])
+			return err
+		case "post-commit":
+			files, err = changedFilesInCommit(ctx, "HEAD")
+		case "post-merge":
+			files, err = changedFilesBetween(ctx, "ORIG_HEAD", "HEAD")
+		case "post-checkout":
+			// Args are prev-HEAD, new-HEAD and a flag that is 1 for branch checkouts
+			if len(hookArgs) < 3 || hookArgs[2] != "1" || hookArgs[0] == hookArgs[1] {
+				return nil
+			}
+			files, err = changedFilesBetween(ctx, hookArgs[0], hookArgs[1])
+		default:
+			return fmt.Errorf("unsupported hook: %s", hook)
+		}
+		if err != nil {
+			return err
+		}
+
+		return syncChangedFiles(ctx, hook, files)
+	},
+}
+
+// reportStagedFindings flags the findings scoped to staged files whose content
+// changed and, in text mode, lists them so they can be verified or updated before
+// the commit is made. Prints nothing when none changed.
+func reportStagedFindings(ctx context.Context) error {
+	files, err := stagedFiles(ctx)
+	if err != nil {
+		return err
+	}
+	project, err := getOrCreateDefaultProject(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	changed, err := recheckFindingsForFiles(ctx, project.ID, files)
+	if err != nil {
+		return err
+	}
+
+	if !outputText {
+		ids := make([]string, 0, len(changed))
+		for _, f := range changed {
+			ids = append(ids, f.ID)
+		}
+		outputResult(map[string]interface{}{
+			"source":           "pre-commit",
+			"files_staged":     len(files),
+			"findings_flagged": ids,
+		})
+		return nil
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	fmt.Printf("memory: %d finding(s) about staged files may be out of date:\n", len(changed))
+	for _, f := range changed {
+		fmt.Printf("  %s  %s\n", f.ID[:8], truncateText(f.Finding, 70))
+	}
+	fmt.Println("  Verify with 'memory verify --id <id>', adding --update \"...\" if it no longer holds")
+	return nil
+}
+
+// gitSyncCmd flags findings about files changed between two revisions
+var gitSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Flag findings about files changed by a pull, merge or checkout",
+	Long: `Diff two revisions and flag every scoped finding whose file changed, so
+knowledge about files teammates modified is down-ranked immediately.
+
+Defaults to comparing the previous HEAD position (HEAD@{1}) with HEAD,
+which covers the last pull, merge, rebase or branch switch.
+
+Examples:
+  memory git sync
+  memory git sync --from origin/main@{1} --to origin/main`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		files, err := changedFilesBetween(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		return syncChangedFiles(ctx, "sync", files)
+	},
+}
+
+// syncChangedFiles flags findings scoped to the given files and reports the result
+func syncChangedFiles(ctx context.Context, source string, files []string) error {
+	project, err := getOrCreateDefaultProject(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	changed, err := recheckFindingsForFiles(ctx, project.ID, files)
+	if err != nil {
+		return err
+	}
+
+	if !outputText {
+		ids := make([]string, 0, len(changed))
+		for _, f := range changed {
+			ids = append(ids, f.ID)
+		}
+		outputResult(map[string]interface{}{
+			"source":           source,
+			"files_changed":    len(files),
+			"findings_flagged": ids,
+		})
+	} else {
+		fmt.Printf("%s: %d file(s) changed, %d finding(s) flagged\n", source, len(files), len(changed))
+		for _, f := range changed {
+			fmt.Printf("  ⚠ %s\n", f.Finding)
+		}
+	}
+	return nil
+}
+
+// gitCommitsCmd lists commits correlated with memory sessions
+var gitCommitsCmd = &cobra.Command{
+	Use:   "commits [revision-range]",
+	Short: "List commits produced by memory sessions",
+	Long: `List commits carrying a Memory-Session trailer together with the session objective.
+
+Examples:
+  memory git commits
+  memory git commits main..HEAD -n 20`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		limit, _ := cmd.Flags().GetInt("limit")
+		revRange := ""
+		if len(args) > 0 {
+			revRange = args[0]
+		}
+
+		commits, err := listSessionCommits(ctx, revRange, limit)
+		if err != nil {
+			return err
+		}
+
+		sessionRepo := stores.Sessions
+		objectives := make(map[string]string)
+		for _, c := range commits {
+			for _, id := range c.SessionIDs {
+				if _, ok := objectives[id]; ok {
+					continue
+				}
+				objectives[id] = ""
+				if s, err := sessionRepo.Get(ctx, id); err == nil && s != nil && s.Subject != nil {
+					objectives[id] = *s.Subject
+				}
+			}
+		}
+
+		if !outputText {
+			items := make([]map[string]interface{}, 0, len(commits))
+			for _, c := range commits {
+				sessions := make([]map[string]interface{}, 0, len(c.SessionIDs))
+				for _, id := range c.SessionIDs {
+					sessions = append(sessions, map[string]interface{}{
+						"session_id": id,
+						"objective":  objectives[id],
+					})
+				}
+				items = append(items, map[string]interface{}{
+					"sha":      c.SHA,
+					"subject":  c.Subject,
+					"sessions": sessions,
+				})
+			}
+			outputResult(map[string]interface{}{
+				"commits": items,
+				"count":   len(items),
+			})
+			return nil
+		}
+
+		if len(commits) == 0 {
+			fmt.Println("No commits with Memory-Session trailers found.")
+			return nil
+		}
+		for _, c := range commits {
+			fmt.Printf("%s %s\n", shortSHA(c.SHA), c.Subject)
+			for _, id := range c.SessionIDs {
+				if objectives[id] != "" {
+					fmt.Printf("    session: %s (%s)\n", id, objectives[id])
+				} else {
+					fmt.Printf("    session: %s\n", id)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	gitCommitsCmd.Flags().IntP("limit", "n", 100, "Maximum number of commits to scan")
+	gitSyncCmd.Flags().String("from", "HEAD@{1}", "Revision to diff from")
+	gitSyncCmd.Flags().String("to", "HEAD", "Revision to diff to")
+
+	gitCmd.AddCommand(gitPrepareCommitMsgCmd, gitCommitsCmd, gitHookCmd, gitSyncCmd)
+	rootCmd.AddCommand(gitCmd)
+}
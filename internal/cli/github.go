@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// githubAPITimeout bounds GitHub API requests
+const githubAPITimeout = 15 * time.Second
+
+// githubRemotePattern extracts owner/name from SSH and HTTPS GitHub remote URLs
+var githubRemotePattern = regexp.MustCompile(`github\.com[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// taskListPattern matches unchecked Markdown task list items
+var taskListPattern = regexp.MustCompile(`^\s*[-*]\s+\[ \]\s+(.+)$`)
+
+// GitHubIssue is the subset of the GitHub issue payload memory uses
+type GitHubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+}
+
+// githubAPIBase returns the API root, overridable for GitHub Enterprise
+func githubAPIBase() string {
+	if base := os.Getenv("GITHUB_API_URL"); base != "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	return "https://api.github.com"
+}
+
+// githubToken returns the API token from the environment (GITHUB_TOKEN or GH_TOKEN)
+func githubToken() string {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// githubRepo resolves owner/name from an explicit value or the origin remote
+func githubRepo(ctx context.Context, explicit string) (string, error) {
+	if explicit != "" {
+		if strings.Count(explicit, "/") != 1 {
+			return "", fmt.Errorf("repository must be owner/name: %s", explicit)
+		}
+		return explicit, nil
+	}
+	remote, err := gitOutput(ctx, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("no origin remote; pass --repo owner/name")
+	}
+	m := githubRemotePattern.FindStringSubmatch(remote)
+	if m == nil {
+		return "", fmt.Errorf("origin is not a GitHub remote: %s", remote)
+	}
+	return m[1] + "/" + m[2], nil
+}
+
+// githubIssueURL builds the web URL of an issue without calling the API
+func githubIssueURL(repo string, number int) string {
+	return fmt.Sprintf("https://github.com/%s/issues/%d", repo, number)
+}
+
+// githubRequest performs an authenticated GitHub API call and decodes the JSON response into out
+func githubRequest(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, githubAPIBase()+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: githubAPITimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("GitHub API %s %s: %d %s", method, path, resp.StatusCode, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchGitHubIssue retrieves an issue by number
+func fetchGitHubIssue(ctx context.Context, repo string, number int) (*GitHubIssue, error) {
+	var issue GitHubIssue
+	if err := githubRequest(ctx, "GET", fmt.Sprintf("/repos/%s/issues/%d", repo, number), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// parseTaskList returns the unchecked task list items in a Markdown body
+func parseTaskList(body string) []string {
+	var tasks []string
+	for _, line := range strings.Split(body, "\n") {
+		if m := taskListPattern.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			tasks = append(tasks, strings.TrimSpace(m[1]))
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"context"
+	"slices"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// globalProjectName names the project holding knowledge that applies to every project,
+// like org-wide toolchain versions or CI rules. The brackets keep it from clashing with
+// a directory name.
+const globalProjectName = "[global]"
+
+// getOrCreateGlobalProject returns the project global knowledge is recorded in
+func getOrCreateGlobalProject(ctx context.Context) (*models.Project, error) {
+	return getOrCreateProjectByName(ctx, globalProjectName)
+}
+
+// withGlobalKnowledge adds the global project to the projects a session's context
+// merges knowledge from, once anything global has been recorded
+func withGlobalKnowledge(ctx context.Context, projectID string, inheritFrom []string) []string {
+	global, err := stores.Projects.GetByName(ctx, globalProjectName)
+	if err != nil || global.ID == projectID || slices.Contains(inheritFrom, global.ID) {
+		return inheritFrom
+	}
+	traceContext(ctx, "merged global knowledge", "project", global.ID)
+	return append(inheritFrom, global.ID)
+}
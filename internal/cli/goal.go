@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// defaultGoalScope is used for goals created from the CLI
+var defaultGoalScope = models.ScopeVector{Breadth: 0.5, Duration: 0.5, Coordination: 0.0}
+
+// linkJiraTicket links a goal or session to a Jira ticket, fetching its summary
+func linkJiraTicket(ctx context.Context, cfg *JiraConfig, targetType models.IssueTargetType, targetID string, issue *JiraIssue) (*models.IssueLink, error) {
+	projectKey, number, err := parseJiraKey(issue.Key)
+	if err != nil {
+		return nil, err
+	}
+	link := models.NewIssueLink(targetType, targetID, projectKey, number)
+	link.Provider = models.IssueProviderJira
+	link.Title = &issue.Fields.Summary
+	link.URL = cfg.browseURL(issue.Key)
+	if err := stores.IssueLinks.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// goalCmd groups goal commands
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Manage goals within the current session",
+}
+
+// goalAddCmd creates a goal in the active session
+var goalAddCmd = &cobra.Command{
+	Use:   "add [objective]",
+	Short: "Add a goal to the current session",
+	Long: `Add a goal to the current session, optionally created from a Jira ticket.
+
+The new goal becomes the active one: breadcrumbs logged afterwards are recorded under
+it, and start and status leave out breadcrumbs recorded under other goals. Switch
+goals with 'memory goal use'.
+
+With --from-jira the ticket summary becomes the objective and the goal is linked to
+the ticket. Requires JIRA_URL, JIRA_EMAIL and JIRA_API_TOKEN.
+
+Examples:
+  memory goal add "Support refresh tokens"
+  memory goal add --from-jira AUTH-142`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		jiraKey, _ := cmd.Flags().GetString("from-jira")
+		if len(args) == 0 && jiraKey == "" {
+			return fmt.Errorf("an objective or --from-jira is required")
+		}
+
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+
+		var cfg *JiraConfig
+		var issue *JiraIssue
+		if jiraKey != "" {
+			if cfg, err = loadJiraConfig(); err != nil {
+				return err
+			}
+			if issue, err = cfg.fetchIssue(ctx, strings.ToUpper(jiraKey)); err != nil {
+				return fmt.Errorf("failed to fetch %s: %w", jiraKey, err)
+			}
+		}
+
+		objective := ""
+		if len(args) > 0 {
+			objective = args[0]
+		} else {
+			objective = fmt.Sprintf("%s %s", issue.Key, issue.Fields.Summary)
+		}
+
+		goal := models.NewGoal(active.SessionID, objective, defaultGoalScope)
+		if err := stores.Goals.Create(ctx, goal); err != nil {
+			return fmt.Errorf("failed to create goal: %w", err)
+		}
+		if active.ProjectID != "" {
+			stores.Projects.IncrementGoals(ctx, active.ProjectID)
+		}
+		active.CurrentGoalID = goal.ID
+		if err := saveActiveSession(ctx, active); err != nil {
+			return fmt.Errorf("failed to save active session: %w", err)
+		}
+
+		var link *models.IssueLink
+		if issue != nil {
+			if link, err = linkJiraTicket(ctx, cfg, models.IssueTargetGoal, goal.ID, issue); err != nil {
+				return fmt.Errorf("failed to link %s: %w", issue.Key, err)
+			}
+		}
+
+		if outputText {
+			fmt.Printf("✓ Goal: %s\n", objective)
+			fmt.Printf("  ID: %s\n", goal.ID)
+			if link != nil {
+				fmt.Printf("  Linked: %s (%s)\n", link.Ref(), link.URL)
+			}
+		} else {
+			result := map[string]interface{}{
+				"status":    "created",
+				"goal_id":   goal.ID,
+				"objective": objective,
+			}
+			if link != nil {
+				result["issue"] = link
+			}
+			outputResult(result)
+		}
+		return nil
+	},
+}
+
+// goalListCmd lists goals in the active session
+var goalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List goals in the current session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		all, _ := cmd.Flags().GetBool("all")
+
+		sessionID, currentID := "", ""
+		if !all {
+			active, err := requireActiveSession(ctx)
+			if err != nil {
+				return err
+			}
+			sessionID, currentID = active.SessionID, active.CurrentGoalID
+		}
+
+		goals, err := stores.Goals.List(ctx, sessionID, nil, 50)
+		if err != nil {
+			return fmt.Errorf("failed to list goals: %w", err)
+		}
+
+		if !outputText {
+			if goals == nil {
+				goals = []*models.Goal{}
+			}
+			result := map[string]interface{}{
+				"goals": goals,
+				"count": len(goals),
+			}
+			if currentID != "" {
+				result["current_goal_id"] = currentID
+			}
+			outputResult(result)
+			return nil
+		}
+
+		if len(goals) == 0 {
+			fmt.Println("No goals.")
+			return nil
+		}
+		for _, g := range goals {
+			icon := "○"
+			if g.IsCompleted {
+				icon = "✓"
+			} else if g.ID == currentID {
+				icon = "◎"
+			}
+			fmt.Printf("  %s %s  %s\n", icon, g.ID[:8], g.Objective)
+		}
+		return nil
+	},
+}
+
+// goalDoneCmd completes a goal and syncs linked Jira tickets
+var goalDoneCmd = &cobra.Command{
+	Use:   "done [goal-id]",
+	Short: "Mark a goal complete",
+	Long: `Mark a goal complete. Linked Jira tickets are transitioned to JIRA_DONE_STATUS
+(default "Done") when Jira is configured; use --no-sync to skip.
+
+Examples:
+  memory goal done 3f2a9c1e-...
+  memory goal done 3f2a9c1e-... --no-sync`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+
+		repo := stores.Goals
+		goal, err := repo.Get(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get goal: %w", err)
+		}
+		if err := repo.Complete(ctx, goal.ID, ""); err != nil {
+			return fmt.Errorf("failed to complete goal: %w", err)
+		}
+		if active, err := loadActiveSession(ctx); err == nil && active.CurrentGoalID == goal.ID {
+			active.CurrentGoalID = ""
+			if err := saveActiveSession(ctx, active); err != nil {
+				return fmt.Errorf("failed to save active session: %w", err)
+			}
+		}
+
+		// Status sync is best effort: the goal is complete either way
+		var synced, syncErrors []string
+		links, _ := stores.IssueLinks.ListByTarget(ctx, models.IssueTargetGoal, goal.ID)
+		if !noSync && len(links) > 0 {
+			cfg, err := loadJiraConfig()
+			for _, l := range links {
+				if l.Provider != models.IssueProviderJira {
+					continue
+				}
+				if err != nil {
+					syncErrors = append(syncErrors, err.Error())
+					break
+				}
+				if _, terr := cfg.transitionIssue(ctx, l.Ref(), cfg.DoneStatus); terr != nil {
+					syncErrors = append(syncErrors, terr.Error())
+				} else {
+					synced = append(synced, l.Ref())
+				}
+			}
+		}
+
+		if outputText {
+			fmt.Printf("✓ Goal complete: %s\n", goal.Objective)
+			for _, ref := range synced {
+				fmt.Printf("  Synced %s\n", ref)
+			}
+			for _, e := range syncErrors {
+				fmt.Printf("  ⚠ %s\n", e)
+			}
+		} else {
+			result := map[string]interface{}{
+				"status":  "completed",
+				"goal_id": goal.ID,
+			}
+			if len(synced) > 0 {
+				result["synced"] = synced
+			}
+			if len(syncErrors) > 0 {
+				result["sync_errors"] = syncErrors
+			}
+			outputResult(result)
+		}
+		return nil
+	},
+}
+
+// goalUseCmd switches the active goal
+var goalUseCmd = &cobra.Command{
+	Use:   "use [goal-id]",
+	Short: "Switch the goal breadcrumbs are recorded under",
+	Long: `Make a goal the active one, so breadcrumbs are recorded under it and start and
+status leave out breadcrumbs from other goals. Goals from earlier sessions can be
+picked up again. With --clear, no goal is active and the context covers the whole
+project.
+
+Examples:
+  memory goal use 3f2a9c1e-...
+  memory goal use --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		clearGoal, _ := cmd.Flags().GetBool("clear")
+		if clearGoal == (len(args) == 1) {
+			return fmt.Errorf("%w: give a goal ID or --clear", db.ErrInvalid)
+		}
+		active, err := requireActiveSession(ctx)
+		if err != nil {
+			return err
+		}
+
+		var goal *models.Goal
+		if !clearGoal {
+			if goal, err = stores.Goals.Get(ctx, args[0]); err != nil {
+				return fmt.Errorf("failed to get goal: %w", err)
+			}
+			if goal.IsCompleted {
+				return fmt.Errorf("%w: goal %s is already complete", db.ErrInvalid, goal.ID)
+			}
+			active.CurrentGoalID = goal.ID
+		} else {
+			active.CurrentGoalID = ""
+		}
+		if err := saveActiveSession(ctx, active); err != nil {
+			return fmt.Errorf("failed to save active session: %w", err)
+		}
+
+		if outputText {
+			if goal == nil {
+				fmt.Println("✓ No active goal")
+			} else {
+				fmt.Printf("✓ Active goal: %s\n", goal.Objective)
+			}
+			return nil
+		}
+		result := map[string]interface{}{"status": "cleared"}
+		if goal != nil {
+			result = map[string]interface{}{
+				"status":    "active",
+				"goal_id":   goal.ID,
+				"objective": goal.Objective,
+			}
+		}
+		outputResult(result)
+		return nil
+	},
+}
+
+func init() {
+	goalUseCmd.Flags().Bool("clear", false, "Clear the active goal")
+	goalAddCmd.Flags().String("from-jira", "", "Jira ticket key to create the goal from")
+	goalListCmd.Flags().Bool("all", false, "List goals from all sessions")
+	goalDoneCmd.Flags().Bool("no-sync", false, "Don't transition linked Jira tickets")
+	goalCmd.AddCommand(goalAddCmd, goalListCmd, goalDoneCmd, goalUseCmd)
+	rootCmd.AddCommand(goalCmd)
+}
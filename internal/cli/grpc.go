@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	memoryv1 "github.com/AbdouB/memory/api/memory/v1"
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/AbdouB/memory/internal/scrub"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcQueryLimit is how many breadcrumbs of each type Query returns when no limit is given
+const grpcQueryLimit = 50
+
+// grpcQueryScanLimit caps how many breadcrumbs of each type Query searches
+const grpcQueryScanLimit = 1000
+
+// grpcWatchInterval is how often Watch looks for new events. Other processes write
+// the database too, so new events are polled for rather than signalled.
+const grpcWatchInterval = 500 * time.Millisecond
+
+// grpcServer serves the gRPC API next to the HTTP sync endpoint, with the same tokens
+type grpcServer struct {
+	memoryv1.UnimplementedMemoryServiceServer
+	token    string          // Full access; empty when only API tokens are accepted
+	scrubber *scrub.Scrubber // Applied to everything leaving the server
+}
+
+// newGRPCServer returns a gRPC server exposing the memory service
+func newGRPCServer(token string, scrubber *scrub.Scrubber) *grpc.Server {
+	server := grpc.NewServer()
+	memoryv1.RegisterMemoryServiceServer(server, &grpcServer{token: token, scrubber: scrubber})
+	return server
+}
+
+// access matches the call's bearer token. API tokens are read on every call so
+// revoking one takes effect at once.
+func (s *grpcServer) access(ctx context.Context) (*syncAccess, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load config: %v", err)
+	}
+	authorization := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	access := tokenAccess(authorization, s.token, cfg.Tokens)
+	if access == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing token")
+	}
+	return access, nil
+}
+
+// readableProject resolves a project by name or ID and checks the token may read it
+func (s *grpcServer) readableProject(ctx context.Context, access *syncAccess, nameOrID string) (*models.Project, error) {
+	project, err := projectByNameOrID(ctx, nameOrID)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if !access.allows(ctx, project.ID, config.AccessRead) {
+		return nil, status.Errorf(codes.PermissionDenied, "token %s may not read project %s", access.token.Name, project.Name)
+	}
+	return project, nil
+}
+
+// Query streams the project's breadcrumbs matching the search
+func (s *grpcServer) Query(req *memoryv1.QueryRequest, stream memoryv1.MemoryService_QueryServer) error {
+	defer queryDuration.ObserveSince(time.Now())
+	ctx := stream.Context()
+	if commandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, commandTimeout)
+		defer cancel()
+	}
+	access, err := s.access(ctx)
+	if err != nil {
+		return err
+	}
+	if req.Project == "" {
+		return status.Error(codes.InvalidArgument, "project is required")
+	}
+	types := req.Types
+	if len(types) == 0 {
+		types = []string{models.EntityFinding, models.EntityUnknown, models.EntityDeadEnd}
+	}
+	for _, t := range types {
+		if t != models.EntityFinding && t != models.EntityUnknown && t != models.EntityDeadEnd {
+			return status.Errorf(codes.InvalidArgument, "unknown breadcrumb type %q (use finding, unknown or dead_end)", t)
+		}
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = grpcQueryLimit
+	}
+	project, err := s.readableProject(ctx, access, req.Project)
+	if err != nil {
+		return err
+	}
+
+	search := strings.ToLower(req.Search)
+	matches := func(texts ...string) bool {
+		for _, text := range texts {
+			if strings.Contains(strings.ToLower(text), search) {
+				return true
+			}
+		}
+		return false
+	}
+	var results []*memoryv1.Breadcrumb
+	repo := stores.Breadcrumbs
+	if slices.Contains(types, models.EntityFinding) {
+		findings, err := repo.ListFindings(ctx, project.ID, "", grpcQueryScanLimit)
+		if err != nil {
+			return grpcError(err)
+		}
+		sent := 0
+		for _, f := range findings {
+			if sent < limit && matches(f.Finding) {
+				b := s.breadcrumb(models.EntityFinding, f.ID, f.ProjectID, f.SessionID, f.Finding, f.Subject, f.Impact, f.CreatedTimestamp, f.Tags)
+				b.Confidence = f.CalculateConfidence()
+				results = append(results, b)
+				sent++
+			}
+		}
+	}
+	if slices.Contains(types, models.EntityUnknown) {
+		resolved := false
+		unknowns, err := repo.ListUnknowns(ctx, project.ID, "", &resolved, grpcQueryScanLimit)
+		if err != nil {
+			return grpcError(err)
+		}
+		sent := 0
+		for _, u := range unknowns {
+			if sent < limit && matches(u.Unknown) {
+				results = append(results, s.breadcrumb(models.EntityUnknown, u.ID, u.ProjectID, u.SessionID, u.Unknown, u.Subject, u.Impact, u.CreatedTimestamp, u.Tags))
+				sent++
+			}
+		}
+	}
+	if slices.Contains(types, models.EntityDeadEnd) {
+		deadEnds, err := repo.ListDeadEnds(ctx, project.ID, "", grpcQueryScanLimit)
+		if err != nil {
+			return grpcError(err)
+		}
+		sent := 0
+		for _, d := range deadEnds {
+			if sent < limit && matches(d.Approach, d.WhyFailed) {
+				b := s.breadcrumb(models.EntityDeadEnd, d.ID, d.ProjectID, d.SessionID, d.Approach, d.Subject, d.Impact, d.CreatedTimestamp, d.Tags)
+				b.WhyFailed = s.scrubber.String(d.WhyFailed)
+				results = append(results, b)
+				sent++
+			}
+		}
+	}
+
+	for _, b := range results {
+		if err := stream.Send(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// breadcrumb converts the fields shared by all breadcrumb types, scrubbing the text
+func (s *grpcServer) breadcrumb(entityType, id, projectID, sessionID, text string, subject *string, impact, created float64, tags models.TagSet) *memoryv1.Breadcrumb {
+	b := &memoryv1.Breadcrumb{
+		Id:               id,
+		Type:             entityType,
+		ProjectId:        projectID,
+		SessionId:        sessionID,
+		Text:             s.scrubber.String(text),
+		Impact:           impact,
+		CreatedTimestamp: created,
+		Tags:             []string(tags),
+	}
+	if subject != nil {
+		b.Scope = *subject
+	}
+	return b
+}
+
+// Watch streams breadcrumb events after the cursor until the call is cancelled
+func (s *grpcServer) Watch(req *memoryv1.WatchRequest, stream memoryv1.MemoryService_WatchServer) error {
+	ctx := stream.Context()
+	access, err := s.access(ctx)
+	if err != nil {
+		return err
+	}
+	projectID := ""
+	if req.Project != "" {
+		project, err := s.readableProject(ctx, access, req.Project)
+		if err != nil {
+			return err
+		}
+		projectID = project.ID
+	}
+	var cursor int64
+	if req.After != nil {
+		cursor = *req.After
+	} else if cursor, err = stores.Sync.LatestEventSeq(ctx); err != nil {
+		return grpcError(err)
+	}
+
+	ticker := time.NewTicker(grpcWatchInterval)
+	defer ticker.Stop()
+	for {
+		batch, err := stores.Sync.SyncBatchAfter(ctx, cursor, syncPageSize)
+		if err != nil {
+			return grpcError(err)
+		}
+		eventProjects, err := stores.Sync.EventProjectIDs(ctx, batch.Events)
+		if err != nil {
+			return grpcError(err)
+		}
+		for _, ev := range batch.Events {
+			eventProject := eventProjects[ev.EntityID]
+			if projectID != "" && eventProject != projectID || !access.allows(ctx, eventProject, config.AccessRead) {
+				continue
+			}
+			payload, err := s.scrubber.JSON([]byte(ev.Payload))
+			if err != nil {
+				return grpcError(err)
+			}
+			if err := stream.Send(&memoryv1.BreadcrumbEvent{
+				Seq:        ev.Seq,
+				Id:         ev.ID,
+				EntityType: ev.EntityType,
+				EntityId:   ev.EntityID,
+				Kind:       string(ev.Kind),
+				ProjectId:  eventProject,
+				Payload:    string(payload),
+				Timestamp:  ev.Timestamp,
+				DeviceId:   ev.DeviceID,
+				Lamport:    ev.Lamport,
+			}); err != nil {
+				return err
+			}
+		}
+		cursor = batch.Cursor
+		if batch.More {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// A token revoked while watching stops the stream
+		if access, err = s.access(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcError maps repository and context errors to gRPC status codes
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, db.ErrInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, db.ErrConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// handoffCmd renders a session's handoff in different formats
+var handoffCmd = &cobra.Command{
+	Use:   "handoff",
+	Short: "Show a session handoff",
+	Long: `Show the handoff for the active session, or the most recent handed-off session
+when none is active: summary, findings, resolved and open questions, dead ends and
+artifacts.
+
+Formats:
+  json      Structured report (default)
+  markdown  Handoff summary, as posted by 'memory publish pr-comment'
+  pr        Pull request description template
+
+Examples:
+  memory handoff
+  memory handoff --format pr > pr.md
+  memory handoff --format markdown --session <session-id>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessionFlag, _ := cmd.Flags().GetString("session")
+		format, _ := cmd.Flags().GetString("format")
+
+		sessionID, err := resolveReportSession(ctx, sessionFlag)
+		if err != nil {
+			return err
+		}
+		report, err := collectSessionReport(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "json":
+			if outputText {
+				fmt.Print(renderHandoffMarkdown(report))
+			} else {
+				outputResult(report)
+			}
+		case "markdown":
+			fmt.Print(renderHandoffMarkdown(report))
+		case "pr":
+			fmt.Print(renderPRDescription(report))
+		default:
+			return fmt.Errorf("unknown format %q (use json, markdown or pr)", format)
+		}
+		return nil
+	},
+}
+
+func init() {
+	handoffCmd.Flags().String("session", "", "Session ID (defaults to active or last handoff)")
+	handoffCmd.Flags().String("format", "json", "Output format: json, markdown or pr")
+	rootCmd.AddCommand(handoffCmd)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd lists the texts a finding has had
+var historyCmd = &cobra.Command{
+	Use:   "history [finding-id]",
+	Short: "Show the earlier texts of a finding",
+	Long: `Show every text a finding has had, oldest first. 'memory verify --update' rewrites
+a finding's text; the earlier texts are kept and listed here with when they were
+written and how often they were verified before being replaced.
+
+Deleted and compacted findings keep their history, by full ID. A short ID prefix
+works for live findings, as with 'memory verify --id'.
+
+Examples:
+  memory history 3f2a9c1e --text
+  memory history 3f2a9c1e-6b0d-4c55-9f3e-1a2b3c4d5e6f`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		pick, _ := cmd.Flags().GetInt("pick")
+
+		repo := stores.Breadcrumbs
+		history, err := repo.FindingHistory(ctx, args[0])
+		if errors.Is(err, db.ErrNotFound) {
+			finding, lookupErr := lookupFinding(ctx, args[0], pick, func(shortID string) string {
+				return "memory history " + shortID
+			})
+			if lookupErr != nil {
+				return lookupErr
+			}
+			history, err = repo.FindingHistory(ctx, finding.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read history: %w", err)
+		}
+
+		if !outputText {
+			outputResult(history)
+			return nil
+		}
+		fmt.Printf("History of finding %s (%d revisions)\n", history.FindingID[:8], len(history.Revisions))
+		for i, r := range history.Revisions {
+			label := ""
+			if i == len(history.Revisions)-1 {
+				label = " (current)"
+			}
+			fmt.Printf("\n%s%s\n  %s\n", formatEventTime(r.Since), label, r.Finding)
+			if r.Verifications > 0 {
+				fmt.Printf("  verified %d time(s), last %s\n", r.Verifications, formatEventTime(*r.LastVerified))
+			}
+		}
+		if history.DeletedAt != nil {
+			fmt.Printf("\nDeleted %s", formatEventTime(*history.DeletedAt))
+			if history.DeleteReason != "" {
+				fmt.Printf(": %s", history.DeleteReason)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// formatEventTime renders an event's Unix timestamp in local time
+func formatEventTime(ts float64) string {
+	return time.UnixMilli(int64(ts * 1000)).Format("2006-01-02 15:04")
+}
+
+func init() {
+	historyCmd.Flags().Int("pick", 0, "Choose the Nth finding when a short ID matches several")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hook scripts written by memory so they can be updated or removed safely
+const hookMarker = "# Installed by memory hooks install"
+
+// managedHooks are the git hooks memory installs
+var managedHooks = []string{"pre-commit", "prepare-commit-msg", "post-commit", "post-merge", "post-checkout"}
+
+// hookBackupSuffix is appended to an existing hook's name when --force replaces it
+const hookBackupSuffix = ".pre-memory"
+
+// hookScript renders the shell script for a managed hook. A hook kept aside by
+// --force runs first and can still fail the git operation; memory never does, even
+// if it is missing or errors. pre-commit's report goes to stderr so the committer sees it.
+func hookScript(name string) string {
+	output := ">/dev/null 2>&1"
+	if name == "pre-commit" {
+		output = "--text >&2 2>/dev/null"
+	}
+	return fmt.Sprintf(`#!/bin/sh
+%s
+previous="$(dirname "$0")/%s%s"
+if [ -x "$previous" ]; then "$previous" "$@" || exit $?; fi
+command -v memory >/dev/null 2>&1 || exit 0
+memory git hook %s "$@" %s || true
+`, hookMarker, name, hookBackupSuffix, name, output)
+}
+
+// gitHooksDir returns the hooks directory for the current repository (respects core.hooksPath)
+func gitHooksDir(ctx context.Context) (string, error) {
+	dir, err := gitOutput(ctx, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return dir, nil
+}
+
+// isManagedHook reports whether a hook file was written by memory
+func isManagedHook(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), hookMarker)
+}
+
+// hooksCmd groups git hook management
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that keep memory in sync with git",
+	Long: `Manage git hooks that call back into memory on commits and merges.
+
+Installed hooks:
+  pre-commit          Lists findings scoped to staged files whose content changed, so
+                      they can be verified or updated in the same commit
+  prepare-commit-msg  Adds a Memory-Session trailer to commit messages
+  post-commit         Flags findings scoped to files in the commit if their content changed
+  post-merge          Flags findings scoped to files changed by a merge or pull
+  post-checkout       Flags findings scoped to files that differ after switching branches
+
+Existing hooks not written by memory are left alone unless --force is given; they
+are then kept as <name>.pre-memory and still run before memory's hook, and uninstall
+puts them back.
+
+Examples:
+  memory hooks install
+  memory hooks uninstall`,
+}
+
+// hooksInstallCmd writes memory's git hooks
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install git hooks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		force, _ := cmd.Flags().GetBool("force")
+
+		dir, err := gitHooksDir(ctx)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create hooks directory: %w", err)
+		}
+
+		installed := make([]string, 0)
+		skipped := make([]string, 0)
+		backedUp := make([]string, 0)
+		for _, name := range managedHooks {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil && !isManagedHook(path) {
+				if !force {
+					skipped = append(skipped, name)
+					continue
+				}
+				if err := os.Rename(path, path+hookBackupSuffix); err != nil {
+					return fmt.Errorf("failed to keep existing %s hook: %w", name, err)
+				}
+				backedUp = append(backedUp, name+hookBackupSuffix)
+			}
+			if err := os.WriteFile(path, []byte(hookScript(name)), 0755); err != nil {
+				return fmt.Errorf("failed to write %s hook: %w", name, err)
+			}
+			installed = append(installed, name)
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"status":    "installed",
+				"hooks_dir": dir,
+				"installed": installed,
+				"skipped":   skipped,
+				"backed_up": backedUp,
+			})
+		} else {
+			for _, name := range installed {
+				fmt.Printf("✓ Installed %s\n", name)
+			}
+			for _, name := range backedUp {
+				fmt.Printf("• Kept the existing hook as %s\n", name)
+			}
+			for _, name := range skipped {
+				fmt.Printf("• Skipped %s (existing hook, use --force to overwrite)\n", name)
+			}
+		}
+		return nil
+	},
+}
+
+// hooksUninstallCmd removes memory's git hooks, leaving user hooks untouched
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove git hooks installed by memory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		dir, err := gitHooksDir(ctx)
+		if err != nil {
+			return err
+		}
+
+		removed := make([]string, 0)
+		restored := make([]string, 0)
+		for _, name := range managedHooks {
+			path := filepath.Join(dir, name)
+			if !isManagedHook(path) {
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove %s hook: %w", name, err)
+			}
+			removed = append(removed, name)
+			// Put back the hook --force set aside
+			if _, err := os.Stat(path + hookBackupSuffix); err == nil {
+				if err := os.Rename(path+hookBackupSuffix, path); err != nil {
+					return fmt.Errorf("failed to restore %s hook: %w", name, err)
+				}
+				restored = append(restored, name)
+			}
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"status":   "uninstalled",
+				"removed":  removed,
+				"restored": restored,
+			})
+		} else {
+			for _, name := range removed {
+				fmt.Printf("✓ Removed %s\n", name)
+			}
+			for _, name := range restored {
+				fmt.Printf("✓ Restored your previous %s\n", name)
+			}
+			if len(removed) == 0 {
+				fmt.Println("No memory hooks installed.")
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	hooksInstallCmd.Flags().Bool("force", false, "Replace existing hooks not written by memory, keeping them as <name>.pre-memory")
+
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
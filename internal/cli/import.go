@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// importFormats are the --from values import understands
+var importFormats = map[string]func(path string) ([]importedItem, error){
+	"mem0":           readMem0Export,
+	"markdown-notes": readMarkdownNotes,
+	"jsonl":          readJSONLines,
+}
+
+// importedItem is a breadcrumb read from another tool, with its original creation
+// time when the format records one
+type importedItem struct {
+	IngestItem
+	createdAt float64
+}
+
+// mem0Memory is the subset of a mem0 memory record import reads
+type mem0Memory struct {
+	Memory    string `json:"memory"`
+	CreatedAt string `json:"created_at"`
+}
+
+// readMem0Export reads a mem0 export: the JSON returned by get_all, either a bare
+// array of memories or one wrapped in "results" or "memories". Every memory becomes a
+// finding, or an unknown when it is a question.
+func readMem0Export(path string) ([]importedItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var memories []mem0Memory
+	if err := json.Unmarshal(data, &memories); err != nil {
+		var wrapped struct {
+			Results  []mem0Memory `json:"results"`
+			Memories []mem0Memory `json:"memories"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse mem0 export: %w", err)
+		}
+		memories = append(wrapped.Results, wrapped.Memories...)
+	}
+
+	var items []importedItem
+	for _, m := range memories {
+		text := strings.TrimSpace(m.Memory)
+		if text == "" {
+			continue
+		}
+		items = append(items, importedItem{
+			IngestItem: IngestItem{Type: noteType(text, models.EntityFinding), Text: text},
+			createdAt:  parseImportTime(m.CreatedAt),
+		})
+	}
+	return items, nil
+}
+
+// parseImportTime reads an RFC 3339 timestamp, with or without a zone; zero if unparseable
+func parseImportTime(s string) float64 {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return float64(t.UnixMilli()) / 1000.0
+		}
+	}
+	return 0
+}
+
+// readJSONLines reads one ingest object per line. Blank lines are skipped and a
+// missing type means finding.
+func readJSONLines(path string) ([]importedItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []importedItem
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var item IngestItem
+		if err := json.Unmarshal([]byte(text), &item); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if item.Type == "" {
+			item.Type = models.EntityFinding
+		}
+		items = append(items, importedItem{IngestItem: item})
+	}
+	return items, scanner.Err()
+}
+
+// Markdown note syntax: headings, list items (bullets, numbers and checkboxes) and
+// the separators between a dead end and why it failed
+var (
+	markdownHeading  = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	markdownListItem = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?(.*)$`)
+	deadEndReason    = regexp.MustCompile(`\s+(?:—|--|->|→)\s+|:\s+|\s+because\s+`)
+)
+
+// readMarkdownNotes reads a markdown file, or every .md file under a directory. Each
+// list item or paragraph becomes a breadcrumb typed by the heading above it: headings
+// mentioning questions or unknowns hold unknowns, ones mentioning dead ends or failed
+// attempts hold dead ends ("approach — why it failed"), anything else findings.
+func readMarkdownNotes(path string) ([]importedItem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".md") {
+				files = append(files, p)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	var items []importedItem
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, parseMarkdownNotes(string(data))...)
+	}
+	return items, nil
+}
+
+// parseMarkdownNotes splits one markdown document into breadcrumbs. Code blocks are skipped.
+func parseMarkdownNotes(doc string) []importedItem {
+	var items []importedItem
+	section := models.EntityFinding
+	var current []string
+	flush := func() {
+		text := strings.Join(current, " ")
+		current = nil
+		if text == "" {
+			return
+		}
+		item := IngestItem{Type: noteType(text, section), Text: text}
+		if item.Type == models.EntityDeadEnd {
+			item.WhyFailed = "No reason recorded in the notes"
+			if loc := deadEndReason.FindStringIndex(text); loc != nil && loc[0] > 0 && loc[1] < len(text) {
+				item.Text, item.WhyFailed = text[:loc[0]], text[loc[1]:]
+			}
+		}
+		items = append(items, importedItem{IngestItem: item})
+	}
+
+	inCode := false
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		switch {
+		case trimmed == "":
+			flush()
+		case markdownHeading.MatchString(trimmed):
+			flush()
+			section = headingType(markdownHeading.FindStringSubmatch(trimmed)[1])
+		case markdownListItem.MatchString(trimmed):
+			flush()
+			current = append(current, markdownListItem.FindStringSubmatch(trimmed)[1])
+		default:
+			current = append(current, trimmed)
+		}
+	}
+	flush()
+	return items
+}
+
+// headingType returns the breadcrumb type of the items under a markdown heading
+func headingType(heading string) string {
+	h := strings.ToLower(heading)
+	for _, word := range []string{"dead end", "dead-end", "tried", "failed", "didn't work", "avoid", "don't", "do not"} {
+		if strings.Contains(h, word) {
+			return models.EntityDeadEnd
+		}
+	}
+	for _, word := range []string{"question", "unknown", "open", "todo", "unclear"} {
+		if strings.Contains(h, word) {
+			return models.EntityUnknown
+		}
+	}
+	return models.EntityFinding
+}
+
+// noteType returns the type of an imported note: questions are unknowns wherever they appear
+func noteType(text, fallback string) string {
+	if fallback != models.EntityDeadEnd && strings.HasSuffix(text, "?") {
+		return models.EntityUnknown
+	}
+	return fallback
+}
+
+// importCmd logs notes exported from other memory tools
+var importCmd = &cobra.Command{
+	Use:   "import --from [format] [path]",
+	Short: "Import findings and unknowns from other memory tools",
+	Long: `Import memories and notes kept by other tools into the active session, so existing
+agent memory carries over. The whole import is logged in one transaction.
+
+Formats:
+  mem0            JSON export of mem0 memories (get_all output); each memory becomes a
+                  finding, or an unknown when it is a question. Creation times are kept.
+  markdown-notes  A markdown file or a directory of them. Each list item or paragraph
+                  becomes a breadcrumb typed by its heading: "Open questions" holds
+                  unknowns, "Dead ends" or "Tried" holds dead ends written as
+                  "approach — why it failed", anything else findings.
+  jsonl           One ingest object per line ({"type", "text", "why_failed", "scope",
+                  "impact"}); the type defaults to finding.
+
+Examples:
+  memory import --from mem0 mem0-export.json --dry-run
+  memory import --from markdown-notes docs/notes/
+  memory import --from jsonl memories.jsonl`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		format, _ := cmd.Flags().GetString("from")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		read, ok := importFormats[format]
+		if !ok {
+			return fmt.Errorf("%w format %q (use mem0, markdown-notes or jsonl)", db.ErrInvalid, format)
+		}
+
+		items, err := read(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("%w: nothing to import from %s", db.ErrInvalid, args[0])
+		}
+
+		active, err := loggingSession(cmd)
+		if err != nil {
+			return err
+		}
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		batch := make([]*LoggedBreadcrumb, 0, len(items))
+		for i, item := range items {
+			b, err := batchBreadcrumb(ctx, active, item.IngestItem, limits)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+			if item.createdAt > 0 {
+				switch {
+				case b.finding != nil:
+					b.finding.CreatedTimestamp = item.createdAt
+				case b.unknown != nil:
+					b.unknown.CreatedTimestamp = item.createdAt
+				case b.deadEnd != nil:
+					b.deadEnd.CreatedTimestamp = item.createdAt
+				}
+			}
+			batch = append(batch, b)
+		}
+
+		if dryRun {
+			if outputText {
+				fmt.Printf("Dry run: would import %d breadcrumbs from %s\n", len(batch), args[0])
+				printBatch(batch)
+			} else {
+				outputResult(map[string]interface{}{
+					"status":      "dry_run",
+					"breadcrumbs": batch,
+					"count":       len(batch),
+				})
+			}
+			return nil
+		}
+		if err := logBatch(ctx, active, batch); err != nil {
+			return err
+		}
+		printBatch(batch)
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().String("from", "", "Source format: mem0, markdown-notes or jsonl")
+	importCmd.Flags().Bool("dry-run", false, "Show what would be imported without logging it")
+	importCmd.Flags().String("session", "", "Log to this session instead of the active one")
+	importCmd.Flags().Bool("allow-long", false, "Keep text longer than the length limit whole")
+	rootCmd.AddCommand(importCmd)
+}
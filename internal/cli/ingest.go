@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// IngestItem is one breadcrumb in a batch read by ingest
+type IngestItem struct {
+	Type      string  `json:"type"`                 // finding, unknown or dead_end
+	Text      string  `json:"text"`                 // The finding, the question or the approach tried
+	WhyFailed string  `json:"why_failed,omitempty"` // Dead ends only
+	Scope     string  `json:"scope,omitempty"`      // File, directory or URL
+	ScopeKind string  `json:"scope_kind,omitempty"` // Findings only: file, dir, url, command or external-api; inferred when empty
+	Impact    float64 `json:"impact,omitempty"`     // Defaults to 0.5
+}
+
+// LoggedBreadcrumb reports one breadcrumb written by a batch
+type LoggedBreadcrumb struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	Text      string `json:"text"`
+	Truncated bool   `json:"truncated,omitempty"` // Cut to the length limit
+
+	finding *models.Finding
+	unknown *models.Unknown
+	deadEnd *models.DeadEnd
+}
+
+// batchBreadcrumb validates an item and builds its breadcrumb for the active session,
+// cutting its text to the limits
+func batchBreadcrumb(ctx context.Context, active *ActiveSession, item IngestItem, limits *textLimits) (*LoggedBreadcrumb, error) {
+	if item.Text == "" {
+		return nil, fmt.Errorf("%w: %s without text", db.ErrInvalid, item.Type)
+	}
+	impact := item.Impact
+	if impact == 0 {
+		impact = defaultImpact
+	}
+	if impact < 0 || impact > 1 {
+		return nil, fmt.Errorf("%w: impact %g must be above 0 and at most 1", db.ErrInvalid, impact)
+	}
+
+	text, whyFailed, truncated := limits.apply(item.Type, item.Text, item.WhyFailed)
+	item.Text, item.WhyFailed = text, whyFailed
+	logged := &LoggedBreadcrumb{Type: item.Type, Text: item.Text, Truncated: truncated}
+	switch item.Type {
+	case models.EntityFinding:
+		kind, err := scopeKindFor(item.Scope, item.ScopeKind)
+		if err != nil {
+			return nil, err
+		}
+		logged.finding = newSessionFinding(ctx, active, item.Text, item.Scope, kind, impact)
+		logged.ID = logged.finding.ID
+	case models.EntityUnknown:
+		logged.unknown = newSessionUnknown(active, item.Text, item.Scope, impact)
+		logged.ID = logged.unknown.ID
+	case models.EntityDeadEnd:
+		if item.WhyFailed == "" {
+			return nil, fmt.Errorf("%w: dead end %q without why_failed", db.ErrInvalid, item.Text)
+		}
+		logged.deadEnd = newSessionDeadEnd(active, item.Text, item.WhyFailed, item.Scope, impact)
+		logged.ID = logged.deadEnd.ID
+	default:
+		return nil, fmt.Errorf("%w: type %q (use finding, unknown or dead_end)", db.ErrInvalid, item.Type)
+	}
+	return logged, nil
+}
+
+// logBatch runs the pre-hooks on every breadcrumb, then writes them all in one
+// transaction so a batch is logged whole or not at all
+func logBatch(ctx context.Context, active *ActiveSession, batch []*LoggedBreadcrumb) error {
+	for _, b := range batch {
+		var err error
+		switch {
+		case b.finding != nil:
+			err = validateWithHook(ctx, "pre-learned", active.ProjectID, b.finding)
+		case b.unknown != nil:
+			err = validateWithHook(ctx, "pre-uncertain", active.ProjectID, b.unknown)
+		case b.deadEnd != nil:
+			err = validateWithHook(ctx, "pre-tried", active.ProjectID, b.deadEnd)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err := stores.InTx(ctx, func(tx *db.Stores) error {
+		for _, b := range batch {
+			var err error
+			switch {
+			case b.finding != nil:
+				err = tx.Breadcrumbs.CreateFinding(ctx, b.finding)
+			case b.unknown != nil:
+				err = tx.Breadcrumbs.CreateUnknown(ctx, b.unknown)
+			case b.deadEnd != nil:
+				err = tx.Breadcrumbs.CreateDeadEnd(ctx, b.deadEnd)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to log %s %q: %w", b.Type, truncateText(b.Text, 40), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, b := range batch {
+		result := map[string]interface{}{
+			"status":     "logged",
+			"type":       b.Type,
+			"id":         b.ID,
+			"session_id": active.SessionID,
+		}
+		switch {
+		case b.finding != nil:
+			result["finding"] = b.Text
+			emitEvent(ctx, EventFindingLogged, active.ProjectID, result)
+		case b.unknown != nil:
+			result["unknown"] = b.Text
+			emitEvent(ctx, EventUnknownLogged, active.ProjectID, result)
+		case b.deadEnd != nil:
+			result["approach"] = b.Text
+			result["why_failed"] = b.deadEnd.WhyFailed
+			emitEvent(ctx, EventDeadEndLogged, active.ProjectID, result)
+		}
+	}
+	maybeAutoCheckpoint(ctx, active, len(batch))
+	return nil
+}
+
+// printBatch reports a logged batch
+func printBatch(batch []*LoggedBreadcrumb) {
+	if !outputText {
+		counts := map[string]int{}
+		for _, b := range batch {
+			counts[b.Type]++
+		}
+		outputResult(map[string]interface{}{
+			"status":      "logged",
+			"breadcrumbs": batch,
+			"count":       len(batch),
+			"findings":    counts[models.EntityFinding],
+			"unknowns":    counts[models.EntityUnknown],
+			"dead_ends":   counts[models.EntityDeadEnd],
+		})
+		return
+	}
+	for _, b := range batch {
+		switch {
+		case b.finding != nil:
+			fmt.Printf("✓ Learned: %s\n", b.Text)
+		case b.unknown != nil:
+			fmt.Printf("? Uncertain: %s\n", b.Text)
+		case b.deadEnd != nil:
+			fmt.Printf("✗ Tried: %s → %s\n", b.Text, b.deadEnd.WhyFailed)
+		}
+		if b.Truncated {
+			fmt.Println(truncationNote)
+		}
+	}
+}
+
+// ingestCmd logs a JSON batch of breadcrumbs
+var ingestCmd = &cobra.Command{
+	Use:   "ingest [file]",
+	Short: "Log a JSON array of findings, unknowns and dead ends",
+	Long: `Read a JSON array of breadcrumbs from stdin (or a file) and log them to the active
+session in one transaction, so an agent can flush a batch of observations in one call.
+Either every breadcrumb is logged or, if one is invalid, none are.
+
+Each object has a "type" (finding, unknown or dead_end) and "text"; dead ends also
+need "why_failed". "scope" and "impact" (default 0.5) are optional.
+
+Examples:
+  echo '[{"type":"finding","text":"Auth uses JWT","scope":"src/auth.go"},
+         {"type":"unknown","text":"Who rotates the keys?","impact":0.9},
+         {"type":"dead_end","text":"Session cookies","why_failed":"CSRF"}]' | memory ingest
+  memory ingest observations.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		input := "-"
+		if len(args) > 0 {
+			input = args[0]
+		}
+		if input == "-" && inRepl && !inDaemon {
+			return fmt.Errorf("%w: the REPL reads commands from stdin; give a file instead", db.ErrInvalid)
+		}
+
+		active, err := loggingSession(cmd)
+		if err != nil {
+			return err
+		}
+
+		var items []IngestItem
+		if err := readInputJSON(input, &items); err != nil {
+			return fmt.Errorf("%w: %v", db.ErrInvalid, err)
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("%w: no breadcrumbs to ingest", db.ErrInvalid)
+		}
+
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		batch := make([]*LoggedBreadcrumb, 0, len(items))
+		for i, item := range items {
+			b, err := batchBreadcrumb(ctx, active, item, limits)
+			if err != nil {
+				return fmt.Errorf("item %d: %w", i+1, err)
+			}
+			batch = append(batch, b)
+		}
+		if err := logBatch(ctx, active, batch); err != nil {
+			return err
+		}
+		printBatch(batch)
+		return nil
+	},
+}
+
+func init() {
+	ingestCmd.Flags().String("session", "", "Log to this session instead of the active one, e.g. to backfill")
+	ingestCmd.Flags().Bool("allow-long", false, "Keep text longer than the length limit whole")
+	rootCmd.AddCommand(ingestCmd)
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// claudeCodeHook is a hook command memory wires into Claude Code settings
+type claudeCodeHook struct {
+	event   string // Claude Code hook event
+	matcher string // Tool name pattern; empty for events without tools
+	command string
+}
+
+// claudeCodeHooks are the hooks integrate claude-code installs. Like the git hooks,
+// they never fail the agent's action, even if memory is missing or errors.
+var claudeCodeHooks = []claudeCodeHook{
+	{event: "SessionStart", command: "memory context --text 2>/dev/null || true"},
+	{event: "PreToolUse", matcher: "Edit|MultiEdit|Write|NotebookEdit", command: "memory recall --hook --text 2>/dev/null || true"},
+}
+
+// isManagedClaudeCodeCommand reports whether a hook command was written by memory
+func isManagedClaudeCodeCommand(command string) bool {
+	for _, h := range claudeCodeHooks {
+		if command == h.command {
+			return true
+		}
+	}
+	return false
+}
+
+// claudeCodeSettingsPath returns the settings file to write: the user's, the local
+// (uncommitted) one or the shared one in the repository root, falling back to the
+// current directory outside a repository
+func claudeCodeSettingsPath(ctx context.Context, user, local bool) (string, error) {
+	if user {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".claude", "settings.json"), nil
+	}
+	root, err := gitRepoRoot(ctx)
+	if err != nil {
+		if root, err = os.Getwd(); err != nil {
+			return "", err
+		}
+	}
+	name := "settings.json"
+	if local {
+		name = "settings.local.json"
+	}
+	return filepath.Join(root, ".claude", name), nil
+}
+
+// withClaudeCodeHooks returns the settings with memory's hooks removed and, unless
+// removing, added back, leaving every other setting and hook as it was. Reports
+// whether any hook was removed.
+func withClaudeCodeHooks(settings map[string]interface{}, remove bool) (map[string]interface{}, bool, error) {
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	if hooks == nil {
+		if _, ok := settings["hooks"]; ok {
+			return nil, false, fmt.Errorf("\"hooks\" is not an object")
+		}
+		hooks = map[string]interface{}{}
+	}
+
+	removed := false
+	for event, v := range hooks {
+		groups, _ := v.([]interface{})
+		kept := make([]interface{}, 0, len(groups))
+		for _, g := range groups {
+			group, ok := g.(map[string]interface{})
+			if !ok {
+				kept = append(kept, g)
+				continue
+			}
+			commands, _ := group["hooks"].([]interface{})
+			keptCommands := make([]interface{}, 0, len(commands))
+			for _, c := range commands {
+				if hook, ok := c.(map[string]interface{}); ok {
+					if command, _ := hook["command"].(string); isManagedClaudeCodeCommand(command) {
+						removed = true
+						continue
+					}
+				}
+				keptCommands = append(keptCommands, c)
+			}
+			if len(keptCommands) > 0 {
+				group["hooks"] = keptCommands
+				kept = append(kept, group)
+			}
+		}
+		if len(kept) > 0 {
+			hooks[event] = kept
+		} else {
+			delete(hooks, event)
+		}
+	}
+
+	if !remove {
+		for _, h := range claudeCodeHooks {
+			group := map[string]interface{}{
+				"hooks": []interface{}{map[string]interface{}{"type": "command", "command": h.command}},
+			}
+			if h.matcher != "" {
+				group["matcher"] = h.matcher
+			}
+			groups, _ := hooks[h.event].([]interface{})
+			hooks[h.event] = append(groups, group)
+		}
+	}
+	if len(hooks) > 0 {
+		settings["hooks"] = hooks
+	} else {
+		delete(settings, "hooks")
+	}
+	return settings, removed, nil
+}
+
+// integrateCmd groups agent tool integrations
+var integrateCmd = &cobra.Command{
+	Use:   "integrate",
+	Short: "Wire memory into coding agents",
+}
+
+// integrateClaudeCodeCmd writes memory's hooks into Claude Code settings
+var integrateClaudeCodeCmd = &cobra.Command{
+	Use:   "claude-code",
+	Short: "Add memory hooks to Claude Code settings",
+	Long: `Add hooks to Claude Code's settings so it consults memory on its own:
+
+  SessionStart  memory context --text, loading what is known into every new session
+  PreToolUse    memory recall --hook --text before Edit, MultiEdit, Write and
+                NotebookEdit, surfacing what is known about the file being changed
+
+Writes .claude/settings.json at the repository root (--local for the uncommitted
+settings.local.json, --user for ~/.claude/settings.json). Other settings and hooks are
+kept, and running it again replaces memory's hooks rather than adding duplicates.
+
+Examples:
+  memory integrate claude-code
+  memory integrate claude-code --user
+  memory integrate claude-code --print
+  memory integrate claude-code --remove`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		user, _ := cmd.Flags().GetBool("user")
+		local, _ := cmd.Flags().GetBool("local")
+		remove, _ := cmd.Flags().GetBool("remove")
+		printOnly, _ := cmd.Flags().GetBool("print")
+		if user && local {
+			return fmt.Errorf("--user and --local are mutually exclusive")
+		}
+
+		path, err := claudeCodeSettingsPath(ctx, user, local)
+		if err != nil {
+			return fmt.Errorf("failed to locate settings: %w", err)
+		}
+		settings := map[string]interface{}{}
+		if data, err := os.ReadFile(path); err == nil {
+			if err := json.Unmarshal(data, &settings); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		settings, removed, err := withClaudeCodeHooks(settings, remove)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		// Hook commands hold shell redirections, which the default encoder escapes
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(settings); err != nil {
+			return err
+		}
+		data := buf.Bytes()
+		if printOnly {
+			fmt.Print(string(data))
+			return nil
+		}
+		if remove && !removed {
+			if outputText {
+				fmt.Printf("No memory hooks in %s.\n", path)
+			} else {
+				outputResult(map[string]interface{}{"status": "not_installed", "path": path})
+			}
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		status := "installed"
+		if remove {
+			status = "removed"
+		}
+		if outputText {
+			if remove {
+				fmt.Printf("✓ Removed memory hooks from %s\n", path)
+			} else {
+				fmt.Printf("✓ Wrote memory hooks to %s\n", path)
+				for _, h := range claudeCodeHooks {
+					fmt.Printf("  %-13s %s\n", h.event, strings.TrimSuffix(h.command, " 2>/dev/null || true"))
+				}
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status": status,
+				"path":   path,
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	integrateClaudeCodeCmd.Flags().Bool("user", false, "Write ~/.claude/settings.json instead of the repository's settings")
+	integrateClaudeCodeCmd.Flags().Bool("local", false, "Write .claude/settings.local.json, which is not committed")
+	integrateClaudeCodeCmd.Flags().Bool("remove", false, "Remove memory's hooks")
+	integrateClaudeCodeCmd.Flags().Bool("print", false, "Print the resulting settings instead of writing them")
+
+	integrateCmd.AddCommand(integrateClaudeCodeCmd)
+	rootCmd.AddCommand(integrateCmd)
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jiraKeyPattern matches Jira issue keys such as PROJ-123
+var jiraKeyPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]+)-(\d+)$`)
+
+// defaultJiraDoneStatus is the transition applied to linked tickets when a goal completes
+const defaultJiraDoneStatus = "Done"
+
+// JiraIssue is the subset of the Jira issue payload memory uses
+type JiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// JiraConfig holds the Jira connection settings read from the environment
+type JiraConfig struct {
+	BaseURL    string // JIRA_URL, e.g. https://acme.atlassian.net
+	Email      string // JIRA_EMAIL
+	Token      string // JIRA_API_TOKEN
+	DoneStatus string // JIRA_DONE_STATUS, defaults to "Done"
+}
+
+// loadJiraConfig returns the Jira settings, or an error when the integration isn't configured
+func loadJiraConfig() (*JiraConfig, error) {
+	cfg := &JiraConfig{
+		BaseURL:    strings.TrimSuffix(os.Getenv("JIRA_URL"), "/"),
+		Email:      os.Getenv("JIRA_EMAIL"),
+		Token:      os.Getenv("JIRA_API_TOKEN"),
+		DoneStatus: os.Getenv("JIRA_DONE_STATUS"),
+	}
+	if cfg.BaseURL == "" || cfg.Email == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("Jira is not configured; set JIRA_URL, JIRA_EMAIL and JIRA_API_TOKEN")
+	}
+	if cfg.DoneStatus == "" {
+		cfg.DoneStatus = defaultJiraDoneStatus
+	}
+	return cfg, nil
+}
+
+// parseJiraKey splits a Jira key into its project key and number
+func parseJiraKey(key string) (string, int, error) {
+	m := jiraKeyPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(key)))
+	if m == nil {
+		return "", 0, fmt.Errorf("invalid Jira key: %s", key)
+	}
+	number, _ := strconv.Atoi(m[2])
+	return m[1], number, nil
+}
+
+// browseURL returns the web URL of a Jira issue
+func (c *JiraConfig) browseURL(key string) string {
+	return c.BaseURL + "/browse/" + key
+}
+
+// request performs an authenticated Jira REST call and decodes the JSON response into out
+func (c *JiraConfig) request(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.Email, c.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: githubAPITimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Jira request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			ErrorMessages []string `json:"errorMessages"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("Jira API %s %s: %d %s", method, path, resp.StatusCode, strings.Join(apiErr.ErrorMessages, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetchIssue retrieves a Jira issue by key
+func (c *JiraConfig) fetchIssue(ctx context.Context, key string) (*JiraIssue, error) {
+	var issue JiraIssue
+	if err := c.request(ctx, "GET", "/rest/api/2/issue/"+key+"?fields=summary,description,status", nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// transitionIssue moves a Jira issue to the named status using the first matching transition.
+// Returns false when the issue is already in that status.
+func (c *JiraConfig) transitionIssue(ctx context.Context, key, status string) (bool, error) {
+	issue, err := c.fetchIssue(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(issue.Fields.Status.Name, status) {
+		return false, nil
+	}
+
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.request(ctx, "GET", "/rest/api/2/issue/"+key+"/transitions", nil, &available); err != nil {
+		return false, err
+	}
+	for _, t := range available.Transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			payload := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return true, c.request(ctx, "POST", "/rest/api/2/issue/"+key+"/transitions", payload, nil)
+		}
+	}
+	return false, fmt.Errorf("no transition to %q available for %s", status, key)
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// truncationMarker ends text cut to its limit, with the characters kept and the original length
+const truncationMarker = " … [truncated to %d of %d characters]"
+
+// textLimits caps the length of breadcrumb text. A nil *textLimits caps nothing.
+type textLimits struct {
+	finding, unknown, approach int
+}
+
+// loadTextLimits returns the configured limits, or nil when the command was given --allow-long
+func loadTextLimits(cmd *cobra.Command) (*textLimits, error) {
+	if allowLong, _ := cmd.Flags().GetBool("allow-long"); allowLong {
+		return nil, nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	finding, unknown, approach := cfg.Limits.Max()
+	return &textLimits{finding: finding, unknown: unknown, approach: approach}, nil
+}
+
+// apply truncates a breadcrumb's text, and a dead end's reason, to the limit for its
+// type and reports whether either was cut
+func (l *textLimits) apply(entityType, text, whyFailed string) (string, string, bool) {
+	if l == nil {
+		return text, whyFailed, false
+	}
+	switch entityType {
+	case models.EntityFinding:
+		text, cut := limitText(text, l.finding)
+		return text, whyFailed, cut
+	case models.EntityUnknown:
+		text, cut := limitText(text, l.unknown)
+		return text, whyFailed, cut
+	case models.EntityDeadEnd:
+		text, cutText := limitText(text, l.approach)
+		whyFailed, cutWhy := limitText(whyFailed, l.approach)
+		return text, whyFailed, cutText || cutWhy
+	}
+	return text, whyFailed, false
+}
+
+// limitText keeps the first max characters of text and marks the cut
+func limitText(text string, max int) (string, bool) {
+	runes := []rune(text)
+	if max <= 0 || len(runes) <= max {
+		return text, false
+	}
+	return string(runes[:max]) + fmt.Sprintf(truncationMarker, max, len(runes)), true
+}
+
+// truncationNote tells the user text was cut, for --text output
+const truncationNote = "  (truncated to the length limit; --allow-long keeps the full text)"
+
+// limitsCmd shows and sets the breadcrumb text length limits
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Show or set the maximum length of breadcrumb text",
+	Long: `Show or set the maximum length of finding, unknown and dead-end text, in characters.
+Longer text, such as a pasted stack trace, would crowd everything else out of the
+context start assembles, so it's cut to the limit and ends with a marker saying how
+long it was. Pass --allow-long to learned, uncertain, tried, verify --update,
+log-all, ingest or import to keep a long text whole.
+
+The defaults are 2000 characters for findings and 1000 for unknowns and dead ends;
+--approach also caps why a dead end failed. Limits are stored in config.json next
+to the database.
+
+Examples:
+  memory limits --text
+  memory limits --finding 4000 --approach 500`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		flags := cmd.Flags()
+		changed := flags.Changed("finding") || flags.Changed("unknown") || flags.Changed("approach")
+		if changed {
+			if cfg.Limits == nil {
+				cfg.Limits = &config.LimitsConfig{}
+			}
+			for name, target := range map[string]*int{
+				"finding":  &cfg.Limits.Finding,
+				"unknown":  &cfg.Limits.Unknown,
+				"approach": &cfg.Limits.Approach,
+			} {
+				if !flags.Changed(name) {
+					continue
+				}
+				n, _ := flags.GetInt(name)
+				if n < 1 {
+					return fmt.Errorf("%w: --%s must be at least 1", db.ErrInvalid, name)
+				}
+				*target = n
+			}
+			if *cfg.Limits == (config.LimitsConfig{}) {
+				cfg.Limits = nil
+			}
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		finding, unknown, approach := cfg.Limits.Max()
+		if !outputText {
+			status := "current"
+			if changed {
+				status = "saved"
+			}
+			outputResult(map[string]interface{}{
+				"status":   status,
+				"finding":  finding,
+				"unknown":  unknown,
+				"approach": approach,
+			})
+			return nil
+		}
+		fmt.Printf("Findings: %d characters\nUnknowns: %d characters\nDead ends: %d characters\n", finding, unknown, approach)
+		return nil
+	},
+}
+
+func init() {
+	limitsCmd.Flags().Int("finding", 0, "Maximum length of a finding")
+	limitsCmd.Flags().Int("unknown", 0, "Maximum length of an unknown")
+	limitsCmd.Flags().Int("approach", 0, "Maximum length of a dead end's approach and why it failed")
+	rootCmd.AddCommand(limitsCmd)
+}
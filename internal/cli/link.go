@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// linkCmd groups commands that connect memory to external trackers
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Link sessions and goals to external trackers",
+}
+
+// resolveLinkTarget returns the goal to link when goalID is set, otherwise the active session
+func resolveLinkTarget(ctx context.Context, goalID string) (models.IssueTargetType, string, error) {
+	if goalID != "" {
+		goal, err := stores.Goals.Get(ctx, goalID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get goal: %w", err)
+		}
+		return models.IssueTargetGoal, goal.ID, nil
+	}
+	active, err := loadActiveSession(ctx)
+	if err != nil || active == nil {
+		return "", "", fmt.Errorf("no active session. Run 'memory start' first or pass --goal")
+	}
+	return models.IssueTargetSession, active.SessionID, nil
+}
+
+// linkIssueCmd links the active session (or a goal) to a GitHub issue
+var linkIssueCmd = &cobra.Command{
+	Use:   "issue [number]",
+	Short: "Link the active session or a goal to a GitHub issue",
+	Long: `Link the active session (or a goal with --goal) to a GitHub issue so work in
+memory can be traced back to the team's backlog.
+
+The repository defaults to the origin remote. The issue title is fetched when the
+GitHub API is reachable; set GITHUB_TOKEN (or GH_TOKEN) for private repositories.
+
+Examples:
+  memory link issue 42
+  memory link issue 42 --goal <goal-id>
+  memory link issue 7 --repo acme/api`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil || number <= 0 {
+			return fmt.Errorf("invalid issue number: %s", args[0])
+		}
+		goalID, _ := cmd.Flags().GetString("goal")
+		repoFlag, _ := cmd.Flags().GetString("repo")
+
+		repo, err := githubRepo(ctx, repoFlag)
+		if err != nil {
+			return err
+		}
+
+		targetType, targetID, err := resolveLinkTarget(ctx, goalID)
+		if err != nil {
+			return err
+		}
+
+		link := models.NewIssueLink(targetType, targetID, repo, number)
+		link.URL = githubIssueURL(repo, number)
+
+		// Title is a convenience; link offline rather than fail
+		var warning string
+		if issue, err := fetchGitHubIssue(ctx, repo, number); err == nil {
+			link.Title = &issue.Title
+			link.URL = issue.HTMLURL
+		} else {
+			warning = err.Error()
+		}
+
+		if err := stores.IssueLinks.Create(ctx, link); err != nil {
+			return fmt.Errorf("failed to link issue: %w", err)
+		}
+
+		if outputText {
+			title := ""
+			if link.Title != nil {
+				title = " " + *link.Title
+			}
+			fmt.Printf("✓ Linked %s %s to %s%s\n", targetType, targetID, link.Ref(), title)
+			if warning != "" {
+				fmt.Printf("  (title not fetched: %s)\n", warning)
+			}
+		} else {
+			result := map[string]interface{}{
+				"status": "linked",
+				"link":   link,
+			}
+			if warning != "" {
+				result["warning"] = warning
+			}
+			outputResult(result)
+		}
+		return nil
+	},
+}
+
+// linkJiraCmd links the active session (or a goal) to a Jira ticket
+var linkJiraCmd = &cobra.Command{
+	Use:   "jira [key]",
+	Short: "Link the active session or a goal to a Jira ticket",
+	Long: `Link the active session (or a goal with --goal) to a Jira ticket. Goals linked
+to tickets have them transitioned when completed with 'memory goal done'.
+
+Requires JIRA_URL, JIRA_EMAIL and JIRA_API_TOKEN.
+
+Examples:
+  memory link jira AUTH-142 --goal <goal-id>
+  memory link jira AUTH-142`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		key := strings.ToUpper(args[0])
+		if _, _, err := parseJiraKey(key); err != nil {
+			return err
+		}
+		goalID, _ := cmd.Flags().GetString("goal")
+
+		cfg, err := loadJiraConfig()
+		if err != nil {
+			return err
+		}
+		targetType, targetID, err := resolveLinkTarget(ctx, goalID)
+		if err != nil {
+			return err
+		}
+		issue, err := cfg.fetchIssue(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", key, err)
+		}
+		link, err := linkJiraTicket(ctx, cfg, targetType, targetID, issue)
+		if err != nil {
+			return fmt.Errorf("failed to link %s: %w", key, err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Linked %s %s to %s %s\n", targetType, targetID, link.Ref(), *link.Title)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "linked",
+				"link":   link,
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	linkIssueCmd.Flags().String("goal", "", "Goal ID to link instead of the active session")
+	linkIssueCmd.Flags().String("repo", "", "GitHub repository (owner/name), defaults to origin")
+	linkJiraCmd.Flags().String("goal", "", "Goal ID to link instead of the active session")
+	linkCmd.AddCommand(linkIssueCmd, linkJiraCmd)
+	rootCmd.AddCommand(linkCmd)
+}
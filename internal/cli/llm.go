@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// llmTimeout bounds one completion; local models can be slow to answer
+const llmTimeout = 2 * time.Minute
+
+// llmMaxTokens caps the length of a completion
+const llmMaxTokens = 1024
+
+// LLM providers for drafting handoffs and compacting findings
+const (
+	llmOpenAI    = "openai" // OpenAI or any server with an OpenAI-compatible chat completions API
+	llmAnthropic = "anthropic"
+	llmOllama    = "ollama"
+)
+
+// llmDefaults are each provider's base URL and models when none is set. Anthropic has
+// no embeddings API, so its embeddings are computed locally.
+var llmDefaults = map[string]struct{ url, model, embedModel string }{
+	llmOpenAI:    {"https://api.openai.com/v1", "gpt-4o-mini", "text-embedding-3-small"},
+	llmAnthropic: {"https://api.anthropic.com", "claude-3-5-haiku-latest", ""},
+	llmOllama:    {"http://localhost:11434", "llama3.1", "nomic-embed-text"},
+}
+
+// LLMConfig holds the LLM provider settings read from the environment
+type LLMConfig struct {
+	Provider string // MEMORY_LLM_PROVIDER: openai, anthropic or ollama
+	BaseURL  string // MEMORY_LLM_URL, defaults to the provider's
+	Model    string // MEMORY_LLM_MODEL, defaults to a small model of the provider's
+	APIKey   string // MEMORY_LLM_API_KEY, or OPENAI_API_KEY / ANTHROPIC_API_KEY
+
+	EmbedModel string // MEMORY_LLM_EMBED_MODEL; empty embeds locally by word counts
+}
+
+// loadLLMConfig returns the LLM settings, or an error when no provider is configured
+func loadLLMConfig() (*LLMConfig, error) {
+	cfg := &LLMConfig{
+		Provider: strings.ToLower(os.Getenv("MEMORY_LLM_PROVIDER")),
+		BaseURL:  strings.TrimSuffix(os.Getenv("MEMORY_LLM_URL"), "/"),
+		Model:    os.Getenv("MEMORY_LLM_MODEL"),
+		APIKey:   os.Getenv("MEMORY_LLM_API_KEY"),
+
+		EmbedModel: os.Getenv("MEMORY_LLM_EMBED_MODEL"),
+	}
+	if cfg.Provider == "" {
+		return nil, fmt.Errorf("no LLM is configured; set MEMORY_LLM_PROVIDER to openai, anthropic or ollama")
+	}
+	defaults, ok := llmDefaults[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider %q (use openai, anthropic or ollama)", cfg.Provider)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaults.url
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaults.model
+	}
+	if cfg.EmbedModel == "" || cfg.Provider == llmAnthropic {
+		cfg.EmbedModel = defaults.embedModel
+	}
+	if cfg.APIKey == "" {
+		switch cfg.Provider {
+		case llmOpenAI:
+			cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+		case llmAnthropic:
+			cfg.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+	}
+	if cfg.APIKey == "" && cfg.Provider == llmAnthropic {
+		return nil, fmt.Errorf("Anthropic needs an API key; set MEMORY_LLM_API_KEY or ANTHROPIC_API_KEY")
+	}
+	return cfg, nil
+}
+
+// complete sends one system and user message and returns the model's reply
+func (c *LLMConfig) complete(ctx context.Context, system, prompt string) (string, error) {
+	switch c.Provider {
+	case llmAnthropic:
+		var resp struct {
+			Content []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+		}
+		body := map[string]interface{}{
+			"model":      c.Model,
+			"max_tokens": llmMaxTokens,
+			"system":     system,
+			"messages":   []map[string]string{{"role": "user", "content": prompt}},
+		}
+		headers := map[string]string{"x-api-key": c.APIKey, "anthropic-version": "2023-06-01"}
+		if err := c.request(ctx, "/v1/messages", headers, body, &resp); err != nil {
+			return "", err
+		}
+		var text strings.Builder
+		for _, block := range resp.Content {
+			if block.Type == "text" {
+				text.WriteString(block.Text)
+			}
+		}
+		return text.String(), nil
+
+	case llmOllama:
+		var resp struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		}
+		body := map[string]interface{}{
+			"model":    c.Model,
+			"stream":   false,
+			"format":   "json",
+			"messages": []map[string]string{{"role": "system", "content": system}, {"role": "user", "content": prompt}},
+		}
+		if err := c.request(ctx, "/api/chat", nil, body, &resp); err != nil {
+			return "", err
+		}
+		return resp.Message.Content, nil
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	body := map[string]interface{}{
+		"model":      c.Model,
+		"max_tokens": llmMaxTokens,
+		"messages":   []map[string]string{{"role": "system", "content": system}, {"role": "user", "content": prompt}},
+	}
+	headers := map[string]string{}
+	if c.APIKey != "" {
+		headers["Authorization"] = "Bearer " + c.APIKey
+	}
+	if err := c.request(ctx, "/chat/completions", headers, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no completion", c.Provider)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// embed returns a vector for each text, for comparing them by cosine similarity
+func (c *LLMConfig) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	if c.EmbedModel == "" {
+		return termVectors(texts), nil
+	}
+	body := map[string]interface{}{"model": c.EmbedModel, "input": texts}
+	var vectors [][]float64
+	if c.Provider == llmOllama {
+		var resp struct {
+			Embeddings [][]float64 `json:"embeddings"`
+		}
+		if err := c.request(ctx, "/api/embed", nil, body, &resp); err != nil {
+			return nil, err
+		}
+		vectors = resp.Embeddings
+	} else {
+		var resp struct {
+			Data []struct {
+				Index     int       `json:"index"`
+				Embedding []float64 `json:"embedding"`
+			} `json:"data"`
+		}
+		headers := map[string]string{}
+		if c.APIKey != "" {
+			headers["Authorization"] = "Bearer " + c.APIKey
+		}
+		if err := c.request(ctx, "/embeddings", headers, body, &resp); err != nil {
+			return nil, err
+		}
+		vectors = make([][]float64, len(resp.Data))
+		for _, d := range resp.Data {
+			if d.Index >= 0 && d.Index < len(vectors) {
+				vectors[d.Index] = d.Embedding
+			}
+		}
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("%s returned %d embeddings for %d texts", c.Provider, len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// termVectors embeds texts by their word counts over a shared vocabulary, for
+// providers without an embeddings API
+func termVectors(texts []string) [][]float64 {
+	vocabulary := map[string]int{}
+	counts := make([]map[int]float64, len(texts))
+	for i, text := range texts {
+		counts[i] = map[int]float64{}
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if len(word) < 3 {
+				continue // Mostly articles and prepositions
+			}
+			index, ok := vocabulary[word]
+			if !ok {
+				index = len(vocabulary)
+				vocabulary[word] = index
+			}
+			counts[i][index]++
+		}
+	}
+	vectors := make([][]float64, len(texts))
+	for i := range texts {
+		vectors[i] = make([]float64, len(vocabulary))
+		for index, n := range counts[i] {
+			vectors[i][index] = n
+		}
+	}
+	return vectors
+}
+
+// cosineSimilarity compares two vectors: 1 for the same direction, 0 for unrelated
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		if i >= len(b) {
+			break
+		}
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// request posts a JSON body to the provider and decodes the JSON response into out
+func (c *LLMConfig) request(ctx context.Context, path string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: llmTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.Provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s API %s: %d %s", c.Provider, path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// handoffDraft is the summary and recommendations a model drafts for a session
+type handoffDraft struct {
+	Summary string   `json:"summary"`
+	Next    []string `json:"next"`
+}
+
+// handoffDraftPrompt tells the model what to draft and in what shape
+const handoffDraftPrompt = `You write handoffs between AI coding sessions working on the same codebase.
+From the session's objective and the breadcrumbs it logged, write:
+- summary: two or three sentences on what the session accomplished and what it learned,
+  stated plainly; don't claim anything the breadcrumbs don't support
+- next: up to five concrete recommendations for the next session, most important first,
+  drawn from open questions, dead ends and unfinished work
+Reply with only a JSON object: {"summary": "...", "next": ["...", "..."]}`
+
+// sessionBreadcrumbs is what a session logged, as text for drafting its handoff
+type sessionBreadcrumbs struct {
+	Findings         []string
+	ResolvedUnknowns []string
+	OpenUnknowns     []string
+	DeadEnds         []string
+}
+
+// draftHandoff asks the configured model for a session's summary and next-session
+// recommendations. The agent's own summary, when given, is passed along as a hint.
+func draftHandoff(ctx context.Context, llm *LLMConfig, objective, hint string, crumbs sessionBreadcrumbs) (*handoffDraft, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Objective: %s\n", objective)
+	if hint != "" {
+		fmt.Fprintf(&prompt, "Agent's own summary: %s\n", hint)
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&prompt, "\n%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&prompt, "- %s\n", item)
+		}
+	}
+	section("Findings", crumbs.Findings)
+	section("Questions resolved", crumbs.ResolvedUnknowns)
+	section("Questions still open", crumbs.OpenUnknowns)
+	section("Dead ends", crumbs.DeadEnds)
+
+	// Breadcrumbs are scrubbed when stored; the objective and hint may not be
+	text := prompt.String()
+	scrubber, err := loadScrubber()
+	if err != nil {
+		return nil, err
+	}
+	if scrubber != nil {
+		text = scrubber.String(text)
+	}
+
+	reply, err := llm.complete(ctx, handoffDraftPrompt, text)
+	if err != nil {
+		return nil, err
+	}
+	// Models often fence JSON in a code block despite being asked not to
+	reply = strings.TrimSpace(reply)
+	if start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}"); start >= 0 && end > start {
+		reply = reply[start : end+1]
+	}
+	var draft handoffDraft
+	if err := json.Unmarshal([]byte(reply), &draft); err != nil {
+		return nil, fmt.Errorf("%s returned an unreadable handoff: %w", llm.Provider, err)
+	}
+	if strings.TrimSpace(draft.Summary) == "" {
+		return nil, fmt.Errorf("%s returned an empty summary", llm.Provider)
+	}
+	return &draft, nil
+}
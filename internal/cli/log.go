@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// currentActor returns who mutations are attributed to: the active session's AI,
+// else the agent detected from the environment, or the local user
+func currentActor(ctx context.Context) string {
+	if active, err := loadActiveSession(ctx); err == nil && active.AIID != "" {
+		return active.AIID
+	}
+	if aiID, ok := detectAIID(); ok {
+		return aiID
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "user:" + u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return "user:" + name
+	}
+	return "user"
+}
+
+// knowledgeEntities are the breadcrumb types shown in the default activity log
+var knowledgeEntities = []string{models.EntityFinding, models.EntityUnknown, models.EntityDeadEnd}
+
+// auditSummary describes an audit event in one line for text output
+func auditSummary(e *models.AuditEvent) string {
+	var payload map[string]interface{}
+	if e.Payload != nil {
+		json.Unmarshal([]byte(*e.Payload), &payload)
+	}
+	for _, key := range []string{"finding", "unknown", "approach", "objective", "subject", "name", "task_summary"} {
+		if s, ok := payload[key].(string); ok && s != "" {
+			return truncateText(s, 60)
+		}
+	}
+	return ""
+}
+
+// logCmd shows the activity log and audit trail
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show recent activity or the full audit trail",
+	Long: `Show what was recorded in this project and by whom.
+
+By default only findings, unknowns and dead ends are listed. With --audit every
+mutation (create, edit, verify, resolve, complete, delete, restore) of every entity is shown
+with its actor and payload. The audit trail is append-only.
+
+Examples:
+  memory log
+  memory log --audit
+  memory log --audit --actor claude-code --since 24h
+  memory log --audit --entity finding --id 3f2a9c1e`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		audit, _ := cmd.Flags().GetBool("audit")
+		actor, _ := cmd.Flags().GetString("actor")
+		entity, _ := cmd.Flags().GetString("entity")
+		entityID, _ := cmd.Flags().GetString("id")
+		since, _ := cmd.Flags().GetDuration("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		filter := db.AuditFilter{
+			ProjectID: project.ID,
+			Actor:     actor,
+			EntityID:  entityID,
+		}
+		if entity != "" {
+			filter.EntityTypes = []string{entity}
+		} else if !audit {
+			filter.EntityTypes = knowledgeEntities
+		}
+		if since > 0 {
+			filter.Since = float64(time.Now().Add(-since).UnixMilli()) / 1000.0
+		}
+
+		events, err := stores.Audit.List(ctx, filter, limit)
+		if err != nil {
+			return fmt.Errorf("failed to read audit trail: %w", err)
+		}
+
+		if events == nil {
+			events = []*models.AuditEvent{}
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"project": project.Name,
+				"events":  events,
+				"count":   len(events),
+			})
+			return nil
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No activity recorded.")
+			return nil
+		}
+		for _, e := range events {
+			ts := time.UnixMilli(int64(e.Timestamp * 1000)).Format("2006-01-02 15:04:05")
+			id := e.EntityID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			line := fmt.Sprintf("%s  %-14s %-8s %s %s", ts, e.Actor, e.Action, e.EntityType, id)
+			if summary := auditSummary(e); summary != "" {
+				line += "  " + summary
+			}
+			fmt.Println(line)
+			if audit && verbosity > 0 && e.Payload != nil {
+				fmt.Printf("    %s\n", *e.Payload)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	logCmd.Flags().Bool("audit", false, "Show every mutation with actor and payload")
+	logCmd.Flags().String("actor", "", "Only show events by this actor (ai_id or user:<name>)")
+	logCmd.Flags().String("entity", "", "Only show this entity type (finding, unknown, dead_end, session, goal, ...)")
+	logCmd.Flags().String("id", "", "Only show events for this entity ID (prefix)")
+	logCmd.Flags().Duration("since", 0, "Only show events within this window (e.g. 24h)")
+	logCmd.Flags().Int("limit", 50, "Maximum number of events")
+	rootCmd.AddCommand(logCmd)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// triedSeparator splits a --tried value into the approach and why it failed
+const triedSeparator = "::"
+
+// logAllCmd logs breadcrumbs of mixed types in one call
+var logAllCmd = &cobra.Command{
+	Use:   "log-all",
+	Short: "Log several findings, unknowns and dead ends at once",
+	Long: `Log any number of findings, unknowns and dead ends in one invocation and one
+transaction, for agents that summarize at the end of a step. Each flag can be repeated.
+A dead end is written as "approach::why it failed".
+
+Examples:
+  memory log-all --learned "Auth uses JWT" --learned "Tokens live 15min" \
+    --uncertain "Who rotates the signing keys?" \
+    --tried "Session cookies::CSRF exposure"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		learned, _ := cmd.Flags().GetStringArray("learned")
+		uncertain, _ := cmd.Flags().GetStringArray("uncertain")
+		tried, _ := cmd.Flags().GetStringArray("tried")
+		impact, err := impactFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		var items []IngestItem
+		for _, text := range learned {
+			items = append(items, IngestItem{Type: models.EntityFinding, Text: strings.TrimSpace(text), Impact: impact})
+		}
+		for _, text := range uncertain {
+			items = append(items, IngestItem{Type: models.EntityUnknown, Text: strings.TrimSpace(text), Impact: impact})
+		}
+		for _, text := range tried {
+			approach, whyFailed, ok := strings.Cut(text, triedSeparator)
+			if !ok {
+				return fmt.Errorf("%w: --tried %q must be \"approach%swhy it failed\"", db.ErrInvalid, text, triedSeparator)
+			}
+			items = append(items, IngestItem{
+				Type:      models.EntityDeadEnd,
+				Text:      strings.TrimSpace(approach),
+				WhyFailed: strings.TrimSpace(whyFailed),
+				Impact:    impact,
+			})
+		}
+		if len(items) == 0 {
+			return fmt.Errorf("%w: give at least one --learned, --uncertain or --tried", db.ErrInvalid)
+		}
+
+		active, err := loggingSession(cmd)
+		if err != nil {
+			return err
+		}
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		batch := make([]*LoggedBreadcrumb, 0, len(items))
+		for _, item := range items {
+			b, err := batchBreadcrumb(ctx, active, item, limits)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, b)
+		}
+		if err := logBatch(ctx, active, batch); err != nil {
+			return err
+		}
+		printBatch(batch)
+		return nil
+	},
+}
+
+func init() {
+	logAllCmd.Flags().StringArray("learned", nil, "A finding (repeatable)")
+	logAllCmd.Flags().StringArray("uncertain", nil, "An open question (repeatable)")
+	logAllCmd.Flags().StringArray("tried", nil, `A dead end as "approach::why it failed" (repeatable)`)
+	logAllCmd.Flags().Float64("impact", defaultImpact, "How much these matter, from trivial (0.1) to critical (1.0)")
+	logAllCmd.Flags().String("session", "", "Log to this session instead of the active one, e.g. to backfill")
+	logAllCmd.Flags().Bool("allow-long", false, "Keep text longer than the length limit whole")
+	rootCmd.AddCommand(logAllCmd)
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+var (
+	verbosity int    // -v logs slow queries, failures and command timing; -vv logs every DB query and git call; -vvv also traces context assembly
+	logFile   string // --log-file path; diagnostics go to stderr when empty
+
+	logOutput *os.File // Open --log-file, closed when the command finishes
+)
+
+func init() {
+	// Until flags are parsed only warnings are logged
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+}
+
+// levelTrace is below debug: why start and status put items into the context or left them out
+const levelTrace = slog.LevelDebug - 4
+
+// traceContext logs a context assembly decision at -vvv
+func traceContext(ctx context.Context, msg string, args ...any) {
+	slog.Log(ctx, levelTrace, "context: "+msg, args...)
+}
+
+// setupLogging installs the default slog logger for the requested verbosity.
+// Without -v only warnings are logged, so agents reading stdout see no noise.
+func setupLogging() error {
+	level := slog.LevelWarn
+	switch {
+	case verbosity >= 3:
+		level = levelTrace
+	case verbosity == 2:
+		level = slog.LevelDebug
+	case verbosity == 1:
+		level = slog.LevelInfo
+	}
+
+	var w io.Writer = os.Stderr
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logOutput = f
+		w = f
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && a.Value.Any() == levelTrace {
+				a.Value = slog.StringValue("TRACE")
+			}
+			return a
+		},
+	})))
+	return nil
+}
+
+// closeLogging flushes and closes the --log-file, if any
+func closeLogging() {
+	if logOutput != nil {
+		logOutput.Close()
+		logOutput = nil
+	}
+}
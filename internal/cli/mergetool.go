@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// mergeDriverName is the git merge driver memory registers
+const mergeDriverName = "memory"
+
+// mergeAttributesLine routes the shared knowledge files to the merge driver
+const mergeAttributesLine = ".memory/shared/**/*.jsonl merge=" + mergeDriverName
+
+// mergetoolCmd merges shared knowledge files for git
+var mergetoolCmd = &cobra.Command{
+	Use:   "mergetool [base] [ours] [theirs]",
+	Short: "Git merge driver for .memory/shared files",
+	Long: `Three-way merge of the JSONL files written by 'memory share export', matching entries
+by ID instead of by line. Git calls it with the common ancestor, our version and their
+version; the merged result replaces ours.
+
+Entries added, changed or removed on only one side take that side. Entries changed on
+both sides keep the most recently verified version with the union of both tags and
+relations; an entry changed on one side and removed on the other is kept.
+
+Run once per clone to register the driver and route .memory/shared through it:
+
+  memory mergetool --install`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if install, _ := cmd.Flags().GetBool("install"); install {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(3)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if install, _ := cmd.Flags().GetBool("install"); install {
+			return installMergeDriver(ctx)
+		}
+
+		base, err := readSharedEntries(args[0])
+		if err != nil {
+			return err
+		}
+		ours, err := readSharedEntries(args[1])
+		if err != nil {
+			return err
+		}
+		theirs, err := readSharedEntries(args[2])
+		if err != nil {
+			return err
+		}
+
+		merged, err := mergeSharedEntries(base, ours, theirs)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[1], merged, 0644)
+	},
+}
+
+// readSharedEntries reads a shared JSONL file into raw lines keyed by entry ID
+func readSharedEntries(path string) (map[string]string, error) {
+	entries := make(map[string]string)
+	err := readJSONL(path, func(line []byte) error {
+		var entry struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		if entry.ID == "" {
+			return fmt.Errorf("entry has no id")
+		}
+		entries[entry.ID] = string(line)
+		return nil
+	})
+	return entries, err
+}
+
+// mergeSharedEntries three-way merges shared entries and renders them sorted by ID
+func mergeSharedEntries(base, ours, theirs map[string]string) ([]byte, error) {
+	ids := make(map[string]bool)
+	for _, side := range []map[string]string{base, ours, theirs} {
+		for id := range side {
+			ids[id] = true
+		}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var out bytes.Buffer
+	for _, id := range sorted {
+		b, inBase := base[id]
+		o, inOurs := ours[id]
+		t, inTheirs := theirs[id]
+
+		var line string
+		switch {
+		case inOurs && inTheirs && o == t:
+			line = o
+		case !inTheirs && inBase && o == b, !inOurs && inBase && t == b:
+			continue // Removed on one side, untouched on the other
+		case !inTheirs:
+			line = o
+		case !inOurs:
+			line = t
+		case inBase && o == b:
+			line = t
+		case inBase && t == b:
+			line = o
+		default:
+			merged, err := mergeSharedEntry(o, t)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge %s: %w", id, err)
+			}
+			line = merged
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// mergeSharedEntry reconciles an entry changed on both sides: the more recently
+// verified version wins and tags and relations are combined
+func mergeSharedEntry(ours, theirs string) (string, error) {
+	if strings.Contains(ours, `"approach":`) {
+		var o, t sharedDeadEnd
+		if err := json.Unmarshal([]byte(ours), &o); err != nil {
+			return "", err
+		}
+		if err := json.Unmarshal([]byte(theirs), &t); err != nil {
+			return "", err
+		}
+		winner := o
+		if theirs > ours { // Nothing orders dead end edits; pick one deterministically
+			winner = t
+		}
+		winner.Tags = unionTags(o.Tags, t.Tags)
+		winner.Relations = unionRelations(o.Relations, t.Relations)
+		data, err := json.Marshal(winner)
+		return string(data), err
+	}
+
+	var o, t sharedFinding
+	if err := json.Unmarshal([]byte(ours), &o); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal([]byte(theirs), &t); err != nil {
+		return "", err
+	}
+	winner := o
+	ov, tv := 0.0, 0.0
+	if o.LastVerifiedTimestamp != nil {
+		ov = *o.LastVerifiedTimestamp
+	}
+	if t.LastVerifiedTimestamp != nil {
+		tv = *t.LastVerifiedTimestamp
+	}
+	if tv > ov || (tv == ov && theirs > ours) {
+		winner = t
+	}
+	winner.Tags = unionTags(o.Tags, t.Tags)
+	winner.Relations = unionRelations(o.Relations, t.Relations)
+	data, err := json.Marshal(winner)
+	return string(data), err
+}
+
+// unionTags combines two tag sets, sorted
+func unionTags(a, b []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range append(append([]string{}, a...), b...) {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// unionRelations combines two relation sets, sorted like the folded read model
+func unionRelations(a, b []models.BreadcrumbRelation) []models.BreadcrumbRelation {
+	seen := make(map[models.BreadcrumbRelation]bool)
+	var out []models.BreadcrumbRelation
+	for _, r := range append(append([]models.BreadcrumbRelation{}, a...), b...) {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TargetID != out[j].TargetID {
+			return out[i].TargetID < out[j].TargetID
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// installMergeDriver registers the driver in the repository config and routes the
+// shared files to it in .gitattributes
+func installMergeDriver(ctx context.Context) error {
+	top, err := gitRepoRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("not in a git repository")
+	}
+	if _, err := gitOutput(ctx, "config", "merge."+mergeDriverName+".name", "memory shared knowledge merge"); err != nil {
+		return fmt.Errorf("failed to configure merge driver: %w", err)
+	}
+	if _, err := gitOutput(ctx, "config", "merge."+mergeDriverName+".driver", "memory mergetool %O %A %B"); err != nil {
+		return fmt.Errorf("failed to configure merge driver: %w", err)
+	}
+
+	attributes := filepath.Join(top, ".gitattributes")
+	data, err := os.ReadFile(attributes)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	added := false
+	if !strings.Contains(string(data), mergeAttributesLine) {
+		content := string(data)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += mergeAttributesLine + "\n"
+		if err := os.WriteFile(attributes, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write .gitattributes: %w", err)
+		}
+		added = true
+	}
+
+	if outputText {
+		fmt.Printf("✓ Registered merge driver %q\n", mergeDriverName)
+		if added {
+			fmt.Println("✓ Added .memory/shared to .gitattributes (commit it so teammates use the driver)")
+		}
+	} else {
+		outputResult(map[string]interface{}{
+			"status":           "installed",
+			"driver":           mergeDriverName,
+			"gitattributes":    attributes,
+			"attributes_added": added,
+		})
+	}
+	return nil
+}
+
+func init() {
+	mergetoolCmd.Flags().Bool("install", false, "Register the merge driver for this repository")
+	rootCmd.AddCommand(mergetoolCmd)
+}
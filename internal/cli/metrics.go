@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"github.com/AbdouB/memory/internal/metrics"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// Metrics served on /metrics by serve and daemon --metrics-addr. One-shot commands
+// record them too, but exit before anyone can scrape them.
+var (
+	breadcrumbsLogged    = metrics.NewCounter("memory_breadcrumbs_logged_total", "Findings, unknowns and dead ends logged or pushed by sync clients, by type.", "type")
+	sessionsStarted      = metrics.NewCounter("memory_sessions_started_total", "Sessions started.")
+	sessionsCompleted    = metrics.NewCounter("memory_sessions_completed_total", "Sessions ended with done.")
+	verifications        = metrics.NewCounter("memory_verifications_total", "Findings verified, locally or by sync clients.")
+	queryDuration        = metrics.NewHistogram("memory_query_duration_seconds", "Time to answer a query command or gRPC Query call.", metrics.DurationBuckets)
+	contextBuildDuration = metrics.NewHistogram("memory_context_build_duration_seconds", "Time to assemble the context start, status and context return.", metrics.DurationBuckets)
+)
+
+// countEvent updates the counters for an event a command emits
+func countEvent(name string) {
+	switch name {
+	case EventSessionStarted:
+		sessionsStarted.Inc()
+	case EventSessionDone:
+		sessionsCompleted.Inc()
+	case EventFindingLogged:
+		breadcrumbsLogged.Inc(models.EntityFinding)
+	case EventUnknownLogged:
+		breadcrumbsLogged.Inc(models.EntityUnknown)
+	case EventDeadEndLogged:
+		breadcrumbsLogged.Inc(models.EntityDeadEnd)
+	}
+}
+
+// countSyncedEvents updates the counters for breadcrumb events a sync client pushed
+func countSyncedEvents(events []*models.BreadcrumbEvent) {
+	for _, ev := range events {
+		switch ev.Kind {
+		case models.EventFindingCreated, models.EventUnknownCreated, models.EventDeadEndCreated:
+			breadcrumbsLogged.Inc(ev.EntityType)
+		case models.EventFindingVerified:
+			verifications.Inc()
+		}
+	}
+}
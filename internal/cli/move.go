@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// mvCmd files a breadcrumb under another project
+var mvCmd = &cobra.Command{
+	Use:   "mv [id...] --to-project [name]",
+	Short: "Move findings, unknowns or dead ends to another project",
+	Long: `Move breadcrumbs logged under the wrong project, typically because they were
+recorded from a directory that defaulted to another project. A moved breadcrumb keeps
+its ID, timestamps, session and history; the move itself is recorded as an event, so
+it survives merges and syncs.
+
+Examples:
+  memory mv 3f2a9c1e-... --to-project auth-service
+  memory mv 3f2a9c1e-... 7b1d04aa-... --to-project auth-service`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return transferBreadcrumbs(cmd, args, false)
+	},
+}
+
+// cpCmd files a copy of a breadcrumb under another project
+var cpCmd = &cobra.Command{
+	Use:   "cp [id...] --to-project [name]",
+	Short: "Copy findings, unknowns or dead ends to another project",
+	Long: `Copy breadcrumbs into another project, leaving the originals where they are. A
+copy gets a new ID but keeps the original's text, timestamps, session, verification,
+tags and relations, plus a copied_from relation back to the original.
+
+Examples:
+  memory cp 3f2a9c1e-... --to-project billing`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return transferBreadcrumbs(cmd, args, true)
+	},
+}
+
+// transferBreadcrumbs moves or copies each breadcrumb to the --to-project project
+func transferBreadcrumbs(cmd *cobra.Command, ids []string, copying bool) error {
+	ctx := cmd.Context()
+	projectName, _ := cmd.Flags().GetString("to-project")
+	if projectName == "" {
+		return fmt.Errorf("%w: --to-project is required", db.ErrInvalid)
+	}
+	project, err := projectByNameOrID(ctx, projectName)
+	if err != nil {
+		return err
+	}
+
+	repo := stores.Breadcrumbs
+	action, status := "move", "moved"
+	if copying {
+		action, status = "copy", "copied"
+	}
+	var results []map[string]interface{}
+	for _, id := range ids {
+		entityType, err := lookupBreadcrumb(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+		result := map[string]interface{}{
+			"type": entityType,
+			"id":   id,
+		}
+		if copying {
+			copyID, err := repo.CopyBreadcrumb(ctx, entityType, id, project.ID)
+			if err != nil {
+				return fmt.Errorf("failed to %s %s: %w", action, entityType, err)
+			}
+			result["copy_id"] = copyID
+		} else if err := repo.MoveBreadcrumb(ctx, entityType, id, project.ID); err != nil {
+			return fmt.Errorf("failed to %s %s: %w", action, entityType, err)
+		}
+		results = append(results, result)
+
+		if outputText {
+			if copying {
+				fmt.Printf("✓ %s %s copied to %s as %s\n", entityType, id, project.Name, result["copy_id"])
+			} else {
+				fmt.Printf("✓ %s %s moved to %s\n", entityType, id, project.Name)
+			}
+		}
+	}
+
+	if !outputText {
+		outputResult(map[string]interface{}{
+			"status":      status,
+			"project":     project.Name,
+			"project_id":  project.ID,
+			"breadcrumbs": results,
+		})
+	}
+	return nil
+}
+
+// projectByNameOrID returns an existing project by name, or by ID when no project has
+// that name
+func projectByNameOrID(ctx context.Context, nameOrID string) (*models.Project, error) {
+	project, err := stores.Projects.GetByName(ctx, nameOrID)
+	if errors.Is(err, db.ErrNotFound) {
+		project, err = stores.Projects.Get(ctx, nameOrID)
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("no project named %s: %w", nameOrID, db.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+func init() {
+	mvCmd.Flags().String("to-project", "", "Project to move the breadcrumbs to (name or ID)")
+	cpCmd.Flags().String("to-project", "", "Project to copy the breadcrumbs to (name or ID)")
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(cpCmd)
+}
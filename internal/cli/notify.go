@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// DigestSession is a completed session included in a digest
+type DigestSession struct {
+	SessionID string             `json:"session_id"`
+	AIID      string             `json:"ai_id"`
+	Objective string             `json:"objective"`
+	Summary   string             `json:"summary"`
+	Duration  string             `json:"duration,omitempty"`
+	Deltas    map[string]float64 `json:"deltas,omitempty"`
+}
+
+// DigestFinding is a finding that went stale during the digest window
+type DigestFinding struct {
+	ID          string `json:"id"`
+	Finding     string `json:"finding"`
+	Scope       string `json:"scope,omitempty"`
+	FileChanged bool   `json:"file_changed"`
+}
+
+// SessionDigest summarizes project activity over a time window
+type SessionDigest struct {
+	Project    string          `json:"project"`
+	Since      time.Time       `json:"since"`
+	Sessions   []DigestSession `json:"sessions"`
+	NewlyStale []DigestFinding `json:"newly_stale"`
+
+	// Filled in by collectDigestStats for memory digest
+	Stats         *DigestStats          `json:"stats,omitempty"`
+	Trend         *ConfidenceTrend      `json:"confidence_trend,omitempty"`
+	AgingUnknowns []models.AgingUnknown `json:"aging_unknowns,omitempty"`
+}
+
+// buildSessionDigest collects completed sessions and newly stale findings since a point in time
+func buildSessionDigest(ctx context.Context, project *models.Project, since time.Time) (*SessionDigest, error) {
+	digest := &SessionDigest{
+		Project:    project.Name,
+		Since:      since,
+		Sessions:   []DigestSession{},
+		NewlyStale: []DigestFinding{},
+	}
+	sinceTS := float64(since.UnixMilli()) / 1000.0
+
+	handoffs, err := stores.Handoffs.List(ctx, project.ID, "", 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list handoffs: %w", err)
+	}
+	sessionRepo := stores.Sessions
+	for _, h := range handoffs {
+		if h.CreatedAt < sinceTS {
+			continue
+		}
+		entry := DigestSession{SessionID: h.SessionID, AIID: h.AIID}
+		if h.TaskSummary != nil {
+			entry.Summary = *h.TaskSummary
+		}
+		if h.DurationSeconds != nil {
+			entry.Duration = (time.Duration(*h.DurationSeconds) * time.Second).Round(time.Minute).String()
+		}
+		if h.EpistemicDeltas != nil {
+			json.Unmarshal([]byte(*h.EpistemicDeltas), &entry.Deltas)
+		}
+		if session, _ := sessionRepo.Get(ctx, h.SessionID); session != nil && session.Subject != nil {
+			entry.Objective = *session.Subject
+		}
+		digest.Sessions = append(digest.Sessions, entry)
+	}
+
+	findings, err := stores.Breadcrumbs.ListFindingsWithStaleness(ctx, project.ID, "", 500)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list findings: %w", err)
+	}
+	primeFindingHashes(ctx, findings)
+	for _, f := range findings {
+		fileChanged := findingFileChanged(ctx, f)
+		if f.GetStalenessStatus(fileChanged) != models.StatusStale || f.StaleSince() < sinceTS {
+			continue
+		}
+		entry := DigestFinding{ID: f.ID, Finding: f.Finding, FileChanged: fileChanged}
+		if f.Subject != nil {
+			entry.Scope = *f.Subject
+		}
+		digest.NewlyStale = append(digest.NewlyStale, entry)
+	}
+	return digest, nil
+}
+
+// renderSlackDigest formats a digest using Slack mrkdwn
+func renderSlackDigest(d *SessionDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Memory digest for %s* (since %s)\n", d.Project, d.Since.Format("Jan 2 15:04"))
+
+	if len(d.Sessions) == 0 {
+		b.WriteString("\nNo sessions completed.\n")
+	} else {
+		fmt.Fprintf(&b, "\n*%d session(s) completed*\n", len(d.Sessions))
+		for _, s := range d.Sessions {
+			title := s.Objective
+			if title == "" {
+				title = s.Summary
+			}
+			fmt.Fprintf(&b, "• *%s* (%s", title, s.AIID)
+			if s.Duration != "" {
+				fmt.Fprintf(&b, ", %s", s.Duration)
+			}
+			b.WriteString(")")
+			if len(s.Deltas) > 0 {
+				keys := make([]string, 0, len(s.Deltas))
+				for k := range s.Deltas {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				parts := make([]string, 0, len(keys))
+				for _, k := range keys {
+					parts = append(parts, fmt.Sprintf("%s %+.2f", k, s.Deltas[k]))
+				}
+				fmt.Fprintf(&b, " — %s", strings.Join(parts, ", "))
+			}
+			b.WriteString("\n")
+			if s.Summary != "" && s.Summary != title {
+				fmt.Fprintf(&b, "    %s\n", s.Summary)
+			}
+		}
+	}
+
+	if len(d.NewlyStale) > 0 {
+		fmt.Fprintf(&b, "\n*%d finding(s) went stale*\n", len(d.NewlyStale))
+		for _, f := range d.NewlyStale {
+			line := f.Finding
+			if f.Scope != "" {
+				line += fmt.Sprintf(" (`%s`)", f.Scope)
+			}
+			if f.FileChanged {
+				line += " — file changed"
+			}
+			fmt.Fprintf(&b, "• %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// postSlackMessage sends text to a Slack incoming webhook
+func postSlackMessage(webhookURL, text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: githubAPITimeout}
+	resp, err := client.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Slack request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("Slack webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notifyCmd posts a digest of recent activity
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Post a digest of recent sessions to Slack",
+	Long: `Build a digest of sessions completed (with their confidence deltas) and findings
+that went stale within a time window, and post it to a Slack incoming webhook.
+
+Without a webhook the digest is printed instead. The webhook can also be set with
+MEMORY_SLACK_WEBHOOK. 'memory notify schedule' has the daemon post it regularly.
+
+Examples:
+  memory notify --slack https://hooks.slack.com/services/...
+  memory notify --since 7d            # Weekly digest preview`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		webhook, _ := cmd.Flags().GetString("slack")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		window, err := parseWindow(sinceStr)
+		if err != nil {
+			return err
+		}
+		if webhook == "" {
+			webhook = os.Getenv("MEMORY_SLACK_WEBHOOK")
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		digest, err := buildSessionDigest(ctx, project, time.Now().Add(-window))
+		if err != nil {
+			return err
+		}
+
+		if webhook == "" {
+			if outputText {
+				fmt.Print(renderSlackDigest(digest))
+			} else {
+				outputResult(digest)
+			}
+			return nil
+		}
+
+		if err := postSlackMessage(webhook, renderSlackDigest(digest)); err != nil {
+			return err
+		}
+		if outputText {
+			fmt.Printf("✓ Digest posted (%d sessions, %d newly stale)\n", len(digest.Sessions), len(digest.NewlyStale))
+		} else {
+			outputResult(map[string]interface{}{
+				"status":      "posted",
+				"sessions":    len(digest.Sessions),
+				"newly_stale": len(digest.NewlyStale),
+			})
+		}
+		return nil
+	},
+}
+
+// digestPostedKey is the meta key holding when the scheduled digest was last posted
+const digestPostedKey = "digest_posted_at"
+
+// digestCheckInterval is how often the daemon checks whether a scheduled digest is due
+const digestCheckInterval = 10 * time.Minute
+
+// postScheduledDigest posts the digest scheduled with 'memory notify schedule' when a
+// full period has passed since the last one, covering the time since then. It returns
+// the digest posted, nil when none was due.
+func postScheduledDigest(ctx context.Context, now time.Time) (*SessionDigest, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Digest == nil || cfg.Digest.Every == "" {
+		return nil, nil
+	}
+	every, err := parseWindow(cfg.Digest.Every)
+	if err != nil {
+		return nil, err
+	}
+	since := now.Add(-every)
+	last, err := stores.Sync.GetMeta(ctx, digestPostedKey)
+	if err != nil {
+		return nil, err
+	}
+	if last != "" {
+		if posted, err := time.Parse(time.RFC3339, last); err == nil {
+			if now.Sub(posted) < every {
+				return nil, nil
+			}
+			since = posted
+		}
+	}
+
+	webhook := cfg.Digest.Slack
+	if webhook == "" {
+		webhook = os.Getenv("MEMORY_SLACK_WEBHOOK")
+	}
+	if webhook == "" {
+		return nil, fmt.Errorf("%w: the digest schedule has no Slack webhook", db.ErrInvalid)
+	}
+	project, err := stores.Projects.Get(ctx, cfg.Digest.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest project: %w", err)
+	}
+	digest, err := buildSessionDigest(ctx, project, since)
+	if err != nil {
+		return nil, err
+	}
+	if err := postSlackMessage(webhook, renderSlackDigest(digest)); err != nil {
+		return nil, err
+	}
+	if err := stores.Sync.SetMeta(ctx, digestPostedKey, now.UTC().Format(time.RFC3339)); err != nil {
+		return nil, fmt.Errorf("failed to record digest: %w", err)
+	}
+	return digest, nil
+}
+
+// notifyScheduleCmd shows or sets the digest the daemon posts
+var notifyScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Have the daemon post the digest regularly",
+	Long: `Set how often 'memory daemon' posts the digest of the current directory's project
+to Slack. The first digest is posted one period after scheduling; each covers the
+time since the one before, so digests missed while the daemon was down are caught up
+in one. The webhook defaults to MEMORY_SLACK_WEBHOOK as seen by the daemon.
+
+Without flags the current schedule is shown. The schedule is stored in config.json
+next to the database.
+
+Examples:
+  memory notify schedule --every 7d --slack https://hooks.slack.com/services/...
+  memory notify schedule --every 1d
+  memory notify schedule --off`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		every, _ := cmd.Flags().GetString("every")
+		webhook, _ := cmd.Flags().GetString("slack")
+		off, _ := cmd.Flags().GetBool("off")
+
+		changed := off || every != "" || webhook != ""
+		switch {
+		case off:
+			cfg.Digest = nil
+		case changed:
+			schedule := &config.DigestConfig{}
+			if cfg.Digest != nil {
+				*schedule = *cfg.Digest
+			}
+			if every != "" {
+				if _, err := parseWindow(every); err != nil {
+					return err
+				}
+				schedule.Every = every
+			}
+			if schedule.Every == "" {
+				return fmt.Errorf("%w: --every is required", db.ErrInvalid)
+			}
+			if webhook != "" {
+				if !isURLScope(webhook) {
+					return fmt.Errorf("%w: --slack must be an http(s) URL", db.ErrInvalid)
+				}
+				schedule.Slack = webhook
+			}
+			project, err := getOrCreateDefaultProject(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get project: %w", err)
+			}
+			schedule.ProjectID = project.ID
+			cfg.Digest = schedule
+			// The first digest is due one period from now
+			if err := stores.Sync.SetMeta(ctx, digestPostedKey, time.Now().UTC().Format(time.RFC3339)); err != nil {
+				return fmt.Errorf("failed to start schedule: %w", err)
+			}
+		}
+		if changed {
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		if !outputText {
+			status := "current"
+			if changed {
+				status = "saved"
+			}
+			result := map[string]interface{}{"status": status, "scheduled": cfg.Digest != nil}
+			if cfg.Digest != nil {
+				result["every"] = cfg.Digest.Every
+				result["project_id"] = cfg.Digest.ProjectID
+				result["slack"] = cfg.Digest.Slack != ""
+			}
+			outputResult(result)
+			return nil
+		}
+		if cfg.Digest == nil {
+			fmt.Println("No digest scheduled")
+			return nil
+		}
+		webhookSource := "config.json"
+		if cfg.Digest.Slack == "" {
+			webhookSource = "MEMORY_SLACK_WEBHOOK"
+		}
+		fmt.Printf("Digest every %s for project %s, posted to the webhook in %s\n", cfg.Digest.Every, cfg.Digest.ProjectID[:8], webhookSource)
+		return nil
+	},
+}
+
+func init() {
+	notifyCmd.Flags().String("slack", "", "Slack incoming webhook URL")
+	notifyCmd.Flags().String("since", "24h", "Digest window, e.g. 24h, 7d or 2w")
+	notifyScheduleCmd.Flags().String("every", "", "Post a digest this often, e.g. 1d or 1w")
+	notifyScheduleCmd.Flags().String("slack", "", "Slack incoming webhook URL")
+	notifyScheduleCmd.Flags().Bool("off", false, "Stop posting scheduled digests")
+	notifyCmd.AddCommand(notifyScheduleCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
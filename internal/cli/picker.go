@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// stdinIsTerminal reports whether a person is typing on stdin
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// findingMatch is one candidate in a disambiguation payload
+type findingMatch struct {
+	Index       int    `json:"index"` // What --pick takes
+	ID          string `json:"id"`
+	ShortID     string `json:"short_id"`
+	Finding     string `json:"finding"`
+	Status      string `json:"status"`
+	DaysOld     int    `json:"days_old"`
+	FileChanged bool   `json:"file_changed"`
+	Command     string `json:"command"` // Runs the same action on this finding
+}
+
+// pickFinding chooses one of several findings matching a search. pick is the 1-based
+// --pick choice, or 0 to ask: in --text mode on a terminal a numbered picker is shown,
+// otherwise the matches are printed with the command that acts on each. command builds
+// that command from a short ID. A nil finding with no error means nothing was chosen.
+func pickFinding(ctx context.Context, findings []*models.Finding, pick int, command func(shortID string) string) (*models.Finding, error) {
+	if pick > 0 {
+		if pick > len(findings) {
+			return nil, fmt.Errorf("%w: --pick %d but only %d findings match", db.ErrInvalid, pick, len(findings))
+		}
+		return findings[pick-1], nil
+	}
+
+	primeFindingHashes(ctx, findings)
+	matches := make([]findingMatch, len(findings))
+	for i, f := range findings {
+		fileChanged := findingFileChanged(ctx, f)
+		matches[i] = findingMatch{
+			Index:       i + 1,
+			ID:          f.ID,
+			ShortID:     f.ID[:8],
+			Finding:     f.Finding,
+			Status:      string(f.GetStalenessStatus(fileChanged)),
+			DaysOld:     int(f.DaysSinceVerified()),
+			FileChanged: fileChanged,
+			Command:     command(f.ID[:8]),
+		}
+	}
+
+	if !outputText {
+		outputResult(map[string]interface{}{
+			"status":  "multiple_matches",
+			"message": "Multiple findings match. Re-run with --pick <index> or one of the commands.",
+			"matches": matches,
+		})
+		return nil, nil
+	}
+
+	fmt.Println("Multiple findings match:")
+	for _, m := range matches {
+		fmt.Printf("  %d) %s %s (id: %s)\n", m.Index, stalenessIcon(models.StalenessStatus(m.Status)), m.Finding, m.ShortID)
+	}
+
+	// The REPL reads commands from stdin, so it can't also answer the picker
+	if inRepl || !stdinIsTerminal() {
+		fmt.Println("Re-run with --pick <number> or --id <id>.")
+		return nil, nil
+	}
+	fmt.Printf("Pick a finding [1-%d, Enter to cancel]: ", len(matches))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.TrimSpace(line)
+	if err != nil && line == "" {
+		fmt.Println("\nRe-run with --pick <number> or --id <id>.")
+		return nil, nil
+	}
+	if line == "" {
+		fmt.Println("Cancelled.")
+		return nil, nil
+	}
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 || n > len(findings) {
+		return nil, fmt.Errorf("%w: pick a number from 1 to %d", db.ErrInvalid, len(findings))
+	}
+	return findings[n-1], nil
+}
+
+// lookupFinding gets a finding by full ID or by the short ID prefix shown in text output.
+// An ambiguous prefix goes through the picker.
+func lookupFinding(ctx context.Context, id string, pick int, command func(shortID string) string) (*models.Finding, error) {
+	repo := stores.Breadcrumbs
+	finding, err := repo.GetFinding(ctx, id)
+	if err == nil || !errors.Is(err, db.ErrNotFound) {
+		return finding, err
+	}
+	findings, err := repo.FindFindingsByIDPrefix(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch len(findings) {
+	case 0:
+		return nil, fmt.Errorf("finding %s: %w", id, db.ErrNotFound)
+	case 1:
+		return findings[0], nil
+	}
+	return pickFinding(ctx, findings, pick, command)
+}
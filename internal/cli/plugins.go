@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// execHookTimeout bounds how long a project hook may run
+const execHookTimeout = 30 * time.Second
+
+// eventHookNames maps events to the project hook run after them
+var eventHookNames = map[string]string{
+	EventSessionStarted: "on-start",
+	EventFindingLogged:  "on-learned",
+	EventUnknownLogged:  "on-uncertain",
+	EventDeadEndLogged:  "on-tried",
+	EventSessionDone:    "on-done",
+}
+
+// execHooksDir returns the directory holding project hooks
+func execHooksDir() string {
+	return filepath.Join(memoryDir(), "hooks")
+}
+
+// findExecHook returns the path of an executable project hook, or empty string if none
+func findExecHook(name string) string {
+	path := filepath.Join(execHooksDir(), name)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		return "" // Not executable, like git ignores non-executable hooks
+	}
+	return path
+}
+
+// runExecHook runs a project hook with the JSON payload on stdin.
+// Returns the hook's trimmed stderr (or stdout) as the error message on non-zero exit.
+// A missing hook is not an error.
+func runExecHook(ctx context.Context, name, projectID string, payload interface{}) error {
+	path := findExecHook(name)
+	if path == "" {
+		return nil
+	}
+
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, execHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"MEMORY_HOOK="+name,
+		"MEMORY_PROJECT_ID="+projectID,
+		"MEMORY_DB="+database.Path(),
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("hook %s timed out after %s", name, execHookTimeout)
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("hook %s: %s", name, msg)
+	}
+	return nil
+}
+
+// validateWithHook runs a pre-* hook before a write; a non-zero exit rejects the write
+func validateWithHook(ctx context.Context, name, projectID string, payload interface{}) error {
+	if err := runExecHook(ctx, name, projectID, payload); err != nil {
+		return fmt.Errorf("rejected by %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// profileEnvVar picks the profile for one process, overriding `profile use`
+const profileEnvVar = "MEMORY_PROFILE"
+
+var (
+	activeProfileName string          // Name of the profile in effect, if any
+	activeProfile     *config.Profile // Its settings, applied before every command
+)
+
+// profilesDir is the user's memory directory. Profiles describe the agents on a
+// machine, so they are shared by every project.
+func profilesDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".memory"
+	}
+	return filepath.Join(home, ".memory")
+}
+
+// selectedProfile returns the name of the profile in effect: MEMORY_PROFILE, else the
+// one chosen with `profile use`
+func selectedProfile(profiles *config.Profiles) string {
+	if name := os.Getenv(profileEnvVar); name != "" {
+		return name
+	}
+	return profiles.Current
+}
+
+// applyProfile loads the profile in effect and applies its output preferences unless
+// the matching flags were given
+func applyProfile(cmd *cobra.Command) error {
+	activeProfileName, activeProfile = "", nil
+	profiles, err := config.LoadProfiles(profilesDir())
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+	name := selectedProfile(profiles)
+	if name == "" {
+		return nil
+	}
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		// Profile commands still run so the missing profile can be created
+		if cmd.Parent() == profileCmd {
+			return nil
+		}
+		return fmt.Errorf("%w: profile %q does not exist (see 'memory profile show')", db.ErrInvalid, name)
+	}
+
+	activeProfileName, activeProfile = name, profile
+	if profile.Text && !cmd.Flags().Changed("text") {
+		outputText = true
+	}
+	if profile.NoColor && !cmd.Flags().Changed("no-color") {
+		noColor = true
+	}
+	return nil
+}
+
+// profileTags returns the tags the profile in effect adds to every breadcrumb
+func profileTags() []string {
+	if activeProfile == nil {
+		return nil
+	}
+	return activeProfile.Tags
+}
+
+// normalizeTags lowercases, trims, sorts and deduplicates tags
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return slices.Compact(normalized)
+}
+
+// printProfile describes a profile in text output
+func printProfile(name string, p *config.Profile, current bool) {
+	marker := " "
+	if current {
+		marker = "*"
+	}
+	fmt.Printf("%s %s\n", marker, name)
+	if p.AIID != "" {
+		fmt.Printf("    AI ID: %s\n", p.AIID)
+	}
+	if len(p.Tags) > 0 {
+		fmt.Printf("    Tags: %s\n", strings.Join(p.Tags, ", "))
+	}
+	var output []string
+	if p.Text {
+		output = append(output, "text")
+	}
+	if p.NoColor {
+		output = append(output, "no color")
+	}
+	if len(output) > 0 {
+		fmt.Printf("    Output: %s\n", strings.Join(output, ", "))
+	}
+}
+
+// profileCmd groups agent profile commands
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage agent profiles on this machine",
+	Long: `A profile names one agent configuration: its AI ID, tags added to every breadcrumb it
+logs, and output preferences. Profiles are stored in ~/.memory/profiles.json and shared
+by every project on the machine.
+
+The profile in effect is the one named by MEMORY_PROFILE, else the one chosen with
+'memory profile use'. Flags still override its settings, and MEMORY_AI_ID its AI ID.`,
+}
+
+// profileCreateCmd creates or replaces a profile
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create an agent profile",
+	Long: `Create a profile. An existing profile is only replaced with --force.
+
+Examples:
+  memory profile create reviewer --ai-id claude-reviewer --tag review --use
+  memory profile create human --ai-id user:alice --text
+  MEMORY_PROFILE=reviewer memory learned "..."`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := strings.TrimSpace(args[0])
+		if name == "" {
+			return fmt.Errorf("%w: profile name is empty", db.ErrInvalid)
+		}
+		aiID, _ := cmd.Flags().GetString("ai-id")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		text, _ := cmd.Flags().GetBool("text-output")
+		plain, _ := cmd.Flags().GetBool("plain")
+		use, _ := cmd.Flags().GetBool("use")
+		force, _ := cmd.Flags().GetBool("force")
+
+		profiles, err := config.LoadProfiles(profilesDir())
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %w", err)
+		}
+		if _, exists := profiles.Profiles[name]; exists && !force {
+			return fmt.Errorf("%w: profile %q already exists (use --force to replace it)", db.ErrInvalid, name)
+		}
+		profile := &config.Profile{
+			AIID:    strings.TrimSpace(aiID),
+			Tags:    normalizeTags(tags),
+			Text:    text,
+			NoColor: plain,
+		}
+		if profiles.Profiles == nil {
+			profiles.Profiles = map[string]*config.Profile{}
+		}
+		profiles.Profiles[name] = profile
+		if use {
+			profiles.Current = name
+		}
+		if err := profiles.Save(profilesDir()); err != nil {
+			return fmt.Errorf("failed to save profiles: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Profile %s saved\n", name)
+			if use {
+				fmt.Println("  Now in use")
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status":  "created",
+				"name":    name,
+				"profile": profile,
+				"current": profiles.Current == name,
+			})
+		}
+		return nil
+	},
+}
+
+// profileUseCmd chooses the profile in effect
+var profileUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Choose the profile in effect on this machine",
+	Long: `Make a profile the default for every command on this machine. MEMORY_PROFILE still
+picks a different one for a single process.
+
+Examples:
+  memory profile use reviewer
+  memory profile use --none`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		none, _ := cmd.Flags().GetBool("none")
+		if none == (len(args) == 1) {
+			return fmt.Errorf("%w: give a profile name or --none", db.ErrInvalid)
+		}
+
+		profiles, err := config.LoadProfiles(profilesDir())
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %w", err)
+		}
+		name := ""
+		if !none {
+			name = args[0]
+			if _, ok := profiles.Profiles[name]; !ok {
+				return fmt.Errorf("%w: profile %q does not exist", db.ErrInvalid, name)
+			}
+		}
+		profiles.Current = name
+		if err := profiles.Save(profilesDir()); err != nil {
+			return fmt.Errorf("failed to save profiles: %w", err)
+		}
+
+		if outputText {
+			if name == "" {
+				fmt.Println("✓ No profile in use")
+			} else {
+				fmt.Printf("✓ Using profile %s\n", name)
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status":  "saved",
+				"current": name,
+			})
+		}
+		return nil
+	},
+}
+
+// profileShowCmd shows one profile, or all of them
+var profileShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show agent profiles",
+	Long: `Show a profile's settings. Without a name, every profile is listed and the one in
+effect is marked with *.
+
+Examples:
+  memory profile show --text
+  memory profile show reviewer`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := config.LoadProfiles(profilesDir())
+		if err != nil {
+			return fmt.Errorf("failed to load profiles: %w", err)
+		}
+		current := selectedProfile(profiles)
+
+		if len(args) == 1 {
+			profile, ok := profiles.Profiles[args[0]]
+			if !ok {
+				return fmt.Errorf("%w: profile %q does not exist", db.ErrInvalid, args[0])
+			}
+			if outputText {
+				printProfile(args[0], profile, args[0] == current)
+			} else {
+				outputResult(map[string]interface{}{
+					"name":    args[0],
+					"profile": profile,
+					"current": args[0] == current,
+				})
+			}
+			return nil
+		}
+
+		names := make([]string, 0, len(profiles.Profiles))
+		for name := range profiles.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if !outputText {
+			if profiles.Profiles == nil {
+				profiles.Profiles = map[string]*config.Profile{}
+			}
+			outputResult(map[string]interface{}{
+				"current":  current,
+				"profiles": profiles.Profiles,
+				"count":    len(names),
+			})
+			return nil
+		}
+		if len(names) == 0 {
+			fmt.Println("No profiles. Create one with 'memory profile create <name>'.")
+			return nil
+		}
+		for _, name := range names {
+			printProfile(name, profiles.Profiles[name], name == current)
+		}
+		return nil
+	},
+}
+
+// whoamiCmd reports the identity commands run under
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the AI ID, profile and session commands run as",
+	Long: `Show which AI ID breadcrumbs and audit entries are attributed to and where it comes
+from: the active session, MEMORY_AI_ID, the profile in effect, the environment of a
+known agent, or the default.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		aiID, source := defaultAIID, "default"
+		if id := os.Getenv(aiIDEnvVar); id != "" {
+			aiID, source = id, "env"
+		} else if activeProfile != nil && activeProfile.AIID != "" {
+			aiID, source = activeProfile.AIID, "profile"
+		} else if id, ok := detectAIID(); ok {
+			aiID, source = id, "detected"
+		}
+
+		sessionID := ""
+		if active, err := loadActiveSession(ctx); err == nil {
+			sessionID = active.SessionID
+			if active.AIID != "" {
+				aiID, source = active.AIID, "session"
+			}
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"ai_id":      aiID,
+				"source":     source,
+				"profile":    activeProfileName,
+				"tags":       profileTags(),
+				"session_id": sessionID,
+				"actor":      currentActor(ctx),
+			})
+			return nil
+		}
+		fmt.Printf("AI ID: %s (%s)\n", aiID, source)
+		if activeProfileName != "" {
+			fmt.Printf("Profile: %s\n", activeProfileName)
+		} else {
+			fmt.Println("Profile: none")
+		}
+		if tags := profileTags(); len(tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(tags, ", "))
+		}
+		if sessionID != "" {
+			fmt.Printf("Session: %s\n", sessionID)
+		} else {
+			fmt.Println("Session: none active")
+		}
+		return nil
+	},
+}
+
+func init() {
+	profileCreateCmd.Flags().String("ai-id", "", "AI identifier for sessions started under this profile")
+	profileCreateCmd.Flags().StringSlice("tag", nil, "Tag added to every breadcrumb logged (repeatable)")
+	profileCreateCmd.Flags().Bool("text-output", false, "Default to human-readable output")
+	profileCreateCmd.Flags().Bool("plain", false, "Default to uncolored text output")
+	profileCreateCmd.Flags().Bool("use", false, "Use the profile on this machine right away")
+	profileCreateCmd.Flags().Bool("force", false, "Replace an existing profile")
+
+	profileUseCmd.Flags().Bool("none", false, "Stop using a profile")
+
+	profileCmd.AddCommand(profileCreateCmd, profileUseCmd, profileShowCmd)
+	rootCmd.AddCommand(profileCmd, whoamiCmd)
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// projectIdleThreshold is how long a project can go without a session before start
+// includes the project briefing alongside the last session's handoff
+const projectIdleThreshold = 14 * 24 * time.Hour
+
+// maxProjectHandoffItems caps each list in a project handoff
+const maxProjectHandoffItems = 10
+
+// jsonList encodes a list for a JSON column, or nil when it is empty
+func jsonList(items []string) *string {
+	if len(items) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(items)
+	s := string(data)
+	return &s
+}
+
+// decodeList reads a JSON list column, ignoring malformed values
+func decodeList(column *string) []string {
+	if column == nil {
+		return nil
+	}
+	var items []string
+	json.Unmarshal([]byte(*column), &items)
+	return items
+}
+
+// appendUnique appends item unless it is empty, already present or the list is full
+func appendUnique(items []string, item string) []string {
+	if item == "" || len(items) >= maxProjectHandoffItems {
+		return items
+	}
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
+
+// synthesizeProjectHandoff aggregates the project's last n session handoffs into a
+// project handoff and stores it
+func synthesizeProjectHandoff(ctx context.Context, project *models.Project, n int) (*models.ProjectHandoff, error) {
+	handoffs, err := stores.Handoffs.List(ctx, project.ID, "", n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list handoffs: %w", err)
+	}
+	if len(handoffs) == 0 {
+		return nil, fmt.Errorf("session handoffs for project %s: %w", project.Name, db.ErrNotFound)
+	}
+
+	bcRepo := stores.Breadcrumbs
+	var sessionIDs, decisions, mistakes, remaining []string
+	deltas := map[string]float64{}
+	pathSessions := map[string]int{}
+	for _, h := range handoffs {
+		sessionIDs = append(sessionIDs, h.SessionID)
+
+		if h.TaskSummary != nil {
+			day := time.Unix(int64(h.CreatedAt), 0).Format("2006-01-02")
+			decisions = appendUnique(decisions, fmt.Sprintf("%s: %s", day, *h.TaskSummary))
+		}
+		if h.EpistemicDeltas != nil {
+			var sessionDeltas map[string]float64
+			if json.Unmarshal([]byte(*h.EpistemicDeltas), &sessionDeltas) == nil {
+				for k, v := range sessionDeltas {
+					deltas[k] += v
+				}
+			}
+		}
+
+		// Files count once per session however often they were touched
+		touched := map[string]bool{}
+		for _, a := range decodeList(h.ArtifactsCreated) {
+			if !isURLScope(a) {
+				touched[a] = true
+			}
+		}
+		findings, _ := bcRepo.ListFindings(ctx, project.ID, h.SessionID, 100)
+		for _, f := range findings {
+			if kind := f.ScopeKind(); kind == models.ScopeFile || kind == models.ScopeDir {
+				touched[*f.Subject] = true
+			}
+		}
+		for p := range touched {
+			pathSessions[p]++
+		}
+
+		deadEnds, _ := bcRepo.ListDeadEnds(ctx, project.ID, h.SessionID, maxProjectHandoffItems)
+		for _, d := range deadEnds {
+			mistakes = appendUnique(mistakes, fmt.Sprintf("%s — %s", d.Approach, d.WhyFailed))
+		}
+	}
+
+	// Files returned to in several sessions are where the project's work concentrates
+	var hotPaths []string
+	for p, count := range pathSessions {
+		if count >= 2 {
+			hotPaths = append(hotPaths, p)
+		}
+	}
+	sort.Slice(hotPaths, func(i, j int) bool {
+		if pathSessions[hotPaths[i]] != pathSessions[hotPaths[j]] {
+			return pathSessions[hotPaths[i]] > pathSessions[hotPaths[j]]
+		}
+		return hotPaths[i] < hotPaths[j]
+	})
+	var patterns []string
+	for _, p := range hotPaths {
+		patterns = appendUnique(patterns, fmt.Sprintf("%s (%d sessions)", p, pathSessions[p]))
+	}
+
+	latest := handoffs[0]
+	var bootstrap *string
+	if latest.NextSessionContext != nil && *latest.NextSessionContext != "" {
+		bootstrap = latest.NextSessionContext
+		remaining = appendUnique(remaining, *latest.NextSessionContext)
+	}
+	resolved := false
+	openUnknowns, _ := bcRepo.ListUnknowns(ctx, project.ID, "", &resolved, maxProjectHandoffItems)
+	for _, u := range openUnknowns {
+		remaining = appendUnique(remaining, u.Unknown)
+	}
+
+	oldest := handoffs[len(handoffs)-1]
+	summary := fmt.Sprintf("%d session(s) between %s and %s",
+		len(handoffs),
+		time.Unix(int64(oldest.CreatedAt), 0).Format("2006-01-02"),
+		time.Unix(int64(latest.CreatedAt), 0).Format("2006-01-02"))
+	if latest.TaskSummary != nil && *latest.TaskSummary != "" {
+		summary += ". Most recently: " + *latest.TaskSummary
+	}
+
+	sessionsJSON, _ := json.Marshal(sessionIDs)
+	handoff := models.NewProjectHandoff(project.ID, summary, string(sessionsJSON))
+	handoff.KeyDecisions = jsonList(decisions)
+	handoff.PatternsDiscovered = jsonList(patterns)
+	handoff.MistakesSummary = jsonList(mistakes)
+	handoff.RemainingWork = jsonList(remaining)
+	handoff.ReposTouched = jsonList(project.Repos)
+	handoff.NextSessionBootstrap = bootstrap
+	if len(deltas) > 0 {
+		deltasJSON, _ := json.Marshal(deltas)
+		s := string(deltasJSON)
+		handoff.TotalLearningDeltas = &s
+	}
+
+	if err := stores.ProjectHandoffs.Create(ctx, handoff); err != nil {
+		return nil, fmt.Errorf("failed to store project handoff: %w", err)
+	}
+	return handoff, nil
+}
+
+// idleProjectBriefing returns the project briefing when the project's last session
+// ended longer than projectIdleThreshold ago, synthesizing a project handoff if none
+// covers that session yet. It returns nil for active projects.
+func idleProjectBriefing(ctx context.Context, projectID string) *models.ProjectBriefing {
+	handoffs, err := stores.Handoffs.List(ctx, projectID, "", 1)
+	if err != nil || len(handoffs) == 0 {
+		return nil
+	}
+	last := handoffs[0]
+	idle := time.Since(time.Unix(int64(last.CreatedAt), 0))
+	if idle < projectIdleThreshold {
+		return nil
+	}
+
+	handoff, err := stores.ProjectHandoffs.Latest(ctx, projectID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if handoff == nil || handoff.CreatedTimestamp < last.CreatedAt {
+		project, err := stores.Projects.Get(ctx, projectID)
+		if err != nil {
+			return nil
+		}
+		if handoff, err = synthesizeProjectHandoff(ctx, project, 10); err != nil {
+			return nil
+		}
+	}
+
+	return &models.ProjectBriefing{
+		IdleFor:       fmt.Sprintf("%.0f days", idle.Hours()/24),
+		Summary:       handoff.ProjectSummary,
+		KeyDecisions:  decodeList(handoff.KeyDecisions),
+		Patterns:      decodeList(handoff.PatternsDiscovered),
+		Mistakes:      decodeList(handoff.MistakesSummary),
+		RemainingWork: decodeList(handoff.RemainingWork),
+	}
+}
+
+// projectCmd groups project-level commands
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Work with the current project as a whole",
+}
+
+// projectHandoffCmd synthesizes a project handoff from recent session handoffs
+var projectHandoffCmd = &cobra.Command{
+	Use:   "handoff",
+	Short: "Summarize the project's recent sessions",
+	Long: `Aggregate the project's last session handoffs into a project handoff: what the
+sessions concluded, files worked on across several sessions, failed approaches and
+remaining work. The result is stored; 'memory start' shows the latest one when the
+project has been idle for two weeks or more, synthesizing it if needed.
+
+Examples:
+  memory project handoff
+  memory project handoff --sessions 25
+  memory project handoff --latest --text   # Show the stored one without synthesizing`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessions, _ := cmd.Flags().GetInt("sessions")
+		latest, _ := cmd.Flags().GetBool("latest")
+		if sessions <= 0 {
+			return fmt.Errorf("%w: --sessions must be positive", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		var handoff *models.ProjectHandoff
+		if latest {
+			handoff, err = stores.ProjectHandoffs.Latest(ctx, project.ID)
+		} else {
+			handoff, err = synthesizeProjectHandoff(ctx, project, sessions)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !outputText {
+			status := "created"
+			if latest {
+				status = "stored"
+			}
+			outputResult(map[string]interface{}{
+				"status":            status,
+				"project_handoff":   handoff,
+				"key_decisions":     decodeList(handoff.KeyDecisions),
+				"patterns":          decodeList(handoff.PatternsDiscovered),
+				"mistakes":          decodeList(handoff.MistakesSummary),
+				"remaining_work":    decodeList(handoff.RemainingWork),
+				"sessions_included": decodeList(&handoff.SessionsIncluded),
+			})
+			return nil
+		}
+
+		fmt.Printf("Project %s\n", project.Name)
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Println(handoff.ProjectSummary)
+		printBriefingList("Key decisions", "•", decodeList(handoff.KeyDecisions))
+		printBriefingList("Patterns", "•", decodeList(handoff.PatternsDiscovered))
+		printBriefingList("Mistakes", "✗", decodeList(handoff.MistakesSummary))
+		printBriefingList("Remaining work", "?", decodeList(handoff.RemainingWork))
+		return nil
+	},
+}
+
+// printBriefingList prints a titled list, skipping empty ones
+func printBriefingList(title, bullet string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("\n%s (%d):\n", title, len(items))
+	for _, item := range items {
+		fmt.Printf("  %s %s\n", bullet, item)
+	}
+}
+
+func init() {
+	projectHandoffCmd.Flags().Int("sessions", 10, "Number of recent session handoffs to aggregate")
+	projectHandoffCmd.Flags().Bool("latest", false, "Show the latest stored project handoff instead of synthesizing one")
+
+	projectCmd.AddCommand(projectHandoffCmd)
+	rootCmd.AddCommand(projectCmd)
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// retentionInterval is how often the daemon applies the retention periods
+const retentionInterval = time.Hour
+
+// retentionCutoffs turns the configured retention periods into cutoffs counted back from now
+func retentionCutoffs(cfg *config.RetentionConfig, now time.Time) (db.RetentionCutoffs, error) {
+	cutoffs := db.RetentionCutoffs{AdHocPrefix: adHocSubjectPrefix}
+	if cfg == nil {
+		return cutoffs, nil
+	}
+	targets := map[string]*time.Time{
+		"findings":          &cutoffs.Findings,
+		"open_unknowns":     &cutoffs.OpenUnknowns,
+		"resolved_unknowns": &cutoffs.ResolvedUnknowns,
+		"dead_ends":         &cutoffs.DeadEnds,
+		"adhoc_sessions":    &cutoffs.AdHocSessions,
+	}
+	for kind, period := range cfg.Periods() {
+		if *period == "" || *period == config.RetentionForever {
+			continue
+		}
+		window, err := parseWindow(*period)
+		if err != nil {
+			return cutoffs, fmt.Errorf("retention for %s: %w", kind, err)
+		}
+		*targets[kind] = now.Add(-window)
+	}
+	return cutoffs, nil
+}
+
+// enforceRetention deletes the breadcrumbs older than their retention period and
+// returns them; with dryRun they're only listed
+func enforceRetention(ctx context.Context, dryRun bool) ([]*db.ExpiredBreadcrumb, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Retention == nil {
+		return nil, nil
+	}
+	cutoffs, err := retentionCutoffs(cfg.Retention, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	expired, err := stores.Breadcrumbs.ExpiredBreadcrumbs(ctx, cutoffs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired breadcrumbs: %w", err)
+	}
+	if dryRun || len(expired) == 0 {
+		return expired, nil
+	}
+
+	periods := cfg.Retention.Periods()
+	err = stores.InTx(ctx, func(tx *db.Stores) error {
+		for _, b := range expired {
+			reason := fmt.Sprintf("Retention: %s older than %s", strings.ReplaceAll(b.Rule, "_", " "), *periods[b.Rule])
+			if err := tx.Breadcrumbs.DeleteBreadcrumb(ctx, b.EntityType, b.ID, reason); err != nil {
+				return fmt.Errorf("failed to delete %s %s: %w", b.EntityType, b.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tidyEvidenceBlobs(ctx)
+	return expired, nil
+}
+
+// pruneCmd deletes breadcrumbs past their retention period
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete breadcrumbs older than their retention period",
+	Long: `Delete the findings, unknowns and dead ends older than the retention period set for
+their kind with 'memory prune policy'. Different kinds stay useful for very different
+times: a dead end can save effort years later, a resolved question rarely matters
+after a month. Kinds without a period are kept forever.
+
+Deletes are soft, like 'memory forget': the entries are hidden but their history is
+kept and 'memory forget --restore' brings them back. 'memory daemon' prunes every hour.
+
+Examples:
+  memory prune --dry-run --text
+  memory prune`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		expired, err := enforceRetention(ctx, dryRun)
+		if err != nil {
+			return err
+		}
+
+		byRule := make(map[string]int)
+		for _, b := range expired {
+			byRule[b.Rule]++
+		}
+		if !outputText {
+			status := "pruned"
+			if dryRun {
+				status = "dry_run"
+			}
+			if expired == nil {
+				expired = []*db.ExpiredBreadcrumb{}
+			}
+			outputResult(map[string]interface{}{
+				"status":      status,
+				"count":       len(expired),
+				"by_rule":     byRule,
+				"breadcrumbs": expired,
+			})
+			return nil
+		}
+		if len(expired) == 0 {
+			fmt.Println("Nothing past its retention period")
+			return nil
+		}
+		if dryRun {
+			fmt.Printf("Dry run: would delete %d breadcrumbs\n", len(expired))
+		} else {
+			fmt.Printf("✓ Deleted %d breadcrumbs\n", len(expired))
+		}
+		for _, b := range expired {
+			fmt.Printf("  %-9s %s  %s  (%s)\n", b.EntityType, b.ID[:8], truncateText(b.Text, 60), strings.ReplaceAll(b.Rule, "_", " "))
+		}
+		return nil
+	},
+}
+
+// prunePolicyCmd shows and sets the retention periods
+var prunePolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show or set how long each kind of breadcrumb is kept",
+	Long: `Show or set the retention period of each kind of breadcrumb:
+
+  findings           since last verified
+  open_unknowns      since asked
+  resolved_unknowns  since resolved
+  dead_ends          since recorded
+  adhoc_sessions     any breadcrumb logged in an ad-hoc session, since logged
+
+Periods are windows such as 30d, 2w or 36h, or "forever". Policies are stored in
+config.json next to the database.
+
+Examples:
+  memory prune policy --set resolved_unknowns=30d --set dead_ends=forever --set adhoc_sessions=14d
+  memory prune policy --text`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		sets, _ := cmd.Flags().GetStringArray("set")
+		if len(sets) > 0 {
+			retention := cfg.Retention
+			if retention == nil {
+				retention = &config.RetentionConfig{}
+			}
+			periods := retention.Periods()
+			for _, s := range sets {
+				kind, period, ok := strings.Cut(s, "=")
+				target, known := periods[kind]
+				if !ok || !known {
+					return fmt.Errorf("%w --set %q (use kind=period with kind one of %s)", db.ErrInvalid, s, strings.Join(retentionKinds(), ", "))
+				}
+				if period != config.RetentionForever {
+					if _, err := parseWindow(period); err != nil {
+						return err
+					}
+				}
+				*target = period
+			}
+			cfg.Retention = retention
+			if *retention == (config.RetentionConfig{}) {
+				cfg.Retention = nil
+			}
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		policy := make(map[string]string)
+		if cfg.Retention != nil {
+			for kind, period := range cfg.Retention.Periods() {
+				policy[kind] = *period
+			}
+		}
+		for _, kind := range retentionKinds() {
+			if policy[kind] == "" {
+				policy[kind] = config.RetentionForever
+			}
+		}
+		if !outputText {
+			status := "current"
+			if len(sets) > 0 {
+				status = "saved"
+			}
+			outputResult(map[string]interface{}{
+				"status": status,
+				"policy": policy,
+			})
+			return nil
+		}
+		for _, kind := range retentionKinds() {
+			fmt.Printf("%-18s %s\n", kind, policy[kind])
+		}
+		return nil
+	},
+}
+
+// retentionKinds lists the kinds of breadcrumb a retention period can be set for
+func retentionKinds() []string {
+	var kinds []string
+	for kind := range (&config.RetentionConfig{}).Periods() {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func init() {
+	pruneCmd.Flags().Bool("dry-run", false, "List what would be deleted without deleting")
+	prunePolicyCmd.Flags().StringArray("set", nil, "Set a retention period: kind=period, e.g. resolved_unknowns=30d (repeatable)")
+	pruneCmd.AddCommand(prunePolicyCmd)
+	rootCmd.AddCommand(pruneCmd)
+}
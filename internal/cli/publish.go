@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// GitHubPullRequest is the subset of the GitHub pull request payload memory uses
+type GitHubPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+}
+
+// GitHubComment is the subset of the GitHub issue comment payload memory uses
+type GitHubComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// findBranchPullRequest returns the open pull request whose head is branch
+func findBranchPullRequest(ctx context.Context, repo, branch string) (*GitHubPullRequest, error) {
+	owner := strings.SplitN(repo, "/", 2)[0]
+	path := fmt.Sprintf("/repos/%s/pulls?state=open&head=%s", repo, url.QueryEscape(owner+":"+branch))
+
+	var pulls []GitHubPullRequest
+	if err := githubRequest(ctx, "GET", path, nil, &pulls); err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, fmt.Errorf("no open pull request for branch %s", branch)
+	}
+	return &pulls[0], nil
+}
+
+// upsertPRComment posts body on a pull request, replacing an earlier comment that
+// carries the same marker so re-publishing doesn't pile up duplicates
+func upsertPRComment(ctx context.Context, repo string, number int, marker, body string) (*GitHubComment, bool, error) {
+	var comments []GitHubComment
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100", repo, number)
+	if err := githubRequest(ctx, "GET", path, nil, &comments); err != nil {
+		return nil, false, err
+	}
+
+	payload := map[string]string{"body": body}
+	var comment GitHubComment
+	for _, c := range comments {
+		if strings.Contains(c.Body, marker) {
+			err := githubRequest(ctx, "PATCH", fmt.Sprintf("/repos/%s/issues/comments/%d", repo, c.ID), payload, &comment)
+			return &comment, true, err
+		}
+	}
+	err := githubRequest(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number), payload, &comment)
+	return &comment, false, err
+}
+
+// publishCmd groups commands that share session outcomes outside memory
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish session summaries to external tools",
+}
+
+// publishPRCommentCmd posts the session handoff on the current branch's pull request
+var publishPRCommentCmd = &cobra.Command{
+	Use:   "pr-comment",
+	Short: "Post the session handoff as a pull request comment",
+	Long: `Render the session handoff (summary, key findings, dead ends avoided and
+remaining unknowns) and post it as a comment on the current branch's pull request.
+
+Reports on the active session, or the most recent handed-off session when none is
+active. Publishing again for the same session updates the earlier comment.
+Requires GITHUB_TOKEN (or GH_TOKEN) with permission to comment.
+
+Examples:
+  memory publish pr-comment
+  memory publish pr-comment --pr 128
+  memory publish pr-comment --session <session-id>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessionFlag, _ := cmd.Flags().GetString("session")
+		prNumber, _ := cmd.Flags().GetInt("pr")
+		repoFlag, _ := cmd.Flags().GetString("repo")
+
+		if githubToken() == "" {
+			return fmt.Errorf("GITHUB_TOKEN (or GH_TOKEN) is required to post comments")
+		}
+		repo, err := githubRepo(ctx, repoFlag)
+		if err != nil {
+			return err
+		}
+
+		sessionID, err := resolveReportSession(ctx, sessionFlag)
+		if err != nil {
+			return err
+		}
+		report, err := collectSessionReport(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+
+		if prNumber == 0 {
+			wt := currentWorktree(ctx)
+			if wt == nil || wt.Branch == "" {
+				return fmt.Errorf("cannot determine current branch; pass --pr")
+			}
+			pr, err := findBranchPullRequest(ctx, repo, wt.Branch)
+			if err != nil {
+				return err
+			}
+			prNumber = pr.Number
+		}
+
+		comment, updated, err := upsertPRComment(ctx, repo, prNumber, reportMarker(sessionID), renderHandoffMarkdown(report))
+		if err != nil {
+			return fmt.Errorf("failed to post comment: %w", err)
+		}
+
+		status := "posted"
+		if updated {
+			status = "updated"
+		}
+		if outputText {
+			fmt.Printf("✓ Comment %s on %s#%d\n", status, repo, prNumber)
+			if comment.HTMLURL != "" {
+				fmt.Printf("  %s\n", comment.HTMLURL)
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status":     status,
+				"repo":       repo,
+				"pr":         prNumber,
+				"session_id": sessionID,
+				"url":        comment.HTMLURL,
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	publishPRCommentCmd.Flags().String("session", "", "Session ID to report on (defaults to active or last handoff)")
+	publishPRCommentCmd.Flags().Int("pr", 0, "Pull request number (defaults to the current branch's open PR)")
+	publishPRCommentCmd.Flags().String("repo", "", "GitHub repository (owner/name), defaults to origin")
+	publishCmd.AddCommand(publishPRCommentCmd)
+	rootCmd.AddCommand(publishCmd)
+}
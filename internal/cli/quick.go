@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -24,22 +28,58 @@ type ActiveSession struct {
 	StartedAt     time.Time `json:"started_at"`
 	ProjectID     string    `json:"project_id,omitempty"`
 	CurrentGoalID string    `json:"current_goal_id,omitempty"`
+	InheritParent bool      `json:"inherit_parent,omitempty"` // Include parent-project knowledge for sub-projects
+	Artifacts     []string  `json:"artifacts,omitempty"`      // Files and URLs produced, listed in the handoff
+
+	// Context filtering by the agent that logged each breadcrumb
+	TrustedAIIDs     []string `json:"trusted_ai_ids,omitempty"`     // Only these AI IDs or agent families
+	PreferSameFamily bool     `json:"prefer_same_family,omitempty"` // Rank this session's agent family first
+
+	// Progress since the last checkpoint, for automatic checkpoints
+	LastCheckpointAt           time.Time `json:"last_checkpoint_at,omitempty"`
+	BreadcrumbsSinceCheckpoint int       `json:"breadcrumbs_since_checkpoint,omitempty"`
+
+	// Set for a session named with --session that isn't the active one; it is never
+	// written to the active session file
+	detached bool
+}
+
+// contextAgents returns the session's context filter by agent, nil when there is none
+func (a *ActiveSession) contextAgents() *agentFilter {
+	prefer := ""
+	if a.PreferSameFamily {
+		prefer = a.AIID
+	}
+	return newAgentFilter(a.TrustedAIIDs, prefer)
+}
+
+// goalID returns the current goal for stamping breadcrumbs, nil when there is none
+func (a *ActiveSession) goalID() *string {
+	if a.CurrentGoalID == "" {
+		return nil
+	}
+	goalID := a.CurrentGoalID
+	return &goalID
 }
 
 // getActiveSessionPath returns the path to store active session
-func getActiveSessionPath() string {
+func getActiveSessionPath(ctx context.Context) string {
 	// Try project-local first
 	if _, err := os.Stat(".memory"); err == nil {
 		return ".memory/active-session.json"
 	}
+	// Subdirectories and linked worktrees use the repository's memory directory
+	if dir := sharedMemoryDir(ctx); dir != "" {
+		return filepath.Join(dir, "active-session.json")
+	}
 	// Fall back to home directory
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".memory", "active-session.json")
 }
 
 // saveActiveSession saves the current active session
-func saveActiveSession(session *ActiveSession) error {
-	path := getActiveSessionPath()
+func saveActiveSession(ctx context.Context, session *ActiveSession) error {
+	path := getActiveSessionPath(ctx)
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -52,8 +92,8 @@ func saveActiveSession(session *ActiveSession) error {
 }
 
 // loadActiveSession loads the current active session
-func loadActiveSession() (*ActiveSession, error) {
-	path := getActiveSessionPath()
+func loadActiveSession(ctx context.Context) (*ActiveSession, error) {
+	path := getActiveSessionPath(ctx)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -66,22 +106,96 @@ func loadActiveSession() (*ActiveSession, error) {
 }
 
 // clearActiveSession removes the active session file
-func clearActiveSession() error {
-	path := getActiveSessionPath()
+func clearActiveSession(ctx context.Context) error {
+	path := getActiveSessionPath(ctx)
 	return os.Remove(path)
 }
 
 // requireActiveSession gets the active session or returns an error
-func requireActiveSession() (*ActiveSession, error) {
-	session, err := loadActiveSession()
+func requireActiveSession(ctx context.Context) (*ActiveSession, error) {
+	session, err := loadActiveSession(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("no active session. Run 'memory start \"objective\"' first")
 	}
 	return session, nil
 }
 
+// adHocSubjectPrefix starts the subject of the per-day session that catches
+// breadcrumbs logged outside a session
+const adHocSubjectPrefix = "Ad-hoc observations "
+
+// adHocSession reuses today's ad-hoc session for the current project, or starts one
+func adHocSession(ctx context.Context) (*ActiveSession, error) {
+	project, err := getOrCreateDefaultProject(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	subject := adHocSubjectPrefix + time.Now().Format("2006-01-02")
+
+	session, err := stores.Sessions.GetOpenBySubject(ctx, project.ID, subject)
+	if errors.Is(err, db.ErrNotFound) {
+		session = models.NewSession(currentActor(ctx))
+		session.ProjectID = &project.ID
+		session.Subject = &subject
+		if err = stores.Sessions.Create(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to create ad-hoc session: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get ad-hoc session: %w", err)
+	}
+
+	return &ActiveSession{
+		SessionID: session.SessionID,
+		AIID:      session.AIID,
+		Objective: subject,
+		StartedAt: session.StartTime,
+		ProjectID: project.ID,
+		detached:  true,
+	}, nil
+}
+
+// loggingSession returns the session a logging command writes to: the one named by
+// --session, which may be another agent's or already ended, or else the active session.
+// With ad-hoc sessions turned on, breadcrumbs logged outside a session go to today's.
+func loggingSession(cmd *cobra.Command) (*ActiveSession, error) {
+	ctx := cmd.Context()
+	sessionID, _ := cmd.Flags().GetString("session")
+	if sessionID == "" {
+		active, err := requireActiveSession(ctx)
+		if err == nil {
+			return active, nil
+		}
+		if cfg, cfgErr := loadConfig(); cfgErr == nil && cfg.Sessions != nil && cfg.Sessions.AdHoc {
+			return adHocSession(ctx)
+		}
+		return nil, err
+	}
+	if active, err := loadActiveSession(ctx); err == nil && active.SessionID == sessionID {
+		return active, nil
+	}
+
+	session, err := stores.Sessions.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.ProjectID == nil {
+		return nil, fmt.Errorf("%w: session %s has no project", db.ErrInvalid, sessionID)
+	}
+	named := &ActiveSession{
+		SessionID: session.SessionID,
+		AIID:      session.AIID,
+		StartedAt: session.StartTime,
+		ProjectID: *session.ProjectID,
+		detached:  true,
+	}
+	if session.Subject != nil {
+		named.Objective = *session.Subject
+	}
+	return named, nil
+}
+
 // getOrCreateDefaultProject gets or creates a default project based on current directory
-func getOrCreateDefaultProject() (*models.Project, error) {
+func getOrCreateDefaultProject(ctx context.Context) (*models.Project, error) {
 	// Get current directory name as default project name
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -89,20 +203,42 @@ func getOrCreateDefaultProject() (*models.Project, error) {
 	}
 	projectName := filepath.Base(cwd)
 
-	repo := db.NewProjectRepository(database)
+	// Linked worktrees resolve to the main checkout's project so knowledge isn't split
+	if wt := currentWorktree(ctx); wt != nil && wt.IsLinked {
+		if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+			cwd = resolved
+		}
+		if rel, err := filepath.Rel(wt.Root, cwd); err == nil && !strings.HasPrefix(rel, "..") {
+			projectName = filepath.Base(filepath.Join(wt.MainRoot, rel))
+		}
+	}
 
-	// Try to find existing project
-	project, err := repo.GetByName(projectName)
-	if err != nil {
+	// Registered monorepo sub-projects take precedence over the directory name
+	if sub, err := resolveSubProject(ctx, cwd); err != nil {
 		return nil, err
+	} else if sub != nil {
+		return sub, nil
 	}
-	if project != nil {
+
+	return getOrCreateProjectByName(ctx, projectName)
+}
+
+// getOrCreateProjectByName returns the project with the given name, creating it if needed
+func getOrCreateProjectByName(ctx context.Context, projectName string) (*models.Project, error) {
+	repo := stores.Projects
+
+	// Try to find existing project
+	project, err := repo.GetByName(ctx, projectName)
+	if err == nil {
 		return project, nil
 	}
+	if !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
 
 	// Create new project
 	project = models.NewProject(projectName, nil)
-	if err := repo.Create(project); err != nil {
+	if err := repo.Create(ctx, project); err != nil {
 		return nil, err
 	}
 
@@ -127,8 +263,183 @@ type EpistemicState struct {
 	MoonPhase            string `json:"moon_phase"`
 }
 
-// calculateEpistemicState derives epistemic vectors from breadcrumb data
+// Thresholds the recommended action is decided by, checked in this order: engagement
+// below its gate means stop, then low coherence reset, low clarity verify, and low
+// knowledge or high uncertainty investigate
+const (
+	engagementGate = 0.60
+	minCoherence   = 0.50
+	minClarity     = 0.40
+	minKnow        = 0.50
+	maxUncertainty = 0.50
+)
+
+// Snapshot returns the numeric vectors of the state
+func (e *EpistemicState) Snapshot() *models.EpistemicSnapshot {
+	return &models.EpistemicSnapshot{
+		Know:        e.Know,
+		Uncertainty: e.Uncertainty,
+		Clarity:     e.Clarity,
+		Coherence:   e.Coherence,
+		Completion:  e.Completion,
+		Engagement:  e.Engagement,
+		Overall:     e.Confidence,
+	}
+}
+
+// defaultImpact is the impact breadcrumbs are logged with unless --impact says otherwise
+const defaultImpact = 0.5
+
+// impactWeight scales a breadcrumb's contribution to the epistemic state by its impact,
+// so a default-impact breadcrumb counts once, a critical one twice and a trivial one
+// a fraction. Rows without an impact count as default.
+func impactWeight(impact float64) float64 {
+	if impact <= 0 {
+		return 1
+	}
+	return impact / defaultImpact
+}
+
+// weightedFindings sums the impact weights of findings
+func weightedFindings(findings []*models.Finding) float64 {
+	total := 0.0
+	for _, f := range findings {
+		total += impactWeight(f.Impact)
+	}
+	return total
+}
+
+// weightedUnknowns sums the impact weights of unknowns
+func weightedUnknowns(unknowns []*models.Unknown) float64 {
+	total := 0.0
+	for _, u := range unknowns {
+		total += impactWeight(u.Impact)
+	}
+	return total
+}
+
+// weightedDeadEnds sums the impact weights of dead ends
+func weightedDeadEnds(deadEnds []*models.DeadEnd) float64 {
+	total := 0.0
+	for _, d := range deadEnds {
+		total += impactWeight(d.Impact)
+	}
+	return total
+}
+
+// impactFlag reads a logging command's --impact flag
+func impactFlag(cmd *cobra.Command) (float64, error) {
+	impact, _ := cmd.Flags().GetFloat64("impact")
+	if impact <= 0 || impact > 1 {
+		return 0, fmt.Errorf("%w: --impact must be above 0 and at most 1", db.ErrInvalid)
+	}
+	return impact, nil
+}
+
+// breadcrumbArgs resolves a logging command's want text arguments. An argument of "-" is
+// read from stdin and --file supplies the last one, since multi-line text with code
+// snippets is awkward to pass as a single shell argument.
+func breadcrumbArgs(cmd *cobra.Command, args []string, want int) ([]string, error) {
+	file, _ := cmd.Flags().GetString("file")
+	given := len(args)
+	if file != "" {
+		given++
+	}
+	if given != want {
+		return nil, fmt.Errorf("%w: accepts %d arg(s), received %d", db.ErrInvalid, want, given)
+	}
+
+	texts := append([]string(nil), args...)
+	readStdin := false
+	for i, arg := range texts {
+		if arg != "-" {
+			continue
+		}
+		if readStdin {
+			return nil, fmt.Errorf("%w: only one argument can be read from stdin", db.ErrInvalid)
+		}
+		if inRepl && !inDaemon {
+			return nil, fmt.Errorf("%w: the REPL reads commands from stdin; use --file instead", db.ErrInvalid)
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		texts[i] = string(data)
+		readStdin = true
+	}
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		texts = append(texts, string(data))
+	}
+
+	for i, text := range texts {
+		if texts[i] = strings.TrimSpace(text); texts[i] == "" {
+			return nil, fmt.Errorf("%w: empty text", db.ErrInvalid)
+		}
+	}
+	return texts, nil
+}
+
+// newSessionFinding builds a finding for the active session, capturing the scope's hash
+// for staleness tracking and the checkout it was made on
+func newSessionFinding(ctx context.Context, active *ActiveSession, text, scope string, kind models.ScopeKind, impact float64) *models.Finding {
+	finding := models.NewFinding(active.ProjectID, active.SessionID, text, impact)
+	finding.GoalID = active.goalID()
+	finding.Tags = profileTags()
+
+	// Set scope and capture its fingerprint for staleness tracking
+	if scope != "" {
+		finding.Subject = &scope
+		finding.SubjectKind = kind
+		hash := getScopeHash(ctx, kind, scope)
+		if hash != "" {
+			finding.SubjectGitHash = &hash
+		}
+	}
+
+	// Record which checkout the finding was made on
+	if wt := currentWorktree(ctx); wt != nil {
+		finding.Worktree = &wt.Root
+		if wt.Branch != "" {
+			finding.GitBranch = &wt.Branch
+		}
+	}
+
+	// Set initial verification timestamp to creation time
+	finding.LastVerifiedTimestamp = &finding.CreatedTimestamp
+	return finding
+}
+
+// newSessionUnknown builds an unknown for the active session
+func newSessionUnknown(active *ActiveSession, text, scope string, impact float64) *models.Unknown {
+	unknown := models.NewUnknown(active.ProjectID, active.SessionID, text, impact)
+	unknown.GoalID = active.goalID()
+	unknown.Tags = profileTags()
+	if scope != "" {
+		unknown.Subject = &scope
+	}
+	return unknown
+}
+
+// newSessionDeadEnd builds a dead end for the active session
+func newSessionDeadEnd(active *ActiveSession, approach, whyFailed, scope string, impact float64) *models.DeadEnd {
+	deadEnd := models.NewDeadEnd(active.ProjectID, active.SessionID, approach, whyFailed, impact)
+	deadEnd.GoalID = active.goalID()
+	deadEnd.Tags = profileTags()
+	if scope != "" {
+		deadEnd.Subject = &scope
+	}
+	return deadEnd
+}
+
+// calculateEpistemicState derives epistemic vectors from breadcrumb data, weighting
+// each breadcrumb by its impact
 func calculateEpistemicState(
+	ctx context.Context,
 	findings []*models.Finding,
 	openUnknowns []*models.Unknown,
 	resolvedUnknowns []*models.Unknown,
@@ -137,14 +448,19 @@ func calculateEpistemicState(
 ) *EpistemicState {
 	state := &EpistemicState{}
 
+	findingWeight := weightedFindings(findings)
+	openWeight := weightedUnknowns(openUnknowns)
+	resolvedWeight := weightedUnknowns(resolvedUnknowns)
+	deadEndWeight := weightedDeadEnds(deadEnds)
+
 	// Know: base 0.5 + findings × 0.1 + resolved × 0.15
-	state.Know = 0.5 + float64(len(findings))*0.1 + float64(len(resolvedUnknowns))*0.15
+	state.Know = 0.5 + findingWeight*0.1 + resolvedWeight*0.15
 	if state.Know > 1.0 {
 		state.Know = 1.0
 	}
 
 	// Uncertainty: base 0.5 + open × 0.1 - resolved × 0.1
-	state.Uncertainty = 0.5 + float64(len(openUnknowns))*0.1 - float64(len(resolvedUnknowns))*0.1
+	state.Uncertainty = 0.5 + openWeight*0.1 - resolvedWeight*0.1
 	if state.Uncertainty < 0 {
 		state.Uncertainty = 0
 	}
@@ -152,35 +468,31 @@ func calculateEpistemicState(
 		state.Uncertainty = 1.0
 	}
 
-	// Clarity: ratio of fresh findings
-	if len(findings) > 0 {
-		freshCount := 0
+	// Clarity: weighted share of fresh findings
+	if findingWeight > 0 {
+		freshWeight := 0.0
 		for _, f := range findings {
-			fileChanged := false
-			if f.Subject != nil && f.SubjectGitHash != nil {
-				fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-			}
+			fileChanged := findingFileChanged(ctx, f)
 			if f.GetStalenessStatus(fileChanged) == models.StatusFresh {
-				freshCount++
+				freshWeight += impactWeight(f.Impact)
 			}
 		}
-		state.Clarity = float64(freshCount) / float64(len(findings))
+		state.Clarity = freshWeight / findingWeight
 	} else {
 		state.Clarity = 0.5 // neutral when no findings
 	}
 
 	// Coherence: 1.0 - (dead_ends / total_breadcrumbs)
-	totalBreadcrumbs := len(findings) + len(openUnknowns) + len(resolvedUnknowns) + len(deadEnds)
-	if totalBreadcrumbs > 0 {
-		state.Coherence = 1.0 - (float64(len(deadEnds)) / float64(totalBreadcrumbs))
+	totalWeight := findingWeight + openWeight + resolvedWeight + deadEndWeight
+	if totalWeight > 0 {
+		state.Coherence = 1.0 - deadEndWeight/totalWeight
 	} else {
 		state.Coherence = 1.0 // perfect coherence when nothing logged
 	}
 
 	// Completion: resolved / total unknowns
-	totalUnknowns := len(openUnknowns) + len(resolvedUnknowns)
-	if totalUnknowns > 0 {
-		state.Completion = float64(len(resolvedUnknowns)) / float64(totalUnknowns)
+	if unknownWeight := openWeight + resolvedWeight; unknownWeight > 0 {
+		state.Completion = resolvedWeight / unknownWeight
 	} else {
 		state.Completion = 0.5 // neutral when no unknowns
 	}
@@ -208,16 +520,16 @@ func calculateEpistemicState(
 	}
 
 	// Derived states
-	state.PassesEngagementGate = state.Engagement >= 0.60
-	state.ReadyToProceed = state.Know >= 0.50 && state.Uncertainty <= 0.50
-	state.NeedsInvestigation = state.Know < 0.50 || state.Uncertainty > 0.50
+	state.PassesEngagementGate = state.Engagement >= engagementGate
+	state.ReadyToProceed = state.Know >= minKnow && state.Uncertainty <= maxUncertainty
+	state.NeedsInvestigation = state.Know < minKnow || state.Uncertainty > maxUncertainty
 
 	// Recommended action
 	if !state.PassesEngagementGate {
 		state.RecommendedAction = "stop"
-	} else if state.Coherence < 0.50 {
+	} else if state.Coherence < minCoherence {
 		state.RecommendedAction = "reset"
-	} else if state.Clarity < 0.40 {
+	} else if state.Clarity < minClarity {
 		state.RecommendedAction = "verify"
 	} else if state.NeedsInvestigation {
 		state.RecommendedAction = "investigate"
@@ -270,45 +582,133 @@ The objective describes what you're working on. Memory will return:
 
 Example:
   memory start "Implement user authentication"
-  memory start "Fix bug in payment flow"`,
-	Args: cobra.ExactArgs(1),
+  memory start "Fix bug in payment flow"
+  memory start "Add token refresh" --inherit   # In a sub-project, also load parent knowledge
+  memory start --from-issue 42                 # Objective from GitHub issue #42, tasks become questions
+  memory start "Finish auth" --goal 3f2a9c1e-...   # Continue an unfinished goal
+  memory start "Review auth" --from-ai-id claude --prefer-same-family`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		objective := args[0]
+		ctx := cmd.Context()
+		issueNumber, _ := cmd.Flags().GetInt("from-issue")
+		issueRepoFlag, _ := cmd.Flags().GetString("repo")
+		if len(args) == 0 && issueNumber == 0 {
+			return fmt.Errorf("an objective or --from-issue is required")
+		}
+
+		// Pull the objective and task list from the GitHub issue
+		var issue *GitHubIssue
+		var issueRepo string
+		if issueNumber > 0 {
+			var err error
+			if issueRepo, err = githubRepo(ctx, issueRepoFlag); err != nil {
+				return err
+			}
+			if issue, err = fetchGitHubIssue(ctx, issueRepo, issueNumber); err != nil {
+				return fmt.Errorf("failed to fetch issue #%d: %w", issueNumber, err)
+			}
+		}
+
+		objective := ""
+		if len(args) > 0 {
+			objective = args[0]
+		} else {
+			objective = fmt.Sprintf("#%d %s", issue.Number, issue.Title)
+		}
 		aiID, _ := cmd.Flags().GetString("ai-id")
+		inherit, _ := cmd.Flags().GetBool("inherit")
+		goalID, _ := cmd.Flags().GetString("goal")
+		trusted, _ := cmd.Flags().GetStringSlice("from-ai-id")
+		preferSameFamily, _ := cmd.Flags().GetBool("prefer-same-family")
 		if aiID == "" {
-			aiID = "claude-code"
+			if detected, ok := detectAIID(); ok {
+				aiID = detected
+			} else {
+				aiID = defaultAIID
+			}
 		}
+		database.SetActor(aiID)
 
 		// Get or create project
-		project, err := getOrCreateDefaultProject()
+		project, err := getOrCreateDefaultProject(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get project: %w", err)
 		}
 
+		// A goal carried over from an earlier session scopes the context from the start
+		var goal *models.Goal
+		if goalID != "" {
+			if goal, err = stores.Goals.Get(ctx, goalID); err != nil {
+				return fmt.Errorf("failed to get goal: %w", err)
+			}
+			if goal.IsCompleted {
+				return fmt.Errorf("%w: goal %s is already complete", db.ErrInvalid, goal.ID)
+			}
+		}
+
 		// Create new session
 		session := models.NewSession(aiID)
 		session.ProjectID = &project.ID
 		session.Subject = &objective
+		if issue != nil && issue.Body != "" {
+			session.SessionNotes = &issue.Body
+		}
 
-		sessionRepo := db.NewSessionRepository(database)
-		if err := sessionRepo.Create(session); err != nil {
+		sessionRepo := stores.Sessions
+		if err := sessionRepo.Create(ctx, session); err != nil {
 			return fmt.Errorf("failed to create session: %w", err)
 		}
 
 		// Save as active session
 		active := &ActiveSession{
-			SessionID: session.SessionID,
-			AIID:      aiID,
-			Objective: objective,
-			StartedAt: time.Now(),
-			ProjectID: project.ID,
-		}
-		if err := saveActiveSession(active); err != nil {
+			SessionID:        session.SessionID,
+			AIID:             aiID,
+			Objective:        objective,
+			StartedAt:        time.Now(),
+			ProjectID:        project.ID,
+			InheritParent:    inherit,
+			TrustedAIIDs:     trusted,
+			PreferSameFamily: preferSameFamily,
+		}
+		if goal != nil {
+			active.CurrentGoalID = goal.ID
+		}
+		if err := saveActiveSession(ctx, active); err != nil {
 			return fmt.Errorf("failed to save active session: %w", err)
 		}
 
+		if issue != nil {
+			link := models.NewIssueLink(models.IssueTargetSession, session.SessionID, issueRepo, issue.Number)
+			link.Title = &issue.Title
+			link.URL = issue.HTMLURL
+			if err := stores.IssueLinks.Create(ctx, link); err != nil {
+				return fmt.Errorf("failed to link issue: %w", err)
+			}
+
+			// Unchecked task list items become open questions for this session
+			bcRepo := stores.Breadcrumbs
+			for _, task := range parseTaskList(issue.Body) {
+				unknown := models.NewUnknown(project.ID, session.SessionID, task, defaultImpact)
+				if err := bcRepo.CreateUnknown(ctx, unknown); err != nil {
+					return fmt.Errorf("failed to seed question: %w", err)
+				}
+			}
+		}
+
 		// Build AI-first session context
-		ctx := buildSessionContext(session.SessionID, project.ID, objective, aiID, active.StartedAt)
+		var inheritFrom []string
+		if inherit {
+			inheritFrom = projectAncestorIDs(ctx, project)
+		}
+		inheritFrom = withGlobalKnowledge(ctx, project.ID, inheritFrom)
+		sessionCtx := buildSessionContext(ctx, session.SessionID, project.ID, objective, aiID, active.StartedAt, inheritFrom, goal, active.contextAgents())
+		recordEpistemicSnapshot(ctx, session.SessionID, models.PhasePreflight, sessionCtx.Vectors)
+
+		response := &models.StartResponse{
+			Status:  "started",
+			Context: sessionCtx,
+		}
+		emitEvent(ctx, EventSessionStarted, project.ID, response)
 
 		if outputText {
 			// Human-readable output
@@ -317,82 +717,58 @@ Example:
 			fmt.Println(strings.Repeat("─", 50))
 
 			// Decision guidance
-			if ctx.Decision != nil {
+			if sessionCtx.Decision != nil {
 				fmt.Printf("\n%s %s (%.0f%% confidence)\n",
-					ctx.Decision.ConfidencePhase,
-					strings.ToUpper(ctx.Decision.Action),
-					ctx.Decision.Confidence*100)
-				fmt.Printf("  %s\n", ctx.Decision.Reason)
+					sessionCtx.Decision.ConfidencePhase,
+					paintAction(strings.ToUpper(sessionCtx.Decision.Action)),
+					sessionCtx.Decision.Confidence*100)
+				fmt.Printf("  %s\n", sessionCtx.Decision.Reason)
 
-				if len(ctx.Decision.Prerequisites) > 0 {
+				if len(sessionCtx.Decision.Prerequisites) > 0 {
 					fmt.Println("\n  Before proceeding:")
-					for _, p := range ctx.Decision.Prerequisites {
+					for _, p := range sessionCtx.Decision.Prerequisites {
 						fmt.Printf("    → %s\n", p)
 					}
 				}
 			}
 
-			// Verification needed
-			if len(ctx.RequiresVerification) > 0 {
-				fmt.Printf("\n⚠ VERIFY BEFORE USING (%d):\n", len(ctx.RequiresVerification))
-				for _, v := range ctx.RequiresVerification {
-					extra := ""
-					if v.FileChanged {
-						extra = " [file changed]"
-					}
-					fmt.Printf("  • %s (%dd old%s)\n", v.Finding, v.DaysStale, extra)
-					fmt.Printf("    %s\n", v.VerifyCommand)
-				}
-			}
+			printContextSections(sessionCtx)
 
-			// Dead ends
-			if len(ctx.DeadEnds) > 0 {
-				fmt.Printf("\n✗ DO NOT REPEAT (%d):\n", len(ctx.DeadEnds))
-				for _, d := range ctx.DeadEnds {
-					fmt.Printf("  • %s\n", d.Approach)
-					fmt.Printf("    Why: %s\n", d.WhyFailed)
-				}
-			}
-
-			// Knowledge
-			if len(ctx.Knowledge) > 0 {
-				fmt.Printf("\n✓ KNOWN (%d):\n", len(ctx.Knowledge))
-				for _, k := range ctx.Knowledge {
-					status := "✓"
-					if k.Status == "aging" {
-						status = "○"
+			// Reference docs
+			if len(sessionCtx.ReferenceDocs) > 0 {
+				fmt.Printf("\n◆ CONSULT (%d):\n", len(sessionCtx.ReferenceDocs))
+				for _, d := range sessionCtx.ReferenceDocs {
+					if d.Description != nil {
+						fmt.Printf("  • %s - %s\n", d.DocPath, *d.Description)
+					} else {
+						fmt.Printf("  • %s\n", d.DocPath)
 					}
-					fmt.Printf("  %s %s\n", status, k.Finding)
 				}
 			}
 
-			// Open questions
-			if len(ctx.OpenQuestions) > 0 {
-				fmt.Printf("\n? OPEN QUESTIONS (%d):\n", len(ctx.OpenQuestions))
-				for _, q := range ctx.OpenQuestions {
-					fmt.Printf("  • %s\n", q)
-				}
+			// Project briefing
+			if b := sessionCtx.ProjectBriefing; b != nil {
+				fmt.Printf("\n─ Project (idle %s) ─\n", b.IdleFor)
+				fmt.Printf("  %s\n", b.Summary)
+				printBriefingList("Key decisions", "•", b.KeyDecisions)
+				printBriefingList("Remaining work", "?", b.RemainingWork)
 			}
 
 			// Continuity
-			if ctx.Continuity != nil {
+			if sessionCtx.Continuity != nil {
 				fmt.Println("\n─ Last Session ─")
-				if ctx.Continuity.TimeSinceLastSession != "" {
-					fmt.Printf("  %s\n", ctx.Continuity.TimeSinceLastSession)
+				if sessionCtx.Continuity.TimeSinceLastSession != "" {
+					fmt.Printf("  %s\n", sessionCtx.Continuity.TimeSinceLastSession)
 				}
-				if ctx.Continuity.Summary != "" {
-					fmt.Printf("  %s\n", ctx.Continuity.Summary)
+				if sessionCtx.Continuity.Summary != "" {
+					fmt.Printf("  %s\n", sessionCtx.Continuity.Summary)
 				}
-				if ctx.Continuity.Recommendations != "" {
-					fmt.Printf("  Recommendations: %s\n", ctx.Continuity.Recommendations)
+				if sessionCtx.Continuity.Recommendations != "" {
+					fmt.Printf("  Recommendations: %s\n", sessionCtx.Continuity.Recommendations)
 				}
 			}
 		} else {
 			// JSON output (default for LLMs)
-			response := &models.StartResponse{
-				Status:  "started",
-				Context: ctx,
-			}
 			outputResult(response)
 		}
 		return nil
@@ -401,71 +777,153 @@ Example:
 
 // buildSessionContext creates an AI-first session context with all information
 // needed for successful task completion
-func buildSessionContext(sessionID, projectID, objective, aiID string, sessionStart time.Time) *models.SessionContext {
-	ctx := &models.SessionContext{
+// inheritFrom lists ancestor project IDs whose knowledge is merged in for sub-projects,
+// and the global project
+func buildSessionContext(ctx context.Context, sessionID, projectID, objective, aiID string, sessionStart time.Time, inheritFrom []string, goal *models.Goal, agents *agentFilter) *models.SessionContext {
+	defer contextBuildDuration.ObserveSince(time.Now())
+	ctx, span := tracer().Start(ctx, "build session context")
+	defer span.End()
+	sessionCtx := &models.SessionContext{
 		SessionID: sessionID,
 		ProjectID: projectID,
 		Objective: objective,
 	}
 
-	bcRepo := db.NewBreadcrumbRepository(database)
+	bcRepo := stores.Breadcrumbs
+
+	// Other goals' breadcrumbs are dropped below, so fetch more to fill the lists.
+	// Untrusted agents' breadcrumbs are left out by the queries themselves.
+	overfetch := 1
+	if goal != nil {
+		overfetch *= goalOverfetch
+	}
+	limit := func(n int) int { return n * overfetch }
 
 	// Get all relevant data
-	findings, _ := bcRepo.ListFindingsWithStaleness(projectID, "", 20)
+	findings, _, _ := bcRepo.ListFindingsPage(ctx, projectID, "", agents.page(limit(20)))
 	resolved := false
-	openUnknowns, _ := bcRepo.ListUnknowns(projectID, "", &resolved, 10)
+	openUnknowns, _, _ := bcRepo.ListUnknownsPage(ctx, projectID, "", &resolved, agents.page(limit(unknownRankWindow)))
 	resolvedFlag := true
-	resolvedUnknowns, _ := bcRepo.ListUnknowns(projectID, "", &resolvedFlag, 10)
-	deadEnds, _ := bcRepo.ListDeadEnds(projectID, "", 10)
+	resolvedUnknowns, _, _ := bcRepo.ListUnknownsPage(ctx, projectID, "", &resolvedFlag, agents.page(limit(10)))
+	deadEnds, _, _ := bcRepo.ListDeadEndsPage(ctx, projectID, "", agents.page(limit(10)))
+
+	// The preferred agent family's breadcrumbs are ranked first; goal scoping below
+	// trims the lists to size
+	if agents != nil {
+		keep := func(n int) int {
+			if goal != nil {
+				return limit(n)
+			}
+			return n
+		}
+		findings, _ = agentScoped(ctx, agents, findings, keep(20), func(f *models.Finding) string { return f.SessionID })
+		openUnknowns, _ = agentScoped(ctx, agents, openUnknowns, keep(unknownRankWindow), func(u *models.Unknown) string { return u.SessionID })
+		resolvedUnknowns, _ = agentScoped(ctx, agents, resolvedUnknowns, keep(10), func(u *models.Unknown) string { return u.SessionID })
+		deadEnds, _ = agentScoped(ctx, agents, deadEnds, keep(10), func(d *models.DeadEnd) string { return d.SessionID })
+		traceContext(ctx, "filtered by agent", "ai_ids", agents.aiIDs, "prefer_family", agents.preferFamily)
+	}
+
+	if goal != nil {
+		var hidden [4]int
+		findings, hidden[0] = goalScoped(findings, goal.ID, 20, func(f *models.Finding) *string { return f.GoalID })
+		openUnknowns, hidden[1] = goalScoped(openUnknowns, goal.ID, unknownRankWindow, func(u *models.Unknown) *string { return u.GoalID })
+		resolvedUnknowns, hidden[2] = goalScoped(resolvedUnknowns, goal.ID, 10, func(u *models.Unknown) *string { return u.GoalID })
+		deadEnds, hidden[3] = goalScoped(deadEnds, goal.ID, 10, func(d *models.DeadEnd) *string { return d.GoalID })
+		sessionCtx.Goal = &models.GoalContext{
+			GoalID:    goal.ID,
+			Objective: goal.Objective,
+			Hidden:    hidden[0] + hidden[1] + hidden[2] + hidden[3],
+		}
+		traceContext(ctx, "scoped to goal", "goal", goal.ID, "hidden_findings", hidden[0],
+			"hidden_open_unknowns", hidden[1], "hidden_resolved_unknowns", hidden[2], "hidden_dead_ends", hidden[3])
+	}
+
+	// Sub-projects opted into inheritance also see parent-level knowledge
+	for _, parentID := range inheritFrom {
+		parentFindings, _, _ := bcRepo.ListFindingsPage(ctx, parentID, "", agents.page(20))
+		parentOpen, _, _ := bcRepo.ListUnknownsPage(ctx, parentID, "", &resolved, agents.page(unknownRankWindow))
+		parentResolved, _, _ := bcRepo.ListUnknownsPage(ctx, parentID, "", &resolvedFlag, agents.page(10))
+		parentDeadEnds, _, _ := bcRepo.ListDeadEndsPage(ctx, parentID, "", agents.page(10))
+		if agents != nil {
+			parentFindings, _ = agentScoped(ctx, agents, parentFindings, 20, func(f *models.Finding) string { return f.SessionID })
+			parentOpen, _ = agentScoped(ctx, agents, parentOpen, unknownRankWindow, func(u *models.Unknown) string { return u.SessionID })
+			parentResolved, _ = agentScoped(ctx, agents, parentResolved, 10, func(u *models.Unknown) string { return u.SessionID })
+			parentDeadEnds, _ = agentScoped(ctx, agents, parentDeadEnds, 10, func(d *models.DeadEnd) string { return d.SessionID })
+		}
+		findings = append(findings, parentFindings...)
+		openUnknowns = append(openUnknowns, parentOpen...)
+		resolvedUnknowns = append(resolvedUnknowns, parentResolved...)
+		deadEnds = append(deadEnds, parentDeadEnds...)
+		traceContext(ctx, "inherited from parent project", "project", parentID, "findings", len(parentFindings),
+			"open_unknowns", len(parentOpen), "resolved_unknowns", len(parentResolved), "dead_ends", len(parentDeadEnds))
+	}
+
+	// Questions assigned to this session come first whatever their rank or goal
+	assigned, assignedQuestions := assignedUnknowns(ctx, append([]string{projectID}, inheritFrom...), objective)
+	sessionCtx.AssignedQuestions = assignedQuestions
+	isAssigned := make(map[string]bool, len(assigned))
+	for _, a := range assignedQuestions {
+		isAssigned[a.ID] = true
+		traceContext(ctx, "included assigned question", "id", a.ID, "assigned_to", a.AssignedTo)
+	}
+	unassigned := openUnknowns[:0]
+	for _, u := range openUnknowns {
+		if !isAssigned[u.ID] {
+			unassigned = append(unassigned, u)
+		}
+	}
+
+	// Only the questions that matter most make it into the context
+	openUnknowns = append(assigned, topUnknowns(ctx, unassigned, newUnknownRelevance(ctx, objective), 10*(1+len(inheritFrom)))...)
+
+	// Hash all scoped files in one git call instead of one per finding
+	primeFindingHashes(ctx, findings)
+
+	sessionCtx.Issues, _ = stores.IssueLinks.ListByTarget(ctx, models.IssueTargetSession, sessionID)
 
 	// Calculate epistemic state
-	epistemic := calculateEpistemicState(findings, openUnknowns, resolvedUnknowns, deadEnds, sessionStart)
+	epistemic := calculateEpistemicState(ctx, findings, openUnknowns, resolvedUnknowns, deadEnds, sessionStart)
 
 	// Build epistemic snapshot
-	ctx.Vectors = &models.EpistemicSnapshot{
-		Know:        epistemic.Know,
-		Uncertainty: epistemic.Uncertainty,
-		Clarity:     epistemic.Clarity,
-		Coherence:   epistemic.Coherence,
-		Completion:  epistemic.Completion,
-		Engagement:  epistemic.Engagement,
-		Overall:     epistemic.Confidence,
-	}
+	sessionCtx.Vectors = epistemic.Snapshot()
 
 	// Build decision guidance - the most important part for AI
-	ctx.Decision = buildDecisionGuidance(epistemic, findings, openUnknowns, deadEnds)
+	var subtasks []*models.SubTask
+	if goal != nil {
+		subtasks, _ = stores.Subtasks.ListByGoal(ctx, goal.ID)
+	}
+	sessionCtx.Decision = buildDecisionGuidance(ctx, epistemic, findings, openUnknowns, deadEnds, subtasks)
+	sessionCtx.Explanation = explainDecision(ctx, epistemic, findings, openUnknowns, resolvedUnknowns, deadEnds)
 
 	// Categorize findings by staleness
 	for _, f := range findings {
-		fileChanged := false
+		fileChanged := findingFileChanged(ctx, f)
 		scope := ""
 		if f.Subject != nil {
 			scope = *f.Subject
-			if f.SubjectGitHash != nil {
-				fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-			}
 		}
 
 		status := f.GetStalenessStatus(fileChanged)
 		confidence := f.CalculateConfidence()
 		daysStale := int(f.DaysSinceVerified())
+		section := "knowledge"
+		if status == models.StatusStale {
+			section = "requires_verification"
+		}
+		traceContext(ctx, "finding", "id", f.ID, "section", section, "status", status,
+			"confidence", math.Round(confidence*100)/100, "days_since_verified", daysStale, "file_changed", fileChanged)
 
 		switch status {
 		case models.StatusStale:
 			// Stale findings need verification
-			verifyCmd := fmt.Sprintf("memory verify \"%s\"", truncateText(f.Finding, 30))
-			if len(f.ID) >= 8 {
-				verifyCmd = fmt.Sprintf("memory verify --id %s", f.ID[:8])
-			}
-
-			ctx.RequiresVerification = append(ctx.RequiresVerification, models.VerificationNeeded{
+			sessionCtx.RequiresVerification = append(sessionCtx.RequiresVerification, models.VerificationNeeded{
 				Finding:       f.Finding,
 				ID:            f.ID,
 				DaysStale:     daysStale,
 				Confidence:    confidence,
 				FileChanged:   fileChanged,
 				Scope:         scope,
-				VerifyCommand: verifyCmd,
+				VerifyCommand: suggestVerifyCommand(f.ID, f.Finding),
 			})
 
 		case models.StatusFresh, models.StatusAging:
@@ -474,7 +932,7 @@ func buildSessionContext(sessionID, projectID, objective, aiID string, sessionSt
 			if status == models.StatusAging {
 				statusStr = "aging"
 			}
-			ctx.Knowledge = append(ctx.Knowledge, models.KnowledgeItem{
+			sessionCtx.Knowledge = append(sessionCtx.Knowledge, models.KnowledgeItem{
 				Finding:    f.Finding,
 				Confidence: confidence,
 				Status:     statusStr,
@@ -489,21 +947,59 @@ func buildSessionContext(sessionID, projectID, objective, aiID string, sessionSt
 		if d.Subject != nil {
 			scope = *d.Subject
 		}
-		ctx.DeadEnds = append(ctx.DeadEnds, models.DeadEndWarning{
+		sessionCtx.DeadEnds = append(sessionCtx.DeadEnds, models.DeadEndWarning{
 			Approach:  d.Approach,
 			WhyFailed: d.WhyFailed,
 			Scope:     scope,
 		})
 	}
 
-	// Add open questions
+	// Add open questions; assigned ones are already listed
 	for _, u := range openUnknowns {
-		ctx.OpenQuestions = append(ctx.OpenQuestions, u.Unknown)
+		if isAssigned[u.ID] {
+			continue
+		}
+		sessionCtx.OpenQuestions = append(sessionCtx.OpenQuestions, u.Unknown)
 	}
 
+	// Group the goal's own breadcrumbs
+	if g := sessionCtx.Goal; g != nil {
+		for _, f := range findings {
+			if f.GoalID != nil && *f.GoalID == g.GoalID {
+				g.Findings = append(g.Findings, f.Finding)
+			}
+		}
+		for _, u := range openUnknowns {
+			if u.GoalID != nil && *u.GoalID == g.GoalID {
+				g.OpenQuestions = append(g.OpenQuestions, u.Unknown)
+			}
+		}
+		for _, d := range deadEnds {
+			if d.GoalID != nil && *d.GoalID == g.GoalID {
+				g.DeadEnds = append(g.DeadEnds, models.DeadEndWarning{Approach: d.Approach, WhyFailed: d.WhyFailed})
+			}
+		}
+	}
+
+	sessionCtx.AgingUnknowns = agingUnknowns(ctx, projectID)
+
+	// Point at registered docs that match the objective or sit beside scoped findings
+	var scopes []string
+	repoRoot, _ := gitRepoRoot(ctx)
+	for _, f := range findings {
+		switch f.ScopeKind() {
+		case models.ScopeFile, models.ScopeDir, models.ScopeURL:
+			scopes = append(scopes, normalizeScopePath(*f.Subject, repoRoot))
+		}
+	}
+	sessionCtx.ReferenceDocs = relevantReferenceDocs(ctx, append([]string{projectID}, inheritFrom...), objective, scopes)
+
+	// Returning to an idle project, the last session alone may not say enough
+	sessionCtx.ProjectBriefing = idleProjectBriefing(ctx, projectID)
+
 	// Build continuity context from last handoff (project-scoped)
-	handoffRepo := db.NewHandoffRepository(database)
-	handoffs, _ := handoffRepo.List(projectID, aiID, 1)
+	handoffRepo := stores.Handoffs
+	handoffs, _ := handoffRepo.List(ctx, projectID, aiID, 1)
 	if len(handoffs) > 0 {
 		h := handoffs[0]
 		continuity := &models.ContinuityContext{}
@@ -545,19 +1041,82 @@ func buildSessionContext(sessionID, projectID, objective, aiID string, sessionSt
 		}
 
 		if hasContent {
-			ctx.Continuity = continuity
+			sessionCtx.Continuity = continuity
+		}
+	}
+
+	return sessionCtx
+}
+
+// goalOverfetch multiplies list limits while a goal is active, since breadcrumbs
+// recorded under other goals are filtered out afterwards
+const goalOverfetch = 5
+
+// goalScoped keeps up to limit items recorded under the goal or under no goal at all,
+// and counts the items left out because they belong to another goal
+func goalScoped[T any](items []T, goalID string, limit int, itemGoal func(T) *string) ([]T, int) {
+	kept := items[:0]
+	hidden := 0
+	for _, item := range items {
+		if g := itemGoal(item); g != nil && *g != goalID {
+			hidden++
+			continue
+		}
+		if len(kept) < limit {
+			kept = append(kept, item)
 		}
 	}
+	return kept, hidden
+}
 
-	return ctx
+// currentGoal loads the session's active goal, or nil when there is none
+func currentGoal(ctx context.Context, active *ActiveSession) *models.Goal {
+	if active.CurrentGoalID == "" {
+		return nil
+	}
+	goal, err := stores.Goals.Get(ctx, active.CurrentGoalID)
+	if err != nil {
+		return nil
+	}
+	return goal
+}
+
+// printGoalContext prints the breadcrumbs recorded under the active goal
+func printGoalContext(g *models.GoalContext) {
+	if g == nil {
+		return
+	}
+	fmt.Printf("\n◎ GOAL: %s\n", g.Objective)
+	for _, f := range g.Findings {
+		fmt.Printf("  ✓ %s\n", f)
+	}
+	for _, q := range g.OpenQuestions {
+		fmt.Printf("  ? %s\n", q)
+	}
+	for _, d := range g.DeadEnds {
+		fmt.Printf("  %s %s — %s\n", paint(ansiRed, "✗"), d.Approach, d.WhyFailed)
+	}
+	if g.Hidden > 0 {
+		fmt.Printf("  (%d breadcrumbs from other goals hidden)\n", g.Hidden)
+	}
+}
+
+// suggestVerifyCommand returns the command an agent should run to verify a finding
+func suggestVerifyCommand(id, finding string) string {
+	if len(id) >= 8 {
+		return fmt.Sprintf("memory verify --id %s", id[:8])
+	}
+	return fmt.Sprintf("memory verify \"%s\"", truncateText(finding, 30))
 }
 
 // buildDecisionGuidance creates the decision support section
 func buildDecisionGuidance(
+	ctx context.Context,
 	epistemic *EpistemicState,
 	findings []*models.Finding,
 	openUnknowns []*models.Unknown,
 	deadEnds []*models.DeadEnd,
+	subtasks []*models.SubTask,
 ) *models.DecisionGuidance {
 	guidance := &models.DecisionGuidance{
 		ReadyToProceed:  epistemic.ReadyToProceed,
@@ -569,10 +1128,7 @@ func buildDecisionGuidance(
 	// Count stale findings
 	staleCount := 0
 	for _, f := range findings {
-		fileChanged := false
-		if f.Subject != nil && f.SubjectGitHash != nil {
-			fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-		}
+		fileChanged := findingFileChanged(ctx, f)
 		if f.GetStalenessStatus(fileChanged) == models.StatusStale {
 			staleCount++
 		}
@@ -590,7 +1146,7 @@ func buildDecisionGuidance(
 			if len(openUnknowns) > 0 {
 				prerequisites = append(prerequisites, fmt.Sprintf("Resolve %d open question(s)", len(openUnknowns)))
 			}
-			if epistemic.Know < 0.50 {
+			if epistemic.Know < minKnow {
 				prerequisites = append(prerequisites, "Log discoveries with `memory learned`")
 			}
 
@@ -612,6 +1168,30 @@ func buildDecisionGuidance(
 		}
 	}
 
+	// Planned work comes before the generic advice: a critical subtask that can start now
+	// is the first prerequisite, and blocked important ones need unblocking
+	next, blocked := planSubtasks(subtasks)
+	if next != nil {
+		ref := subtaskRef(next)
+		guidance.NextSubtask = &ref
+		if next.EpistemicImportance == models.ImportanceCritical {
+			prerequisites = append([]string{fmt.Sprintf("Complete critical subtask: %s (`memory subtask done %s`)", next.Description, next.ID[:8])}, prerequisites...)
+		} else if epistemic.ReadyToProceed {
+			guidance.Reason += fmt.Sprintf(" Next subtask: %s.", next.Description)
+		}
+	}
+	for _, b := range blocked {
+		guidance.BlockedSubtasks = append(guidance.BlockedSubtasks, subtaskRef(b))
+		important := b.EpistemicImportance == models.ImportanceCritical || b.EpistemicImportance == models.ImportanceHigh
+		if important && b.Status == models.TaskStatusBlocked {
+			prerequisites = append(prerequisites, fmt.Sprintf("Unblock %s subtask: %s", b.EpistemicImportance, b.Description))
+		}
+	}
+	if next == nil && len(blocked) > 0 {
+		guidance.ReadyToProceed = false
+		guidance.Reason += fmt.Sprintf(" All %d remaining subtask(s) of the goal are blocked.", len(blocked))
+	}
+
 	guidance.Prerequisites = prerequisites
 	return guidance
 }
@@ -626,27 +1206,28 @@ func truncateText(text string, maxLen int) string {
 
 // buildBootstrapContext is deprecated, use buildSessionContext instead
 // Kept for backward compatibility
-func buildBootstrapContext(projectID, aiID string, sessionStart time.Time) map[string]interface{} {
+func buildBootstrapContext(ctx context.Context, projectID, aiID string, sessionStart time.Time) map[string]interface{} {
 	context := map[string]interface{}{}
 
-	bcRepo := db.NewBreadcrumbRepository(database)
+	bcRepo := stores.Breadcrumbs
 
 	// Get recent findings with staleness data
-	findings, _ := bcRepo.ListFindingsWithStaleness(projectID, "", 20)
+	findings, _ := bcRepo.ListFindingsWithStaleness(ctx, projectID, "", 20)
 
 	// Get open unknowns
 	resolved := false
-	unknowns, _ := bcRepo.ListUnknowns(projectID, "", &resolved, 10)
+	unknowns, _ := bcRepo.ListUnknowns(ctx, projectID, "", &resolved, 10)
 
 	// Get resolved unknowns for epistemic calculation
 	resolvedFlag := true
-	resolvedUnknowns, _ := bcRepo.ListUnknowns(projectID, "", &resolvedFlag, 10)
+	resolvedUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, "", &resolvedFlag, 10)
 
 	// Get dead ends to avoid
-	deadEnds, _ := bcRepo.ListDeadEnds(projectID, "", 5)
+	deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, "", 5)
+	primeFindingHashes(ctx, findings)
 
 	// Calculate epistemic state from historical project data
-	epistemic := calculateEpistemicState(findings, unknowns, resolvedUnknowns, deadEnds, sessionStart)
+	epistemic := calculateEpistemicState(ctx, findings, unknowns, resolvedUnknowns, deadEnds, sessionStart)
 	context["epistemic_state"] = epistemic
 
 	// Process findings
@@ -656,10 +1237,7 @@ func buildBootstrapContext(projectID, aiID string, sessionStart time.Time) map[s
 		var freshFindings []string
 
 		for _, f := range findings {
-			fileChanged := false
-			if f.Subject != nil && f.SubjectGitHash != nil {
-				fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-			}
+			fileChanged := findingFileChanged(ctx, f)
 			status := f.GetStalenessStatus(fileChanged)
 
 			if status == models.StatusStale {
@@ -702,8 +1280,8 @@ func buildBootstrapContext(projectID, aiID string, sessionStart time.Time) map[s
 	}
 
 	// Get last session handoff (project-scoped)
-	handoffRepo := db.NewHandoffRepository(database)
-	handoffs, _ := handoffRepo.List(projectID, aiID, 1)
+	handoffRepo := stores.Handoffs
+	handoffs, _ := handoffRepo.List(ctx, projectID, aiID, 1)
 	if len(handoffs) > 0 {
 		h := handoffs[0]
 		lastSession := map[string]interface{}{}
@@ -728,39 +1306,111 @@ var doneCmd = &cobra.Command{
 	Long: `End the current session with a summary of what was accomplished.
 
 This will:
-- Calculate epistemic state and show delta from baseline
+- Calculate epistemic state and show its change since start
 - Create a handoff for future sessions
 - Store remaining unknowns for next time
 
+With --dry-run the handoff that would be written is printed and the session stays open.
+Each --next recommendation is carried into the handoff for the next session and
+ranked first by 'memory suggest'.
+
+With --summarize an LLM drafts the summary and next-session recommendations from the
+session's breadcrumbs; a summary given alongside is passed to it as a hint, and used
+as is if the LLM can't be reached. Configure the provider with MEMORY_LLM_PROVIDER
+(openai, anthropic or ollama), MEMORY_LLM_API_KEY (or OPENAI_API_KEY /
+ANTHROPIC_API_KEY), and optionally MEMORY_LLM_MODEL and MEMORY_LLM_URL, e.g. for an
+OpenAI-compatible server.
+
 Example:
-  memory done "Implemented JWT authentication with refresh tokens"`,
-	Args: cobra.ExactArgs(1),
+  memory done "Implemented JWT authentication with refresh tokens"
+  memory done "Implemented JWT auth" --next "Add refresh token rotation"
+  memory done "Implemented JWT auth" --dry-run
+  memory done --summarize`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		summary := args[0]
+		ctx := cmd.Context()
+		summarize, _ := cmd.Flags().GetBool("summarize")
+		summary := ""
+		if len(args) > 0 {
+			summary = args[0]
+		}
+		if summary == "" && !summarize {
+			return fmt.Errorf("%w: give a summary, or --summarize to draft one", db.ErrInvalid)
+		}
 
-		active, err := requireActiveSession()
+		active, err := requireActiveSession(ctx)
 		if err != nil {
 			return err
 		}
 
-		// Calculate session stats
-		bcRepo := db.NewBreadcrumbRepository(database)
-		findings, _ := bcRepo.ListFindingsWithStaleness(active.ProjectID, active.SessionID, 100)
+		// Calculate session stats. A handoff built from a failed read would pass for an
+		// empty session, so nothing is written unless every read succeeds.
+		bcRepo := stores.Breadcrumbs
+		findings, err := bcRepo.ListFindingsWithStaleness(ctx, active.ProjectID, active.SessionID, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list findings: %w", err)
+		}
 		resolved := true
-		resolvedUnknowns, _ := bcRepo.ListUnknowns(active.ProjectID, active.SessionID, &resolved, 100)
+		resolvedUnknowns, err := bcRepo.ListUnknowns(ctx, active.ProjectID, active.SessionID, &resolved, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list resolved unknowns: %w", err)
+		}
 		unresolved := false
-		openUnknowns, _ := bcRepo.ListUnknowns(active.ProjectID, active.SessionID, &unresolved, 100)
-		deadEnds, _ := bcRepo.ListDeadEnds(active.ProjectID, active.SessionID, 100)
+		openUnknowns, err := bcRepo.ListUnknowns(ctx, active.ProjectID, active.SessionID, &unresolved, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list open unknowns: %w", err)
+		}
+		deadEnds, err := bcRepo.ListDeadEnds(ctx, active.ProjectID, active.SessionID, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list dead ends: %w", err)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dryRun {
+			flagChangedScopes(ctx, findings) // Staleness counts, but nothing is recorded
+		} else {
+			primeFindingHashes(ctx, findings)
+		}
 
 		// Calculate full epistemic state
-		epistemic := calculateEpistemicState(findings, openUnknowns, resolvedUnknowns, deadEnds, active.StartedAt)
+		epistemic := calculateEpistemicState(ctx, findings, openUnknowns, resolvedUnknowns, deadEnds, active.StartedAt)
+
+		// Draft the summary before anything is written, so a failure leaves the session open
+		var draft *handoffDraft
+		if summarize {
+			llm, err := loadLLMConfig()
+			if err != nil {
+				return err
+			}
+			var crumbs sessionBreadcrumbs
+			for _, f := range findings {
+				crumbs.Findings = append(crumbs.Findings, f.Finding)
+			}
+			for _, u := range resolvedUnknowns {
+				crumbs.ResolvedUnknowns = append(crumbs.ResolvedUnknowns, u.Unknown)
+			}
+			for _, u := range openUnknowns {
+				crumbs.OpenUnknowns = append(crumbs.OpenUnknowns, u.Unknown)
+			}
+			for _, d := range deadEnds {
+				crumbs.DeadEnds = append(crumbs.DeadEnds, d.Approach+" (failed: "+d.WhyFailed+")")
+			}
+			draft, err = draftHandoff(ctx, llm, active.Objective, summary, crumbs)
+			if err != nil {
+				if summary == "" {
+					return fmt.Errorf("failed to draft the handoff: %w", err)
+				}
+				slog.Warn("failed to draft the handoff; using the given summary", "err", err)
+			} else {
+				summary = draft.Summary
+			}
+		}
 
 		// Create handoff (project-scoped)
-		handoffRepo := db.NewHandoffRepository(database)
 		handoffInput := &models.HandoffCreateInput{
 			SessionID:   active.SessionID,
 			ProjectID:   active.ProjectID,
 			TaskSummary: summary,
+			Artifacts:   active.Artifacts,
 		}
 
 		// Collect key findings
@@ -775,48 +1425,99 @@ Example:
 		for _, u := range openUnknowns {
 			remainingUnknowns = append(remainingUnknowns, u.Unknown)
 		}
+		next, _ := cmd.Flags().GetStringArray("next")
+		if draft != nil {
+			next = append(next, draft.Next...)
+		}
+
+		// Questions assigned to the next session are carried explicitly, wherever they were logged
+		carried, _ := assignedUnknowns(ctx, []string{active.ProjectID}, "")
+		for _, u := range carried {
+			next = append(next, "Answer: "+u.Unknown)
+			if !slices.Contains(remainingUnknowns, u.Unknown) {
+				remainingUnknowns = append(remainingUnknowns, u.Unknown)
+			}
+		}
 		handoffInput.RemainingUnknowns = remainingUnknowns
+		handoffInput.NextSessionContext = strings.Join(next, "\n")
+
+		// Deltas are measured from the state start computed for this session
+		baseline, baselineRecorded := sessionBaseline(ctx, active.SessionID)
+		delta := vectorDeltas(baseline, epistemic.Snapshot())
+		if baselineRecorded {
+			// Done is a completed outcome with nothing invalidated yet; 'memory
+			// calibration' revises this as findings are later deleted
+			handoffInput.CalibrationStatus = models.CalibrationStatusFor(baseline.Overall, 1)
+		}
+		handoffInput.EpistemicDeltas = delta
+		handoffInput.DurationSeconds = time.Since(active.StartedAt).Seconds()
 
-		handoffRepo.Create(handoffInput, active.AIID)
+		if dryRun {
+			return previewDone(active, handoffInput, epistemic, len(findings), len(resolvedUnknowns), len(openUnknowns), len(deadEnds))
+		}
 
-		// End session
-		sessionRepo := db.NewSessionRepository(database)
-		sessionRepo.End(active.SessionID)
+		// Write the handoff and end the session together so a failure can't leave the
+		// session half closed
+		err = stores.InTx(ctx, func(tx *db.Stores) error {
+			if _, err := tx.Handoffs.Create(ctx, handoffInput, active.AIID); err != nil {
+				return fmt.Errorf("failed to create handoff: %w", err)
+			}
+			if err := tx.Sessions.End(ctx, active.SessionID); err != nil {
+				return fmt.Errorf("failed to end session: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 
-		// Clear active session
-		clearActiveSession()
+		recordEpistemicSnapshot(ctx, active.SessionID, models.PhasePostflight, epistemic.Snapshot())
+
+		if err := clearActiveSession(ctx); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("session ended but the active session file could not be removed: %w", err)
+		}
 
 		duration := time.Since(active.StartedAt)
 
+		result := map[string]interface{}{
+			"status":          "completed",
+			"session_id":      active.SessionID,
+			"objective":       active.Objective,
+			"summary":         summary,
+			"duration":        duration.String(),
+			"epistemic_state": epistemic,
+			"stats": map[string]interface{}{
+				"findings":          len(findings),
+				"unknowns_resolved": len(resolvedUnknowns),
+				"unknowns_open":     len(openUnknowns),
+				"dead_ends":         len(deadEnds),
+				"artifacts":         len(active.Artifacts),
+				"carried_unknowns":  len(carried),
+			},
+			"delta":             delta,
+			"baseline":          baseline,
+			"baseline_recorded": baselineRecorded,
+			"summarized":        draft != nil,
+		}
+		emitEvent(ctx, EventSessionDone, active.ProjectID, result)
+
 		if !outputText {
-			result := map[string]interface{}{
-				"status":          "completed",
-				"objective":       active.Objective,
-				"summary":         summary,
-				"duration":        duration.String(),
-				"epistemic_state": epistemic,
-				"stats": map[string]interface{}{
-					"findings":          len(findings),
-					"unknowns_resolved": len(resolvedUnknowns),
-					"unknowns_open":     len(openUnknowns),
-					"dead_ends":         len(deadEnds),
-				},
-				"delta": map[string]interface{}{
-					"know":        epistemic.Know - 0.5,
-					"uncertainty": epistemic.Uncertainty - 0.5,
-					"clarity":     epistemic.Clarity - 0.5,
-				},
-			}
 			outputResult(result)
 		} else {
 			fmt.Printf("Session completed: %s\n", active.Objective)
 			fmt.Println(strings.Repeat("─", 50))
 			fmt.Printf("Duration: %s\n\n", duration.Round(time.Minute))
 
-			fmt.Println("Epistemic Delta:")
-			fmt.Printf("  Know:        %+.2f (0.50 → %.2f)\n", epistemic.Know-0.5, epistemic.Know)
-			fmt.Printf("  Uncertainty: %+.2f (0.50 → %.2f)\n", epistemic.Uncertainty-0.5, epistemic.Uncertainty)
-			fmt.Printf("  Clarity:     %+.2f (0.50 → %.2f)\n", epistemic.Clarity-0.5, epistemic.Clarity)
+			if baselineRecorded {
+				fmt.Println("Epistemic Delta (from start):")
+			} else {
+				fmt.Println("Epistemic Delta (from default 0.50 baseline):")
+			}
+			final := epistemic.Snapshot()
+			for _, vec := range trendVectors {
+				label := strings.ToUpper(vec.name[:1]) + vec.name[1:] + ":"
+				fmt.Printf("  %-12s %+.2f (%.2f → %.2f)\n", label, delta[vec.name], vec.value(baseline), vec.value(final))
+			}
 
 			// Final state
 			confidenceLabel := "Critical"
@@ -830,71 +1531,255 @@ Example:
 			fmt.Printf("\nFinal: %s %s (%.0f%% confidence)\n", epistemic.MoonPhase, confidenceLabel, epistemic.Confidence*100)
 
 			// Stats
-			fmt.Printf("\nStats: %d findings, %d resolved, %d open, %d dead ends\n",
-				len(findings), len(resolvedUnknowns), len(openUnknowns), len(deadEnds))
+			fmt.Printf("\nStats: %d findings, %d resolved, %d open, %d dead ends, %d artifacts\n",
+				len(findings), len(resolvedUnknowns), len(openUnknowns), len(deadEnds), len(active.Artifacts))
+			if len(carried) > 0 {
+				fmt.Printf("Carried %d assigned question(s) into the handoff\n", len(carried))
+			}
 		}
 		return nil
 	},
 }
 
+// previewDone prints the handoff a done would write, scrubbed as it would be stored,
+// without ending the session
+func previewDone(active *ActiveSession, input *models.HandoffCreateInput, epistemic *EpistemicState, findings, resolved, open, deadEnds int) error {
+	scrubber, err := loadScrubber()
+	if err != nil {
+		return err
+	}
+	if scrubber != nil {
+		input.TaskSummary = scrubber.String(input.TaskSummary)
+		for i := range input.KeyFindings {
+			input.KeyFindings[i] = scrubber.String(input.KeyFindings[i])
+		}
+		for i := range input.RemainingUnknowns {
+			input.RemainingUnknowns[i] = scrubber.String(input.RemainingUnknowns[i])
+		}
+	}
+
+	if !outputText {
+		outputResult(map[string]interface{}{
+			"status":          "dry_run",
+			"session_id":      active.SessionID,
+			"objective":       active.Objective,
+			"handoff":         input,
+			"epistemic_state": epistemic,
+			"stats": map[string]interface{}{
+				"findings":          findings,
+				"unknowns_resolved": resolved,
+				"unknowns_open":     open,
+				"dead_ends":         deadEnds,
+			},
+		})
+		return nil
+	}
+
+	fmt.Printf("Dry run: session %s would end (nothing written)\n", active.SessionID[:8])
+	fmt.Println(strings.Repeat("─", 50))
+	fmt.Printf("Handoff summary: %s\n", input.TaskSummary)
+	fmt.Printf("Duration: %s\n", (time.Duration(input.DurationSeconds) * time.Second).Round(time.Minute))
+	if len(input.KeyFindings) > 0 {
+		fmt.Printf("\nKey findings (%d):\n", len(input.KeyFindings))
+		for _, f := range input.KeyFindings {
+			fmt.Printf("  • %s\n", f)
+		}
+	}
+	if len(input.RemainingUnknowns) > 0 {
+		fmt.Printf("\nRemaining unknowns (%d):\n", len(input.RemainingUnknowns))
+		for _, u := range input.RemainingUnknowns {
+			fmt.Printf("  ? %s\n", u)
+		}
+	}
+	if len(input.Artifacts) > 0 {
+		fmt.Printf("\nArtifacts (%d):\n", len(input.Artifacts))
+		for _, a := range input.Artifacts {
+			fmt.Printf("  + %s\n", a)
+		}
+	}
+	fmt.Println("\nEpistemic deltas:")
+	for _, vec := range trendVectors {
+		label := strings.ToUpper(vec.name[:1]) + vec.name[1:] + ":"
+		fmt.Printf("  %-12s %+.2f\n", label, input.EpistemicDeltas[vec.name])
+	}
+	fmt.Printf("\nStats: %d findings, %d resolved, %d open, %d dead ends\n", findings, resolved, open, deadEnds)
+	return nil
+}
+
 // learnedCmd logs a finding/discovery
 var learnedCmd = &cobra.Command{
-	Use:   "learned [insight]",
-	Short: "Log something you learned",
+	Use:     "learned [insight]",
+	Aliases: []string{"l"},
+	Short:   "Log something you learned",
 	Long: `Log a finding, discovery, or insight gained during work.
 
-Use --scope to associate the finding with a specific file for staleness tracking.
+Use --scope to associate the finding with what it's about for staleness tracking.
+When that changes the finding goes stale. How a change is detected depends on the
+scope's kind, inferred from --scope unless --scope-kind names it:
+
+  file          the file's git blob hash
+  dir           the blob hashes of every file under the directory
+  url           the ETag/Last-Modified header, so external docs decay when they change;
+                fetched at most every 15 minutes
+  command       the executable the command runs, so a tool upgrade is noticed; the
+                command is never run
+  external-api  nothing to fingerprint; the finding only ages, so pair it with --check
 
 Example:
   memory learned "Auth uses JWT with 15min expiry"
   memory learned "Database connection pool is set to 10" --scope config/db.go
-  memory learned "Rate limiting is handled by nginx"`,
-	Args: cobra.ExactArgs(1),
+  memory learned "Pagination uses cursors" --scope https://api.example.com/docs
+  memory learned "Migrations run in filename order" --scope db/migrations
+  memory learned "protoc 3 rejects optional fields" --scope protoc --scope-kind command
+  memory learned "Stripe caps refunds at the charge" --scope stripe/refunds --scope-kind external-api
+  memory learned "Rate limiting is handled by nginx"
+  memory learned "Auth tests cover token refresh" --check "go test ./auth/..."
+  memory learned "Retry logic added to client" --link-head
+  memory learned "Tokens are checked before expiry" --snippet auth/jwt.go:20-45
+  memory learned "CI requires signed commits" --global   # Shown in every project
+  git diff HEAD~1 | memory learned -            # Text from stdin
+  memory learned --file notes/retry.md          # Text from a file
+
+Text longer than the limit set with 'memory limits' (2000 characters by default) is
+cut and marked as truncated; --allow-long keeps it whole.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		findingText := args[0]
+		ctx := cmd.Context()
+		args, err := breadcrumbArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		findingText, _, truncated := limits.apply(models.EntityFinding, args[0], "")
 		scope, _ := cmd.Flags().GetString("scope")
+		scopeKindName, _ := cmd.Flags().GetString("scope-kind")
+		check, _ := cmd.Flags().GetString("check")
+		snippetSpec, _ := cmd.Flags().GetString("snippet")
+		linkHead, _ := cmd.Flags().GetBool("link-head")
+		global, _ := cmd.Flags().GetBool("global")
+		impact, err := impactFlag(cmd)
+		if err != nil {
+			return err
+		}
 
-		active, err := requireActiveSession()
+		active, err := loggingSession(cmd)
 		if err != nil {
 			return err
 		}
 
-		finding := models.NewFinding(active.ProjectID, active.SessionID, findingText, 0.5)
+		var snippet *models.CodeSnippet
+		if snippetSpec != "" {
+			if snippet, err = captureSnippet(snippetSpec); err != nil {
+				return err
+			}
+			if scope == "" {
+				scope = snippet.Path // The finding goes stale when the file changes
+			}
+		}
 
-		// Set scope and capture git hash for staleness tracking
+		// Resolve HEAD before writing so a missing repo doesn't leave a half-linked finding
+		headSHA := ""
+		if linkHead {
+			headSHA, err = resolveCommit(ctx, "HEAD")
+			if err != nil {
+				return fmt.Errorf("failed to resolve HEAD: %w", err)
+			}
+		}
+
+		var kind models.ScopeKind
 		if scope != "" {
-			finding.Subject = &scope
-			hash := getFileGitHash(scope)
-			if hash != "" {
-				finding.SubjectGitHash = &hash
+			if kind, err = scopeKindFor(scope, scopeKindName); err != nil {
+				return err
+			}
+		} else if scopeKindName != "" {
+			return fmt.Errorf("%w: --scope-kind needs --scope", db.ErrInvalid)
+		}
+
+		finding := newSessionFinding(ctx, active, findingText, scope, kind, impact)
+		finding.Snippet = snippet
+		if check != "" {
+			finding.VerifyCheck = &check
+		}
+		if global {
+			globalProject, err := getOrCreateGlobalProject(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get global project: %w", err)
 			}
+			finding.ProjectID = globalProject.ID
 		}
 
-		// Set initial verification timestamp to creation time
-		finding.LastVerifiedTimestamp = &finding.CreatedTimestamp
+		if err := validateWithHook(ctx, "pre-learned", active.ProjectID, finding); err != nil {
+			return err
+		}
 
-		repo := db.NewBreadcrumbRepository(database)
-		if err := repo.CreateFinding(finding); err != nil {
+		repo := stores.Breadcrumbs
+		if err := repo.CreateFinding(ctx, finding); err != nil {
 			return fmt.Errorf("failed to log finding: %w", err)
 		}
 
-		if !outputText {
-			result := map[string]interface{}{
-				"status":  "logged",
-				"type":    "finding",
-				"finding": findingText,
+		if headSHA != "" {
+			link := models.NewCommitLink(finding.ID, headSHA, models.CommitProduced)
+			if err := stores.CommitLinks.Create(ctx, link); err != nil {
+				return fmt.Errorf("failed to link commit: %w", err)
 			}
-			if scope != "" {
-				result["scope"] = scope
-				if finding.SubjectGitHash != nil {
-					result["git_hash"] = *finding.SubjectGitHash
-				}
+		}
+
+		result := map[string]interface{}{
+			"status":     "logged",
+			"type":       "finding",
+			"id":         finding.ID,
+			"session_id": active.SessionID,
+			"finding":    findingText,
+		}
+		if scope != "" {
+			result["scope"] = scope
+			result["scope_kind"] = kind
+			if finding.SubjectGitHash != nil {
+				result["git_hash"] = *finding.SubjectGitHash
 			}
+		}
+		if check != "" {
+			result["check"] = check
+		}
+		if snippet != nil {
+			result["snippet"] = snippet.Location()
+		}
+		if headSHA != "" {
+			result["commit"] = headSHA
+		}
+		if global {
+			result["global"] = true
+		}
+		if truncated {
+			result["truncated"] = true
+		}
+		emitEvent(ctx, EventFindingLogged, active.ProjectID, result)
+		maybeAutoCheckpoint(ctx, active, 1)
+
+		if !outputText {
 			outputResult(result)
 		} else {
 			fmt.Printf("✓ Learned: %s\n", findingText)
+			if truncated {
+				fmt.Println(truncationNote)
+			}
+			if global {
+				fmt.Println("  (global: applies to every project)")
+			}
 			if scope != "" {
-				fmt.Printf("  (scoped to: %s)\n", scope)
+				fmt.Printf("  (scoped to %s: %s)\n", kind, scope)
+			}
+			if check != "" {
+				fmt.Printf("  (verify with: %s)\n", check)
+			}
+			if snippet != nil {
+				fmt.Printf("  (snippet: %s, %d lines)\n", snippet.Location(), snippet.EndLine-snippet.StartLine+1)
+			}
+			if headSHA != "" {
+				fmt.Printf("  (linked to commit: %s)\n", shortSHA(headSHA))
 			}
 		}
 		return nil
@@ -903,42 +1788,78 @@ Example:
 
 // uncertainCmd logs an unknown/knowledge gap
 var uncertainCmd = &cobra.Command{
-	Use:   "uncertain [question]",
-	Short: "Log something you're uncertain about",
+	Use:     "uncertain [question]",
+	Aliases: []string{"u"},
+	Short:   "Log something you're uncertain about",
 	Long: `Log a question, knowledge gap, or area of uncertainty.
 
 Example:
   memory uncertain "How does token refresh work?"
   memory uncertain "What's the rate limiting strategy?"
-  memory uncertain "Where is the config stored?"`,
-	Args: cobra.ExactArgs(1),
+  memory uncertain "Where is the config stored?"
+  memory uncertain "Are refresh tokens revoked on logout?" --impact 1.0
+  memory uncertain "Why does the nightly job time out?" --next-session
+  memory uncertain "Which claims does the gateway forward?" --for-objective "gateway"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		unknownText := args[0]
+		ctx := cmd.Context()
+		args, err := breadcrumbArgs(cmd, args, 1)
+		if err != nil {
+			return err
+		}
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		unknownText, _, truncated := limits.apply(models.EntityUnknown, args[0], "")
 		scope, _ := cmd.Flags().GetString("scope")
+		nextSession, _ := cmd.Flags().GetBool("next-session")
+		forObjective, _ := cmd.Flags().GetString("for-objective")
+		impact, err := impactFlag(cmd)
+		if err != nil {
+			return err
+		}
 
-		active, err := requireActiveSession()
+		active, err := loggingSession(cmd)
 		if err != nil {
 			return err
 		}
 
-		unknown := models.NewUnknown(active.ProjectID, active.SessionID, unknownText, 0.5)
-		if scope != "" {
-			unknown.Subject = &scope
+		unknown := newSessionUnknown(active, unknownText, scope, impact)
+		if err := validateWithHook(ctx, "pre-uncertain", active.ProjectID, unknown); err != nil {
+			return err
 		}
 
-		repo := db.NewBreadcrumbRepository(database)
-		if err := repo.CreateUnknown(unknown); err != nil {
+		repo := stores.Breadcrumbs
+		if err := repo.CreateUnknown(ctx, unknown); err != nil {
 			return fmt.Errorf("failed to log unknown: %w", err)
 		}
+		if tags := assignmentTags(nextSession, forObjective); len(tags) > 0 {
+			if err := repo.TagBreadcrumb(ctx, models.EntityUnknown, unknown.ID, tags); err != nil {
+				return fmt.Errorf("failed to assign unknown: %w", err)
+			}
+		}
+
+		result := map[string]interface{}{
+			"status":     "logged",
+			"type":       "unknown",
+			"id":         unknown.ID,
+			"session_id": active.SessionID,
+			"unknown":    unknownText,
+		}
+		if truncated {
+			result["truncated"] = true
+		}
+		emitEvent(ctx, EventUnknownLogged, active.ProjectID, result)
+		maybeAutoCheckpoint(ctx, active, 1)
 
 		if !outputText {
-			outputResult(map[string]interface{}{
-				"status":  "logged",
-				"type":    "unknown",
-				"unknown": unknownText,
-			})
+			outputResult(result)
 		} else {
 			fmt.Printf("? Uncertain: %s\n", unknownText)
+			if truncated {
+				fmt.Println(truncationNote)
+			}
 		}
 		return nil
 	},
@@ -946,40 +1867,69 @@ Example:
 
 // triedCmd logs a failed approach
 var triedCmd = &cobra.Command{
-	Use:   "tried [approach] [why-failed]",
-	Short: "Log a failed approach",
+	Use:     "tried [approach] [why-failed]",
+	Aliases: []string{"t"},
+	Short:   "Log a failed approach",
 	Long: `Log an approach that was tried but didn't work, to avoid repeating it.
 
 Example:
   memory tried "passport-local" "Too complex for our needs"
   memory tried "localStorage for tokens" "XSS vulnerability"
-  memory tried "sync file writes" "Blocking the event loop"`,
-	Args: cobra.ExactArgs(2),
+  memory tried "sync file writes" "Blocking the event loop"
+  memory tried "retry without jitter" --file why.md   # Why it failed, from a file`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		approach := args[0]
-		whyFailed := args[1]
+		ctx := cmd.Context()
+		args, err := breadcrumbArgs(cmd, args, 2)
+		if err != nil {
+			return err
+		}
+		limits, err := loadTextLimits(cmd)
+		if err != nil {
+			return err
+		}
+		approach, whyFailed, truncated := limits.apply(models.EntityDeadEnd, args[0], args[1])
+		impact, err := impactFlag(cmd)
+		if err != nil {
+			return err
+		}
 
-		active, err := requireActiveSession()
+		active, err := loggingSession(cmd)
 		if err != nil {
 			return err
 		}
 
-		deadEnd := models.NewDeadEnd(active.ProjectID, active.SessionID, approach, whyFailed, 0.5)
+		deadEnd := newSessionDeadEnd(active, approach, whyFailed, "", impact)
+		if err := validateWithHook(ctx, "pre-tried", active.ProjectID, deadEnd); err != nil {
+			return err
+		}
 
-		repo := db.NewBreadcrumbRepository(database)
-		if err := repo.CreateDeadEnd(deadEnd); err != nil {
+		repo := stores.Breadcrumbs
+		if err := repo.CreateDeadEnd(ctx, deadEnd); err != nil {
 			return fmt.Errorf("failed to log dead end: %w", err)
 		}
 
+		result := map[string]interface{}{
+			"status":     "logged",
+			"type":       "dead_end",
+			"id":         deadEnd.ID,
+			"session_id": active.SessionID,
+			"approach":   approach,
+			"why_failed": whyFailed,
+		}
+		if truncated {
+			result["truncated"] = true
+		}
+		emitEvent(ctx, EventDeadEndLogged, active.ProjectID, result)
+		maybeAutoCheckpoint(ctx, active, 1)
+
 		if !outputText {
-			outputResult(map[string]interface{}{
-				"status":     "logged",
-				"type":       "dead_end",
-				"approach":   approach,
-				"why_failed": whyFailed,
-			})
+			outputResult(result)
 		} else {
 			fmt.Printf("✗ Tried: %s → %s\n", approach, whyFailed)
+			if truncated {
+				fmt.Println(truncationNote)
+			}
 		}
 		return nil
 	},
@@ -987,11 +1937,13 @@ Example:
 
 // statusCmd shows current session status
 var statusCmd = &cobra.Command{
-	Use:   "status",
-	Short: "Show current session status",
-	Long:  `Show the current session status with AI-optimized context including decision guidance, knowledge state, and progress.`,
+	Use:     "status",
+	Aliases: []string{"s"},
+	Short:   "Show current session status",
+	Long:    `Show the current session status with AI-optimized context including decision guidance, knowledge state, and progress.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		active, err := loadActiveSession()
+		ctx := cmd.Context()
+		active, err := loadActiveSession(ctx)
 		if err != nil {
 			if !outputText {
 				response := &models.StatusResponse{
@@ -1008,16 +1960,25 @@ var statusCmd = &cobra.Command{
 		duration := time.Since(active.StartedAt)
 
 		// Build the same context structure as start for consistency
-		ctx := buildSessionContext(active.SessionID, active.ProjectID, active.Objective, active.AIID, active.StartedAt)
+		var inheritFrom []string
+		if active.InheritParent {
+			if project, _ := stores.Projects.Get(ctx, active.ProjectID); project != nil {
+				inheritFrom = projectAncestorIDs(ctx, project)
+			}
+		}
+		inheritFrom = withGlobalKnowledge(ctx, active.ProjectID, inheritFrom)
+		sessionCtx := buildSessionContext(ctx, active.SessionID, active.ProjectID, active.Objective, active.AIID, active.StartedAt, inheritFrom, currentGoal(ctx, active), active.contextAgents())
+		recordEpistemicSnapshot(ctx, active.SessionID, models.PhaseCheck, sessionCtx.Vectors)
+		maybeAutoCheckpoint(ctx, active, 0)
 
 		// Calculate counts from context
 		counts := &models.BreadcrumbCounts{
-			DeadEnds:     len(ctx.DeadEnds),
-			UnknownsOpen: len(ctx.OpenQuestions),
+			DeadEnds:     len(sessionCtx.DeadEnds),
+			UnknownsOpen: len(sessionCtx.OpenQuestions),
 		}
 
 		// Count findings by status
-		for _, k := range ctx.Knowledge {
+		for _, k := range sessionCtx.Knowledge {
 			counts.Findings++
 			if k.Status == "fresh" {
 				counts.FindingsFresh++
@@ -1025,7 +1986,7 @@ var statusCmd = &cobra.Command{
 				counts.FindingsAging++
 			}
 		}
-		counts.FindingsStale = len(ctx.RequiresVerification)
+		counts.FindingsStale = len(sessionCtx.RequiresVerification)
 		counts.Findings += counts.FindingsStale
 
 		if !outputText {
@@ -1033,7 +1994,7 @@ var statusCmd = &cobra.Command{
 				Status:   "active",
 				Duration: duration.Round(time.Second).String(),
 				Counts:   counts,
-				Context:  ctx,
+				Context:  sessionCtx,
 			}
 			outputResult(response)
 		} else {
@@ -1041,36 +2002,39 @@ var statusCmd = &cobra.Command{
 			fmt.Println(strings.Repeat("─", 50))
 
 			// Decision guidance
-			if ctx.Decision != nil {
+			if sessionCtx.Decision != nil {
 				fmt.Printf("\n%s %s (%.0f%% confidence)\n",
-					ctx.Decision.ConfidencePhase,
-					strings.ToUpper(ctx.Decision.Action),
-					ctx.Decision.Confidence*100)
-				fmt.Printf("  %s\n", ctx.Decision.Reason)
+					sessionCtx.Decision.ConfidencePhase,
+					paintAction(strings.ToUpper(sessionCtx.Decision.Action)),
+					sessionCtx.Decision.Confidence*100)
+				fmt.Printf("  %s\n", sessionCtx.Decision.Reason)
 
-				if len(ctx.Decision.Prerequisites) > 0 {
+				if len(sessionCtx.Decision.Prerequisites) > 0 {
 					fmt.Println("\n  Before proceeding:")
-					for _, p := range ctx.Decision.Prerequisites {
+					for _, p := range sessionCtx.Decision.Prerequisites {
 						fmt.Printf("    → %s\n", p)
 					}
 				}
 			}
 
 			// Vectors
-			if ctx.Vectors != nil {
+			if sessionCtx.Vectors != nil {
 				fmt.Println("\nVectors:")
-				fmt.Printf("  Know:        %s %.0f%%\n", formatVectorBar(ctx.Vectors.Know), ctx.Vectors.Know*100)
-				fmt.Printf("  Uncertainty: %s %.0f%%\n", formatVectorBar(ctx.Vectors.Uncertainty), ctx.Vectors.Uncertainty*100)
-				fmt.Printf("  Clarity:     %s %.0f%%\n", formatVectorBar(ctx.Vectors.Clarity), ctx.Vectors.Clarity*100)
-				fmt.Printf("  Coherence:   %s %.0f%%\n", formatVectorBar(ctx.Vectors.Coherence), ctx.Vectors.Coherence*100)
-				fmt.Printf("  Completion:  %s %.0f%%\n", formatVectorBar(ctx.Vectors.Completion), ctx.Vectors.Completion*100)
-				fmt.Printf("  Engagement:  %s %.0f%%\n", formatVectorBar(ctx.Vectors.Engagement), ctx.Vectors.Engagement*100)
+				fmt.Printf("  Know:        %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Know), sessionCtx.Vectors.Know*100)
+				fmt.Printf("  Uncertainty: %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Uncertainty), sessionCtx.Vectors.Uncertainty*100)
+				fmt.Printf("  Clarity:     %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Clarity), sessionCtx.Vectors.Clarity*100)
+				fmt.Printf("  Coherence:   %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Coherence), sessionCtx.Vectors.Coherence*100)
+				fmt.Printf("  Completion:  %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Completion), sessionCtx.Vectors.Completion*100)
+				fmt.Printf("  Engagement:  %s %.0f%%\n", formatVectorBar(sessionCtx.Vectors.Engagement), sessionCtx.Vectors.Engagement*100)
 			}
 
+			printAssignedQuestions(sessionCtx.AssignedQuestions)
+			printGoalContext(sessionCtx.Goal)
+
 			// Verification needed
-			if len(ctx.RequiresVerification) > 0 {
-				fmt.Printf("\n⚠ VERIFY BEFORE USING (%d):\n", len(ctx.RequiresVerification))
-				for _, v := range ctx.RequiresVerification {
+			if len(sessionCtx.RequiresVerification) > 0 {
+				fmt.Printf("\n%s VERIFY BEFORE USING (%d):\n", stalenessIcon(models.StatusStale), len(sessionCtx.RequiresVerification))
+				for _, v := range sessionCtx.RequiresVerification {
 					extra := ""
 					if v.FileChanged {
 						extra = " [file changed]"
@@ -1081,33 +2045,30 @@ var statusCmd = &cobra.Command{
 			}
 
 			// Dead ends
-			if len(ctx.DeadEnds) > 0 {
-				fmt.Printf("\n✗ DO NOT REPEAT (%d):\n", len(ctx.DeadEnds))
-				for _, d := range ctx.DeadEnds {
-					fmt.Printf("  • %s\n", d.Approach)
+			if len(sessionCtx.DeadEnds) > 0 {
+				fmt.Printf("\n%s DO NOT REPEAT (%d):\n", paint(ansiRed, "✗"), len(sessionCtx.DeadEnds))
+				for _, d := range sessionCtx.DeadEnds {
+					fmt.Printf("  • %s\n", paint(ansiRed, d.Approach))
 					fmt.Printf("    Why: %s\n", d.WhyFailed)
 				}
 			}
 
 			// Knowledge
-			if len(ctx.Knowledge) > 0 {
-				fmt.Printf("\n✓ KNOWN (%d):\n", len(ctx.Knowledge))
-				for _, k := range ctx.Knowledge {
-					status := "✓"
-					if k.Status == "aging" {
-						status = "○"
-					}
-					fmt.Printf("  %s %s\n", status, k.Finding)
+			if len(sessionCtx.Knowledge) > 0 {
+				fmt.Printf("\n✓ KNOWN (%d):\n", len(sessionCtx.Knowledge))
+				for _, k := range sessionCtx.Knowledge {
+					fmt.Printf("  %s %s\n", stalenessIcon(models.StalenessStatus(k.Status)), k.Finding)
 				}
 			}
 
 			// Open questions
-			if len(ctx.OpenQuestions) > 0 {
-				fmt.Printf("\n? OPEN QUESTIONS (%d):\n", len(ctx.OpenQuestions))
-				for _, q := range ctx.OpenQuestions {
+			if len(sessionCtx.OpenQuestions) > 0 {
+				fmt.Printf("\n? OPEN QUESTIONS (%d):\n", len(sessionCtx.OpenQuestions))
+				for _, q := range sessionCtx.OpenQuestions {
 					fmt.Printf("  • %s\n", q)
 				}
 			}
+			printAgingUnknowns(sessionCtx.AgingUnknowns)
 
 			// Summary counts
 			fmt.Printf("\nSession: %d findings, %d open questions, %d dead ends\n",
@@ -1117,119 +2078,235 @@ var statusCmd = &cobra.Command{
 	},
 }
 
+// printContextSections prints a session context's assigned questions, goal, findings
+// to verify, dead ends, knowledge and open questions as text
+func printContextSections(sessionCtx *models.SessionContext) {
+	printAssignedQuestions(sessionCtx.AssignedQuestions)
+	printGoalContext(sessionCtx.Goal)
+
+	// Verification needed
+	if len(sessionCtx.RequiresVerification) > 0 {
+		fmt.Printf("\n%s VERIFY BEFORE USING (%d):\n", stalenessIcon(models.StatusStale), len(sessionCtx.RequiresVerification))
+		for _, v := range sessionCtx.RequiresVerification {
+			extra := ""
+			if v.FileChanged {
+				extra = " [file changed]"
+			}
+			fmt.Printf("  • %s (%dd old%s)\n", v.Finding, v.DaysStale, extra)
+			fmt.Printf("    %s\n", v.VerifyCommand)
+		}
+	}
+
+	// Dead ends
+	if len(sessionCtx.DeadEnds) > 0 {
+		fmt.Printf("\n%s DO NOT REPEAT (%d):\n", paint(ansiRed, "✗"), len(sessionCtx.DeadEnds))
+		for _, d := range sessionCtx.DeadEnds {
+			fmt.Printf("  • %s\n", paint(ansiRed, d.Approach))
+			fmt.Printf("    Why: %s\n", d.WhyFailed)
+		}
+	}
+
+	// Knowledge
+	if len(sessionCtx.Knowledge) > 0 {
+		fmt.Printf("\n✓ KNOWN (%d):\n", len(sessionCtx.Knowledge))
+		for _, k := range sessionCtx.Knowledge {
+			fmt.Printf("  %s %s\n", stalenessIcon(models.StalenessStatus(k.Status)), k.Finding)
+		}
+	}
+
+	// Open questions
+	if len(sessionCtx.OpenQuestions) > 0 {
+		fmt.Printf("\n? OPEN QUESTIONS (%d):\n", len(sessionCtx.OpenQuestions))
+		for _, q := range sessionCtx.OpenQuestions {
+			fmt.Printf("  • %s\n", q)
+		}
+	}
+	printAgingUnknowns(sessionCtx.AgingUnknowns)
+}
+
 // verifyCmd verifies/refreshes a stale finding
 var verifyCmd = &cobra.Command{
 	Use:   "verify [search-text]",
 	Short: "Verify a stale finding",
 	Long: `Verify a finding to refresh its confidence timestamp.
 
-Use this when you've confirmed a finding is still accurate.
+Use this when you've confirmed a finding is still accurate. With --update the text is
+rewritten; the earlier text is kept and 'memory history <id>' lists it. The new text
+is held to the finding length limit as learned's is; --allow-long keeps it whole.
+A check run with --run is killed and counts as failed after 5 minutes, or --timeout.
 
 Examples:
   memory verify "JWT"                    # Find and verify findings containing "JWT"
   memory verify --id abc123              # Verify by ID
-  memory verify "old text" --update "new text"  # Update the finding text`,
+  memory verify --run abc123             # Run the finding's --check command
+  memory verify --run abc123 --timeout 30s      # Give up on a hung check sooner
+  memory verify "JWT" --pick 2           # Verify the second of several matches
+  memory verify "old text" --update "new text"  # Update the finding text
+  memory verify --id abc123 --attach trace.log  # Keep a file as evidence
+  memory verify --id abc123 --show-evidence     # Print the recorded evidence
+
+Check output longer than 4000 characters and attached files are stored whole under
+.memory/blobs/, named by their SHA-256; the database only keeps the hash.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
 		findingID, _ := cmd.Flags().GetString("id")
 		updateText, _ := cmd.Flags().GetString("update")
+		runID, _ := cmd.Flags().GetString("run")
+		pick, _ := cmd.Flags().GetInt("pick")
+		attach, _ := cmd.Flags().GetString("attach")
+		showEvidence, _ := cmd.Flags().GetBool("show-evidence")
+		checkTimeout, _ := cmd.Flags().GetDuration("timeout")
+		if runID != "" {
+			findingID = runID
+		}
 
 		// Get active session for project context
-		active, err := loadActiveSession()
+		active, err := loadActiveSession(ctx)
 		projectID := ""
 		if err == nil && active != nil {
 			projectID = active.ProjectID
 		}
 
-		repo := db.NewBreadcrumbRepository(database)
+		repo := stores.Breadcrumbs
+
+		// Re-running on one of several matches keeps the other flags
+		command := func(shortID string) string {
+			c := "memory verify --id " + shortID
+			if runID != "" {
+				c = "memory verify --run " + shortID
+			}
+			if updateText != "" {
+				c += fmt.Sprintf(" --update %q", updateText)
+			}
+			return c
+		}
 
 		// Find the finding either by ID or text search
 		var targetFinding *models.Finding
 
 		if findingID != "" {
-			// Look up by ID
-			targetFinding, err = repo.GetFinding(findingID)
+			// Look up by ID or short ID
+			targetFinding, err = lookupFinding(ctx, findingID, pick, command)
 			if err != nil {
 				return fmt.Errorf("failed to get finding: %w", err)
 			}
-			if targetFinding == nil {
-				return fmt.Errorf("finding not found: %s", findingID)
-			}
 		} else if len(args) > 0 {
 			// Search by text
 			searchText := args[0]
-			findings, err := repo.FindFindingByText(projectID, searchText)
+			findings, err := repo.FindFindingByText(ctx, projectID, searchText)
 			if err != nil {
 				return fmt.Errorf("failed to search findings: %w", err)
 			}
 			if len(findings) == 0 {
 				return fmt.Errorf("no findings found matching: %s", searchText)
 			}
+			targetFinding = findings[0]
 			if len(findings) > 1 {
-				// Show matches and ask user to be more specific
+				if targetFinding, err = pickFinding(ctx, findings, pick, command); err != nil {
+					return err
+				}
+			}
+		} else {
+			return fmt.Errorf("provide search text or --id flag")
+		}
+		if targetFinding == nil {
+			// Several matched and none was picked; the matches were printed
+			return nil
+		}
+		if showEvidence {
+			return printEvidence(targetFinding)
+		}
+
+		// An attached file is kept whole as a blob, with its hash as the evidence
+		attachedBlob := ""
+		if attach != "" {
+			data, err := os.ReadFile(attach)
+			if err != nil {
+				return fmt.Errorf("failed to read attachment: %w", err)
+			}
+			evidence, hash, err := storeEvidence(data, true)
+			if err != nil {
+				return err
+			}
+			if err := repo.RecordVerificationEvidence(ctx, targetFinding.ID, evidence); err != nil {
+				return fmt.Errorf("failed to record evidence: %w", err)
+			}
+			targetFinding.Version++
+			attachedBlob = hash
+		}
+
+		// Run the attached check and only verify if it passes
+		var checkResult *CheckResult
+		if runID != "" {
+			if targetFinding.VerifyCheck == nil || *targetFinding.VerifyCheck == "" {
+				return fmt.Errorf("finding has no verification check: %s", targetFinding.ID)
+			}
+			checkResult = runVerificationCheck(ctx, *targetFinding.VerifyCheck, checkTimeout)
+			evidence, hash, err := storeEvidence(checkResult.full, false)
+			if err != nil {
+				return err
+			}
+			checkResult.Blob = hash
+			if err := repo.RecordVerificationEvidence(ctx, targetFinding.ID, evidence); err != nil {
+				return fmt.Errorf("failed to record evidence: %w", err)
+			}
+			targetFinding.Version++ // Recording evidence is itself a change
+			if !checkResult.Passed {
 				if !outputText {
-					result := map[string]interface{}{
-						"status":  "multiple_matches",
-						"message": "Multiple findings match. Use --id to specify.",
-						"matches": make([]map[string]interface{}, 0),
-					}
-					for _, f := range findings {
-						fileChanged := false
-						if f.Subject != nil && f.SubjectGitHash != nil {
-							fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-						}
-						result["matches"] = append(result["matches"].([]map[string]interface{}), map[string]interface{}{
-							"id":           f.ID,
-							"finding":      f.Finding,
-							"status":       string(f.GetStalenessStatus(fileChanged)),
-							"days_old":     int(f.DaysSinceVerified()),
-							"file_changed": fileChanged,
-						})
-					}
-					outputResult(result)
+					outputResult(map[string]interface{}{
+						"status":  "check_failed",
+						"id":      targetFinding.ID,
+						"finding": targetFinding.Finding,
+						"check":   checkResult,
+					})
 				} else {
-					fmt.Println("Multiple matches found. Use --id to specify:")
-					for _, f := range findings {
-						fileChanged := false
-						if f.Subject != nil && f.SubjectGitHash != nil {
-							fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-						}
-						status := f.GetStalenessStatus(fileChanged)
-						statusIcon := "✓"
-						if status == models.StatusAging {
-							statusIcon = "○"
-						} else if status == models.StatusStale {
-							statusIcon = "⚠"
-						}
-						fmt.Printf("  %s %s (id: %s)\n", statusIcon, f.Finding, f.ID[:8])
+					fmt.Printf("✗ Check failed: %s\n", targetFinding.Finding)
+					if checkResult.TimedOut {
+						fmt.Printf("  $ %s (timed out after %s)\n", checkResult.Command, checkTimeout)
+					} else {
+						fmt.Printf("  $ %s (exit %d)\n", checkResult.Command, checkResult.ExitCode)
 					}
+					if checkResult.Output != "" {
+						fmt.Println(checkResult.Output)
+					}
+				}
+				if checkResult.TimedOut {
+					return fmt.Errorf("verification check timed out after %s", checkTimeout)
 				}
-				return nil
+				return fmt.Errorf("verification check failed with exit code %d", checkResult.ExitCode)
 			}
-			targetFinding = findings[0]
-		} else {
-			return fmt.Errorf("provide search text or --id flag")
 		}
 
 		// Calculate new git hash if finding has a subject file
 		var newGitHash *string
 		if targetFinding.Subject != nil {
-			hash := getFileGitHash(*targetFinding.Subject)
+			hash := getScopeHash(ctx, targetFinding.ScopeKind(), *targetFinding.Subject)
 			if hash != "" {
 				newGitHash = &hash
 			}
 		}
 
-		// Update text if provided
+		// Update text if provided, cut to the length limit as learned cuts it
 		var newText *string
+		truncated := false
 		if updateText != "" {
+			limits, err := loadTextLimits(cmd)
+			if err != nil {
+				return err
+			}
+			updateText, _, truncated = limits.apply(models.EntityFinding, updateText, "")
 			newText = &updateText
 		}
 
-		// Verify the finding
-		if err := repo.VerifyFinding(targetFinding.ID, newGitHash, newText); err != nil {
+		// Verify the finding, failing if another agent changed it since it was read
+		if err := repo.VerifyFinding(ctx, targetFinding.ID, targetFinding.Version, newGitHash, newText); err != nil {
+			if errors.Is(err, db.ErrVersionConflict) {
+				return fmt.Errorf("finding changed while verifying, re-run verify to see the latest version: %w", err)
+			}
 			return fmt.Errorf("failed to verify finding: %w", err)
 		}
+		verifications.Inc()
 
 		displayText := targetFinding.Finding
 		if newText != nil {
@@ -1237,18 +2314,37 @@ Examples:
 		}
 
 		if !outputText {
-			outputResult(map[string]interface{}{
+			result := map[string]interface{}{
 				"status":   "verified",
 				"id":       targetFinding.ID,
 				"finding":  displayText,
 				"updated":  newText != nil,
 				"git_hash": newGitHash,
-			})
+			}
+			if truncated {
+				result["truncated"] = true
+			}
+			if checkResult != nil {
+				result["check"] = checkResult
+			}
+			if attachedBlob != "" {
+				result["evidence_blob"] = attachedBlob
+			}
+			outputResult(result)
 		} else {
 			fmt.Printf("✓ Verified: %s\n", displayText)
 			if newText != nil {
 				fmt.Printf("  (updated from: %s)\n", targetFinding.Finding)
 			}
+			if truncated {
+				fmt.Println(truncationNote)
+			}
+			if checkResult != nil {
+				fmt.Printf("  (check passed in %s: %s)\n", checkResult.Duration, checkResult.Command)
+			}
+			if attachedBlob != "" {
+				fmt.Printf("  (evidence attached: %s, blob %s)\n", attach, attachedBlob[:12])
+			}
 		}
 
 		return nil
@@ -1273,15 +2369,24 @@ Examples:
   memory query "authn jwt" -f     # Fuzzy search across all types
   memory query --unknowns         # Show open questions
   memory query --dead-ends        # Show failed approaches
-  memory query --all              # Show everything`,
+  memory query --all              # Show everything
+  memory query --ai-id cursor     # Only what Cursor sessions logged
+  memory query --global           # Knowledge recorded with learned --global`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		defer queryDuration.ObserveSince(time.Now())
+		ctx := cmd.Context()
 		showUnknowns, _ := cmd.Flags().GetBool("unknowns")
 		showDeadEnds, _ := cmd.Flags().GetBool("dead-ends")
 		showAll, _ := cmd.Flags().GetBool("all")
 		fuzzySearch, _ := cmd.Flags().GetBool("fuzzy")
 		limit, _ := cmd.Flags().GetInt("limit")
 		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		cursorToken, _ := cmd.Flags().GetString("cursor")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+		aiIDs, _ := cmd.Flags().GetStringSlice("ai-id")
+		preferSameFamily, _ := cmd.Flags().GetBool("prefer-same-family")
+		global, _ := cmd.Flags().GetBool("global")
 
 		searchText := ""
 		if len(args) > 0 {
@@ -1289,12 +2394,56 @@ Examples:
 		}
 
 		// Get project (but don't require active session)
-		project, err := getOrCreateDefaultProject()
+		getProject := getOrCreateDefaultProject
+		if global {
+			getProject = getOrCreateGlobalProject
+		}
+		project, err := getProject(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get project: %w", err)
 		}
 
-		bcRepo := db.NewBreadcrumbRepository(database)
+		// Paging walks one list, newest first; searches rank matches instead
+		page := db.Page{Size: limit}
+		paged := cursorToken != "" || pageSize > 0
+		if paged {
+			if searchText != "" || fuzzySearch {
+				return fmt.Errorf("%w: --cursor and --page-size can't be combined with a search", db.ErrInvalid)
+			}
+			if showAll || showUnknowns && showDeadEnds {
+				return fmt.Errorf("%w: --cursor and --page-size page one list; pick findings, --unknowns or --dead-ends", db.ErrInvalid)
+			}
+			if pageSize > 0 {
+				page.Size = pageSize
+			}
+			if cursorToken != "" {
+				if page.After, err = db.ParseCursor(cursorToken); err != nil {
+					return err
+				}
+			}
+		}
+		var next *db.Cursor
+
+		// --ai-id is applied by the list queries, so pages stay keyset pages; ranking a
+		// family first needs more than a page to rank
+		prefer := ""
+		if preferSameFamily {
+			prefer = currentActor(ctx)
+		}
+		agents := newAgentFilter(aiIDs, prefer)
+		if agents != nil {
+			if paged && agents.preferFamily != "" {
+				return fmt.Errorf("%w: --cursor and --page-size can't be combined with --prefer-same-family", db.ErrInvalid)
+			}
+			if paged {
+				limit = page.Size
+			}
+			after := page.After
+			page = agents.page(page.Size)
+			page.After = after
+		}
+
+		bcRepo := stores.Breadcrumbs
 
 		// Determine what to show
 		showFindings := !showUnknowns && !showDeadEnds || showAll
@@ -1303,7 +2452,7 @@ Examples:
 
 		// If fuzzy search is enabled, search across all types and return unified results
 		if fuzzySearch && searchText != "" {
-			return runFuzzyQuery(bcRepo, project.ID, searchText, showFindings, showUnknownsFlag, showDeadEndsFlag, limit, threshold)
+			return runFuzzyQuery(ctx, bcRepo, project.ID, searchText, showFindings, showUnknownsFlag, showDeadEndsFlag, limit, threshold, agents)
 		}
 
 		// For JSON output, build structured response
@@ -1315,17 +2464,19 @@ Examples:
 			if showFindings {
 				var findings []*models.Finding
 				if searchText != "" {
-					findings, _ = bcRepo.FindFindingByText(project.ID, searchText)
+					findings, _ = bcRepo.FindFindingByText(ctx, project.ID, searchText)
 				} else {
-					findings, _ = bcRepo.ListFindingsWithStaleness(project.ID, "", limit)
+					if findings, next, err = bcRepo.ListFindingsPage(ctx, project.ID, "", page); err != nil {
+						return err
+					}
 				}
+				findings, _ = agentScoped(ctx, agents, findings, limit, func(f *models.Finding) string { return f.SessionID })
+				primeFindingHashes(ctx, findings)
+				commitsByFinding := findingCommits(ctx, findings)
 
 				findingsList := make([]map[string]interface{}, 0)
 				for _, f := range findings {
-					fileChanged := false
-					if f.Subject != nil && f.SubjectGitHash != nil {
-						fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-					}
+					fileChanged := findingFileChanged(ctx, f)
 					item := map[string]interface{}{
 						"id":         f.ID,
 						"finding":    f.Finding,
@@ -1337,6 +2488,18 @@ Examples:
 						item["scope"] = *f.Subject
 						item["file_changed"] = fileChanged
 					}
+					if links := commitsByFinding[f.ID]; len(links) > 0 {
+						item["commits"] = links
+					}
+					if f.GitBranch != nil {
+						item["branch"] = *f.GitBranch
+					}
+					if f.Worktree != nil {
+						item["worktree"] = *f.Worktree
+					}
+					if f.Snippet != nil {
+						item["snippet"] = f.Snippet
+					}
 					findingsList = append(findingsList, item)
 				}
 				result["findings"] = findingsList
@@ -1345,7 +2508,12 @@ Examples:
 
 			if showUnknownsFlag {
 				resolved := false
-				unknowns, _ := bcRepo.ListUnknowns(project.ID, "", &resolved, limit)
+				unknowns, nextUnknown, err := bcRepo.ListUnknownsPage(ctx, project.ID, "", &resolved, page)
+				if err != nil {
+					return err
+				}
+				next = nextUnknown
+				unknowns, _ = agentScoped(ctx, agents, unknowns, limit, func(u *models.Unknown) string { return u.SessionID })
 				unknownsList := make([]map[string]interface{}, 0)
 				for _, u := range unknowns {
 					item := map[string]interface{}{
@@ -1362,7 +2530,12 @@ Examples:
 			}
 
 			if showDeadEndsFlag {
-				deadEnds, _ := bcRepo.ListDeadEnds(project.ID, "", limit)
+				deadEnds, nextDeadEnd, err := bcRepo.ListDeadEndsPage(ctx, project.ID, "", page)
+				if err != nil {
+					return err
+				}
+				next = nextDeadEnd
+				deadEnds, _ = agentScoped(ctx, agents, deadEnds, limit, func(d *models.DeadEnd) string { return d.SessionID })
 				deadEndsList := make([]map[string]interface{}, 0)
 				for _, d := range deadEnds {
 					item := map[string]interface{}{
@@ -1379,6 +2552,9 @@ Examples:
 				result["dead_ends_count"] = len(deadEndsList)
 			}
 
+			if paged && next != nil {
+				result["next_cursor"] = next.String()
+			}
 			outputResult(result)
 			return nil
 		}
@@ -1390,31 +2566,32 @@ Examples:
 		if showFindings {
 			var findings []*models.Finding
 			if searchText != "" {
-				findings, _ = bcRepo.FindFindingByText(project.ID, searchText)
+				findings, _ = bcRepo.FindFindingByText(ctx, project.ID, searchText)
+				findings, _ = agentScoped(ctx, agents, findings, limit, func(f *models.Finding) string { return f.SessionID })
 				fmt.Printf("\n✓ FINDINGS matching \"%s\" (%d):\n", searchText, len(findings))
 			} else {
-				findings, _ = bcRepo.ListFindingsWithStaleness(project.ID, "", limit)
+				if findings, next, err = bcRepo.ListFindingsPage(ctx, project.ID, "", page); err != nil {
+					return err
+				}
+				findings, _ = agentScoped(ctx, agents, findings, limit, func(f *models.Finding) string { return f.SessionID })
 				fmt.Printf("\n✓ FINDINGS (%d):\n", len(findings))
 			}
+			primeFindingHashes(ctx, findings)
+			commitsByFinding := findingCommits(ctx, findings)
 
 			if len(findings) == 0 {
 				fmt.Println("  (none)")
 			} else {
 				for _, f := range findings {
-					fileChanged := false
-					if f.Subject != nil && f.SubjectGitHash != nil {
-						fileChanged = checkFileChanged(*f.Subject, *f.SubjectGitHash)
-					}
+					fileChanged := findingFileChanged(ctx, f)
 					status := f.GetStalenessStatus(fileChanged)
 					days := int(f.DaysSinceVerified())
 
-					statusIcon := "✓"
+					statusIcon := stalenessIcon(status)
 					extra := ""
 					if status == models.StatusAging {
-						statusIcon = "○"
 						extra = fmt.Sprintf(" [%dd]", days)
 					} else if status == models.StatusStale {
-						statusIcon = "⚠"
 						extra = fmt.Sprintf(" [stale: %dd]", days)
 						if fileChanged {
 							extra += " [file changed]"
@@ -1425,13 +2602,25 @@ Examples:
 					if f.Subject != nil {
 						fmt.Printf("    scope: %s\n", *f.Subject)
 					}
+					if links := commitsByFinding[f.ID]; len(links) > 0 {
+						commits := make([]string, 0, len(links))
+						for _, l := range links {
+							commits = append(commits, fmt.Sprintf("%s (%s)", shortSHA(l.CommitSHA), l.Relation))
+						}
+						fmt.Printf("    commits: %s\n", strings.Join(commits, ", "))
+					}
 				}
 			}
 		}
 
 		if showUnknownsFlag {
 			resolved := false
-			unknowns, _ := bcRepo.ListUnknowns(project.ID, "", &resolved, limit)
+			unknowns, nextUnknown, err := bcRepo.ListUnknownsPage(ctx, project.ID, "", &resolved, page)
+			if err != nil {
+				return err
+			}
+			next = nextUnknown
+			unknowns, _ = agentScoped(ctx, agents, unknowns, limit, func(u *models.Unknown) string { return u.SessionID })
 			fmt.Printf("\n? OPEN QUESTIONS (%d):\n", len(unknowns))
 
 			if len(unknowns) == 0 {
@@ -1447,14 +2636,19 @@ Examples:
 		}
 
 		if showDeadEndsFlag {
-			deadEnds, _ := bcRepo.ListDeadEnds(project.ID, "", limit)
-			fmt.Printf("\n✗ DEAD ENDS (%d):\n", len(deadEnds))
+			deadEnds, nextDeadEnd, err := bcRepo.ListDeadEndsPage(ctx, project.ID, "", page)
+			if err != nil {
+				return err
+			}
+			next = nextDeadEnd
+			deadEnds, _ = agentScoped(ctx, agents, deadEnds, limit, func(d *models.DeadEnd) string { return d.SessionID })
+			fmt.Printf("\n%s DEAD ENDS (%d):\n", paint(ansiRed, "✗"), len(deadEnds))
 
 			if len(deadEnds) == 0 {
 				fmt.Println("  (none)")
 			} else {
 				for _, d := range deadEnds {
-					fmt.Printf("  • %s\n", d.Approach)
+					fmt.Printf("  • %s\n", paint(ansiRed, d.Approach))
 					fmt.Printf("    Why: %s\n", d.WhyFailed)
 					if d.Subject != nil {
 						fmt.Printf("    scope: %s\n", *d.Subject)
@@ -1463,18 +2657,40 @@ Examples:
 			}
 		}
 
+		if paged && next != nil {
+			fmt.Printf("\nMore: memory query%s --cursor %s\n", pageFlags(showUnknowns, showDeadEnds, pageSize, aiIDs), next.String())
+		}
 		return nil
 	},
 }
 
+// pageFlags repeats the query flags that select the list being paged
+func pageFlags(unknowns, deadEnds bool, pageSize int, aiIDs []string) string {
+	flags := ""
+	for _, id := range aiIDs {
+		flags += " --ai-id " + id
+	}
+	if unknowns {
+		flags += " --unknowns"
+	}
+	if deadEnds {
+		flags += " --dead-ends"
+	}
+	if pageSize > 0 {
+		flags += fmt.Sprintf(" --page-size %d", pageSize)
+	}
+	return flags
+}
+
 // runFuzzyQuery performs fuzzy search across all breadcrumb types
-func runFuzzyQuery(bcRepo *db.BreadcrumbRepository, projectID, query string, showFindings, showUnknowns, showDeadEnds bool, limit int, threshold float64) error {
+func runFuzzyQuery(ctx context.Context, bcRepo db.BreadcrumbStore, projectID, query string, showFindings, showUnknowns, showDeadEnds bool, limit int, threshold float64, agents *agentFilter) error {
 	// Collect all items into search items
 	var items []search.SearchItem
 
 	// Load findings
 	if showFindings {
-		findings, _ := bcRepo.ListFindingsWithStaleness(projectID, "", 500)
+		findings, _ := bcRepo.ListFindingsWithStaleness(ctx, projectID, "", 500)
+		findings, _ = agentScoped(ctx, agents, findings, len(findings), func(f *models.Finding) string { return f.SessionID })
 		for _, f := range findings {
 			scope := ""
 			if f.Subject != nil {
@@ -1492,7 +2708,8 @@ func runFuzzyQuery(bcRepo *db.BreadcrumbRepository, projectID, query string, sho
 	// Load unknowns
 	if showUnknowns {
 		resolved := false
-		unknowns, _ := bcRepo.ListUnknowns(projectID, "", &resolved, 500)
+		unknowns, _ := bcRepo.ListUnknowns(ctx, projectID, "", &resolved, 500)
+		unknowns, _ = agentScoped(ctx, agents, unknowns, len(unknowns), func(u *models.Unknown) string { return u.SessionID })
 		for _, u := range unknowns {
 			scope := ""
 			if u.Subject != nil {
@@ -1509,7 +2726,8 @@ func runFuzzyQuery(bcRepo *db.BreadcrumbRepository, projectID, query string, sho
 
 	// Load dead ends
 	if showDeadEnds {
-		deadEnds, _ := bcRepo.ListDeadEnds(projectID, "", 500)
+		deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, "", 500)
+		deadEnds, _ = agentScoped(ctx, agents, deadEnds, len(deadEnds), func(d *models.DeadEnd) string { return d.SessionID })
 		for _, d := range deadEnds {
 			scope := ""
 			if d.Subject != nil {
@@ -1603,41 +2821,45 @@ func runFuzzyQuery(bcRepo *db.BreadcrumbRepository, projectID, query string, sho
 	return nil
 }
 
-// getFileGitHash returns the git blob hash for a file
-// Returns empty string if not in a git repo or file doesn't exist
-func getFileGitHash(filePath string) string {
-	// Try to get git hash for the file
-	cmd := exec.Command("git", "hash-object", filePath)
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
-
-// checkFileChanged compares a stored git hash with the current file's hash
-func checkFileChanged(filePath string, storedHash string) bool {
-	if storedHash == "" || filePath == "" {
-		return false // Can't determine change without both values
-	}
-	currentHash := getFileGitHash(filePath)
-	if currentHash == "" {
-		return false // File not in git, can't determine
-	}
-	return currentHash != storedHash
-}
-
 func init() {
 	// start command flags
-	startCmd.Flags().String("ai-id", "claude-code", "AI identifier")
+	startCmd.Flags().String("ai-id", "", "AI identifier (detected from the environment when omitted, else claude-code)")
+	startCmd.Flags().Bool("inherit", false, "Include parent-project knowledge when in a sub-project")
+	startCmd.Flags().Int("from-issue", 0, "GitHub issue number to take the objective and tasks from")
+	startCmd.Flags().String("repo", "", "GitHub repository (owner/name) for --from-issue, defaults to origin")
+	startCmd.Flags().String("goal", "", "Goal ID to continue; scopes the context to its breadcrumbs")
+	startCmd.Flags().StringSlice("from-ai-id", nil, "Only include context logged by these AI IDs or agent families, e.g. claude (repeatable)")
+	startCmd.Flags().Bool("prefer-same-family", false, "Rank context logged by this session's agent family first")
 
 	// Scope flags for logging commands
-	learnedCmd.Flags().String("scope", "", "File/directory scope for the finding")
+	doneCmd.Flags().Bool("dry-run", false, "Print the handoff that would be written without ending the session")
+	doneCmd.Flags().StringArray("next", nil, "Recommendation for the next session (repeatable)")
+	doneCmd.Flags().Bool("summarize", false, "Draft the summary and recommendations with the configured LLM")
+	learnedCmd.Flags().String("scope", "", "What the finding is about: a file, directory, URL, command or external API")
+	learnedCmd.Flags().String("scope-kind", "", "Kind of --scope: "+scopeKindNames()+" (inferred when omitted)")
 	uncertainCmd.Flags().String("scope", "", "File/directory scope for the unknown")
+	uncertainCmd.Flags().Bool("next-session", false, "Assign the question to the next session")
+	uncertainCmd.Flags().String("for-objective", "", "Assign the question to sessions whose objective matches")
+	learnedCmd.Flags().String("check", "", "Shell command whose exit status verifies the finding")
+	learnedCmd.Flags().Bool("link-head", false, "Link the finding to the current HEAD commit")
+	learnedCmd.Flags().String("snippet", "", "Capture the code the finding is about: path:start-end (scopes the finding to the file)")
+	learnedCmd.Flags().Bool("global", false, "Record the finding once for every project's start context, e.g. org-wide rules")
+	for _, c := range []*cobra.Command{learnedCmd, uncertainCmd, triedCmd} {
+		c.Flags().Float64("impact", defaultImpact, "How much this matters, from trivial (0.1) to critical (1.0)")
+		c.Flags().String("session", "", "Log to this session instead of the active one, e.g. to backfill")
+		c.Flags().String("file", "", "Read the text (for tried, why it failed) from this file; \"-\" as an argument reads stdin")
+		c.Flags().Bool("allow-long", false, "Keep text longer than the length limit whole")
+	}
 
 	// verify command flags
 	verifyCmd.Flags().String("id", "", "Finding ID to verify")
 	verifyCmd.Flags().String("update", "", "New text to update the finding with")
+	verifyCmd.Flags().String("run", "", "Finding ID whose verification check should be executed")
+	verifyCmd.Flags().Int("pick", 0, "Which of several matching findings to verify (1-based)")
+	verifyCmd.Flags().String("attach", "", "File to keep as the verification's evidence (log, screenshot, trace)")
+	verifyCmd.Flags().Bool("show-evidence", false, "Print the finding's recorded evidence instead of verifying it")
+	verifyCmd.Flags().Duration("timeout", verifyCheckTimeout, "How long --run lets the check run before killing it and failing")
+	verifyCmd.Flags().Bool("allow-long", false, "Keep --update text longer than the length limit whole")
 
 	// query command flags
 	queryCmd.Flags().BoolP("unknowns", "u", false, "Show open questions/unknowns")
@@ -1646,6 +2868,11 @@ func init() {
 	queryCmd.Flags().BoolP("fuzzy", "f", false, "Enable fuzzy search across all types")
 	queryCmd.Flags().Float64P("threshold", "t", 0.3, "Minimum score threshold for fuzzy matches (0.0-1.0)")
 	queryCmd.Flags().IntP("limit", "n", 50, "Maximum number of results")
+	queryCmd.Flags().String("cursor", "", "Resume a listing after the next_cursor of the previous page")
+	queryCmd.Flags().Int("page-size", 0, "Results per page; prints next_cursor when more follow (default --limit)")
+	queryCmd.Flags().StringSlice("ai-id", nil, "Only show breadcrumbs logged by these AI IDs or agent families, e.g. claude (repeatable)")
+	queryCmd.Flags().Bool("prefer-same-family", false, "List breadcrumbs logged by your own agent family first")
+	queryCmd.Flags().Bool("global", false, "Query the global knowledge shared by every project")
 
 	// Register core commands
 	rootCmd.AddCommand(
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// buildFileRecall turns the knowledge collected for a file into a compact context block
+func buildFileRecall(path string, entries []BlameEntry, limit int) *models.FileRecall {
+	recall := &models.FileRecall{Path: path}
+	for _, e := range entries {
+		switch e.Type {
+		case "finding":
+			if e.Status == string(models.StatusStale) {
+				if len(recall.RequiresVerification) < limit {
+					recall.RequiresVerification = append(recall.RequiresVerification, models.VerificationNeeded{
+						Finding:       e.Text,
+						ID:            e.ID,
+						DaysStale:     e.DaysOld,
+						Confidence:    e.Freshness,
+						FileChanged:   e.FileChanged,
+						Scope:         e.Scope,
+						VerifyCommand: suggestVerifyCommand(e.ID, e.Text),
+					})
+				}
+			} else if len(recall.Knowledge) < limit {
+				recall.Knowledge = append(recall.Knowledge, models.KnowledgeItem{
+					Finding:    e.Text,
+					Confidence: e.Freshness,
+					Status:     e.Status,
+					Scope:      e.Scope,
+				})
+			}
+		case "dead_end":
+			if len(recall.DeadEnds) < limit {
+				recall.DeadEnds = append(recall.DeadEnds, models.DeadEndWarning{
+					Approach:  e.Text,
+					WhyFailed: e.SecondaryText,
+					Scope:     e.Scope,
+				})
+			}
+		case "unknown":
+			if len(recall.OpenQuestions) < limit {
+				recall.OpenQuestions = append(recall.OpenQuestions, e.Text)
+			}
+		}
+	}
+	return recall
+}
+
+// recallCmd returns compact per-file context for editor and agent hooks
+var recallCmd = &cobra.Command{
+	Use:   "recall [path...]",
+	Short: "Recall what is known about files before editing them",
+	Long: `Return a compact context block for specific files: fresh findings, stale
+findings to verify, dead ends and open questions.
+
+Intended to be called by editor or agent hooks right before a file is modified.
+Files with nothing recorded are omitted. With --hook the file is read from the Claude
+Code hook payload on stdin (tool_input.file_path) instead of the arguments.
+
+Examples:
+  memory recall internal/auth/jwt.go
+  memory recall src/api.ts src/db.ts --text
+  memory recall --hook --text   # From a PreToolUse hook`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		limit, _ := cmd.Flags().GetInt("limit")
+		hook, _ := cmd.Flags().GetBool("hook")
+		if hook {
+			var payload struct {
+				ToolInput struct {
+					FilePath     string `json:"file_path"`
+					NotebookPath string `json:"notebook_path"`
+				} `json:"tool_input"`
+			}
+			if err := readStdinJSON(&payload); err != nil {
+				return fmt.Errorf("%w: %v", db.ErrInvalid, err)
+			}
+			args = nil
+			for _, path := range []string{payload.ToolInput.FilePath, payload.ToolInput.NotebookPath} {
+				if path != "" {
+					args = append(args, path)
+				}
+			}
+			// Tools that touch no file have nothing to recall
+			if len(args) == 0 {
+				return nil
+			}
+		} else if len(args) == 0 {
+			return fmt.Errorf("%w: give at least one path, or --hook", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		files := make([]*models.FileRecall, 0, len(args))
+		for _, path := range args {
+			entries, err := collectFileKnowledge(ctx, project.ID, path, false)
+			if err != nil {
+				return fmt.Errorf("failed to recall %s: %w", path, err)
+			}
+			if len(entries) == 0 {
+				continue
+			}
+			files = append(files, buildFileRecall(path, entries, limit))
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"files": files,
+				"count": len(files),
+			})
+			return nil
+		}
+
+		if len(files) == 0 {
+			// Hooks add their output to the agent's context; stay quiet when there's nothing
+			if !hook {
+				fmt.Println("Nothing recorded for these files.")
+			}
+			return nil
+		}
+		for _, f := range files {
+			fmt.Printf("── %s ──\n", f.Path)
+			for _, v := range f.RequiresVerification {
+				fmt.Printf("  %s %s (%s)\n", stalenessIcon(models.StatusStale), v.Finding, v.VerifyCommand)
+			}
+			for _, d := range f.DeadEnds {
+				fmt.Printf("  %s %s — %s\n", paint(ansiRed, "✗"), d.Approach, d.WhyFailed)
+			}
+			for _, k := range f.Knowledge {
+				fmt.Printf("  %s %s\n", stalenessIcon(models.StalenessStatus(k.Status)), k.Finding)
+			}
+			for _, q := range f.OpenQuestions {
+				fmt.Printf("  ? %s\n", q)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	recallCmd.Flags().IntP("limit", "n", 5, "Maximum items per category per file")
+	recallCmd.Flags().Bool("hook", false, "Read the file from a Claude Code hook payload on stdin")
+	rootCmd.AddCommand(recallCmd)
+}
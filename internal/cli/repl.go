@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// inRepl is set while the REPL or daemon runs commands, which then reuse its open database
+var inRepl bool
+
+// splitCommandLine splits a REPL line into arguments the way a POSIX shell would for
+// plain words, single and double quotes, and backslash escapes
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("%w: unterminated quote or escape", db.ErrInvalid)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// resetFlags restores every flag to its default, since cobra keeps parsed values between
+// executions of the same command
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var defaults []string
+			if d := strings.Trim(f.DefValue, "[]"); d != "" {
+				defaults = strings.Split(d, ",")
+			}
+			sv.Replace(defaults)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// runReplLine runs one REPL line as a memory command
+func runReplLine(cmd *cobra.Command, line string, text bool) error {
+	args, err := splitCommandLine(line)
+	if err != nil || len(args) == 0 {
+		return err
+	}
+	if args[0] == "memory" {
+		args = args[1:]
+	}
+	return runInProcess(cmd.Context(), args, text)
+}
+
+// runInProcess runs a memory command against the database the REPL or daemon holds open
+func runInProcess(ctx context.Context, args []string, text bool) error {
+	if len(args) > 0 && (args[0] == "repl" || args[0] == "serve" || args[0] == "daemon" || args[0] == "backup") {
+		return fmt.Errorf("%w: %s can't run inside the REPL or daemon", db.ErrInvalid, args[0])
+	}
+
+	resetFlags(rootCmd)
+	outputText = text
+	rootCmd.SetArgs(args)
+	start := time.Now()
+	replQuiet := quietStdout != nil
+	ran, err := rootCmd.ExecuteContextC(ctx)
+	endCommandSpan(err)
+	if cancelTimeout != nil {
+		cancelTimeout()
+		cancelTimeout = nil
+	}
+	// A quiet REPL stays quiet; a quiet line only silences itself
+	if !replQuiet {
+		restoreStdout()
+	}
+	if err == nil {
+		slog.Info("command finished", "command", ran.CommandPath(), "duration", time.Since(start))
+	}
+	return err
+}
+
+// replCmd runs commands interactively against one open database
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Run commands interactively with the database kept open",
+	Long: `Read memory commands from stdin, one per line, and run them against a database
+opened once. This saves the process start and database open of each invocation in
+tight agent or human loops.
+
+Lines are written as on the command line without the leading "memory" (which is
+accepted too), with shell-style quoting. Output is JSON unless the REPL or the line
+is given --text. A failing command prints its error and the REPL carries on.
+"exit", "quit" or end of input leaves.
+
+Examples:
+  memory repl --text
+  printf 'learned "Tokens expire after 1h"\nstatus\n' | memory repl`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text := outputText
+		interactive := stdinIsTerminal()
+
+		inRepl = true
+		defer func() { inRepl = false }()
+
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for {
+			// The prompt goes to stderr so piped JSON output stays clean
+			if interactive {
+				fmt.Fprint(os.Stderr, "memory> ")
+			}
+			if !scanner.Scan() {
+				break
+			}
+			line := strings.TrimSpace(scanner.Text())
+			if line == "exit" || line == "quit" {
+				break
+			}
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := runReplLine(cmd, line, text); err != nil {
+				outputError(err)
+				slog.Info("command failed", "line", line, "err", err)
+			}
+		}
+		outputText = text
+		return scanner.Err()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replCmd)
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// reportMarkerPrefix tags rendered reports so published comments can be found and updated
+const reportMarkerPrefix = "<!-- memory-session:"
+
+// resolveReportSession picks the session to report on: an explicit ID, the active
+// session, or the most recent handed-off session of the current project
+func resolveReportSession(ctx context.Context, sessionID string) (string, error) {
+	if sessionID != "" {
+		return sessionID, nil
+	}
+	if active, err := loadActiveSession(ctx); err == nil && active != nil {
+		return active.SessionID, nil
+	}
+
+	project, err := getOrCreateDefaultProject(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
+	}
+	handoffs, err := stores.Handoffs.List(ctx, project.ID, "", 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to list handoffs: %w", err)
+	}
+	if len(handoffs) == 0 {
+		return "", fmt.Errorf("no active session or previous handoff to report on")
+	}
+	return handoffs[0].SessionID, nil
+}
+
+// collectSessionReport gathers a session's handoff and breadcrumbs into a report
+func collectSessionReport(ctx context.Context, sessionID string) (*models.SessionReport, error) {
+	session, err := stores.Sessions.Get(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	report := &models.SessionReport{
+		SessionID:         session.SessionID,
+		Active:            session.EndTime == nil,
+		Findings:          []string{},
+		ResolvedQuestions: []string{},
+		OpenQuestions:     []string{},
+		DeadEnds:          []models.DeadEndWarning{},
+	}
+	if session.Subject != nil {
+		report.Objective = *session.Subject
+	}
+	projectID := ""
+	if session.ProjectID != nil {
+		projectID = *session.ProjectID
+	}
+
+	handoff, err := stores.Handoffs.Get(ctx, sessionID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get handoff: %w", err)
+	}
+	if handoff != nil {
+		if handoff.TaskSummary != nil {
+			report.Summary = *handoff.TaskSummary
+		}
+		if handoff.ArtifactsCreated != nil {
+			json.Unmarshal([]byte(*handoff.ArtifactsCreated), &report.Artifacts)
+		}
+	}
+
+	report.Issues, _ = stores.IssueLinks.ListByTarget(ctx, models.IssueTargetSession, sessionID)
+
+	bcRepo := stores.Breadcrumbs
+	findings, _ := bcRepo.ListFindingsWithStaleness(ctx, projectID, sessionID, 100)
+	for _, f := range findings {
+		report.Findings = append(report.Findings, f.Finding)
+	}
+	resolved := true
+	resolvedUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &resolved, 100)
+	for _, u := range resolvedUnknowns {
+		report.ResolvedQuestions = append(report.ResolvedQuestions, u.Unknown)
+	}
+	unresolved := false
+	openUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &unresolved, 100)
+	for _, u := range openUnknowns {
+		report.OpenQuestions = append(report.OpenQuestions, u.Unknown)
+	}
+	deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, sessionID, 100)
+	for _, d := range deadEnds {
+		warning := models.DeadEndWarning{Approach: d.Approach, WhyFailed: d.WhyFailed}
+		if d.Subject != nil {
+			warning.Scope = *d.Subject
+		}
+		report.DeadEnds = append(report.DeadEnds, warning)
+	}
+
+	return report, nil
+}
+
+// reportMarker returns the hidden marker identifying a session's published report
+func reportMarker(sessionID string) string {
+	return reportMarkerPrefix + sessionID + " -->"
+}
+
+// renderHandoffMarkdown renders a session report as a Markdown handoff summary
+func renderHandoffMarkdown(report *models.SessionReport) string {
+	var b strings.Builder
+	b.WriteString(reportMarker(report.SessionID) + "\n")
+	b.WriteString("## Memory session summary\n\n")
+	fmt.Fprintf(&b, "**Objective:** %s\n\n", report.Objective)
+	if report.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", report.Summary)
+	} else if report.Active {
+		b.WriteString("_Session still in progress._\n\n")
+	}
+	if len(report.Issues) > 0 {
+		refs := make([]string, 0, len(report.Issues))
+		for _, issue := range report.Issues {
+			refs = append(refs, issue.Ref())
+		}
+		fmt.Fprintf(&b, "Related: %s\n\n", strings.Join(refs, ", "))
+	}
+
+	writeList(&b, "Key findings", report.Findings)
+
+	if len(report.DeadEnds) > 0 {
+		b.WriteString("### Dead ends avoided\n\n")
+		for _, d := range report.DeadEnds {
+			fmt.Fprintf(&b, "- %s — %s\n", d.Approach, d.WhyFailed)
+		}
+		b.WriteString("\n")
+	}
+
+	writeList(&b, "Artifacts", report.Artifacts)
+	writeList(&b, "Remaining unknowns", report.OpenQuestions)
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeList renders a Markdown section with a bullet list, skipping empty sections
+func writeList(b *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### %s\n\n", heading)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+	b.WriteString("\n")
+}
+
+// renderPRDescription renders a session report as a pull request description template
+func renderPRDescription(report *models.SessionReport) string {
+	var b strings.Builder
+	b.WriteString("## Summary\n\n")
+	if report.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", report.Summary)
+	} else {
+		fmt.Fprintf(&b, "%s\n\n", report.Objective)
+	}
+	for _, issue := range report.Issues {
+		if issue.Provider == models.IssueProviderGitHub {
+			fmt.Fprintf(&b, "Closes %s\n", issue.Ref())
+		} else {
+			fmt.Fprintf(&b, "Refs %s\n", issue.Ref())
+		}
+	}
+	if len(report.Issues) > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(report.Findings)+len(report.ResolvedQuestions)+len(report.DeadEnds)+len(report.Artifacts)+len(report.OpenQuestions) > 0 {
+		b.WriteString("## Details\n\n")
+	}
+	writeList(&b, "What changed and why", report.Findings)
+	writeList(&b, "Questions answered", report.ResolvedQuestions)
+
+	if len(report.DeadEnds) > 0 {
+		b.WriteString("### Approaches ruled out\n\n")
+		for _, d := range report.DeadEnds {
+			fmt.Fprintf(&b, "- %s — %s\n", d.Approach, d.WhyFailed)
+		}
+		b.WriteString("\n")
+	}
+
+	writeList(&b, "Artifacts", report.Artifacts)
+	writeList(&b, "Open questions / follow-ups", report.OpenQuestions)
+
+	b.WriteString("## Test plan\n\n- [ ] \n")
+	return b.String()
+}
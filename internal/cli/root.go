@@ -2,10 +2,14 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/AbdouB/memory/internal/db"
 	"github.com/spf13/cobra"
@@ -13,8 +17,16 @@ import (
 
 var (
 	database   *db.DB
-	outputText bool // --text flag for human-readable output (default is JSON for LLMs)
-	verbose    bool
+	stores     *db.Stores // Repositories the commands read and write through
+	outputText bool       // --text flag for human-readable output (default is JSON for LLMs)
+
+	commandTimeout time.Duration      // --timeout; zero means no limit
+	cancelTimeout  context.CancelFunc // Releases the --timeout context when the command ends
+
+	emitAliases string // --emit-aliases shell; prints shell functions for the short commands
+
+	quiet       bool     // --quiet drops everything written to stdout; errors still go to stderr
+	quietStdout *os.File // The real stdout while --quiet has it pointed at the null device
 )
 
 // rootCmd is the base command
@@ -35,39 +47,154 @@ Quick Start:
   memory verify "text"               # Verify stale findings
 
 For more information, visit: https://github.com/AbdouB/memory`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if emitAliases == "" {
+			return cmd.Help()
+		}
+		script, err := shellAliases(emitAliases)
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	},
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip DB init for help commands
-		if cmd.Name() == "help" || cmd.Name() == "version" {
+		// Flags and arguments are valid by now; later failures aren't usage errors
+		cmd.SilenceUsage = true
+
+		if quiet && quietStdout == nil {
+			devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+			if err != nil {
+				return fmt.Errorf("failed to silence output: %w", err)
+			}
+			quietStdout, os.Stdout = os.Stdout, devNull
+		}
+
+		// Commands run from the REPL share its logger and database
+		if !inRepl {
+			if err := setupLogging(); err != nil {
+				return err
+			}
+			if err := setupTracing(cmd.Context()); err != nil {
+				return fmt.Errorf("failed to set up tracing: %w", err)
+			}
+		}
+
+		// Bound the whole command, including DB locks, git and HTTP calls.
+		// The sync server runs until stopped and bounds each request instead,
+		// and the REPL and daemon bound each command they run.
+		ctx := cmd.Context()
+		if commandTimeout > 0 && cmd.Name() != "serve" && cmd.Name() != "repl" && cmd.Name() != "daemon" {
+			ctx, cancelTimeout = context.WithTimeout(ctx, commandTimeout)
+			cmd.SetContext(ctx)
+		}
+		// Servers aren't traced as a whole; each command the REPL and daemon run is
+		if cmd.Name() != "serve" && cmd.Name() != "repl" && cmd.Name() != "daemon" {
+			ctx = startCommandSpan(cmd)
+		}
+
+		// Skip DB init for help commands, the git merge driver and printing aliases
+		if cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == "mergetool" || emitAliases != "" {
+			return nil
+		}
+
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+
+		// Hash cache is per command run so repeated invocations see file edits
+		resetScopeHashCache()
+		if inRepl {
 			return nil
 		}
 
 		var err error
-		database, err = db.Open("")
+		database, err = db.Open(ctx, databasePath(ctx))
 		if err != nil {
 			return fmt.Errorf("failed to open database: %w", err)
 		}
+		database.SetActor(currentActor(ctx))
+		stores = db.NewStores(database)
+
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+		if scrubber != nil {
+			database.SetScrubber(scrubber)
+		}
 		return nil
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		if database != nil {
+		if database != nil && !inRepl {
 			database.Close()
 		}
 	},
 }
 
-// Execute runs the CLI
+// databasePath returns the absolute path of the database the current directory uses:
+// subdirectories and linked worktrees share the repository's, otherwise the default
+func databasePath(ctx context.Context) string {
+	path := db.DefaultDBPath()
+	if dir := sharedMemoryDir(ctx); dir != "" {
+		path = filepath.Join(dir, "sessions.db")
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// Execute runs the CLI, through the daemon when one serves this database
 func Execute() error {
-	return rootCmd.Execute()
+	if exit, forwarded := forwardToDaemon(); forwarded {
+		if exit != 0 {
+			return daemonExit(exit)
+		}
+		return nil
+	}
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	endCommandSpan(err)
+	if cancelTimeout != nil {
+		cancelTimeout()
+	}
+	restoreStdout()
+	if err != nil {
+		outputError(err)
+		slog.Info("command failed", "command", cmd.CommandPath(), "duration", time.Since(start), "err", err)
+	} else {
+		slog.Info("command finished", "command", cmd.CommandPath(), "duration", time.Since(start))
+	}
+	shutdownTracing()
+	closeLogging()
+	return err
 }
 
 func init() {
+	// Errors are printed by Execute with their code
+	rootCmd.SilenceErrors = true
+
 	rootCmd.PersistentFlags().BoolVar(&outputText, "text", false, "Human-readable text output (default is JSON for LLM consumption)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Log diagnostics to stderr (-v for timing and failures, -vv for every DB query and git call, -vvv for context assembly decisions)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Append diagnostics to this file instead of stderr")
+	rootCmd.Flags().StringVar(&emitAliases, "emit-aliases", "", "Print shell functions for the short commands (sh or fish)")
+	rootCmd.Flags().Lookup("emit-aliases").NoOptDefVal = "sh"
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print nothing on success; errors and the exit status still report failures")
+	rootCmd.PersistentFlags().DurationVar(&commandTimeout, "timeout", 0, "Abort the command after this long, e.g. 30s (0 = no limit)")
 
 	// Add version command (core 7 commands are added in quick.go)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// restoreStdout undoes --quiet once a command has finished
+func restoreStdout() {
+	if quietStdout != nil {
+		os.Stdout.Close()
+		os.Stdout, quietStdout = quietStdout, nil
+	}
+}
+
 // outputResult outputs the result in the appropriate format
 // Default is JSON (for LLMs), use --text for human-readable
 func outputResult(result interface{}) {
@@ -89,6 +216,7 @@ func outputError(err error) {
 		result := map[string]interface{}{
 			"status": "error",
 			"error":  err.Error(),
+			"code":   errorCode(err),
 		}
 		enc := json.NewEncoder(os.Stderr)
 		enc.Encode(result)
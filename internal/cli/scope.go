@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// httpFreshnessTimeout bounds how long a URL scope check may take, and how long
+// primeURLFingerprints waits for all of them together
+const httpFreshnessTimeout = 5 * time.Second
+
+// urlFreshnessTTL is how long a URL scope's fingerprint is trusted before it's fetched
+// again, so commands run in a loop don't request every URL each time
+const urlFreshnessTTL = 15 * time.Minute
+
+// urlFetchConcurrency caps how many URL scopes are fetched at once
+const urlFetchConcurrency = 8
+
+// urlFingerprintKeyPrefix prefixes the meta keys holding checked URL fingerprints
+const urlFingerprintKeyPrefix = "url_fingerprint:"
+
+// isURLScope reports whether a scope refers to an HTTP(S) resource
+func isURLScope(scope string) bool {
+	return strings.HasPrefix(scope, "http://") || strings.HasPrefix(scope, "https://")
+}
+
+// scopeKindFor returns the kind of a scope given on the command line: the one named,
+// or else inferred, telling directories apart from files by looking at the disk
+func scopeKindFor(scope, named string) (models.ScopeKind, error) {
+	if named != "" {
+		kind, ok := models.ParseScopeKind(named)
+		if !ok {
+			return "", fmt.Errorf("%w scope kind %q (use %s)", db.ErrInvalid, named, scopeKindNames())
+		}
+		return kind, nil
+	}
+	kind := models.InferScopeKind(scope)
+	if kind == models.ScopeFile {
+		if info, err := os.Stat(scope); err == nil && info.IsDir() {
+			kind = models.ScopeDir
+		}
+	}
+	return kind, nil
+}
+
+// scopeKindNames lists the scope kinds for help and error messages
+func scopeKindNames() string {
+	names := make([]string, len(models.ScopeKinds))
+	for i, k := range models.ScopeKinds {
+		names[i] = string(k)
+	}
+	return strings.Join(names, ", ")
+}
+
+// getScopeHash returns a fingerprint for a scope that changes when what it names changes.
+// Files use their git blob hash, directories a digest of the blob hashes of the files
+// under them, URLs the ETag or Last-Modified response header and commands the
+// executable they run. External APIs have none, so findings about them only age.
+// Returns empty string if no fingerprint can be determined.
+func getScopeHash(ctx context.Context, kind models.ScopeKind, scope string) string {
+	// Files and URLs are cached by scope alone, as primeFileGitHashes stores them
+	key := scope
+	if kind == models.ScopeDir || kind == models.ScopeCommand {
+		key = string(kind) + " " + scope
+	}
+	if hash, ok := cachedScopeHash(key); ok {
+		return hash
+	}
+
+	var hash string
+	switch kind {
+	case models.ScopeURL:
+		hash = urlFingerprint(ctx, scope)
+	case models.ScopeDir:
+		hash = getDirFingerprint(ctx, scope)
+	case models.ScopeCommand:
+		hash = getCommandFingerprint(scope)
+	case models.ScopeExternalAPI:
+	default:
+		hash = getFileGitHash(ctx, scope)
+	}
+	storeScopeHash(key, hash)
+	return hash
+}
+
+// getDirFingerprint digests the paths and blob hashes of the tracked and untracked,
+// not ignored, files under a directory, so adding, removing or editing any of them
+// changes it
+func getDirFingerprint(ctx context.Context, dir string) string {
+	output, err := gitRun(ctx, exec.CommandContext(ctx, "git", "ls-files", "-co", "--exclude-standard", "--", dir))
+	if err != nil {
+		return ""
+	}
+	var paths []string
+	for _, p := range strings.Split(string(output), "\n") {
+		// Deleted but still tracked files are listed too; hash-object would fail on them
+		if info, err := os.Stat(p); p != "" && err == nil && info.Mode().IsRegular() {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	cmd := exec.CommandContext(ctx, "git", "hash-object", "--stdin-paths")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\n") + "\n")
+	output, err = gitRun(ctx, cmd)
+	if err != nil {
+		return ""
+	}
+	hashes := strings.Fields(string(output))
+	if len(hashes) != len(paths) {
+		return ""
+	}
+	digest := sha256.New()
+	for i, p := range paths {
+		fmt.Fprintf(digest, "%s %s\n", p, hashes[i])
+	}
+	return "dir:" + hex.EncodeToString(digest.Sum(nil))
+}
+
+// getCommandFingerprint identifies the executable a command line runs by its resolved
+// path, size and modification time, which change when the tool is upgraded or replaced.
+// The command itself is never run.
+func getCommandFingerprint(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	path, err := exec.LookPath(fields[0])
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("command:%s:%d:%d", path, info.Size(), info.ModTime().Unix())
+}
+
+// primeURLFingerprints fetches the fingerprints of many URL scopes concurrently, within
+// one httpFreshnessTimeout overall, and stores them in the cache. A URL not answered in
+// time counts as unreachable for this run and is tried again on the next.
+func primeURLFingerprints(ctx context.Context, urls []string) {
+	ctx, cancel := context.WithTimeout(ctx, httpFreshnessTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, urlFetchConcurrency)
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+		if _, ok := cachedScopeHash(url); ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			storeScopeHash(url, urlFingerprint(ctx, url))
+		}()
+	}
+	wg.Wait()
+}
+
+// urlFingerprint returns a URL scope's fingerprint, fetching it only when the one last
+// fetched is older than urlFreshnessTTL
+func urlFingerprint(ctx context.Context, url string) string {
+	key := urlFingerprintKeyPrefix + url
+	if stores != nil {
+		if value, err := stores.Sync.GetMeta(ctx, key); err == nil {
+			if checked, fingerprint, ok := strings.Cut(value, " "); ok {
+				if at, err := time.Parse(time.RFC3339, checked); err == nil && time.Since(at) < urlFreshnessTTL {
+					return fingerprint
+				}
+			}
+		}
+	}
+
+	fingerprint := getURLFingerprint(ctx, url)
+	if stores != nil && ctx.Err() == nil {
+		// A cache that can't be written only costs a fetch next time
+		_ = stores.Sync.SetMeta(ctx, key, time.Now().UTC().Format(time.RFC3339)+" "+fingerprint)
+	}
+	return fingerprint
+}
+
+// getURLFingerprint fetches the response headers for a URL and returns its validator.
+// ETag is preferred over Last-Modified because it is content-based.
+func getURLFingerprint(ctx context.Context, url string) string {
+	client := &http.Client{Timeout: httpFreshnessTimeout}
+
+	fetch := func(method string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return client.Do(req)
+	}
+
+	resp, err := fetch(http.MethodHead)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		// Some servers reject HEAD; fall back to GET and discard the body
+		resp.Body.Close()
+		resp, err = fetch(http.MethodGet)
+	}
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return ""
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return "etag:" + etag
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		return "last-modified:" + lastModified
+	}
+	return ""
+}
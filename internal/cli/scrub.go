@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/AbdouB/memory/internal/scrub"
+	"github.com/spf13/cobra"
+)
+
+// scrubCmd groups personal data scrubbing configuration
+var scrubCmd = &cobra.Command{
+	Use:   "scrub",
+	Short: "Configure personal data scrubbing",
+	Long: `Remove personal data from everything memory stores or shares. Email addresses,
+listed names and custom patterns (customer IDs, account numbers, ...) are replaced with
+[email], [name] and [<rule>] before findings, sessions, goals and handoffs are written,
+and again before 'share export' and 'sync push', so rules added later still protect
+what leaves the machine.
+
+Settings are stored in config.json next to the database.
+
+Examples:
+  memory scrub emails
+  memory scrub name "Jane Doe" "John Smith"
+  memory scrub rule customer 'CUST-[0-9]+'
+  memory scrub test "Jane Doe (jane@acme.com) reported CUST-1234"`,
+}
+
+// scrubEmailsCmd toggles email scrubbing
+var scrubEmailsCmd = &cobra.Command{
+	Use:   "emails",
+	Short: "Scrub email addresses",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		off, _ := cmd.Flags().GetBool("off")
+		return updateScrubConfig(func(sc *config.ScrubConfig) error {
+			sc.Emails = !off
+			return nil
+		})
+	},
+}
+
+// scrubNameCmd adds or removes names
+var scrubNameCmd = &cobra.Command{
+	Use:   "name [name...]",
+	Short: "Scrub people's names",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remove, _ := cmd.Flags().GetBool("remove")
+		return updateScrubConfig(func(sc *config.ScrubConfig) error {
+			for _, name := range args {
+				name = strings.TrimSpace(name)
+				idx := -1
+				for i, n := range sc.Names {
+					if strings.EqualFold(n, name) {
+						idx = i
+					}
+				}
+				switch {
+				case remove && idx >= 0:
+					sc.Names = append(sc.Names[:idx], sc.Names[idx+1:]...)
+				case remove:
+					return fmt.Errorf("%q is not scrubbed", name)
+				case idx < 0 && name != "":
+					sc.Names = append(sc.Names, name)
+				}
+			}
+			return nil
+		})
+	},
+}
+
+// scrubRuleCmd adds or removes a pattern rule
+var scrubRuleCmd = &cobra.Command{
+	Use:   "rule [name] [pattern]",
+	Short: "Scrub matches of a regular expression",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		remove, _ := cmd.Flags().GetBool("remove")
+		name := args[0]
+		if !remove && len(args) != 2 {
+			return fmt.Errorf("a pattern is required")
+		}
+		return updateScrubConfig(func(sc *config.ScrubConfig) error {
+			kept := sc.Rules[:0]
+			for _, r := range sc.Rules {
+				if r.Name != name {
+					kept = append(kept, r)
+				}
+			}
+			if remove && len(kept) == len(sc.Rules) {
+				return fmt.Errorf("no scrub rule named %s", name)
+			}
+			sc.Rules = kept
+			if !remove {
+				sc.Rules = append(sc.Rules, config.ScrubRule{Name: name, Pattern: args[1]})
+			}
+			return nil
+		})
+	},
+}
+
+// scrubListCmd shows the scrub configuration
+var scrubListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show what is scrubbed",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		sc := cfg.Scrub
+		if sc == nil {
+			sc = &config.ScrubConfig{}
+		}
+
+		if !outputText {
+			outputResult(sc)
+			return nil
+		}
+		if !sc.Emails && len(sc.Names) == 0 && len(sc.Rules) == 0 {
+			fmt.Println("Nothing is scrubbed.")
+			return nil
+		}
+		if sc.Emails {
+			fmt.Println("  emails → [email]")
+		}
+		for _, n := range sc.Names {
+			fmt.Printf("  %s → [name]\n", n)
+		}
+		for _, r := range sc.Rules {
+			fmt.Printf("  /%s/ → [%s]\n", r.Pattern, r.Name)
+		}
+		return nil
+	},
+}
+
+// scrubTestCmd previews scrubbing on a piece of text
+var scrubTestCmd = &cobra.Command{
+	Use:   "test [text]",
+	Short: "Show how text would be scrubbed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+		scrubbed := s.String(args[0])
+		if outputText {
+			fmt.Println(scrubbed)
+		} else {
+			outputResult(map[string]interface{}{
+				"input":    args[0],
+				"scrubbed": scrubbed,
+				"changed":  scrubbed != args[0],
+			})
+		}
+		return nil
+	},
+}
+
+// updateScrubConfig applies a change to the scrub settings, validates and saves them
+func updateScrubConfig(change func(sc *config.ScrubConfig) error) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Scrub == nil {
+		cfg.Scrub = &config.ScrubConfig{}
+	}
+	if err := change(cfg.Scrub); err != nil {
+		return err
+	}
+	if _, err := scrub.New(cfg.Scrub); err != nil {
+		return err
+	}
+	if !cfg.Scrub.Emails && len(cfg.Scrub.Names) == 0 && len(cfg.Scrub.Rules) == 0 {
+		cfg.Scrub = nil
+	}
+	if err := cfg.Save(memoryDir()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if outputText {
+		fmt.Println("✓ Scrub settings saved")
+	} else {
+		outputResult(map[string]interface{}{
+			"status": "saved",
+			"scrub":  cfg.Scrub,
+		})
+	}
+	return nil
+}
+
+// loadScrubber compiles the configured scrub rules; nil when nothing is scrubbed
+func loadScrubber() (*scrub.Scrubber, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return scrub.New(cfg.Scrub)
+}
+
+// scrubSharedEntries removes personal data from shared entries, keeping their types
+// so they serialize with the usual field order
+func scrubSharedEntries(s *scrub.Scrubber, values []interface{}) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = s.JSON(data); err != nil {
+			return nil, err
+		}
+		scrubbed := reflect.New(reflect.TypeOf(v))
+		if err := json.Unmarshal(data, scrubbed.Interface()); err != nil {
+			return nil, err
+		}
+		out = append(out, scrubbed.Elem().Interface())
+	}
+	return out, nil
+}
+
+// scrubSyncBatch removes personal data from the event payloads of an outgoing batch
+func scrubSyncBatch(s *scrub.Scrubber, batch *models.SyncBatch) error {
+	for _, ev := range batch.Events {
+		data, err := s.JSON([]byte(ev.Payload))
+		if err != nil {
+			return err
+		}
+		ev.Payload = string(data)
+	}
+	return nil
+}
+
+func init() {
+	scrubEmailsCmd.Flags().Bool("off", false, "Stop scrubbing email addresses")
+	scrubNameCmd.Flags().Bool("remove", false, "Stop scrubbing these names")
+	scrubRuleCmd.Flags().Bool("remove", false, "Remove the rule")
+	scrubCmd.AddCommand(scrubEmailsCmd, scrubNameCmd, scrubRuleCmd, scrubListCmd, scrubTestCmd)
+	rootCmd.AddCommand(scrubCmd)
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/metrics"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/AbdouB/memory/internal/scrub"
+	"github.com/spf13/cobra"
+)
+
+// syncPageSize bounds the events exchanged per sync request
+const syncPageSize = 500
+
+// syncMaxBody bounds the size of a pushed batch
+const syncMaxBody = 32 << 20
+
+// syncEventsPath is the sync endpoint, relative to the remote URL
+const syncEventsPath = "/v1/sync/events"
+
+// serveCmd runs the sync server
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve this database to memory sync clients",
+	Long: `Run a sync server over this database so findings, unknowns and dead ends follow you
+across machines. Clients push and pull with 'memory sync'. Every request must carry the
+token as a bearer token; the token can also be set with MEMORY_SYNC_TOKEN.
+
+A shared server can instead, or also, accept API tokens limited to some projects and
+read-only on others; see 'memory serve token'.
+
+With --grpc-addr it also serves the gRPC API defined in api/memory/v1/memory.proto,
+for orchestrators managing many agents: Query streams a project's breadcrumbs and Watch
+streams new breadcrumb events as they are recorded. Calls carry the same tokens, as
+"authorization: Bearer <token>" metadata.
+
+Prometheus metrics (breadcrumbs logged, sessions, verifications, query latency and
+context build time) are served unauthenticated on /metrics.
+
+The server speaks plain HTTP and gRPC without TLS. Put it behind a TLS-terminating
+proxy before exposing it beyond localhost.
+
+Examples:
+  memory serve --token s3cret
+  MEMORY_SYNC_TOKEN=s3cret memory serve --addr 0.0.0.0:8420
+  memory serve --token s3cret --grpc-addr 127.0.0.1:8421`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		grpcAddr, _ := cmd.Flags().GetString("grpc-addr")
+		if token == "" {
+			token = os.Getenv("MEMORY_SYNC_TOKEN")
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if token == "" && len(cfg.Tokens) == 0 {
+			return fmt.Errorf("a token is required (--token, MEMORY_SYNC_TOKEN or 'memory serve token create')")
+		}
+
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle(syncEventsPath, &syncServer{token: token, scrubber: scrubber})
+		mux.Handle("/metrics", metrics.Handler())
+
+		errc := make(chan error, 2)
+		if grpcAddr != "" {
+			listener, err := net.Listen("tcp", grpcAddr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", grpcAddr, err)
+			}
+			fmt.Fprintf(os.Stderr, "Serving gRPC on %s\n", grpcAddr)
+			go func() { errc <- newGRPCServer(token, scrubber).Serve(listener) }()
+		}
+		fmt.Fprintf(os.Stderr, "Serving %s on http://%s\n", database.Path(), addr)
+		go func() { errc <- http.ListenAndServe(addr, mux) }()
+		return <-errc
+	},
+}
+
+// syncServer exchanges breadcrumb event batches with sync clients
+type syncServer struct {
+	token    string          // Full access; empty when only API tokens are accepted
+	scrubber *scrub.Scrubber // Applied to events leaving the server
+	mu       sync.Mutex      // Serializes merges into the database
+}
+
+// ServeHTTP serves GET (pull a page after a cursor) and POST (push a batch)
+func (s *syncServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if commandTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, commandTimeout)
+		defer cancel()
+	}
+	// API tokens are read on every request so revoking one takes effect at once
+	cfg, err := loadConfig()
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, "failed to load config: "+err.Error())
+		return
+	}
+	access := requestAccess(r, s.token, cfg.Tokens)
+	if access == nil {
+		writeHTTPError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+		batch, err := stores.Sync.SyncBatchAfter(ctx, after, syncPageSize)
+		if err == nil {
+			err = access.filterSyncBatch(ctx, batch)
+		}
+		if err == nil {
+			err = scrubSyncBatch(s.scrubber, batch)
+		}
+		if err == nil {
+			err = attachBatchBlobs(batch)
+		}
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeHTTPJSON(w, batch)
+	case http.MethodPost:
+		var batch models.SyncBatch
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, syncMaxBody)).Decode(&batch); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, "invalid batch: "+err.Error())
+			return
+		}
+		if err := access.checkSyncWrite(ctx, &batch); err != nil {
+			writeHTTPError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err := storeBatchBlobs(&batch); err != nil {
+			writeHTTPError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		s.mu.Lock()
+		events, err := stores.Sync.NewSyncEvents(ctx, batch.Events)
+		var result *db.MergeResult
+		if err == nil {
+			result, err = stores.Sync.ApplySyncBatch(ctx, &batch)
+		}
+		s.mu.Unlock()
+		if err != nil {
+			writeHTTPError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		countSyncedEvents(events)
+		writeHTTPJSON(w, result)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeHTTPError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// writeHTTPJSON writes a JSON response
+func writeHTTPJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeHTTPError writes a JSON error response
+func writeHTTPError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:8420", "Address to listen on")
+	serveCmd.Flags().String("token", "", "Token clients must present")
+	serveCmd.Flags().String("grpc-addr", "", "Also serve the gRPC API on this address")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// sessionsCmd lists past sessions a page at a time
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List sessions, newest first",
+	Long: `List recorded sessions, newest first, one page at a time.
+
+When more sessions follow, the output includes next_cursor; pass it to --cursor to
+read the next page. Pages stay consistent while new sessions are started.
+
+Examples:
+  memory sessions
+  memory sessions --page-size 100
+  memory sessions --cursor <next_cursor>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		aiID, _ := cmd.Flags().GetString("ai-id")
+		cursorToken, _ := cmd.Flags().GetString("cursor")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
+
+		if pageSize <= 0 {
+			return fmt.Errorf("%w: --page-size must be positive", db.ErrInvalid)
+		}
+		page := db.Page{Size: pageSize}
+		if cursorToken != "" {
+			after, err := db.ParseCursor(cursorToken)
+			if err != nil {
+				return err
+			}
+			page.After = after
+		}
+
+		sessions, next, err := stores.Sessions.ListPage(ctx, aiID, page)
+		if err != nil {
+			return fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if sessions == nil {
+			sessions = []*models.Session{}
+		}
+
+		if !outputText {
+			result := map[string]interface{}{
+				"sessions": sessions,
+				"count":    len(sessions),
+			}
+			if next != nil {
+				result["next_cursor"] = next.String()
+			}
+			outputResult(result)
+			return nil
+		}
+
+		if len(sessions) == 0 {
+			fmt.Println("No sessions recorded.")
+			return nil
+		}
+		for _, s := range sessions {
+			state := "open"
+			if s.EndTime != nil {
+				state = "ended"
+			}
+			id := s.SessionID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			line := fmt.Sprintf("%s  %s  %-14s %-5s", s.CreatedAt.Format("2006-01-02 15:04:05"), id, s.AIID, state)
+			if s.Subject != nil {
+				line += "  " + truncateText(*s.Subject, 60)
+			}
+			fmt.Println(line)
+		}
+		if next != nil {
+			fmt.Printf("\nMore: memory sessions --cursor %s\n", next.String())
+		}
+		return nil
+	},
+}
+
+// SessionSide summarizes one of the sessions a diff compares
+type SessionSide struct {
+	SessionID        string                    `json:"session_id"`
+	Objective        string                    `json:"objective"`
+	StartedAt        time.Time                 `json:"started_at"`
+	EndedAt          *time.Time                `json:"ended_at,omitempty"`
+	Vectors          *models.EpistemicSnapshot `json:"vectors"`
+	VectorsRecorded  bool                      `json:"vectors_recorded"` // False when recomputed from breadcrumbs
+	Findings         int                       `json:"findings"`
+	UnknownsResolved int                       `json:"unknowns_resolved"`
+	DeadEnds         int                       `json:"dead_ends"`
+}
+
+// SessionDiff compares two sessions and the knowledge recorded between them
+type SessionDiff struct {
+	From               SessionSide        `json:"from"`
+	To                 SessionSide        `json:"to"`
+	VectorDeltas       map[string]float64 `json:"vector_deltas"`
+	FindingsAdded      []string           `json:"findings_added"`
+	UnknownsResolved   []string           `json:"unknowns_resolved"`
+	DeadEndsIntroduced []string           `json:"dead_ends_introduced"`
+}
+
+// sessionSide loads a session with its latest recorded vectors, recomputing them from
+// its breadcrumbs for sessions that predate snapshots
+func sessionSide(ctx context.Context, sessionID string) (*SessionSide, string, error) {
+	session, err := stores.Sessions.Get(ctx, sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+	projectID := ""
+	if session.ProjectID != nil {
+		projectID = *session.ProjectID
+	}
+	side := &SessionSide{SessionID: session.SessionID, StartedAt: session.StartTime, EndedAt: session.EndTime}
+	if session.Subject != nil {
+		side.Objective = *session.Subject
+	}
+
+	reflexes, err := stores.Reflexes.ListBySession(ctx, sessionID, 1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(reflexes) > 0 {
+		snapshot := snapshotFromReflex(reflexes[0])
+		side.Vectors, side.VectorsRecorded = &snapshot, true
+	} else {
+		end := time.Now()
+		if session.EndTime != nil {
+			end = *session.EndTime
+		}
+		side.Vectors = sessionEpistemicState(ctx, projectID, sessionID, end.Sub(session.StartTime)).Snapshot()
+	}
+
+	bcRepo := stores.Breadcrumbs
+	findings, _ := bcRepo.ListFindings(ctx, projectID, sessionID, 1000)
+	resolved := true
+	resolvedUnknowns, _ := bcRepo.ListUnknowns(ctx, projectID, sessionID, &resolved, 1000)
+	deadEnds, _ := bcRepo.ListDeadEnds(ctx, projectID, sessionID, 1000)
+	side.Findings, side.UnknownsResolved, side.DeadEnds = len(findings), len(resolvedUnknowns), len(deadEnds)
+	return side, projectID, nil
+}
+
+// sessionDiffCmd compares two sessions
+var sessionDiffCmd = &cobra.Command{
+	Use:   "diff <session-id> <session-id>",
+	Short: "Compare two sessions",
+	Long: `Compare two sessions: how each epistemic vector moved from the earlier session to
+the later one, and the findings added, unknowns resolved and dead ends introduced from
+the end of the earlier session through the end of the later one, whichever sessions
+recorded them. Use it to judge whether a change of approach improved what is known.
+
+Sessions are ordered by start time, so the arguments can come in either order.
+
+Examples:
+  memory session diff <session-id> <session-id> --text`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		from, fromProject, err := sessionSide(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		to, toProject, err := sessionSide(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if fromProject != toProject {
+			return fmt.Errorf("%w: sessions belong to different projects", db.ErrInvalid)
+		}
+		if to.StartedAt.Before(from.StartedAt) {
+			from, to = to, from
+		}
+
+		diff := &SessionDiff{
+			From:               *from,
+			To:                 *to,
+			VectorDeltas:       vectorDeltas(from.Vectors, to.Vectors),
+			FindingsAdded:      []string{},
+			UnknownsResolved:   []string{},
+			DeadEndsIntroduced: []string{},
+		}
+
+		// The span runs from the end of the earlier session through the end of the later
+		// one; an earlier session still open counts from its start
+		sinceTime := from.StartedAt
+		if from.EndedAt != nil {
+			sinceTime = *from.EndedAt
+		}
+		untilTime := time.Now()
+		if to.EndedAt != nil {
+			untilTime = *to.EndedAt
+		}
+		since := float64(sinceTime.UnixMilli()) / 1000.0
+		until := float64(untilTime.UnixMilli()) / 1000.0
+
+		bcRepo := stores.Breadcrumbs
+		findings, err := listBetween(since, until, func(p db.Page) ([]*models.Finding, *db.Cursor, error) {
+			return bcRepo.ListFindingsPage(ctx, fromProject, "", p)
+		}, func(f *models.Finding) float64 { return f.CreatedTimestamp })
+		if err != nil {
+			return fmt.Errorf("failed to list findings: %w", err)
+		}
+		for _, f := range findings {
+			diff.FindingsAdded = append(diff.FindingsAdded, f.Finding)
+		}
+		deadEnds, err := listBetween(since, until, func(p db.Page) ([]*models.DeadEnd, *db.Cursor, error) {
+			return bcRepo.ListDeadEndsPage(ctx, fromProject, "", p)
+		}, func(d *models.DeadEnd) float64 { return d.CreatedTimestamp })
+		if err != nil {
+			return fmt.Errorf("failed to list dead ends: %w", err)
+		}
+		for _, d := range deadEnds {
+			diff.DeadEndsIntroduced = append(diff.DeadEndsIntroduced, fmt.Sprintf("%s — %s", d.Approach, d.WhyFailed))
+		}
+
+		// Old questions can be answered in the span, so resolution time decides
+		resolved := true
+		resolvedUnknowns, err := bcRepo.ListUnknowns(ctx, fromProject, "", &resolved, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to list resolved unknowns: %w", err)
+		}
+		for _, u := range resolvedUnknowns {
+			if u.ResolvedTimestamp != nil && *u.ResolvedTimestamp > since && *u.ResolvedTimestamp <= until {
+				diff.UnknownsResolved = append(diff.UnknownsResolved, u.Unknown)
+			}
+		}
+
+		if !outputText {
+			outputResult(diff)
+			return nil
+		}
+
+		fmt.Printf("From: %s  %s\n", from.StartedAt.Format("2006-01-02 15:04"), from.Objective)
+		fmt.Printf("To:   %s  %s\n", to.StartedAt.Format("2006-01-02 15:04"), to.Objective)
+		fmt.Println(strings.Repeat("─", 50))
+		for _, vec := range trendVectors {
+			fmt.Printf("  %-12s %3.0f%% → %3.0f%%  (%+.0f)\n", vec.name,
+				vec.value(from.Vectors)*100, vec.value(to.Vectors)*100, diff.VectorDeltas[vec.name]*100)
+		}
+		fmt.Printf("\nPer session: findings %d → %d, unknowns resolved %d → %d, dead ends %d → %d\n",
+			from.Findings, to.Findings, from.UnknownsResolved, to.UnknownsResolved, from.DeadEnds, to.DeadEnds)
+		printBriefingList("Findings added", "+", diff.FindingsAdded)
+		printBriefingList("Unknowns resolved", "✓", diff.UnknownsResolved)
+		printBriefingList("Dead ends introduced", "✗", diff.DeadEndsIntroduced)
+		return nil
+	},
+}
+
+// sessionCmd groups commands about individual sessions
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Inspect and configure individual sessions",
+}
+
+// sessionAdHocCmd turns per-day ad-hoc sessions on or off
+var sessionAdHocCmd = &cobra.Command{
+	Use:   "adhoc",
+	Short: "Log outside a session into a per-day ad-hoc session",
+	Long: `With ad-hoc sessions on, learned, uncertain and tried no longer fail when no session
+is active. The breadcrumb goes to an "Ad-hoc observations <date>" session for the
+current project instead, started on first use and reused for the rest of the day, so
+quick observations aren't lost. Run without flags to show the current setting.
+
+Examples:
+  memory session adhoc --on
+  memory session adhoc --off
+  memory session adhoc --text`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		flags := cmd.Flags()
+		if flags.Changed("off") && flags.Changed("on") {
+			return fmt.Errorf("%w: give either --on or --off", db.ErrInvalid)
+		}
+		changed := flags.Changed("on") || flags.Changed("off")
+		if changed {
+			cfg.Sessions = nil
+			if flags.Changed("on") {
+				cfg.Sessions = &config.SessionsConfig{AdHoc: true}
+			}
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		enabled := cfg.Sessions != nil && cfg.Sessions.AdHoc
+		if !outputText {
+			status := "current"
+			if changed {
+				status = "saved"
+			}
+			outputResult(map[string]interface{}{
+				"status":  status,
+				"enabled": enabled,
+			})
+			return nil
+		}
+		if !enabled {
+			fmt.Println("Ad-hoc sessions are off; logging needs an active session")
+			return nil
+		}
+		fmt.Println("Ad-hoc sessions are on; logging without a session goes to today's ad-hoc session")
+		return nil
+	},
+}
+
+func init() {
+	sessionAdHocCmd.Flags().Bool("on", false, "Log into an ad-hoc session when none is active")
+	sessionAdHocCmd.Flags().Bool("off", false, "Require an active session to log")
+	sessionCmd.AddCommand(sessionAdHocCmd)
+	sessionCmd.AddCommand(sessionDiffCmd)
+	rootCmd.AddCommand(sessionCmd)
+
+	sessionsCmd.Flags().String("ai-id", "", "Only list sessions by this AI")
+	sessionsCmd.Flags().String("cursor", "", "Resume after the next_cursor of the previous page")
+	sessionsCmd.Flags().Int("page-size", 20, "Sessions per page")
+	rootCmd.AddCommand(sessionsCmd)
+}
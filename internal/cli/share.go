@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// Shared knowledge files under .memory/shared, or .memory/shared/<root path> for
+// monorepo sub-projects
+const (
+	sharedFindingsFile = "findings.jsonl"
+	sharedDeadEndsFile = "dead_ends.jsonl"
+)
+
+// sharedExportLimit caps how many findings or dead ends are exported per project
+const sharedExportLimit = 100000
+
+// sharedFinding is the committed form of a finding. Machine-local state (worktree,
+// file change detection, verification output, versions) is left out.
+type sharedFinding struct {
+	ID                    string                      `json:"id"`
+	SessionID             string                      `json:"session_id"`
+	Finding               string                      `json:"finding"`
+	Subject               *string                     `json:"subject,omitempty"`
+	SubjectKind           models.ScopeKind            `json:"subject_kind,omitempty"`
+	Impact                float64                     `json:"impact"`
+	CreatedTimestamp      float64                     `json:"created_timestamp"`
+	LastVerifiedTimestamp *float64                    `json:"last_verified_timestamp,omitempty"`
+	SubjectGitHash        *string                     `json:"subject_git_hash,omitempty"`
+	VerifyCheck           *string                     `json:"verify_check,omitempty"`
+	Tags                  []string                    `json:"tags,omitempty"`
+	Relations             []models.BreadcrumbRelation `json:"relations,omitempty"`
+}
+
+// sharedDeadEnd is the committed form of a dead end
+type sharedDeadEnd struct {
+	ID               string                      `json:"id"`
+	SessionID        string                      `json:"session_id"`
+	Approach         string                      `json:"approach"`
+	WhyFailed        string                      `json:"why_failed"`
+	Subject          *string                     `json:"subject,omitempty"`
+	Impact           float64                     `json:"impact"`
+	CreatedTimestamp float64                     `json:"created_timestamp"`
+	Tags             []string                    `json:"tags,omitempty"`
+	Relations        []models.BreadcrumbRelation `json:"relations,omitempty"`
+}
+
+// ShareChange is one entry an import created or updated
+type ShareChange struct {
+	ID     string             `json:"id"`
+	Type   string             `json:"type"`
+	Action models.AuditAction `json:"action"` // create or edit
+	Text   string             `json:"text"`
+}
+
+// ShareResult summarizes a share import or export
+type ShareResult struct {
+	Dir      string        `json:"dir"`
+	DryRun   bool          `json:"dry_run,omitempty"`
+	Imported int           `json:"imported"`
+	Changes  []ShareChange `json:"changes,omitempty"`
+	Findings int           `json:"findings,omitempty"`
+	DeadEnds int           `json:"dead_ends,omitempty"`
+}
+
+// shareCmd groups git-backed knowledge sharing commands
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Share findings and dead ends through the git repository",
+	Long: `Share knowledge with teammates without any infrastructure: findings and dead ends are
+written as sorted JSONL under .memory/shared/ in the repository (.memory/shared/<path>/
+for monorepo sub-projects), committed like any other file, and merged back in after a pull.
+Both commands work on the project of the current directory.
+
+One entry per line, sorted by ID, keeps diffs small and lets git merge concurrent
+additions. If .memory/ is ignored, add !.memory/shared/ to .gitignore.
+
+Examples:
+  memory share export && git add .memory/shared && git commit -m "Share findings"
+  git pull && memory share import`,
+}
+
+// shareExportCmd writes the current project's knowledge to the shared files
+var shareExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write this project's findings and dead ends to .memory/shared",
+	Long: `Write the current project's findings and dead ends to .memory/shared.
+Entries already in the files are imported first, so teammates' knowledge is never dropped.
+Entries deleted with 'memory forget' are left out, which removes them for everyone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		dir, err := sharedProjectDir(ctx, project)
+		if err != nil {
+			return err
+		}
+
+		result, err := importSharedProject(ctx, project, dir, false)
+		if err != nil {
+			return err
+		}
+
+		repo := stores.Breadcrumbs
+		findings, err := repo.ListFindings(ctx, project.ID, "", sharedExportLimit)
+		if err != nil {
+			return fmt.Errorf("failed to list findings: %w", err)
+		}
+		deadEnds, err := repo.ListDeadEnds(ctx, project.ID, "", sharedExportLimit)
+		if err != nil {
+			return fmt.Errorf("failed to list dead ends: %w", err)
+		}
+
+		sharedFindings := make([]interface{}, 0, len(findings))
+		sort.Slice(findings, func(i, j int) bool { return findings[i].ID < findings[j].ID })
+		for _, f := range findings {
+			sharedFindings = append(sharedFindings, sharedFinding{
+				ID:                    f.ID,
+				SessionID:             f.SessionID,
+				Finding:               f.Finding,
+				Subject:               f.Subject,
+				SubjectKind:           f.SubjectKind,
+				Impact:                f.Impact,
+				CreatedTimestamp:      f.CreatedTimestamp,
+				LastVerifiedTimestamp: f.LastVerifiedTimestamp,
+				SubjectGitHash:        f.SubjectGitHash,
+				VerifyCheck:           f.VerifyCheck,
+				Tags:                  f.Tags,
+				Relations:             f.Relations,
+			})
+		}
+		sharedDeadEnds := make([]interface{}, 0, len(deadEnds))
+		sort.Slice(deadEnds, func(i, j int) bool { return deadEnds[i].ID < deadEnds[j].ID })
+		for _, d := range deadEnds {
+			sharedDeadEnds = append(sharedDeadEnds, sharedDeadEnd{
+				ID:               d.ID,
+				SessionID:        d.SessionID,
+				Approach:         d.Approach,
+				WhyFailed:        d.WhyFailed,
+				Subject:          d.Subject,
+				Impact:           d.Impact,
+				CreatedTimestamp: d.CreatedTimestamp,
+				Tags:             d.Tags,
+				Relations:        d.Relations,
+			})
+		}
+
+		// Rules added since the entries were stored still apply to what leaves the machine
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+		if sharedFindings, err = scrubSharedEntries(scrubber, sharedFindings); err != nil {
+			return fmt.Errorf("failed to scrub findings: %w", err)
+		}
+		if sharedDeadEnds, err = scrubSharedEntries(scrubber, sharedDeadEnds); err != nil {
+			return fmt.Errorf("failed to scrub dead ends: %w", err)
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := writeJSONL(filepath.Join(dir, sharedFindingsFile), sharedFindings); err != nil {
+			return fmt.Errorf("failed to write findings: %w", err)
+		}
+		if err := writeJSONL(filepath.Join(dir, sharedDeadEndsFile), sharedDeadEnds); err != nil {
+			return fmt.Errorf("failed to write dead ends: %w", err)
+		}
+		result.Findings = len(sharedFindings)
+		result.DeadEnds = len(sharedDeadEnds)
+
+		if outputText {
+			fmt.Printf("✓ Exported %d findings and %d dead ends to %s", result.Findings, result.DeadEnds, dir)
+			if result.Imported > 0 {
+				fmt.Printf(" (%d merged from teammates)", result.Imported)
+			}
+			fmt.Println()
+		} else {
+			outputResult(result)
+		}
+		return nil
+	},
+}
+
+// shareImportCmd merges the shared files into the local database
+var shareImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Merge teammates' findings and dead ends from .memory/shared",
+	Long: `Merge the current project's shared findings and dead ends into the local database,
+typically after a git pull. New entries are added; entries you already have pick up newer
+verifications, tags and relations. Importing is idempotent. With --dry-run the entries
+that would change are listed and nothing is written.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		dir, err := sharedProjectDir(ctx, project)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return fmt.Errorf("nothing shared yet (%s does not exist)", dir)
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		result, err := importSharedProject(ctx, project, dir, dryRun)
+		if err != nil {
+			return err
+		}
+
+		if outputText {
+			if dryRun {
+				fmt.Printf("Dry run: would import %d new or updated entries from %s\n", result.Imported, dir)
+				for _, c := range result.Changes {
+					fmt.Printf("  %-6s %-8s %s  %s\n", c.Action, c.Type, c.ID[:8], truncateText(c.Text, 60))
+				}
+			} else {
+				fmt.Printf("✓ Imported %d new or updated entries from %s\n", result.Imported, dir)
+			}
+		} else {
+			outputResult(result)
+		}
+		return nil
+	},
+}
+
+// sharedProjectDir returns the shared directory for a project: .memory/shared at the top
+// of the current checkout, nested by root path for sub-projects. Keying on the repository
+// layout rather than the project name lets clones in differently named directories share.
+func sharedProjectDir(ctx context.Context, project *models.Project) (string, error) {
+	top, err := gitRepoRoot(ctx)
+	if err != nil {
+		return "", fmt.Errorf("sharing requires a git repository")
+	}
+	dir := filepath.Join(top, ".memory", "shared")
+	if project.RootPath != nil && *project.RootPath != "" {
+		dir = filepath.Join(dir, filepath.FromSlash(*project.RootPath))
+	}
+	return dir, nil
+}
+
+// importSharedProject merges one project's shared files into the given local project.
+// With dryRun the changes are reported but not written.
+func importSharedProject(ctx context.Context, project *models.Project, dir string, dryRun bool) (*ShareResult, error) {
+	result := &ShareResult{Dir: dir, DryRun: dryRun}
+
+	var findings []sharedFinding
+	if err := readJSONL(filepath.Join(dir, sharedFindingsFile), func(line []byte) error {
+		var f sharedFinding
+		if err := json.Unmarshal(line, &f); err != nil {
+			return err
+		}
+		findings = append(findings, f)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read shared findings: %w", err)
+	}
+
+	var deadEnds []sharedDeadEnd
+	if err := readJSONL(filepath.Join(dir, sharedDeadEndsFile), func(line []byte) error {
+		var d sharedDeadEnd
+		if err := json.Unmarshal(line, &d); err != nil {
+			return err
+		}
+		deadEnds = append(deadEnds, d)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read shared dead ends: %w", err)
+	}
+
+	// One transaction for the whole import: a single commit instead of one per
+	// breadcrumb, and a half-applied share never lands
+	err := stores.InTx(ctx, func(tx *db.Stores) error {
+		repo := tx.Breadcrumbs
+		for _, s := range findings {
+			action, err := repo.ImportFinding(ctx, &models.Finding{
+				ID:                    s.ID,
+				ProjectID:             project.ID,
+				SessionID:             s.SessionID,
+				Finding:               s.Finding,
+				CreatedTimestamp:      s.CreatedTimestamp,
+				Subject:               s.Subject,
+				SubjectKind:           s.SubjectKind,
+				Impact:                s.Impact,
+				LastVerifiedTimestamp: s.LastVerifiedTimestamp,
+				SubjectGitHash:        s.SubjectGitHash,
+				VerifyCheck:           s.VerifyCheck,
+				Tags:                  s.Tags,
+				Relations:             s.Relations,
+			}, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to import finding %s: %w", s.ID, err)
+			}
+			if action != "" {
+				result.Imported++
+				result.Changes = append(result.Changes, ShareChange{ID: s.ID, Type: models.EntityFinding, Action: action, Text: s.Finding})
+			}
+		}
+
+		for _, s := range deadEnds {
+			action, err := repo.ImportDeadEnd(ctx, &models.DeadEnd{
+				ID:               s.ID,
+				ProjectID:        project.ID,
+				SessionID:        s.SessionID,
+				Approach:         s.Approach,
+				WhyFailed:        s.WhyFailed,
+				CreatedTimestamp: s.CreatedTimestamp,
+				Subject:          s.Subject,
+				Impact:           s.Impact,
+				Tags:             s.Tags,
+				Relations:        s.Relations,
+			}, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to import dead end %s: %w", s.ID, err)
+			}
+			if action != "" {
+				result.Imported++
+				result.Changes = append(result.Changes, ShareChange{ID: s.ID, Type: models.EntityDeadEnd, Action: action, Text: s.Approach})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// readJSONL calls fn for each non-empty line of a JSONL file. A missing file has no lines.
+func readJSONL(path string, fn func(line []byte) error) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// writeJSONL writes one JSON value per line, replacing the file
+func writeJSONL(path string, values []interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	for _, v := range values {
+		line, err := json.Marshal(v)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func init() {
+	shareImportCmd.Flags().Bool("dry-run", false, "List the entries that would change without writing")
+	shareCmd.AddCommand(shareExportCmd)
+	shareCmd.AddCommand(shareImportCmd)
+	rootCmd.AddCommand(shareCmd)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// maxSnippetLines caps how much code --snippet captures
+const maxSnippetLines = 200
+
+// snippetLanguages maps file extensions to the language recorded with a snippet, named
+// as Markdown code fences name them
+var snippetLanguages = map[string]string{
+	".go": "go", ".py": "python", ".js": "javascript", ".jsx": "jsx", ".ts": "typescript",
+	".tsx": "tsx", ".rb": "ruby", ".rs": "rust", ".java": "java", ".kt": "kotlin",
+	".swift": "swift", ".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".hpp": "cpp",
+	".cs": "csharp", ".php": "php", ".scala": "scala", ".sh": "bash", ".bash": "bash",
+	".sql": "sql", ".html": "html", ".css": "css", ".scss": "scss", ".json": "json",
+	".yaml": "yaml", ".yml": "yaml", ".toml": "toml", ".xml": "xml", ".md": "markdown",
+	".proto": "protobuf", ".tf": "hcl", ".lua": "lua", ".ex": "elixir", ".exs": "elixir",
+	".erl": "erlang", ".hs": "haskell", ".ml": "ocaml", ".dart": "dart", ".vue": "vue",
+}
+
+// snippetFileLanguages names languages of files recognized by name rather than extension
+var snippetFileLanguages = map[string]string{
+	"Dockerfile": "dockerfile", "Makefile": "makefile", "go.mod": "go-mod",
+}
+
+// captureSnippet reads the code a --snippet value points at: path:start-end, or
+// path:line for a single line
+func captureSnippet(spec string) (*models.CodeSnippet, error) {
+	invalid := fmt.Errorf("%w --snippet %q (use path:start-end, e.g. auth/jwt.go:20-45)", db.ErrInvalid, spec)
+	i := strings.LastIndex(spec, ":")
+	if i <= 0 {
+		return nil, invalid
+	}
+	path, lines := spec[:i], spec[i+1:]
+	startStr, endStr, isRange := strings.Cut(lines, "-")
+	start, err := strconv.Atoi(startStr)
+	if err != nil || start < 1 {
+		return nil, invalid
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(endStr); err != nil || end < start {
+			return nil, invalid
+		}
+	}
+	if end-start+1 > maxSnippetLines {
+		return nil, fmt.Errorf("%w: --snippet spans %d lines, at most %d are captured", db.ErrInvalid, end-start+1, maxSnippetLines)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippet: %w", err)
+	}
+	fileLines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if start > len(fileLines) {
+		return nil, fmt.Errorf("%w: %s has %d lines, the snippet starts at %d", db.ErrInvalid, path, len(fileLines), start)
+	}
+	end = min(end, len(fileLines))
+
+	language := snippetLanguages[strings.ToLower(filepath.Ext(path))]
+	if l, ok := snippetFileLanguages[filepath.Base(path)]; ok {
+		language = l
+	}
+	return &models.CodeSnippet{
+		Path:      filepath.ToSlash(filepath.Clean(path)),
+		StartLine: start,
+		EndLine:   end,
+		Language:  language,
+		Code:      strings.Join(fileLines[start-1:end], "\n"),
+	}, nil
+}
+
+// printSnippet prints a finding's snippet as a fenced code block, indented under it
+func printSnippet(s *models.CodeSnippet, indent string) {
+	fmt.Printf("%s%s\n%s```%s\n", indent, s.Location(), indent, s.Language)
+	for _, line := range strings.Split(s.Code, "\n") {
+		fmt.Printf("%s%s\n", indent, line)
+	}
+	fmt.Printf("%s```\n", indent)
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// sourceCmd groups epistemic source commands
+var sourceCmd = &cobra.Command{
+	Use:   "source",
+	Short: "Track the docs, URLs and code that findings rest on",
+}
+
+// sourceAddCmd records a source in the current project
+var sourceAddCmd = &cobra.Command{
+	Use:   "add [title]",
+	Short: "Record a source with its own confidence",
+	Long: `Record a document, URL or piece of code as a source in the current project.
+
+The confidence (0-1) is how far the source itself can be trusted, independent of the
+findings drawn from it. The active session, if any, is recorded as its discoverer.
+
+Examples:
+  memory source add "RFC 6749 section 6" --type doc --url https://datatracker.ietf.org/doc/html/rfc6749#section-6
+  memory source add "auth/refresh.go" --type code --confidence 0.9`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sourceType, _ := cmd.Flags().GetString("type")
+		url, _ := cmd.Flags().GetString("url")
+		description, _ := cmd.Flags().GetString("description")
+		confidence, _ := cmd.Flags().GetFloat64("confidence")
+		layer, _ := cmd.Flags().GetString("layer")
+
+		if confidence < 0 || confidence > 1 {
+			return fmt.Errorf("%w: --confidence must be between 0 and 1", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		source := models.NewEpistemicSource(project.ID, sourceType, args[0], confidence)
+		if url != "" {
+			source.SourceURL = &url
+		}
+		if description != "" {
+			source.Description = &description
+		}
+		if layer != "" {
+			source.EpistemicLayer = &layer
+		}
+		if active, err := loadActiveSession(ctx); err == nil {
+			source.SessionID = &active.SessionID
+			source.DiscoveredByAI = &active.AIID
+		}
+
+		if err := stores.Sources.Create(ctx, source); err != nil {
+			return fmt.Errorf("failed to create source: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Source: %s (%s, confidence %.2f)\n", source.Title, source.SourceType, source.Confidence)
+			fmt.Printf("  ID: %s\n", source.ID)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":    "created",
+				"source_id": source.ID,
+				"source":    source,
+			})
+		}
+		return nil
+	},
+}
+
+// sourceListCmd lists the project's sources or those behind one finding
+var sourceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sources in the current project",
+	Long: `List sources in the current project, newest first. With --finding, list the
+sources linked to that finding, most confident first.
+
+Examples:
+  memory source list
+  memory source list --type url
+  memory source list --finding 3f2a9c1e-...`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sourceType, _ := cmd.Flags().GetString("type")
+		findingID, _ := cmd.Flags().GetString("finding")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		var sources []*models.EpistemicSource
+		var err error
+		if findingID != "" {
+			sources, err = stores.Sources.ListByFinding(ctx, findingID)
+		} else {
+			project, perr := getOrCreateDefaultProject(ctx)
+			if perr != nil {
+				return fmt.Errorf("failed to get project: %w", perr)
+			}
+			sources, err = stores.Sources.List(ctx, project.ID, sourceType, limit)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list sources: %w", err)
+		}
+
+		if !outputText {
+			if sources == nil {
+				sources = []*models.EpistemicSource{}
+			}
+			outputResult(map[string]interface{}{
+				"sources": sources,
+				"count":   len(sources),
+			})
+			return nil
+		}
+
+		if len(sources) == 0 {
+			fmt.Println("No sources.")
+			return nil
+		}
+		for _, s := range sources {
+			fmt.Printf("  %s  %-6s %.2f  %s\n", s.ID[:8], s.SourceType, s.Confidence, truncateText(s.Title, 60))
+			if s.SourceURL != nil {
+				fmt.Printf("      %s\n", *s.SourceURL)
+			}
+		}
+		return nil
+	},
+}
+
+// sourceLinkCmd links a finding to a source it rests on
+var sourceLinkCmd = &cobra.Command{
+	Use:   "link [source-id] [finding-id]",
+	Short: "Link a finding to a source",
+	Long: `Record that a finding rests on a source. Linking the same pair twice is a no-op.
+
+Examples:
+  memory source link 9b1c... 3f2a9c1e-...`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sourceID, findingID := args[0], args[1]
+
+		if _, err := stores.Breadcrumbs.GetFinding(ctx, findingID); err != nil {
+			return fmt.Errorf("failed to get finding: %w", err)
+		}
+		linked, err := stores.Sources.LinkFinding(ctx, sourceID, findingID)
+		if err != nil {
+			return fmt.Errorf("failed to link source: %w", err)
+		}
+
+		status := "linked"
+		if !linked {
+			status = "already_linked"
+		}
+		if outputText {
+			if linked {
+				fmt.Printf("✓ Linked finding %s to source %s\n", findingID, sourceID)
+			} else {
+				fmt.Printf("Finding %s is already linked to source %s\n", findingID, sourceID)
+			}
+		} else {
+			outputResult(map[string]interface{}{
+				"status":     status,
+				"source_id":  sourceID,
+				"finding_id": findingID,
+			})
+		}
+		return nil
+	},
+}
+
+func init() {
+	sourceAddCmd.Flags().String("type", "doc", "Source type (doc, url, code, ...)")
+	sourceAddCmd.Flags().String("url", "", "Where the source lives")
+	sourceAddCmd.Flags().String("description", "", "What the source covers")
+	sourceAddCmd.Flags().Float64("confidence", 0.7, "How far the source can be trusted (0-1)")
+	sourceAddCmd.Flags().String("layer", "", "Epistemic layer the source informs")
+
+	sourceListCmd.Flags().String("type", "", "Only list sources of this type")
+	sourceListCmd.Flags().String("finding", "", "List the sources linked to this finding")
+	sourceListCmd.Flags().Int("limit", 50, "Maximum number of sources")
+
+	sourceCmd.AddCommand(sourceAddCmd, sourceListCmd, sourceLinkCmd)
+	rootCmd.AddCommand(sourceCmd)
+}
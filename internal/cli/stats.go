@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd shows aggregate figures for a project's knowledge base
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show knowledge base statistics for the project",
+	Long: `Show counts of findings by staleness, unknowns by resolution and dead ends, how
+many findings were ever verified, how long findings live, and which files collect the
+most breadcrumbs and dead ends.
+
+Staleness uses file changes already recorded on findings; run 'memory status' or a
+git hook to record new ones.
+
+Examples:
+  memory stats
+  memory stats --project auth-service --text`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		projectName, _ := cmd.Flags().GetString("project")
+
+		var project *models.Project
+		var err error
+		if projectName != "" {
+			project, err = stores.Projects.GetByName(ctx, projectName)
+		} else {
+			project, err = getOrCreateDefaultProject(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		stats, err := stores.Breadcrumbs.ProjectStats(ctx, project.ID)
+		if err != nil {
+			return fmt.Errorf("failed to compute stats: %w", err)
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"project": project.Name,
+				"stats":   stats,
+			})
+			return nil
+		}
+
+		f, u := stats.Findings, stats.Unknowns
+		fmt.Printf("Project %s\n", project.Name)
+		fmt.Println(strings.Repeat("─", 50))
+		fmt.Printf("Findings:  %d (%d fresh, %d aging, %d stale; %d deleted)\n", f.Total, f.Fresh, f.Aging, f.Stale, f.Deleted)
+		fmt.Printf("  Verified:     %d (%.0f%%), %d with checks, %d with changed files\n",
+			f.Verified, f.VerificationRate*100, f.WithCheck, f.FileChanged)
+		fmt.Printf("  Average age:  %.1f days", f.AvgAgeDays)
+		if f.Deleted > 0 {
+			fmt.Printf(" (deleted ones lived %.1f days)", f.AvgLifetimeDays)
+		}
+		fmt.Println()
+		fmt.Printf("Unknowns:  %d (%d open, %d resolved, %.0f%% resolution rate)\n", u.Total, u.Open, u.Resolved, u.ResolutionRate*100)
+		if u.Resolved > 0 {
+			fmt.Printf("  Average time to resolve: %.1f days\n", u.AvgDaysToResolve)
+		}
+		fmt.Printf("Dead ends: %d\n", stats.DeadEnds.Total)
+
+		if len(stats.TopScopes) > 0 {
+			fmt.Println("\nMost-scoped files:")
+			for _, s := range stats.TopScopes {
+				fmt.Printf("  %4d  %s\n", s.Count, s.Scope)
+			}
+		}
+		if len(stats.DeadEndHotspots) > 0 {
+			fmt.Println("\nDead-end hotspots:")
+			for _, s := range stats.DeadEndHotspots {
+				fmt.Printf("  %4d  %s\n", s.Count, s.Scope)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().String("project", "", "Project name (defaults to the current directory's project)")
+	rootCmd.AddCommand(statsCmd)
+}
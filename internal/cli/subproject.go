@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// repoProject returns the project for the repository containing the working directory.
+// Linked worktrees resolve to the main checkout's project.
+func repoProject(ctx context.Context, wt *WorktreeInfo) (*models.Project, error) {
+	return getOrCreateProjectByName(ctx, filepath.Base(wt.MainRoot))
+}
+
+// worktreeRelPath returns dir relative to the worktree root with forward slashes
+func worktreeRelPath(wt *WorktreeInfo, dir string) (string, bool) {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	rel, err := filepath.Rel(wt.Root, dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// pathWithin reports whether rel lies inside (or is) the sub-project directory root
+func pathWithin(rel, root string) bool {
+	return rel == root || strings.HasPrefix(rel, root+"/")
+}
+
+// deepestSubProject walks registered sub-projects down from parent and returns the
+// most specific one containing rel, or nil when rel isn't inside any of them
+func deepestSubProject(ctx context.Context, parent *models.Project, rel string) (*models.Project, error) {
+	repo := stores.Projects
+	var found *models.Project
+	current := parent
+	for {
+		children, err := repo.ListChildren(ctx, current.ID)
+		if err != nil {
+			return nil, err
+		}
+		var next *models.Project
+		for _, child := range children {
+			if child.RootPath != nil && pathWithin(rel, *child.RootPath) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return found, nil
+		}
+		found, current = next, next
+	}
+}
+
+// resolveSubProject returns the registered sub-project containing dir, or nil.
+// Lookups never create projects so plain repositories behave as before.
+func resolveSubProject(ctx context.Context, dir string) (*models.Project, error) {
+	wt := currentWorktree(ctx)
+	if wt == nil {
+		return nil, nil
+	}
+	rel, ok := worktreeRelPath(wt, dir)
+	if !ok || rel == "." {
+		return nil, nil
+	}
+	parent, err := stores.Projects.GetByName(ctx, filepath.Base(wt.MainRoot))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deepestSubProject(ctx, parent, rel)
+}
+
+// projectAncestorIDs returns the IDs of a project's parents, nearest first
+func projectAncestorIDs(ctx context.Context, project *models.Project) []string {
+	repo := stores.Projects
+	var ids []string
+	seen := map[string]bool{project.ID: true}
+	for parentID := project.ParentID; parentID != nil && !seen[*parentID]; {
+		seen[*parentID] = true
+		ids = append(ids, *parentID)
+		parent, err := repo.Get(ctx, *parentID)
+		if err != nil || parent == nil {
+			break
+		}
+		parentID = parent.ParentID
+	}
+	return ids
+}
+
+// subprojectCmd groups monorepo sub-project commands
+var subprojectCmd = &cobra.Command{
+	Use:   "subproject",
+	Short: "Manage monorepo sub-projects",
+	Long: `Register directories of a monorepo as sub-projects with their own knowledge.
+
+Running memory anywhere inside a registered directory scopes sessions, findings and
+queries to that sub-project. Use 'memory start --inherit' to also load knowledge
+recorded at the parent level.
+
+Examples:
+  memory subproject add services/auth
+  memory subproject list`,
+}
+
+// subprojectAddCmd registers a directory as a sub-project
+var subprojectAddCmd = &cobra.Command{
+	Use:   "add [path]",
+	Short: "Register a directory as a sub-project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		wt := currentWorktree(ctx)
+		if wt == nil {
+			return fmt.Errorf("sub-projects require a git repository")
+		}
+
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid path: %w", err)
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return fmt.Errorf("not a directory: %s", args[0])
+		}
+		rel, ok := worktreeRelPath(wt, dir)
+		if !ok || rel == "." {
+			return fmt.Errorf("path must be a subdirectory of %s", wt.Root)
+		}
+
+		root, err := repoProject(ctx, wt)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		// Nest under the most specific existing sub-project
+		parent, err := deepestSubProject(ctx, root, rel)
+		if err != nil {
+			return fmt.Errorf("failed to resolve parent: %w", err)
+		}
+		if parent == nil {
+			parent = root
+		} else if *parent.RootPath == rel {
+			return fmt.Errorf("already registered as %s", parent.Name)
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = root.Name + "/" + rel
+		}
+		repo := stores.Projects
+		if _, err := repo.GetByName(ctx, name); err == nil {
+			return fmt.Errorf("project name already in use: %s: %w", name, db.ErrConflict)
+		} else if !errors.Is(err, db.ErrNotFound) {
+			return fmt.Errorf("failed to check name: %w", err)
+		}
+
+		project := models.NewProject(name, nil)
+		project.ParentID = &parent.ID
+		project.RootPath = &rel
+		if err := repo.Create(ctx, project); err != nil {
+			return fmt.Errorf("failed to create sub-project: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Sub-project: %s (%s, parent %s)\n", project.Name, rel, parent.Name)
+		} else {
+			outputResult(map[string]interface{}{
+				"id":        project.ID,
+				"name":      project.Name,
+				"root_path": rel,
+				"parent_id": parent.ID,
+				"parent":    parent.Name,
+			})
+		}
+		return nil
+	},
+}
+
+// subprojectListCmd lists the sub-projects of the current repository
+var subprojectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List sub-projects of the current repository",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		wt := currentWorktree(ctx)
+		if wt == nil {
+			return fmt.Errorf("sub-projects require a git repository")
+		}
+		root, err := repoProject(ctx, wt)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+
+		repo := stores.Projects
+		var all []*models.Project
+		queue := []*models.Project{root}
+		for len(queue) > 0 {
+			children, err := repo.ListChildren(ctx, queue[0].ID)
+			if err != nil {
+				return fmt.Errorf("failed to list sub-projects: %w", err)
+			}
+			queue = append(queue[1:], children...)
+			all = append(all, children...)
+		}
+
+		if !outputText {
+			if all == nil {
+				all = []*models.Project{}
+			}
+			outputResult(map[string]interface{}{
+				"project":     root.Name,
+				"subprojects": all,
+				"count":       len(all),
+			})
+			return nil
+		}
+
+		fmt.Printf("Sub-projects of %s\n", root.Name)
+		fmt.Println(strings.Repeat("─", 50))
+		if len(all) == 0 {
+			fmt.Println("None registered. Use 'memory subproject add <path>'.")
+			return nil
+		}
+		for _, p := range all {
+			fmt.Printf("  %s  %s\n", *p.RootPath, p.Name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	subprojectAddCmd.Flags().String("name", "", "Project name (defaults to <repo>/<path>)")
+	subprojectCmd.AddCommand(subprojectAddCmd, subprojectListCmd)
+	rootCmd.AddCommand(subprojectCmd)
+}
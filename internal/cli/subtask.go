@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// importanceRank orders subtasks most important first
+var importanceRank = map[models.EpistemicImportance]int{
+	models.ImportanceCritical: 0,
+	models.ImportanceHigh:     1,
+	models.ImportanceMedium:   2,
+	models.ImportanceLow:      3,
+}
+
+// subtaskDone reports whether a subtask no longer needs work
+func subtaskDone(s *models.SubTask) bool {
+	return s.Status == models.TaskStatusCompleted || s.Status == models.TaskStatusSkipped
+}
+
+// subtaskRef summarizes a subtask for decision guidance
+func subtaskRef(s *models.SubTask) models.SubtaskRef {
+	return models.SubtaskRef{
+		ID:          s.ID,
+		Description: s.Description,
+		Importance:  s.EpistemicImportance,
+		Status:      s.Status,
+	}
+}
+
+// planSubtasks picks the next subtask to work on, the most important unfinished one whose
+// dependencies are all done, in creation order among equals, and lists the subtasks that
+// are blocked or wait on unfinished dependencies
+func planSubtasks(subtasks []*models.SubTask) (next *models.SubTask, blocked []*models.SubTask) {
+	done := map[string]bool{}
+	for _, s := range subtasks {
+		if subtaskDone(s) {
+			done[s.ID] = true
+		}
+	}
+
+	var ready []*models.SubTask
+	for _, s := range subtasks {
+		if done[s.ID] {
+			continue
+		}
+		waiting := false
+		for _, dep := range s.Dependencies {
+			if !done[dep] {
+				waiting = true
+				break
+			}
+		}
+		if waiting || s.Status == models.TaskStatusBlocked {
+			blocked = append(blocked, s)
+			continue
+		}
+		ready = append(ready, s)
+	}
+	if len(ready) == 0 {
+		return nil, blocked
+	}
+
+	// ListByGoal returns creation order, which the stable sort keeps among equals
+	sort.SliceStable(ready, func(i, j int) bool {
+		return importanceRank[ready[i].EpistemicImportance] < importanceRank[ready[j].EpistemicImportance]
+	})
+	return ready[0], blocked
+}
+
+// resolveSubtask finds a subtask of the active goal by ID or ID prefix
+func resolveSubtask(subtasks []*models.SubTask, id string) (*models.SubTask, error) {
+	var match *models.SubTask
+	for _, s := range subtasks {
+		if s.ID == id {
+			return s, nil
+		}
+		if strings.HasPrefix(s.ID, id) {
+			if match != nil {
+				return nil, fmt.Errorf("%w: subtask ID %q is ambiguous", db.ErrInvalid, id)
+			}
+			match = s
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("subtask %s: %w", id, db.ErrNotFound)
+	}
+	return match, nil
+}
+
+// activeGoalSubtasks loads the active goal and its subtasks
+func activeGoalSubtasks(cmd *cobra.Command) (*models.Goal, []*models.SubTask, error) {
+	ctx := cmd.Context()
+	active, err := requireActiveSession(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	goal := currentGoal(ctx, active)
+	if goal == nil {
+		return nil, nil, fmt.Errorf("%w: no active goal; run 'memory goal add' or 'memory goal use' first", db.ErrInvalid)
+	}
+	subtasks, err := stores.Subtasks.ListByGoal(ctx, goal.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list subtasks: %w", err)
+	}
+	return goal, subtasks, nil
+}
+
+// subtaskCmd groups subtask commands
+var subtaskCmd = &cobra.Command{
+	Use:   "subtask",
+	Short: "Plan the active goal as subtasks",
+	Long: `Break the active goal into subtasks. Decision guidance in start and status names the
+next subtask to work on, and lists blocked critical and high-importance subtasks as
+prerequisites.`,
+}
+
+// subtaskAddCmd plans a subtask under the active goal
+var subtaskAddCmd = &cobra.Command{
+	Use:   "add [description]",
+	Short: "Add a subtask to the active goal",
+	Long: `Add a subtask to the active goal.
+
+Examples:
+  memory subtask add "Write migration" --importance critical
+  memory subtask add "Backfill tokens" --after 1a2b3c4d`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		importance, _ := cmd.Flags().GetString("importance")
+		after, _ := cmd.Flags().GetStringSlice("after")
+		if _, ok := importanceRank[models.EpistemicImportance(importance)]; !ok {
+			return fmt.Errorf("%w importance %q (use critical, high, medium or low)", db.ErrInvalid, importance)
+		}
+
+		goal, subtasks, err := activeGoalSubtasks(cmd)
+		if err != nil {
+			return err
+		}
+		subtask := models.NewSubTask(goal.ID, args[0], models.EpistemicImportance(importance))
+		for _, id := range after {
+			dep, err := resolveSubtask(subtasks, id)
+			if err != nil {
+				return err
+			}
+			subtask.Dependencies = append(subtask.Dependencies, dep.ID)
+		}
+		if err := stores.Subtasks.Create(ctx, subtask); err != nil {
+			return fmt.Errorf("failed to create subtask: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Subtask: %s\n", subtask.Description)
+			fmt.Printf("  ID: %s\n", subtask.ID)
+			return nil
+		}
+		outputResult(map[string]interface{}{
+			"status":     "created",
+			"subtask_id": subtask.ID,
+			"goal_id":    goal.ID,
+		})
+		return nil
+	},
+}
+
+// subtaskListCmd lists the active goal's subtasks
+var subtaskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the active goal's subtasks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		goal, subtasks, err := activeGoalSubtasks(cmd)
+		if err != nil {
+			return err
+		}
+		next, _ := planSubtasks(subtasks)
+
+		if !outputText {
+			if subtasks == nil {
+				subtasks = []*models.SubTask{}
+			}
+			result := map[string]interface{}{
+				"goal_id":  goal.ID,
+				"subtasks": subtasks,
+				"count":    len(subtasks),
+			}
+			if next != nil {
+				result["next_subtask_id"] = next.ID
+			}
+			outputResult(result)
+			return nil
+		}
+
+		fmt.Printf("Goal: %s\n", goal.Objective)
+		if len(subtasks) == 0 {
+			fmt.Println("No subtasks.")
+			return nil
+		}
+		for _, s := range subtasks {
+			icon := "○"
+			switch {
+			case subtaskDone(s):
+				icon = "✓"
+			case s.Status == models.TaskStatusBlocked:
+				icon = "✗"
+			case next != nil && s.ID == next.ID:
+				icon = "→"
+			}
+			fmt.Printf("  %s %s  %-8s %s\n", icon, s.ID[:8], s.EpistemicImportance, s.Description)
+		}
+		return nil
+	},
+}
+
+// subtaskStatusCmd builds a command that moves a subtask to a status
+func subtaskStatusCmd(use, short string, status models.TaskStatus) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [subtask-id]",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			_, subtasks, err := activeGoalSubtasks(cmd)
+			if err != nil {
+				return err
+			}
+			subtask, err := resolveSubtask(subtasks, args[0])
+			if err != nil {
+				return err
+			}
+
+			if status == models.TaskStatusCompleted {
+				evidence, _ := cmd.Flags().GetString("evidence")
+				err = stores.Subtasks.Complete(ctx, subtask.ID, evidence)
+			} else {
+				err = stores.Subtasks.UpdateStatus(ctx, subtask.ID, status)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update subtask: %w", err)
+			}
+
+			if outputText {
+				fmt.Printf("✓ Subtask %s: %s\n", strings.ReplaceAll(string(status), "_", " "), subtask.Description)
+				return nil
+			}
+			outputResult(map[string]interface{}{
+				"status":     string(status),
+				"subtask_id": subtask.ID,
+			})
+			return nil
+		},
+	}
+}
+
+func init() {
+	subtaskAddCmd.Flags().String("importance", string(models.ImportanceMedium), "critical, high, medium or low")
+	subtaskAddCmd.Flags().StringSlice("after", nil, "Subtask IDs that must be done first")
+
+	doneCmd := subtaskStatusCmd("done", "Mark a subtask complete", models.TaskStatusCompleted)
+	doneCmd.Flags().String("evidence", "", "What shows the subtask is done")
+	subtaskCmd.AddCommand(
+		subtaskAddCmd,
+		subtaskListCmd,
+		doneCmd,
+		subtaskStatusCmd("start", "Mark a subtask in progress", models.TaskStatusInProgress),
+		subtaskStatusCmd("block", "Mark a subtask blocked", models.TaskStatusBlocked),
+		subtaskStatusCmd("unblock", "Mark a blocked subtask pending again", models.TaskStatusPending),
+		subtaskStatusCmd("skip", "Skip a subtask", models.TaskStatusSkipped),
+	)
+	rootCmd.AddCommand(subtaskCmd)
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// Suggestion kinds
+const (
+	SuggestRecommendation = "recommendation"
+	SuggestSubtask        = "subtask"
+	SuggestVerify         = "verify"
+	SuggestQuestion       = "question"
+)
+
+// staleImpactFloor is the least impact a stale finding needs to be suggested
+const staleImpactFloor = 0.5
+
+// subtaskScore ranks blocked subtasks by importance
+var subtaskScore = map[models.EpistemicImportance]float64{
+	models.ImportanceCritical: 1.0,
+	models.ImportanceHigh:     0.8,
+	models.ImportanceMedium:   0.5,
+	models.ImportanceLow:      0.3,
+}
+
+// Suggestion is one ranked next step
+type Suggestion struct {
+	Rank    int     `json:"rank"`
+	Kind    string  `json:"kind"`
+	Text    string  `json:"text"`
+	Reason  string  `json:"reason"`
+	Command string  `json:"command,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// handoffRecommendations splits the latest handoff's recommendations into steps
+func handoffRecommendations(h *models.HandoffReport) []*Suggestion {
+	if h == nil || h.NextSessionContext == nil {
+		return nil
+	}
+	var suggestions []*Suggestion
+	for _, line := range strings.Split(*h.NextSessionContext, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if line == "" {
+			continue
+		}
+		suggestions = append(suggestions, &Suggestion{
+			Kind:   SuggestRecommendation,
+			Text:   line,
+			Reason: "recommended by the last session",
+			Score:  1,
+		})
+	}
+	return suggestions
+}
+
+// blockedSubtaskSuggestions lists the blocked subtasks of the active goal, or of the
+// goals the last session left unfinished
+func blockedSubtaskSuggestions(ctx context.Context, active *ActiveSession, h *models.HandoffReport) []*Suggestion {
+	var goals []*models.Goal
+	if active != nil {
+		if goal := currentGoal(ctx, active); goal != nil {
+			goals = append(goals, goal)
+		}
+	}
+	if len(goals) == 0 && h != nil {
+		completed := false
+		goals, _ = stores.Goals.List(ctx, h.SessionID, &completed, 20)
+	}
+
+	var suggestions []*Suggestion
+	for _, goal := range goals {
+		subtasks, _ := stores.Subtasks.ListByGoal(ctx, goal.ID)
+		_, blocked := planSubtasks(subtasks)
+		for _, s := range blocked {
+			reason := fmt.Sprintf("%s subtask of %q is blocked", s.EpistemicImportance, goal.Objective)
+			if s.Status != models.TaskStatusBlocked {
+				reason = fmt.Sprintf("%s subtask of %q waits on unfinished subtasks", s.EpistemicImportance, goal.Objective)
+			}
+			suggestions = append(suggestions, &Suggestion{
+				Kind:    SuggestSubtask,
+				Text:    "Unblock: " + s.Description,
+				Reason:  reason,
+				Command: fmt.Sprintf("memory subtask unblock %s", s.ID[:8]),
+				Score:   subtaskScore[s.EpistemicImportance],
+			})
+		}
+	}
+	return suggestions
+}
+
+// suggestCmd ranks what to do next across questions, stale findings, subtasks and the handoff
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Rank what to do next",
+	Long: `Combine the last session's recommendations, blocked subtasks, stale high-impact
+findings and open questions into one ranked "do this next" list. The text output
+is meant to be pasted as the first prompt of a fresh agent session.
+
+Recommendations from the handoff rank first, in their own order. Blocked subtasks,
+stale findings and open questions follow, ranked by importance and impact.
+
+Examples:
+  memory suggest --text
+  memory suggest --limit 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		active, _ := loadActiveSession(ctx)
+		var goalID string
+		if active != nil {
+			goalID = active.CurrentGoalID
+		}
+
+		var handoff *models.HandoffReport
+		if handoffs, _ := stores.Handoffs.List(ctx, project.ID, "", 1); len(handoffs) > 0 {
+			handoff = handoffs[0]
+		}
+
+		suggestions := blockedSubtaskSuggestions(ctx, active, handoff)
+
+		bcRepo := stores.Breadcrumbs
+		findings, _ := bcRepo.ListFindingsWithStaleness(ctx, project.ID, "", 200)
+		primeFindingHashes(ctx, findings)
+		for _, f := range findings {
+			if f.Impact < staleImpactFloor || (goalID != "" && f.GoalID != nil && *f.GoalID != goalID) {
+				continue
+			}
+			if f.GetStalenessStatus(findingFileChanged(ctx, f)) != models.StatusStale {
+				continue
+			}
+			suggestions = append(suggestions, &Suggestion{
+				Kind:    SuggestVerify,
+				Text:    "Verify: " + f.Finding,
+				Reason:  fmt.Sprintf("stale for %dd, impact %.1f", int(f.DaysSinceVerified()), f.Impact),
+				Command: suggestVerifyCommand(f.ID, f.Finding),
+				Score:   f.Impact,
+			})
+		}
+
+		resolved := false
+		unknowns, _ := bcRepo.ListUnknowns(ctx, project.ID, "", &resolved, 200)
+		for _, u := range unknowns {
+			if goalID != "" && u.GoalID != nil && *u.GoalID != goalID {
+				continue
+			}
+			age := time.Since(time.UnixMilli(int64(u.CreatedTimestamp * 1000)))
+			suggestions = append(suggestions, &Suggestion{
+				Kind:    SuggestQuestion,
+				Text:    "Answer: " + u.Unknown,
+				Reason:  fmt.Sprintf("open for %dd, impact %.1f", int(age.Hours()/24), u.Impact),
+				Command: `memory learned "<answer>"`,
+				// A question is worth a little less than a stale finding of the same impact,
+				// which may already be misleading work
+				Score: u.Impact * 0.95,
+			})
+		}
+
+		// The last session's explicit advice outranks inferred work and keeps its own order
+		sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].Score > suggestions[j].Score })
+		suggestions = append(handoffRecommendations(handoff), suggestions...)
+		if limit > 0 && len(suggestions) > limit {
+			suggestions = suggestions[:limit]
+		}
+		for i, s := range suggestions {
+			s.Rank = i + 1
+			s.Score = math.Round(s.Score*100) / 100
+		}
+
+		if !outputText {
+			if suggestions == nil {
+				suggestions = []*Suggestion{}
+			}
+			outputResult(map[string]interface{}{
+				"project":     project.Name,
+				"suggestions": suggestions,
+				"count":       len(suggestions),
+			})
+			return nil
+		}
+
+		if len(suggestions) == 0 {
+			fmt.Println("Nothing pending: no recommendations, blocked subtasks, stale findings or open questions.")
+			return nil
+		}
+		fmt.Printf("Do this next in %s:\n\n", project.Name)
+		for _, s := range suggestions {
+			fmt.Printf("%d. %s (%s)\n", s.Rank, s.Text, s.Reason)
+			if s.Command != "" {
+				fmt.Printf("   `%s`\n", s.Command)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	suggestCmd.Flags().Int("limit", 10, "Maximum number of suggestions (0 for all)")
+	rootCmd.AddCommand(suggestCmd)
+}
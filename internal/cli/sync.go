@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// syncTimeout bounds each request to the sync server
+const syncTimeout = 60 * time.Second
+
+// syncCmd groups remote sync commands
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Push and pull breadcrumbs to a sync server",
+	Long: `Exchange findings, unknowns and dead ends with a server started by 'memory serve',
+so knowledge follows you across laptop, CI and devcontainers. Only new events are sent
+each time, and merges are deterministic, so push and pull can run in any order.
+
+The remote is remembered in config.json after the first use. The token can also be set
+with MEMORY_SYNC_TOKEN.
+
+Examples:
+  memory sync push --remote https://memory.example.com --token s3cret
+  memory sync pull`,
+}
+
+// syncPushCmd sends local events to the remote
+var syncPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Send new local breadcrumbs to the sync server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		remote, err := resolveSyncRemote(cmd)
+		if err != nil {
+			return err
+		}
+
+		scrubber, err := loadScrubber()
+		if err != nil {
+			return err
+		}
+
+		cursorKey := "sync_push:" + remote.URL
+		cursor, err := syncCursor(ctx, cursorKey)
+		if err != nil {
+			return err
+		}
+		sent, added := 0, 0
+		for {
+			batch, err := stores.Sync.SyncBatchAfter(ctx, cursor, syncPageSize)
+			if err != nil {
+				return fmt.Errorf("failed to read events: %w", err)
+			}
+			if len(batch.Events) == 0 {
+				break
+			}
+			if err := scrubSyncBatch(scrubber, batch); err != nil {
+				return fmt.Errorf("failed to scrub events: %w", err)
+			}
+			if err := attachBatchBlobs(batch); err != nil {
+				return fmt.Errorf("failed to read evidence blobs: %w", err)
+			}
+			var result db.MergeResult
+			if err := syncRequest(ctx, remote, http.MethodPost, syncEventsPath, batch, &result); err != nil {
+				return err
+			}
+			sent += len(batch.Events)
+			added += result.Events
+			cursor = batch.Cursor
+			if err := stores.Sync.SetMeta(ctx, cursorKey, strconv.FormatInt(cursor, 10)); err != nil {
+				return fmt.Errorf("failed to save sync cursor: %w", err)
+			}
+			if !batch.More {
+				break
+			}
+		}
+
+		if outputText {
+			fmt.Printf("✓ Pushed %d events to %s (%d new)\n", sent, remote.URL, added)
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "pushed",
+				"remote": remote.URL,
+				"sent":   sent,
+				"new":    added,
+			})
+		}
+		return nil
+	},
+}
+
+// syncPullCmd fetches remote events and merges them
+var syncPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Fetch and merge new breadcrumbs from the sync server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		remote, err := resolveSyncRemote(cmd)
+		if err != nil {
+			return err
+		}
+
+		cursorKey := "sync_pull:" + remote.URL
+		cursor, err := syncCursor(ctx, cursorKey)
+		if err != nil {
+			return err
+		}
+		received, added := 0, 0
+		for {
+			var batch models.SyncBatch
+			path := syncEventsPath + "?after=" + strconv.FormatInt(cursor, 10)
+			if err := syncRequest(ctx, remote, http.MethodGet, path, nil, &batch); err != nil {
+				return err
+			}
+			if len(batch.Events) == 0 {
+				break
+			}
+			if err := storeBatchBlobs(&batch); err != nil {
+				return fmt.Errorf("failed to store pulled blobs: %w", err)
+			}
+			result, err := stores.Sync.ApplySyncBatch(ctx, &batch)
+			if err != nil {
+				return fmt.Errorf("failed to merge pulled events: %w", err)
+			}
+			received += len(batch.Events)
+			added += result.Events
+			cursor = batch.Cursor
+			if err := stores.Sync.SetMeta(ctx, cursorKey, strconv.FormatInt(cursor, 10)); err != nil {
+				return fmt.Errorf("failed to save sync cursor: %w", err)
+			}
+			if !batch.More {
+				break
+			}
+		}
+
+		if outputText {
+			fmt.Printf("✓ Pulled %d events from %s (%d new)\n", received, remote.URL, added)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":   "pulled",
+				"remote":   remote.URL,
+				"received": received,
+				"new":      added,
+			})
+		}
+		return nil
+	},
+}
+
+// resolveSyncRemote combines --remote/--token, MEMORY_SYNC_TOKEN and config.json.
+// A remote given on the command line is saved for next time.
+func resolveSyncRemote(cmd *cobra.Command) (*config.SyncRemote, error) {
+	url, _ := cmd.Flags().GetString("remote")
+	token, _ := cmd.Flags().GetString("token")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	remote := &config.SyncRemote{}
+	if cfg.Sync != nil {
+		*remote = *cfg.Sync
+	}
+
+	if url != "" {
+		if !isURLScope(url) {
+			return nil, fmt.Errorf("remote must be an http(s) URL: %s", url)
+		}
+		remote.URL = strings.TrimSuffix(url, "/")
+		if token != "" {
+			remote.Token = token
+		}
+		cfg.Sync = remote
+		if err := cfg.Save(memoryDir()); err != nil {
+			return nil, fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	if remote.URL == "" {
+		return nil, fmt.Errorf("no sync remote configured; pass --remote https://...")
+	}
+
+	resolved := *remote
+	if token != "" {
+		resolved.Token = token
+	} else if env := os.Getenv("MEMORY_SYNC_TOKEN"); env != "" {
+		resolved.Token = env
+	}
+	if resolved.Token == "" {
+		return nil, fmt.Errorf("no sync token; pass --token or set MEMORY_SYNC_TOKEN")
+	}
+	return &resolved, nil
+}
+
+// syncCursor reads a saved sync position, 0 if none
+func syncCursor(ctx context.Context, key string) (int64, error) {
+	value, err := stores.Sync.GetMeta(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read sync cursor: %w", err)
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// syncRequest performs an authenticated request against the sync server and decodes
+// the JSON response into out
+func syncRequest(ctx context.Context, remote *config.SyncRemote, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, remote.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+remote.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: syncTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sync request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("sync server %s %s: %d %s", method, path, resp.StatusCode, apiErr.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func init() {
+	syncCmd.PersistentFlags().String("remote", "", "Sync server URL (remembered in config.json)")
+	syncCmd.PersistentFlags().String("token", "", "Sync server token")
+	syncCmd.AddCommand(syncPushCmd)
+	syncCmd.AddCommand(syncPullCmd)
+	rootCmd.AddCommand(syncCmd)
+}
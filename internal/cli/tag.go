@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// tagCmd adds tags to a breadcrumb
+var tagCmd = &cobra.Command{
+	Use:   "tag [id] [tag...]",
+	Short: "Tag a finding, unknown or dead end",
+	Long: `Add one or more tags to a finding, unknown or dead end. Tags are only ever added,
+so tags applied on different machines survive a merge.
+
+Examples:
+  memory tag 3f2a9c1e-... auth security`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		id := args[0]
+		var tags []string
+		for _, tag := range args[1:] {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if len(tags) == 0 {
+			return fmt.Errorf("no tags given")
+		}
+
+		repo := stores.Breadcrumbs
+		entityType, err := lookupBreadcrumb(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+		err = repo.TagBreadcrumb(ctx, entityType, id, tags)
+		if err != nil {
+			return fmt.Errorf("failed to tag %s: %w", entityType, err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Tagged %s %s: %s\n", entityType, id, strings.Join(tags, ", "))
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "tagged",
+				"type":   entityType,
+				"id":     id,
+				"tags":   tags,
+			})
+		}
+		return nil
+	},
+}
+
+// relateCmd links two breadcrumbs
+var relateCmd = &cobra.Command{
+	Use:   "relate [id] [target-id]",
+	Short: "Link a finding, unknown or dead end to another",
+	Long: `Record a relation from one breadcrumb to another. Like tags, relations are only
+ever added, so relations recorded on different machines survive a merge.
+
+Examples:
+  memory relate 3f2a9c1e-... 7b1d04aa-...
+  memory relate 3f2a9c1e-... 7b1d04aa-... --as supersedes`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		id, targetID := args[0], args[1]
+		kind, _ := cmd.Flags().GetString("as")
+		switch kind {
+		case models.RelationRelated, models.RelationSupersedes, models.RelationContradicts:
+		default:
+			return fmt.Errorf("invalid relation %q (use related, supersedes or contradicts)", kind)
+		}
+		if id == targetID {
+			return fmt.Errorf("cannot relate a breadcrumb to itself")
+		}
+
+		repo := stores.Breadcrumbs
+		entityType, err := lookupBreadcrumb(ctx, repo, id)
+		if err != nil {
+			return err
+		}
+		if _, err := lookupBreadcrumb(ctx, repo, targetID); err != nil {
+			return err
+		}
+		relation := models.BreadcrumbRelation{TargetID: targetID, Kind: kind}
+		err = repo.RelateBreadcrumb(ctx, entityType, id, relation)
+		if err != nil {
+			return fmt.Errorf("failed to relate %s: %w", entityType, err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ %s %s %s %s\n", entityType, id, kind, targetID)
+		} else {
+			outputResult(map[string]interface{}{
+				"status":   "related",
+				"type":     entityType,
+				"id":       id,
+				"relation": relation,
+			})
+		}
+		return nil
+	},
+}
+
+// lookupBreadcrumb returns the entity type of a breadcrumb ID, failing if there is none
+func lookupBreadcrumb(ctx context.Context, repo db.BreadcrumbStore, id string) (string, error) {
+	entityType, err := repo.BreadcrumbType(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		return "", fmt.Errorf("no finding, unknown or dead end with ID %s: %w", id, db.ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", id, err)
+	}
+	return entityType, nil
+}
+
+func init() {
+	relateCmd.Flags().String("as", models.RelationRelated, "Relation kind: related, supersedes or contradicts")
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(relateCmd)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// timelineCmd shows how an investigation unfolded
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Show findings, unknowns, dead ends and goal changes in time order",
+	Long: `Interleave everything recorded for the project in the order it happened: sessions
+starting and ending, findings logged and verified, unknowns opened and resolved, dead
+ends, deletions, tags, and goals and subtasks created, completed or updated.
+
+With --session, show that session's breadcrumbs and goals, plus verifications,
+resolutions and other changes made while it ran.
+
+Examples:
+  memory timeline --text
+  memory timeline --session <session-id> --text
+  memory timeline --since 2d --limit 50`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessionID, _ := cmd.Flags().GetString("session")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return fmt.Errorf("%w: --limit must not be negative", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		filter := db.TimelineFilter{ProjectID: project.ID, SessionID: sessionID}
+		if sinceFlag != "" {
+			window, err := parseWindow(sinceFlag)
+			if err != nil {
+				return err
+			}
+			filter.Since = float64(time.Now().Add(-window).UnixMilli()) / 1000.0
+		}
+
+		entries, err := stores.Timeline.List(ctx, filter, limit)
+		if err != nil {
+			return err
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"entries": entries,
+				"count":   len(entries),
+			})
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("Nothing recorded yet.")
+			return nil
+		}
+		lastDay := ""
+		for _, e := range entries {
+			t := time.UnixMilli(int64(e.Timestamp * 1000))
+			if day := t.Format("Mon Jan 2, 2006"); day != lastDay {
+				if lastDay != "" {
+					fmt.Println()
+				}
+				fmt.Println(day)
+				fmt.Println(strings.Repeat("─", 50))
+				lastDay = day
+			}
+			line := fmt.Sprintf("%s  %-26s %s", t.Format("15:04:05"), e.Kind, truncateText(e.Text, 80))
+			if e.Detail != "" {
+				line += " — " + truncateText(e.Detail, 60)
+			}
+			fmt.Println(strings.TrimRight(line, " "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	timelineCmd.Flags().String("session", "", "Only this session's breadcrumbs, goals and the changes made while it ran")
+	timelineCmd.Flags().String("since", "", "Only entries within this window, e.g. 7d, 2w or 36h")
+	timelineCmd.Flags().Int("limit", 200, "Show at most this many of the latest entries (0 for all)")
+	rootCmd.AddCommand(timelineCmd)
+}
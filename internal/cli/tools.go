@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// toolParam is one parameter of a tool: a positional argument or a flag of its command
+type toolParam struct {
+	name        string
+	kind        string // JSON schema type: string, number, integer, boolean or array (of strings)
+	description string
+	required    bool
+	flag        bool // Passed as --name rather than as the next positional argument
+}
+
+// toolDef describes a memory command as a callable tool. A call runs
+// memory <command> with the positional parameters in order, then the flags.
+type toolDef struct {
+	command     string
+	description string
+	params      []toolParam
+}
+
+// coreTools are the commands exported as tools, in the order an agent uses them
+var coreTools = []toolDef{
+	{
+		command:     "start",
+		description: "Start a work session on an objective. Returns decision guidance, fresh knowledge, findings to verify, dead ends not to repeat and open questions for the project. Call before working on a task.",
+		params: []toolParam{
+			{name: "objective", kind: "string", description: "What this session is trying to achieve", required: true},
+			{name: "goal", kind: "string", description: "Goal ID to continue; scopes the context to its breadcrumbs", flag: true},
+			{name: "inherit", kind: "boolean", description: "Include parent-project knowledge when in a sub-project", flag: true},
+		},
+	},
+	{
+		command:     "learned",
+		description: "Record a finding: something learned about the codebase or task that later sessions should know.",
+		params: []toolParam{
+			{name: "insight", kind: "string", description: "The finding, as a self-contained statement", required: true},
+			{name: "scope", kind: "string", description: "File, directory or URL the finding is about", flag: true},
+			{name: "impact", kind: "number", description: "How much this matters, from trivial (0.1) to critical (1.0); default 0.5", flag: true},
+			{name: "check", kind: "string", description: "Shell command whose exit status verifies the finding", flag: true},
+		},
+	},
+	{
+		command:     "uncertain",
+		description: "Record an open question or unknown that blocks confidence in the work.",
+		params: []toolParam{
+			{name: "question", kind: "string", description: "The open question", required: true},
+			{name: "scope", kind: "string", description: "File or directory the question is about", flag: true},
+			{name: "impact", kind: "number", description: "How much this matters, from trivial (0.1) to critical (1.0); default 0.5", flag: true},
+		},
+	},
+	{
+		command:     "tried",
+		description: "Record a dead end: an approach that failed and why, so it is not repeated.",
+		params: []toolParam{
+			{name: "approach", kind: "string", description: "The approach that was tried", required: true},
+			{name: "why_failed", kind: "string", description: "Why it failed", required: true},
+			{name: "impact", kind: "number", description: "How much this matters, from trivial (0.1) to critical (1.0); default 0.5", flag: true},
+		},
+	},
+	{
+		command:     "verify",
+		description: "Confirm a stale finding is still true, refreshing its confidence, optionally correcting its text.",
+		params: []toolParam{
+			{name: "search_text", kind: "string", description: "Text of the finding to verify; use id instead when known"},
+			{name: "id", kind: "string", description: "ID of the finding to verify", flag: true},
+			{name: "update", kind: "string", description: "Corrected text for the finding", flag: true},
+		},
+	},
+	{
+		command:     "query",
+		description: "Search recorded findings, and with the flags unknowns and dead ends, of the current project.",
+		params: []toolParam{
+			{name: "search", kind: "string", description: "Text to search for; omit to list recent entries"},
+			{name: "all", kind: "boolean", description: "Search findings, unknowns and dead ends", flag: true},
+			{name: "unknowns", kind: "boolean", description: "Search open questions", flag: true},
+			{name: "dead_ends", kind: "boolean", description: "Search dead ends", flag: true},
+			{name: "fuzzy", kind: "boolean", description: "Match approximately instead of by substring", flag: true},
+			{name: "limit", kind: "integer", description: "Maximum number of results; default 50", flag: true},
+		},
+	},
+	{
+		command:     "done",
+		description: "End the session with a summary, writing a handoff the next session starts from.",
+		params: []toolParam{
+			{name: "summary", kind: "string", description: "What the session accomplished", required: true},
+			{name: "next", kind: "array", description: "Recommendations for the next session", flag: true},
+		},
+	},
+}
+
+// toolName is a tool's name in function calling, e.g. memory_learned
+func (t toolDef) toolName() string {
+	return "memory_" + t.command
+}
+
+// inputSchema returns the JSON schema of the tool's parameters
+func (t toolDef) inputSchema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, p := range t.params {
+		prop := map[string]interface{}{
+			"type":        p.kind,
+			"description": p.description,
+		}
+		if p.kind == "array" {
+			prop["items"] = map[string]interface{}{"type": "string"}
+		}
+		properties[p.name] = prop
+		if p.required {
+			required = append(required, p.name)
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// toolSpecFormats render the core tools in each provider's function calling format
+var toolSpecFormats = map[string]func(t toolDef) map[string]interface{}{
+	"openai": func(t toolDef) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "function",
+			"function": map[string]interface{}{
+				"name":        t.toolName(),
+				"description": t.description,
+				"parameters":  t.inputSchema(),
+			},
+		}
+	},
+	"anthropic": func(t toolDef) map[string]interface{} {
+		return map[string]interface{}{
+			"name":         t.toolName(),
+			"description":  t.description,
+			"input_schema": t.inputSchema(),
+		}
+	},
+}
+
+// toolsCmd groups commands exposing memory to function calling
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Describe memory's commands as tools for function calling",
+}
+
+// toolsSpecCmd prints the JSON schemas of the core commands
+var toolsSpecCmd = &cobra.Command{
+	Use:   "spec",
+	Short: "Print tool definitions for the core commands",
+	Long: `Print the core commands (start, learned, uncertain, tried, verify, query and done) as
+tool definitions in OpenAI or Anthropic function calling format, so an orchestrator can
+register memory as callable tools.
+
+A call to memory_<command> runs memory <command> with the parameters that are
+positional arguments in the order listed (objective, insight, question, approach and
+why_failed, search_text, search, summary), and the others as flags: name becomes --name
+with underscores as dashes, booleans are passed only when true and each item of an
+array is a repeated flag.
+
+Examples:
+  memory tools spec --format openai > memory-tools.json
+  memory tools spec --format anthropic`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		render, ok := toolSpecFormats[format]
+		if !ok {
+			return fmt.Errorf("%w format %q (use openai or anthropic)", db.ErrInvalid, format)
+		}
+		tools := make([]map[string]interface{}, 0, len(coreTools))
+		for _, t := range coreTools {
+			tools = append(tools, render(t))
+		}
+		// JSON even with --text: the spec is meant to be loaded by a program
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tools)
+	},
+}
+
+func init() {
+	toolsSpecCmd.Flags().String("format", "openai", "Tool definition format: openai or anthropic")
+	toolsCmd.AddCommand(toolsSpecCmd)
+	rootCmd.AddCommand(toolsCmd)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName names the spans the CLI records
+const tracerName = "github.com/AbdouB/memory/internal/cli"
+
+// tracingShutdownTimeout bounds how long exiting waits to export the last spans
+const tracingShutdownTimeout = 5 * time.Second
+
+var (
+	tracerProvider *sdktrace.TracerProvider // nil unless an OTLP endpoint is configured
+	commandSpan    trace.Span               // Span of the running command, ended when it finishes
+)
+
+// tracer returns the CLI's tracer; spans are dropped unless tracing is set up
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// setupTracing exports spans over OTLP/HTTP when the standard OpenTelemetry
+// environment names an endpoint (OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT). Headers, protocol details and the service name
+// come from the usual OTEL_* variables too.
+func setupTracing(ctx context.Context) error {
+	if tracerProvider != nil || strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return nil
+	}
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return err
+	}
+	// Later options win, so OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the default name
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", "memory")),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return err
+	}
+	tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return nil
+}
+
+// shutdownTracing exports the spans still buffered
+func shutdownTracing() {
+	if tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		slog.Warn("failed to export traces", "err", err)
+	}
+	tracerProvider = nil
+}
+
+// startCommandSpan opens the span covering a command. A TRACEPARENT in the environment,
+// set by the agent pipeline calling memory, makes it a child of the caller's span.
+// Returns the command's context, now carrying the span.
+func startCommandSpan(cmd *cobra.Command) context.Context {
+	ctx := cmd.Context()
+	if parent := os.Getenv("TRACEPARENT"); parent != "" {
+		ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{
+			"traceparent": parent,
+			"tracestate":  os.Getenv("TRACESTATE"),
+		})
+	}
+	ctx, commandSpan = tracer().Start(ctx, cmd.CommandPath(), trace.WithAttributes(
+		attribute.String("memory.command", cmd.CommandPath()),
+	))
+	cmd.SetContext(ctx)
+	return ctx
+}
+
+// endCommandSpan closes the command's span, marking it failed when the command failed
+func endCommandSpan(err error) {
+	if commandSpan == nil {
+		return
+	}
+	if err != nil {
+		commandSpan.RecordError(err)
+		commandSpan.SetStatus(codes.Error, err.Error())
+	}
+	commandSpan.End()
+	commandSpan = nil
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// sparkTicks are the bar heights a sparkline draws values from 0 to 1 with
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// TrendPoint is a session's latest epistemic snapshot
+type TrendPoint struct {
+	SessionID string `json:"session_id"`
+	Date      string `json:"date"`
+	Phase     string `json:"phase"`
+	models.EpistemicSnapshot
+}
+
+// trendVectors names the vectors a trend shows, in display order
+var trendVectors = []struct {
+	name  string
+	value func(*models.EpistemicSnapshot) float64
+}{
+	{"know", func(s *models.EpistemicSnapshot) float64 { return s.Know }},
+	{"uncertainty", func(s *models.EpistemicSnapshot) float64 { return s.Uncertainty }},
+	{"clarity", func(s *models.EpistemicSnapshot) float64 { return s.Clarity }},
+	{"coherence", func(s *models.EpistemicSnapshot) float64 { return s.Coherence }},
+	{"completion", func(s *models.EpistemicSnapshot) float64 { return s.Completion }},
+	{"engagement", func(s *models.EpistemicSnapshot) float64 { return s.Engagement }},
+	{"overall", func(s *models.EpistemicSnapshot) float64 { return s.Overall }},
+}
+
+// recordEpistemicSnapshot stores the vectors computed for a session so trends can be
+// drawn later. Failures only warn; the snapshot is never worth failing a command over.
+func recordEpistemicSnapshot(ctx context.Context, sessionID string, phase models.CASCADEPhase, snapshot *models.EpistemicSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	if err := stores.Reflexes.Create(ctx, models.NewSnapshotReflex(sessionID, phase, snapshot)); err != nil {
+		slog.Warn("failed to record epistemic snapshot", "session", sessionID, "err", err)
+	}
+}
+
+// defaultBaseline is the state sessions are measured from when start recorded none
+var defaultBaseline = models.EpistemicSnapshot{
+	Know: 0.5, Uncertainty: 0.5, Clarity: 0.5, Coherence: 0.5, Completion: 0.5, Engagement: 0.5, Overall: 0.5,
+}
+
+// sessionBaseline returns the preflight snapshot start recorded for a session, or the
+// default baseline for sessions started before snapshots were kept
+func sessionBaseline(ctx context.Context, sessionID string) (*models.EpistemicSnapshot, bool) {
+	reflex, err := stores.Reflexes.GetLatestByPhase(ctx, sessionID, string(models.PhasePreflight))
+	if err != nil {
+		baseline := defaultBaseline
+		return &baseline, false
+	}
+	snapshot := snapshotFromReflex(reflex)
+	return &snapshot, true
+}
+
+// vectorDeltas returns how far each vector moved from one snapshot to another
+func vectorDeltas(from, to *models.EpistemicSnapshot) map[string]float64 {
+	deltas := make(map[string]float64, len(trendVectors))
+	for _, vec := range trendVectors {
+		deltas[vec.name] = math.Round((vec.value(to)-vec.value(from))*100) / 100
+	}
+	return deltas
+}
+
+// snapshotFromReflex reads the vectors back out of a stored reflex
+func snapshotFromReflex(r *models.Reflex) models.EpistemicSnapshot {
+	value := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	return models.EpistemicSnapshot{
+		Know:        value(r.Know),
+		Uncertainty: value(r.Uncertainty),
+		Clarity:     value(r.Clarity),
+		Coherence:   value(r.Coherence),
+		Completion:  value(r.Completion),
+		Engagement:  value(r.Engagement),
+		Overall:     value(r.Confidence),
+	}
+}
+
+// sparkline draws values between 0 and 1 as a row of bars
+func sparkline(values []float64) string {
+	var b strings.Builder
+	top := len(sparkTicks) - 1
+	for _, v := range values {
+		i := int(v*float64(top) + 0.5)
+		if i < 0 {
+			i = 0
+		} else if i > top {
+			i = top
+		}
+		b.WriteRune(sparkTicks[i])
+	}
+	return b.String()
+}
+
+// trendCmd shows how epistemic vectors moved across recent sessions
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show epistemic vectors across recent sessions",
+	Long: `Show how each epistemic vector moved over the project's last sessions. Start, status
+and done record a snapshot of the vectors each time they compute them; each session
+contributes its latest one, so a finished session shows its state at done.
+
+Examples:
+  memory trend --text
+  memory trend --sessions 30`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessions, _ := cmd.Flags().GetInt("sessions")
+		if sessions <= 0 {
+			return fmt.Errorf("%w: --sessions must be positive", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		reflexes, err := stores.Reflexes.ListLatestPerSession(ctx, project.ID, sessions)
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots: %w", err)
+		}
+
+		// Snapshots come newest first; the series reads oldest first
+		series := make([]TrendPoint, 0, len(reflexes))
+		for i := len(reflexes) - 1; i >= 0; i-- {
+			r := reflexes[i]
+			series = append(series, TrendPoint{
+				SessionID:         r.SessionID,
+				Date:              time.UnixMilli(int64(r.Timestamp * 1000)).Format("2006-01-02"),
+				Phase:             r.Phase,
+				EpistemicSnapshot: snapshotFromReflex(r),
+			})
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"project": project.Name,
+				"series":  series,
+				"count":   len(series),
+			})
+			return nil
+		}
+
+		if len(series) == 0 {
+			fmt.Println("No snapshots yet. Start, status and done record one each time they run.")
+			return nil
+		}
+		fmt.Printf("Project %s: %d session(s), %s to %s\n", project.Name, len(series), series[0].Date, series[len(series)-1].Date)
+		fmt.Println(strings.Repeat("─", 50))
+		for _, vec := range trendVectors {
+			values := make([]float64, len(series))
+			for i := range series {
+				values[i] = vec.value(&series[i].EpistemicSnapshot)
+			}
+			first, last := values[0], values[len(values)-1]
+			fmt.Printf("  %-12s %s  %3.0f%% → %3.0f%%\n", vec.name, sparkline(values), first*100, last*100)
+		}
+		return nil
+	},
+}
+
+// HistoryEntry is one recorded epistemic snapshot
+type HistoryEntry struct {
+	SessionID string  `json:"session_id"`
+	Timestamp float64 `json:"timestamp"`
+	Phase     string  `json:"phase"`
+	models.EpistemicSnapshot
+}
+
+// trendHistoryCmd lists every recorded epistemic snapshot
+var trendHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recorded epistemic snapshots",
+	Long: `List every epistemic state start (PREFLIGHT), status (CHECK) and done (POSTFLIGHT)
+recorded, newest first, for charting confidence over time or analysing drift within a
+session.
+
+Examples:
+  memory trend history --since 7d
+  memory trend history --session <session-id> --text`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		sessionID, _ := cmd.Flags().GetString("session")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 {
+			return fmt.Errorf("%w: --limit must be positive", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		filter := db.ReflexFilter{ProjectID: project.ID, SessionID: sessionID}
+		if sinceFlag != "" {
+			window, err := parseWindow(sinceFlag)
+			if err != nil {
+				return err
+			}
+			filter.Since = float64(time.Now().Add(-window).UnixMilli()) / 1000.0
+		}
+
+		reflexes, err := stores.Reflexes.ListHistory(ctx, filter, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		entries := make([]HistoryEntry, 0, len(reflexes))
+		for _, r := range reflexes {
+			entries = append(entries, HistoryEntry{
+				SessionID:         r.SessionID,
+				Timestamp:         r.Timestamp,
+				Phase:             r.Phase,
+				EpistemicSnapshot: snapshotFromReflex(r),
+			})
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"snapshots": entries,
+				"count":     len(entries),
+			})
+			return nil
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No snapshots recorded.")
+			return nil
+		}
+		fmt.Printf("%-16s  %-8s  %-10s  %5s %5s %5s %5s %5s %5s  %s\n",
+			"Time", "Session", "Phase", "Know", "Unc", "Clar", "Coh", "Comp", "Eng", "Overall")
+		for _, e := range entries {
+			id := e.SessionID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			fmt.Printf("%-16s  %-8s  %-10s  %4.0f%% %4.0f%% %4.0f%% %4.0f%% %4.0f%% %4.0f%%  %.0f%%\n",
+				time.UnixMilli(int64(e.Timestamp*1000)).Format("2006-01-02 15:04"), id, e.Phase,
+				e.Know*100, e.Uncertainty*100, e.Clarity*100, e.Coherence*100, e.Completion*100, e.Engagement*100, e.Overall*100)
+		}
+		return nil
+	},
+}
+
+func init() {
+	trendCmd.Flags().Int("sessions", 20, "Number of recent sessions to include")
+	trendHistoryCmd.Flags().String("session", "", "Only snapshots of this session")
+	trendHistoryCmd.Flags().String("since", "", "Only snapshots within this window, e.g. 7d, 2w or 36h")
+	trendHistoryCmd.Flags().Int("limit", 100, "Maximum number of snapshots")
+	trendCmd.AddCommand(trendHistoryCmd)
+	rootCmd.AddCommand(trendCmd)
+}
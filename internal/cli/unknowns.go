@@ -0,0 +1,568 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/AbdouB/memory/internal/db"
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// Open unknown ranking: impact dominates, long-open questions gain up to ageWeight, and
+// questions about files being worked on gain scopeWeight
+const (
+	unknownAgeWeight   = 0.25
+	unknownAgeHorizon  = 30 * 24 * time.Hour // Age at which the age bonus is full
+	unknownScopeWeight = 0.3
+	unknownRankWindow  = 50 // Open unknowns considered when picking the top ones for context
+
+	// Aging questions at least this impactful are escalated rather than closed
+	unknownEscalateImpact = 0.5
+)
+
+// RankedUnknown is an open unknown with its priority
+type RankedUnknown struct {
+	*models.Unknown
+	Priority float64 `json:"priority"`
+	Relevant bool    `json:"relevant,omitempty"` // Scoped to a file being worked on or named in the objective
+	AgeDays  int     `json:"age_days"`
+}
+
+// unknownRelevance reports whether unknowns are scoped to the current work: files changed
+// in the working tree, or files and directories the objective names
+type unknownRelevance struct {
+	changed   []string
+	objective string
+	repoRoot  string
+}
+
+// newUnknownRelevance collects the working tree changes once for ranking a batch
+func newUnknownRelevance(ctx context.Context, objective string) *unknownRelevance {
+	r := &unknownRelevance{objective: strings.ToLower(objective)}
+	r.repoRoot, _ = gitRepoRoot(ctx)
+	r.changed, _ = workingTreeChanges(ctx)
+	return r
+}
+
+// relevant reports whether an unknown's scope touches the current work
+func (r *unknownRelevance) relevant(u *models.Unknown) bool {
+	if u.Subject == nil || *u.Subject == "" {
+		return false
+	}
+	scope := normalizeScopePath(*u.Subject, r.repoRoot)
+	for _, changed := range r.changed {
+		if changed == scope || strings.HasPrefix(changed, scope+"/") {
+			return true
+		}
+	}
+	base := strings.ToLower(path.Base(scope))
+	return r.objective != "" && base != "." && strings.Contains(r.objective, base)
+}
+
+// rankUnknowns orders open unknowns by priority, highest first
+func rankUnknowns(unknowns []*models.Unknown, relevance *unknownRelevance) []*RankedUnknown {
+	ranked := make([]*RankedUnknown, 0, len(unknowns))
+	for _, u := range unknowns {
+		age := time.Since(time.UnixMilli(int64(u.CreatedTimestamp * 1000)))
+		priority := u.Impact + unknownAgeWeight*math.Min(float64(age)/float64(unknownAgeHorizon), 1)
+		relevant := relevance != nil && relevance.relevant(u)
+		if relevant {
+			priority += unknownScopeWeight
+		}
+		ranked = append(ranked, &RankedUnknown{
+			Unknown:  u,
+			Priority: math.Round(priority*100) / 100,
+			Relevant: relevant,
+			AgeDays:  int(age.Hours() / 24),
+		})
+	}
+	// Newest first among equals, as the unknowns were listed
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Priority > ranked[j].Priority })
+	return ranked
+}
+
+// topUnknowns keeps the limit highest-priority open unknowns
+func topUnknowns(ctx context.Context, unknowns []*models.Unknown, relevance *unknownRelevance, limit int) []*models.Unknown {
+	ranked := rankUnknowns(unknowns, relevance)
+	for i, r := range ranked {
+		msg := "included question"
+		if i >= limit {
+			msg = "left out lower-priority question"
+		}
+		traceContext(ctx, msg, "id", r.ID, "rank", i+1, "priority", r.Priority, "relevant", r.Relevant, "age_days", r.AgeDays)
+	}
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	top := make([]*models.Unknown, len(ranked))
+	for i, r := range ranked {
+		top[i] = r.Unknown
+	}
+	return top
+}
+
+// agingUnknowns lists the project's questions open longer than the configured threshold,
+// oldest first, each with a recommendation to escalate or close it
+func agingUnknowns(ctx context.Context, projectID string) []models.AgingUnknown {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	threshold := cfg.Unknowns.AgingThreshold()
+	resolved := false
+	unknowns, _ := stores.Breadcrumbs.ListUnknowns(ctx, projectID, "", &resolved, 1000)
+
+	aging := []models.AgingUnknown{}
+	// Listed newest first; walk backwards for oldest first
+	for i := len(unknowns) - 1; i >= 0; i-- {
+		u := unknowns[i]
+		age := time.Since(time.UnixMilli(int64(u.CreatedTimestamp * 1000)))
+		if age < threshold {
+			break
+		}
+		a := models.AgingUnknown{
+			ID:             u.ID,
+			Unknown:        u.Unknown,
+			AgeDays:        int(age.Hours() / 24),
+			Impact:         u.Impact,
+			Recommendation: models.AgingClose,
+			Command:        fmt.Sprintf("memory unknowns triage %s --close", u.ID[:8]),
+		}
+		if u.Impact >= unknownEscalateImpact {
+			a.Recommendation = models.AgingEscalate
+			// Escalating makes the question planned work instead of background noise
+			a.Command = fmt.Sprintf("memory goal add %q", "Answer: "+truncateText(u.Unknown, 80))
+		}
+		aging = append(aging, a)
+	}
+	return aging
+}
+
+// printAgingUnknowns prints aging questions with what to do about each
+func printAgingUnknowns(aging []models.AgingUnknown) {
+	if len(aging) == 0 {
+		return
+	}
+	fmt.Printf("\n⏳ OPEN TOO LONG (%d) — escalate or close:\n", len(aging))
+	for _, a := range aging {
+		fmt.Printf("  • %s (%dd, %s)\n", a.Unknown, a.AgeDays, a.Recommendation)
+		fmt.Printf("    %s\n", a.Command)
+	}
+}
+
+// matchUnknowns resolves IDs or ID prefixes against the open unknowns
+func matchUnknowns(unknowns []*models.Unknown, ids []string) ([]*models.Unknown, error) {
+	var matched []*models.Unknown
+	for _, id := range ids {
+		var match *models.Unknown
+		for _, u := range unknowns {
+			if !strings.HasPrefix(u.ID, id) {
+				continue
+			}
+			if match != nil {
+				return nil, fmt.Errorf("%w: unknown ID %q is ambiguous", db.ErrInvalid, id)
+			}
+			match = u
+		}
+		if match == nil {
+			return nil, fmt.Errorf("open unknown %s: %w", id, db.ErrNotFound)
+		}
+		matched = append(matched, match)
+	}
+	return matched, nil
+}
+
+// assignmentTags builds the tags that assign an unknown to the next session or to
+// sessions working on an objective
+func assignmentTags(nextSession bool, objective string) []string {
+	var tags []string
+	if nextSession {
+		tags = append(tags, models.TagNextSession)
+	}
+	if objective = strings.ToLower(strings.TrimSpace(objective)); objective != "" {
+		tags = append(tags, models.TagObjectivePrefix+objective)
+	}
+	return tags
+}
+
+// unknownAssignment reports who an unknown is assigned to in a session with the given
+// objective: "next-session", the matching objective, or "" when it is not assigned. An
+// objective assignment matches when either objective contains the other.
+func unknownAssignment(u *models.Unknown, objective string) string {
+	objective = strings.ToLower(objective)
+	for _, tag := range u.Tags {
+		if tag == models.TagNextSession {
+			return models.TagNextSession
+		}
+	}
+	for _, tag := range u.Tags {
+		assigned, ok := strings.CutPrefix(tag, models.TagObjectivePrefix)
+		if ok && objective != "" && (strings.Contains(objective, assigned) || strings.Contains(assigned, objective)) {
+			return assigned
+		}
+	}
+	return ""
+}
+
+// assignedUnknowns lists the open unknowns of the projects assigned to a session with
+// the given objective, highest impact first
+func assignedUnknowns(ctx context.Context, projectIDs []string, objective string) ([]*models.Unknown, []models.AssignedQuestion) {
+	var unknowns []*models.Unknown
+	var assigned []models.AssignedQuestion
+	resolved := false
+	for _, projectID := range projectIDs {
+		open, _ := stores.Breadcrumbs.ListUnknowns(ctx, projectID, "", &resolved, 1000)
+		for _, u := range open {
+			to := unknownAssignment(u, objective)
+			if to == "" {
+				continue
+			}
+			unknowns = append(unknowns, u)
+			assigned = append(assigned, models.AssignedQuestion{ID: u.ID, Unknown: u.Unknown, Impact: u.Impact, AssignedTo: to})
+		}
+	}
+	sort.SliceStable(assigned, func(i, j int) bool { return assigned[i].Impact > assigned[j].Impact })
+	return unknowns, assigned
+}
+
+// printAssignedQuestions prints the questions handed to this session
+func printAssignedQuestions(assigned []models.AssignedQuestion) {
+	if len(assigned) == 0 {
+		return
+	}
+	fmt.Printf("\n! ASSIGNED TO THIS SESSION (%d):\n", len(assigned))
+	for _, a := range assigned {
+		fmt.Printf("  • %s (%s)\n", a.Unknown, a.AssignedTo)
+	}
+}
+
+// unknownsCmd groups commands for open questions
+var unknownsCmd = &cobra.Command{
+	Use:   "unknowns",
+	Short: "Rank and triage open questions",
+}
+
+// unknownsListCmd lists open unknowns by priority
+var unknownsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List open questions by priority",
+	Long: `List open questions ranked by priority: impact, plus a bonus for questions open a
+long time and for questions scoped to files changed in the working tree or named in
+the active session's objective. start and status show the top ones.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		limit, _ := cmd.Flags().GetInt("limit")
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		objective := ""
+		if active, err := loadActiveSession(ctx); err == nil {
+			objective = active.Objective
+		}
+
+		resolved := false
+		unknowns, err := stores.Breadcrumbs.ListUnknowns(ctx, project.ID, "", &resolved, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to list unknowns: %w", err)
+		}
+		ranked := rankUnknowns(unknowns, newUnknownRelevance(ctx, objective))
+		total := len(ranked)
+		if limit > 0 && len(ranked) > limit {
+			ranked = ranked[:limit]
+		}
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"unknowns": ranked,
+				"count":    len(ranked),
+				"total":    total,
+			})
+			return nil
+		}
+		if len(ranked) == 0 {
+			fmt.Println("No open questions.")
+			return nil
+		}
+		for _, r := range ranked {
+			mark := " "
+			if r.Relevant {
+				mark = "◆"
+			}
+			fmt.Printf("%s %.2f  %s  %3dd  %s\n", mark, r.Priority, r.ID[:8], r.AgeDays, r.Unknown.Unknown)
+		}
+		if total > len(ranked) {
+			fmt.Printf("\n%d more; use --limit 0 to list all\n", total-len(ranked))
+		}
+		return nil
+	},
+}
+
+// unknownsTriageCmd bulk-adjusts impact or closes obsolete questions
+var unknownsTriageCmd = &cobra.Command{
+	Use:   "triage [id...]",
+	Short: "Bulk-adjust impact or close obsolete questions",
+	Long: `Select open questions by ID (or ID prefix), by age with --older-than, or by impact with
+--below, then set their impact with --impact or close them with --close. Closing is a
+soft delete, undone with 'memory forget <id> --restore'.
+
+Examples:
+  memory unknowns triage 3f2a9c1e 7b0d4e22 --impact 0.9
+  memory unknowns triage --older-than 60d --below 0.3 --close --reason "Superseded by v2 design"
+  memory unknowns triage --older-than 30d --impact 0.2 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		flags := cmd.Flags()
+		olderThan, _ := flags.GetString("older-than")
+		below, _ := flags.GetFloat64("below")
+		impact, _ := flags.GetFloat64("impact")
+		closeSelected, _ := flags.GetBool("close")
+		reason, _ := flags.GetString("reason")
+		dryRun, _ := flags.GetBool("dry-run")
+
+		setImpact := flags.Changed("impact")
+		if setImpact == closeSelected {
+			return fmt.Errorf("%w: give either --impact or --close", db.ErrInvalid)
+		}
+		if setImpact && (impact < 0 || impact > 1) {
+			return fmt.Errorf("%w: impact must be between 0 and 1", db.ErrInvalid)
+		}
+		if len(args) == 0 && olderThan == "" && !flags.Changed("below") {
+			return fmt.Errorf("%w: select questions by ID, --older-than or --below", db.ErrInvalid)
+		}
+		if reason == "" {
+			reason = "obsolete"
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		resolved := false
+		selected, err := stores.Breadcrumbs.ListUnknowns(ctx, project.ID, "", &resolved, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to list unknowns: %w", err)
+		}
+		if len(args) > 0 {
+			if selected, err = matchUnknowns(selected, args); err != nil {
+				return err
+			}
+		}
+		if olderThan != "" {
+			window, err := parseWindow(olderThan)
+			if err != nil {
+				return err
+			}
+			cutoff := float64(time.Now().Add(-window).UnixMilli()) / 1000.0
+			kept := selected[:0]
+			for _, u := range selected {
+				if u.CreatedTimestamp < cutoff {
+					kept = append(kept, u)
+				}
+			}
+			selected = kept
+		}
+		if flags.Changed("below") {
+			kept := selected[:0]
+			for _, u := range selected {
+				if u.Impact < below {
+					kept = append(kept, u)
+				}
+			}
+			selected = kept
+		}
+
+		if !dryRun {
+			for _, u := range selected {
+				if setImpact {
+					err = stores.Breadcrumbs.SetUnknownImpact(ctx, u.ID, impact)
+				} else {
+					err = stores.Breadcrumbs.DeleteBreadcrumb(ctx, models.EntityUnknown, u.ID, reason)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to triage unknown %s: %w", u.ID, err)
+				}
+			}
+		}
+
+		action := "closed"
+		if setImpact {
+			action = "reprioritized"
+		}
+		if !outputText {
+			ids := make([]string, 0, len(selected))
+			for _, u := range selected {
+				ids = append(ids, u.ID)
+			}
+			result := map[string]interface{}{
+				"status":  action,
+				"ids":     ids,
+				"count":   len(ids),
+				"dry_run": dryRun,
+			}
+			if setImpact {
+				result["impact"] = impact
+			}
+			outputResult(result)
+			return nil
+		}
+
+		verb := "✓"
+		if dryRun {
+			verb = "Would have"
+		}
+		if setImpact {
+			fmt.Printf("%s set impact %g on %d question(s)\n", verb, impact, len(selected))
+		} else {
+			fmt.Printf("%s closed %d question(s) as %q\n", verb, len(selected), reason)
+		}
+		for _, u := range selected {
+			fmt.Printf("  %s  %s\n", u.ID[:8], u.Unknown)
+		}
+		return nil
+	},
+}
+
+// unknownsAssignCmd assigns open unknowns to the next session or to an objective
+var unknownsAssignCmd = &cobra.Command{
+	Use:   "assign [id...]",
+	Short: "Assign questions to the next session or to an objective",
+	Long: `Assign open questions to a future session. Questions assigned with --next-session
+are carried into the handoff by 'memory done' and shown at the top of the next start.
+Questions assigned with --objective are shown at the top of any session whose objective
+matches. Either stays assigned until the question is answered or closed.
+
+Examples:
+  memory unknowns assign 3f2a9c1e --next-session
+  memory unknowns assign 3f2a9c1e 7b0d4e22 --objective "token refresh"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		nextSession, _ := cmd.Flags().GetBool("next-session")
+		objective, _ := cmd.Flags().GetString("objective")
+		tags := assignmentTags(nextSession, objective)
+		if len(tags) == 0 {
+			return fmt.Errorf("%w: give --next-session or --objective", db.ErrInvalid)
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		resolved := false
+		open, err := stores.Breadcrumbs.ListUnknowns(ctx, project.ID, "", &resolved, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to list unknowns: %w", err)
+		}
+		matched, err := matchUnknowns(open, args)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(matched))
+		for _, u := range matched {
+			if err := stores.Breadcrumbs.TagBreadcrumb(ctx, models.EntityUnknown, u.ID, tags); err != nil {
+				return fmt.Errorf("failed to assign unknown %s: %w", u.ID, err)
+			}
+			ids = append(ids, u.ID)
+		}
+
+		if outputText {
+			for _, u := range matched {
+				fmt.Printf("✓ Assigned: %s (%s)\n", u.Unknown, strings.Join(tags, ", "))
+			}
+			return nil
+		}
+		outputResult(map[string]interface{}{
+			"status": "assigned",
+			"ids":    ids,
+			"tags":   tags,
+			"count":  len(ids),
+		})
+		return nil
+	},
+}
+
+// unknownsAgingCmd lists aging questions and sets the aging threshold
+var unknownsAgingCmd = &cobra.Command{
+	Use:   "aging",
+	Short: "List questions open too long",
+	Long: `List questions open longer than the aging threshold, 14 days unless set with --days.
+start, status and digest flag them too, recommending to escalate those with impact of
+0.5 or more into a goal and to close the rest.
+
+Examples:
+  memory unknowns aging --text
+  memory unknowns aging --days 30`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		if cmd.Flags().Changed("days") {
+			days, _ := cmd.Flags().GetInt("days")
+			if days < 1 {
+				return fmt.Errorf("%w: --days must be at least 1", db.ErrInvalid)
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			cfg.Unknowns = &config.UnknownsConfig{AgingDays: days}
+			if days == config.DefaultUnknownAgingDays {
+				cfg.Unknowns = nil
+			}
+			if err := cfg.Save(memoryDir()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		project, err := getOrCreateDefaultProject(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get project: %w", err)
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		threshold := cfg.Unknowns.AgingThreshold()
+		aging := agingUnknowns(ctx, project.ID)
+
+		if !outputText {
+			outputResult(map[string]interface{}{
+				"threshold_days": int(threshold.Hours() / 24),
+				"aging_unknowns": aging,
+				"count":          len(aging),
+			})
+			return nil
+		}
+		if len(aging) == 0 {
+			fmt.Printf("No questions open longer than %dd.\n", int(threshold.Hours()/24))
+			return nil
+		}
+		printAgingUnknowns(aging)
+		return nil
+	},
+}
+
+func init() {
+	unknownsAgingCmd.Flags().Int("days", 0, "Set the aging threshold in days")
+	unknownsCmd.AddCommand(unknownsAgingCmd)
+	unknownsListCmd.Flags().Int("limit", 20, "Maximum number of questions (0 for all)")
+	unknownsTriageCmd.Flags().String("older-than", "", "Select questions open longer than this, e.g. 30d")
+	unknownsTriageCmd.Flags().Float64("below", 0, "Select questions with impact below this")
+	unknownsTriageCmd.Flags().Float64("impact", 0, "Set the selected questions' impact (0.0-1.0)")
+	unknownsTriageCmd.Flags().Bool("close", false, "Close the selected questions as obsolete")
+	unknownsTriageCmd.Flags().String("reason", "", "Why the questions are closed (default \"obsolete\")")
+	unknownsTriageCmd.Flags().Bool("dry-run", false, "List the selected questions without changing them")
+	unknownsAssignCmd.Flags().Bool("next-session", false, "Assign to the next session")
+	unknownsAssignCmd.Flags().String("objective", "", "Assign to sessions whose objective matches")
+	unknownsCmd.AddCommand(unknownsListCmd, unknownsTriageCmd, unknownsAssignCmd)
+	rootCmd.AddCommand(unknownsCmd)
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// webhookCmd groups webhook configuration commands
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Configure event webhooks",
+	Long: `Configure HTTP webhooks fired when memory events occur. Each delivery is a POST
+with the JSON payload the command prints, wrapped in {event, timestamp, project_id, data}.
+
+Events: ` + strings.Join(knownEvents, ", ") + `
+
+Webhooks are stored in config.json next to the database. With --secret, payloads
+are signed in the X-Memory-Signature header (sha256=<hex HMAC>).
+
+Examples:
+  memory webhook add https://example.com/hook
+  memory webhook add https://example.com/hook --event finding_logged --event session_done
+  memory webhook list
+  memory webhook remove https://example.com/hook`,
+}
+
+// webhookAddCmd registers a webhook
+var webhookAddCmd = &cobra.Command{
+	Use:   "add [url]",
+	Short: "Add a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := args[0]
+		events, _ := cmd.Flags().GetStringSlice("event")
+		secret, _ := cmd.Flags().GetString("secret")
+
+		if !isURLScope(url) {
+			return fmt.Errorf("webhook URL must be http(s): %s", url)
+		}
+		for _, e := range events {
+			if !isKnownEvent(e) {
+				return fmt.Errorf("unknown event %q (known: %s)", e, strings.Join(knownEvents, ", "))
+			}
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Re-adding a URL replaces its subscription
+		hook := config.Webhook{URL: url, Events: events, Secret: secret}
+		replaced := false
+		for i, h := range cfg.Webhooks {
+			if h.URL == url {
+				cfg.Webhooks[i] = hook
+				replaced = true
+			}
+		}
+		if !replaced {
+			cfg.Webhooks = append(cfg.Webhooks, hook)
+		}
+		if err := cfg.Save(memoryDir()); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		status := "added"
+		if replaced {
+			status = "updated"
+		}
+		if outputText {
+			fmt.Printf("✓ Webhook %s: %s (%s)\n", status, url, describeEvents(events))
+		} else {
+			outputResult(map[string]interface{}{
+				"status": status,
+				"url":    url,
+				"events": describeEvents(events),
+			})
+		}
+		return nil
+	},
+}
+
+// webhookListCmd lists configured webhooks
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhooks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if !outputText {
+			hooks := make([]map[string]interface{}, 0, len(cfg.Webhooks))
+			for _, h := range cfg.Webhooks {
+				hooks = append(hooks, map[string]interface{}{
+					"url":    h.URL,
+					"events": describeEvents(h.Events),
+					"signed": h.Secret != "",
+				})
+			}
+			outputResult(map[string]interface{}{
+				"webhooks": hooks,
+				"count":    len(hooks),
+			})
+			return nil
+		}
+
+		if len(cfg.Webhooks) == 0 {
+			fmt.Println("No webhooks configured.")
+			return nil
+		}
+		for _, h := range cfg.Webhooks {
+			signed := ""
+			if h.Secret != "" {
+				signed = " [signed]"
+			}
+			fmt.Printf("  %s (%s)%s\n", h.URL, describeEvents(h.Events), signed)
+		}
+		return nil
+	},
+}
+
+// webhookRemoveCmd removes a webhook
+var webhookRemoveCmd = &cobra.Command{
+	Use:   "remove [url]",
+	Short: "Remove a webhook",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		kept := cfg.Webhooks[:0]
+		for _, h := range cfg.Webhooks {
+			if h.URL != args[0] {
+				kept = append(kept, h)
+			}
+		}
+		if len(kept) == len(cfg.Webhooks) {
+			return fmt.Errorf("no webhook configured for %s", args[0])
+		}
+		cfg.Webhooks = kept
+		if err := cfg.Save(memoryDir()); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		if outputText {
+			fmt.Printf("✓ Webhook removed: %s\n", args[0])
+		} else {
+			outputResult(map[string]interface{}{
+				"status": "removed",
+				"url":    args[0],
+			})
+		}
+		return nil
+	},
+}
+
+// isKnownEvent reports whether name is an event memory emits
+func isKnownEvent(name string) bool {
+	if name == "*" {
+		return true
+	}
+	for _, e := range knownEvents {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// describeEvents renders a subscription list for display
+func describeEvents(events []string) string {
+	if len(events) == 0 {
+		return "all events"
+	}
+	return strings.Join(events, ", ")
+}
+
+func init() {
+	webhookAddCmd.Flags().StringSlice("event", nil, "Event to subscribe to (repeatable, default all)")
+	webhookAddCmd.Flags().String("secret", "", "Shared secret for signing payloads")
+	webhookCmd.AddCommand(webhookAddCmd, webhookListCmd, webhookRemoveCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
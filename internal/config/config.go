@@ -0,0 +1,246 @@
+// Package config loads and saves per-project settings stored next to the database,
+// and the per-user agent profiles
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the config file inside the memory directory
+const FileName = "config.json"
+
+// Webhook is an HTTP endpoint notified when events occur
+type Webhook struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"` // Empty means every event
+	Secret string   `json:"secret,omitempty"` // Signs payloads with HMAC-SHA256 when set
+}
+
+// Matches reports whether the webhook subscribes to an event
+func (w Webhook) Matches(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncRemote is the server breadcrumbs are pushed to and pulled from
+type SyncRemote struct {
+	URL   string `json:"url"`
+	Token string `json:"token,omitempty"`
+}
+
+// Access levels an API token can be granted on a project
+const (
+	AccessRead  = "read"
+	AccessWrite = "write" // Includes read
+)
+
+// ProjectGrant gives an API token access to one project, or to every project with "*"
+type ProjectGrant struct {
+	Project string `json:"project"` // Project ID or name, or "*"
+	Access  string `json:"access"`
+}
+
+// APIToken is a token the sync server accepts, limited to the projects it was granted
+type APIToken struct {
+	Name   string         `json:"name"`
+	Hash   string         `json:"hash"` // SHA-256 of the token, hex encoded; the token itself isn't kept
+	Grants []ProjectGrant `json:"grants"`
+}
+
+// Allows reports whether the token grants access to a project, identified by ID and name
+func (t *APIToken) Allows(projectID, projectName, access string) bool {
+	for _, g := range t.Grants {
+		if g.Project != "*" && g.Project != projectID && (projectName == "" || g.Project != projectName) {
+			continue
+		}
+		if g.Access == AccessWrite || g.Access == access {
+			return true
+		}
+	}
+	return false
+}
+
+// ScrubRule replaces matches of a regular expression, e.g. customer IDs
+type ScrubRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// ScrubConfig lists the personal data removed from text before it is stored or shared
+type ScrubConfig struct {
+	Emails bool        `json:"emails,omitempty"` // Replace email addresses with [email]
+	Names  []string    `json:"names,omitempty"`  // Replace these names, case-insensitively, with [name]
+	Rules  []ScrubRule `json:"rules,omitempty"`  // Replace pattern matches with [<rule name>]
+}
+
+// Default automatic checkpoint intervals
+const (
+	DefaultCheckpointBreadcrumbs = 10
+	DefaultCheckpointMinutes     = 30
+)
+
+// CheckpointConfig sets how often sessions are checkpointed automatically
+type CheckpointConfig struct {
+	Off         bool `json:"off,omitempty"`
+	Breadcrumbs int  `json:"breadcrumbs,omitempty"` // Checkpoint after this many breadcrumbs; 0 means the default
+	Minutes     int  `json:"minutes,omitempty"`     // Or after this many minutes; 0 means the default
+}
+
+// Intervals returns the breadcrumb and time intervals, applying defaults
+func (c *CheckpointConfig) Intervals() (int, time.Duration) {
+	breadcrumbs, minutes := DefaultCheckpointBreadcrumbs, DefaultCheckpointMinutes
+	if c != nil && c.Breadcrumbs > 0 {
+		breadcrumbs = c.Breadcrumbs
+	}
+	if c != nil && c.Minutes > 0 {
+		minutes = c.Minutes
+	}
+	return breadcrumbs, time.Duration(minutes) * time.Minute
+}
+
+// DefaultUnknownAgingDays is how long a question stays open before it is flagged
+const DefaultUnknownAgingDays = 14
+
+// UnknownsConfig sets when open questions are flagged as aging
+type UnknownsConfig struct {
+	AgingDays int `json:"aging_days,omitempty"` // 0 means the default
+}
+
+// AgingThreshold returns how long a question may stay open before it is flagged
+func (c *UnknownsConfig) AgingThreshold() time.Duration {
+	days := DefaultUnknownAgingDays
+	if c != nil && c.AgingDays > 0 {
+		days = c.AgingDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// SessionsConfig sets how logging behaves outside a session
+type SessionsConfig struct {
+	AdHoc bool `json:"adhoc,omitempty"` // Log into a per-day ad-hoc session when none is active
+}
+
+// Default maximum lengths of breadcrumb text, in characters
+const (
+	DefaultMaxFindingChars  = 2000
+	DefaultMaxUnknownChars  = 1000
+	DefaultMaxApproachChars = 1000
+)
+
+// LimitsConfig caps the length of breadcrumb text; longer text is truncated with a marker
+type LimitsConfig struct {
+	Finding  int `json:"finding,omitempty"`  // 0 means the default
+	Unknown  int `json:"unknown,omitempty"`  // 0 means the default
+	Approach int `json:"approach,omitempty"` // Also caps why a dead end failed; 0 means the default
+}
+
+// Max returns the finding, unknown and approach limits, applying defaults
+func (c *LimitsConfig) Max() (finding, unknown, approach int) {
+	finding, unknown, approach = DefaultMaxFindingChars, DefaultMaxUnknownChars, DefaultMaxApproachChars
+	if c != nil && c.Finding > 0 {
+		finding = c.Finding
+	}
+	if c != nil && c.Unknown > 0 {
+		unknown = c.Unknown
+	}
+	if c != nil && c.Approach > 0 {
+		approach = c.Approach
+	}
+	return finding, unknown, approach
+}
+
+// RetentionForever keeps a kind of breadcrumb however old it gets
+const RetentionForever = "forever"
+
+// RetentionConfig sets how long each kind of breadcrumb is kept before 'memory prune'
+// and the daemon delete it. Periods are windows such as 30d or 2w; empty or
+// RetentionForever keeps them.
+type RetentionConfig struct {
+	Findings         string `json:"findings,omitempty"`          // Since last verified
+	OpenUnknowns     string `json:"open_unknowns,omitempty"`     // Since asked
+	ResolvedUnknowns string `json:"resolved_unknowns,omitempty"` // Since resolved
+	DeadEnds         string `json:"dead_ends,omitempty"`         // Since recorded
+	AdHocSessions    string `json:"adhoc_sessions,omitempty"`    // Breadcrumbs of ad-hoc sessions, since logged
+}
+
+// Periods returns the period of each kind of breadcrumb, keyed by its JSON name
+func (c *RetentionConfig) Periods() map[string]*string {
+	return map[string]*string{
+		"findings":          &c.Findings,
+		"open_unknowns":     &c.OpenUnknowns,
+		"resolved_unknowns": &c.ResolvedUnknowns,
+		"dead_ends":         &c.DeadEnds,
+		"adhoc_sessions":    &c.AdHocSessions,
+	}
+}
+
+// DigestConfig schedules the Slack digest 'memory daemon' posts
+type DigestConfig struct {
+	Every     string `json:"every"`           // Window between digests, such as 1d or 1w
+	Slack     string `json:"slack,omitempty"` // Incoming webhook URL; MEMORY_SLACK_WEBHOOK when empty
+	ProjectID string `json:"project_id"`      // Project the digest covers
+}
+
+// BackupConfig schedules the S3 backups 'memory daemon' uploads
+type BackupConfig struct {
+	Every string `json:"every"` // Window between backups, such as 6h or 1d
+	S3    string `json:"s3"`    // Destination, s3://bucket/path; ending in / gets timestamped names
+}
+
+// Config holds project-level settings
+type Config struct {
+	Webhooks    []Webhook         `json:"webhooks,omitempty"`
+	Sync        *SyncRemote       `json:"sync,omitempty"`
+	Scrub       *ScrubConfig      `json:"scrub,omitempty"`
+	Checkpoints *CheckpointConfig `json:"checkpoints,omitempty"`
+	Unknowns    *UnknownsConfig   `json:"unknowns,omitempty"`
+	Sessions    *SessionsConfig   `json:"sessions,omitempty"`
+	Retention   *RetentionConfig  `json:"retention,omitempty"`
+	Limits      *LimitsConfig     `json:"limits,omitempty"`
+	Digest      *DigestConfig     `json:"digest,omitempty"`
+	Backup      *BackupConfig     `json:"backup,omitempty"`
+	Tokens      []APIToken        `json:"tokens,omitempty"` // Accepted by 'memory serve'
+}
+
+// Path returns the config file path within a memory directory
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads the config from a memory directory. A missing file yields an empty config.
+func Load(dir string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(Path(dir))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Save writes the config to a memory directory
+func (c *Config) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(dir), append(data, '\n'), 0600) // May hold webhook secrets and sync tokens
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ProfilesFileName is the name of the profiles file inside the user's memory directory
+const ProfilesFileName = "profiles.json"
+
+// Profile is one agent configuration on a machine
+type Profile struct {
+	AIID    string   `json:"ai_id,omitempty"`
+	Tags    []string `json:"tags,omitempty"`     // Added to every breadcrumb logged
+	Text    bool     `json:"text,omitempty"`     // Human-readable output without --text
+	NoColor bool     `json:"no_color,omitempty"` // Plain --text output without --no-color
+}
+
+// Profiles holds a machine's agent profiles and the one in use
+type Profiles struct {
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+}
+
+// ProfilesPath returns the profiles file path within a memory directory
+func ProfilesPath(dir string) string {
+	return filepath.Join(dir, ProfilesFileName)
+}
+
+// LoadProfiles reads the profiles from a memory directory. A missing file yields none.
+func LoadProfiles(dir string) (*Profiles, error) {
+	profiles := &Profiles{}
+	data, err := os.ReadFile(ProfilesPath(dir))
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Save writes the profiles to a memory directory
+func (p *Profiles) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ProfilesPath(dir), append(data, '\n'), 0644)
+}
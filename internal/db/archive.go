@@ -0,0 +1,227 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationArchivedBreadcrumbs records the breadcrumbs archived out of this database.
+// Their events left with them, so events a merge or sync peer still holds for them
+// would either bring them back or fail to replay without their created event; those
+// events are skipped instead. Merging the archive back lifts the record.
+const migrationArchivedBreadcrumbs = `
+CREATE TABLE IF NOT EXISTS archived_breadcrumbs (
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    archived_at REAL NOT NULL,
+    PRIMARY KEY (entity_type, entity_id)
+);
+`
+
+// notArchived filters breadcrumb events down to those of breadcrumbs still in this database
+const notArchived = `(entity_type, entity_id) NOT IN (SELECT entity_type, entity_id FROM main.archived_breadcrumbs)`
+
+// archivedFromKey is the archive's meta key naming the device whose breadcrumbs it
+// holds, so merging it back into that database is recognized as a restore
+const archivedFromKey = "archived_from"
+
+// isArchived reports whether a breadcrumb was archived out of this database
+func isArchived(ctx context.Context, tx *sqlx.Tx, entityType, entityID string) (bool, error) {
+	var n int
+	err := tx.GetContext(ctx, &n, `SELECT COUNT(*) FROM main.archived_breadcrumbs WHERE entity_type = ? AND entity_id = ?`, entityType, entityID)
+	return n > 0, err
+}
+
+// ArchiveResult counts what an archive moved out of the database
+type ArchiveResult struct {
+	Sessions int `json:"sessions"`
+	Findings int `json:"findings"`
+	Unknowns int `json:"unknowns"`
+	DeadEnds int `json:"dead_ends"`
+	Events   int `json:"events"`
+	Kept     int `json:"kept"` // Breadcrumbs of archived sessions left in place because they're still in use
+}
+
+// ArchiveSessions moves sessions that started before the cutoff and have ended, other
+// than exclude, into the database at path along with their breadcrumbs and handoffs.
+// The session rows and handoffs are copied and stay behind as summaries; breadcrumbs,
+// their events and commit links are moved. Breadcrumbs still in use stay: findings verified since
+// the cutoff and open questions.
+//
+// Archived breadcrumbs are recorded so events for them arriving later from a merge or
+// sync peer are skipped. The archive must already be on the current schema. Merging it
+// back with MergeBreadcrumbs restores the breadcrumbs. With dryRun the counts are computed and
+// the archive isn't opened.
+func (d *DB) ArchiveSessions(ctx context.Context, path string, before time.Time, exclude string, dryRun bool) (*ArchiveResult, error) {
+	conn, err := d.Connx(ctx) // ATTACH is per connection
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if !dryRun {
+		if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS archive`, path); err != nil {
+			return nil, err
+		}
+		defer conn.ExecContext(ctx, `DETACH DATABASE archive`)
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Projects and sessions may be copied after the rows referring to them
+	if _, err := tx.ExecContext(ctx, `PRAGMA defer_foreign_keys = ON`); err != nil {
+		return nil, err
+	}
+
+	// Session times are stored as text in their recorded zone, so they're compared here
+	var sessions []struct {
+		ID      string     `db:"session_id"`
+		Start   time.Time  `db:"start_time"`
+		EndTime *time.Time `db:"end_time"`
+	}
+	if err := tx.SelectContext(ctx, &sessions, `SELECT session_id, start_time, end_time FROM main.sessions`); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE archive_sessions (session_id TEXT PRIMARY KEY)`); err != nil {
+		return nil, err
+	}
+	result := &ArchiveResult{}
+	for _, s := range sessions {
+		if s.EndTime == nil || !s.Start.Before(before) || s.ID == exclude {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO archive_sessions (session_id) VALUES (?)`, s.ID); err != nil {
+			return nil, err
+		}
+		result.Sessions++
+	}
+
+	cutoff := float64(before.UnixMilli()) / 1000.0
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE archive_entities (entity_type TEXT NOT NULL, entity_id TEXT NOT NULL, PRIMARY KEY (entity_type, entity_id))`); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO archive_entities
+		SELECT 'finding', id FROM main.project_findings
+		WHERE session_id IN (SELECT session_id FROM archive_sessions)
+		AND NOT (deleted_at IS NULL AND COALESCE(last_verified_timestamp, created_timestamp) >= ?)
+		UNION ALL
+		SELECT 'unknown', id FROM main.project_unknowns
+		WHERE session_id IN (SELECT session_id FROM archive_sessions)
+		AND NOT (deleted_at IS NULL AND NOT is_resolved)
+		UNION ALL
+		SELECT 'dead_end', id FROM main.project_dead_ends
+		WHERE session_id IN (SELECT session_id FROM archive_sessions)`, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select breadcrumbs: %w", err)
+	}
+	if err := tx.GetContext(ctx, &result.Kept, `SELECT
+		(SELECT COUNT(*) FROM main.project_findings WHERE session_id IN (SELECT session_id FROM archive_sessions)) +
+		(SELECT COUNT(*) FROM main.project_unknowns WHERE session_id IN (SELECT session_id FROM archive_sessions)) +
+		(SELECT COUNT(*) FROM main.project_dead_ends WHERE session_id IN (SELECT session_id FROM archive_sessions))`); err != nil {
+		return nil, err
+	}
+	moved, _ := res.RowsAffected()
+	result.Kept -= int(moved)
+	counts := map[string]*int{"finding": &result.Findings, "unknown": &result.Unknowns, "dead_end": &result.DeadEnds}
+	for entityType, n := range counts {
+		if err := tx.GetContext(ctx, n, `SELECT COUNT(*) FROM archive_entities WHERE entity_type = ?`, entityType); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.GetContext(ctx, &result.Events, `SELECT COUNT(*) FROM main.breadcrumb_events
+		WHERE (entity_type, entity_id) IN (SELECT entity_type, entity_id FROM archive_entities)`); err != nil {
+		return nil, err
+	}
+	if dryRun || moved == 0 {
+		return result, nil
+	}
+
+	// Copy, then remove what moved. Projects are small and copied whole so the archive
+	// stands on its own.
+	copies := []struct{ table, where string }{
+		{"projects", ``},
+		{"sessions", `session_id IN (SELECT session_id FROM archive_sessions)`},
+		{"handoff_reports", `session_id IN (SELECT session_id FROM archive_sessions)`},
+		{"project_findings", `id IN (SELECT entity_id FROM archive_entities WHERE entity_type = 'finding')`},
+		{"project_unknowns", `id IN (SELECT entity_id FROM archive_entities WHERE entity_type = 'unknown')`},
+		{"project_dead_ends", `id IN (SELECT entity_id FROM archive_entities WHERE entity_type = 'dead_end')`},
+		{"finding_commits", `finding_id IN (SELECT entity_id FROM archive_entities WHERE entity_type = 'finding')`},
+	}
+	for _, c := range copies {
+		if err := copyArchiveRows(ctx, tx, c.table, c.where); err != nil {
+			return nil, fmt.Errorf("failed to archive %s: %w", c.table, err)
+		}
+	}
+	const eventColumns = `id, entity_type, entity_id, kind, payload, timestamp, device_id, lamport`
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO archive.breadcrumb_events (`+eventColumns+`)
+		SELECT `+eventColumns+` FROM main.breadcrumb_events
+		WHERE (entity_type, entity_id) IN (SELECT entity_type, entity_id FROM archive_entities) `+breadcrumbReplayOrder); err != nil {
+		return nil, fmt.Errorf("failed to archive events: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DROP TRIGGER main.breadcrumb_events_no_delete`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM main.breadcrumb_events
+		WHERE (entity_type, entity_id) IN (SELECT entity_type, entity_id FROM archive_entities)`); err != nil {
+		return nil, fmt.Errorf("failed to remove archived events: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, breadcrumbEventsNoDelete); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO main.archived_breadcrumbs (entity_type, entity_id, archived_at)
+		SELECT entity_type, entity_id, ? FROM archive_entities`, float64(time.Now().UnixMilli())/1000.0); err != nil {
+		return nil, fmt.Errorf("failed to record archived breadcrumbs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO archive.meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, archivedFromKey, d.DeviceID()); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM main.finding_commits
+		WHERE finding_id IN (SELECT entity_id FROM archive_entities WHERE entity_type = 'finding')`); err != nil {
+		return nil, fmt.Errorf("failed to remove archived commit links: %w", err)
+	}
+	for entityType, table := range breadcrumbTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM main.`+table+`
+			WHERE id IN (SELECT entity_id FROM archive_entities WHERE entity_type = ?)`, entityType); err != nil {
+			return nil, fmt.Errorf("failed to remove archived %s: %w", table, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DROP TABLE temp.archive_sessions; DROP TABLE temp.archive_entities`); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	// Give the freed pages back so the file shrinks
+	if _, err := conn.ExecContext(ctx, `VACUUM main`); err != nil {
+		return nil, fmt.Errorf("failed to compact the database: %w", err)
+	}
+	return result, nil
+}
+
+// copyArchiveRows copies a table's matching rows into the archive, skipping rows it
+// already has. Columns are named because ALTER TABLE migrations may have added them to
+// the two databases in different orders.
+func copyArchiveRows(ctx context.Context, tx *sqlx.Tx, table, where string) error {
+	var columns []string
+	if err := tx.SelectContext(ctx, &columns, `SELECT name FROM pragma_table_info(?, 'main')`, table); err != nil {
+		return err
+	}
+	list := strings.Join(columns, ", ")
+	query := `INSERT OR IGNORE INTO archive.` + table + ` (` + list + `) SELECT ` + list + ` FROM main.` + table
+	if where != "" {
+		query += ` WHERE ` + where
+	}
+	_, err := tx.ExecContext(ctx, query)
+	return err
+}
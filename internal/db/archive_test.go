@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// TestArchiveThenMerge covers events for archived breadcrumbs arriving from a peer
+// that still holds them: they're skipped instead of wedging the replay for want of
+// their created event, and merging the archive back restores the breadcrumbs.
+func TestArchiveThenMerge(t *testing.T) {
+	ctx := context.Background()
+	local := openTestDB(t, "local.db")
+	peer := openTestDB(t, "peer.db")
+	project, session := seedSession(t, local, "archived")
+	localRepo, peerRepo := NewBreadcrumbRepository(local), NewBreadcrumbRepository(peer)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "The importer skips blank rows", 0.5)
+	deadEnd := models.NewDeadEnd(project.ID, session.SessionID, "Stream the CSV", "Rows span lines", 0.5)
+	if err := localRepo.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	if err := localRepo.CreateDeadEnd(ctx, deadEnd); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewSessionRepository(local).End(ctx, session.SessionID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The peer has the breadcrumbs, and keeps changing the finding after the archive
+	if _, err := peer.MergeBreadcrumbs(ctx, local.Path(), nil, false); err != nil {
+		t.Fatalf("merge into peer: %v", err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "archive.db")
+	archive, err := Open(ctx, archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+	archived, err := local.ArchiveSessions(ctx, archivePath, time.Now().Add(time.Hour), "", false)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if archived.Findings != 1 || archived.DeadEnds != 1 {
+		t.Fatalf("archived %+v, want the finding and the dead end", archived)
+	}
+	if _, err := localRepo.GetFinding(ctx, finding.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("archived finding still present: %v", err)
+	}
+	pf, _ := peerRepo.GetFinding(ctx, finding.ID)
+	updated := "The importer skips blank and comment rows"
+	if err := peerRepo.VerifyFinding(ctx, finding.ID, pf.Version, nil, &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := local.MergeBreadcrumbs(ctx, peer.Path(), nil, false)
+	if err != nil {
+		t.Fatalf("merge peer after archive: %v", err)
+	}
+	if result.Events != 0 || result.Skipped != 3 {
+		t.Errorf("merge peer = %+v, want 0 events and 3 skipped", result)
+	}
+	batch, err := peer.SyncBatchAfter(ctx, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = local.ApplySyncBatch(ctx, batch)
+	if err != nil {
+		t.Fatalf("sync from peer after archive: %v", err)
+	}
+	if result.Events != 0 || result.Skipped != 3 {
+		t.Errorf("sync from peer = %+v, want 0 events and 3 skipped", result)
+	}
+	if _, err := local.RebuildBreadcrumbs(ctx); err != nil {
+		t.Fatalf("rebuild after archive: %v", err)
+	}
+	if _, err := localRepo.GetFinding(ctx, finding.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("peer brought the archived finding back: %v", err)
+	}
+
+	// Merging the archive back restores the breadcrumbs, and the peer's later change
+	// can follow them
+	result, err = local.MergeBreadcrumbs(ctx, archivePath, nil, false)
+	if err != nil {
+		t.Fatalf("merge archive back: %v", err)
+	}
+	if result.Events != 2 {
+		t.Errorf("merge archive = %+v, want 2 events", result)
+	}
+	if _, err := local.MergeBreadcrumbs(ctx, peer.Path(), nil, false); err != nil {
+		t.Fatalf("merge peer after restore: %v", err)
+	}
+	lf, err := localRepo.GetFinding(ctx, finding.ID)
+	if err != nil {
+		t.Fatalf("restored finding: %v", err)
+	}
+	if lf.Finding != updated {
+		t.Errorf("restored finding = %q, want the peer's %q", lf.Finding, updated)
+	}
+	deadEnds, err := localRepo.ListDeadEnds(ctx, project.ID, "", 10)
+	if err != nil || len(deadEnds) != 1 {
+		t.Errorf("restored dead ends = %v, %v", deadEnds, err)
+	}
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// SetActor sets who subsequent mutations are attributed to in the audit trail
+func (d *DB) SetActor(actor string) {
+	d.actor = actor
+}
+
+// Actor returns who mutations are currently attributed to
+func (d *DB) Actor() string {
+	if d.actor == "" {
+		return "unknown"
+	}
+	return d.actor
+}
+
+// audit appends a mutation to the audit trail
+func (d *DB) audit(ctx context.Context, action models.AuditAction, entityType, entityID string, projectID *string, payload interface{}) error {
+	var payloadJSON *string
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if data, err = d.scrubJSON(data); err != nil {
+			return err
+		}
+		s := string(data)
+		payloadJSON = &s
+	}
+	if projectID != nil && *projectID == "" {
+		projectID = nil
+	}
+
+	query := `
+		INSERT INTO audit_events (timestamp, actor, action, entity_type, entity_id, project_id, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := d.ExecContext(ctx, query,
+		float64(time.Now().UnixMilli())/1000.0,
+		d.Actor(),
+		action,
+		entityType,
+		entityID,
+		projectID,
+		payloadJSON,
+	)
+	return err
+}
+
+// AuditRepository reads the audit trail
+type AuditRepository struct {
+	db *DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AuditFilter narrows an audit trail listing
+type AuditFilter struct {
+	ProjectID   string
+	Actor       string
+	EntityTypes []string
+	EntityID    string
+	Since       float64
+}
+
+// List lists audit events matching the filter, newest first
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter, limit int) ([]*models.AuditEvent, error) {
+	query := `SELECT * FROM audit_events WHERE 1=1`
+	var args []interface{}
+
+	if filter.ProjectID != "" {
+		query += ` AND project_id = ?`
+		args = append(args, filter.ProjectID)
+	}
+	if filter.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, filter.Actor)
+	}
+	if len(filter.EntityTypes) > 0 {
+		query += ` AND entity_type IN (` + strings.TrimSuffix(strings.Repeat("?,", len(filter.EntityTypes)), ",") + `)`
+		for _, t := range filter.EntityTypes {
+			args = append(args, t)
+		}
+	}
+	if filter.EntityID != "" {
+		query += ` AND entity_id LIKE ?`
+		args = append(args, filter.EntityID+"%")
+	}
+	if filter.Since > 0 {
+		query += ` AND timestamp >= ?`
+		args = append(args, filter.Since)
+	}
+
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	var events []*models.AuditEvent
+	if err := r.db.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// sessionProjectID looks up a session's project for audit entries
+func (d *DB) sessionProjectID(ctx context.Context, sessionID string) *string {
+	var projectID sql.NullString
+	if err := d.GetContext(ctx, &projectID, `SELECT project_id FROM sessions WHERE session_id = ?`, sessionID); err != nil || !projectID.Valid {
+		return nil
+	}
+	return &projectID.String
+}
+
+// goalProjectID looks up the project of a goal's session for audit entries
+func (d *DB) goalProjectID(ctx context.Context, goalID string) *string {
+	var sessionID string
+	if err := d.GetContext(ctx, &sessionID, `SELECT session_id FROM goals WHERE id = ?`, goalID); err != nil {
+		return nil
+	}
+	return d.sessionProjectID(ctx, sessionID)
+}
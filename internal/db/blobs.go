@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/AbdouB/memory/internal/blob"
+)
+
+// migrationCarriedBlobs holds copies of evidence blobs inside a database file that
+// leaves its memory directory, a backup snapshot or an archive, so the blobs its
+// findings refer to travel with it. The working database keeps it empty.
+const migrationCarriedBlobs = `
+CREATE TABLE IF NOT EXISTS carried_blobs (
+    hash TEXT PRIMARY KEY,
+    data BLOB NOT NULL
+);
+`
+
+// EvidenceBlobs returns the hashes of the blobs findings' evidence refers to. Deleted
+// findings count when they were deleted since deletedSince, as they can still be
+// restored; the zero time counts every deleted finding.
+func (d *DB) EvidenceBlobs(ctx context.Context, deletedSince time.Time) ([]string, error) {
+	var evidence []string
+	if err := d.SelectContext(ctx, &evidence, `SELECT DISTINCT verification_evidence FROM project_findings
+		WHERE verification_evidence LIKE ? AND (deleted_at IS NULL OR deleted_at >= ?)`,
+		blob.RefPrefix+"%", float64(deletedSince.UnixMilli())/1000.0); err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(evidence))
+	for _, e := range evidence {
+		if hash, ok := blob.ParseRef(e); ok {
+			hashes = append(hashes, hash)
+		}
+	}
+	return hashes, nil
+}
+
+// CarryBlob stores a copy of a blob in the database. Carrying it again is a no-op.
+func (d *DB) CarryBlob(ctx context.Context, hash string, data []byte) error {
+	_, err := d.ExecContext(ctx, `INSERT OR IGNORE INTO carried_blobs (hash, data) VALUES (?, ?)`, hash, data)
+	return err
+}
+
+// CarriedBlobs calls fn with each blob the database carries
+func (d *DB) CarriedBlobs(ctx context.Context, fn func(hash string, data []byte) error) error {
+	rows, err := d.QueryxContext(ctx, `SELECT hash, data FROM carried_blobs ORDER BY hash`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hash string
+		var data []byte
+		if err := rows.Scan(&hash, &data); err != nil {
+			return err
+		}
+		if err := fn(hash, data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DropCarriedBlobs empties the carried blobs once they've been written to a blob store
+func (d *DB) DropCarriedBlobs(ctx context.Context) error {
+	_, err := d.ExecContext(ctx, `DELETE FROM carried_blobs`)
+	return err
+}
@@ -0,0 +1,793 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationBreadcrumbEvents creates the breadcrumb event stream. The project_findings,
+// project_unknowns and project_dead_ends tables are read models projected from it.
+const migrationBreadcrumbEvents = `
+CREATE TABLE IF NOT EXISTS breadcrumb_events (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    id TEXT NOT NULL UNIQUE,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    timestamp REAL NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_breadcrumb_events_entity ON breadcrumb_events(entity_type, entity_id, seq);
+
+CREATE TRIGGER IF NOT EXISTS breadcrumb_events_no_update
+BEFORE UPDATE ON breadcrumb_events
+BEGIN
+    SELECT RAISE(ABORT, 'breadcrumb_events is append-only');
+END;
+
+` + breadcrumbEventsNoDelete
+
+// breadcrumbEventsNoDelete rejects deletes from the event stream. Archiving lifts it
+// for the one transaction that moves events out.
+const breadcrumbEventsNoDelete = `
+CREATE TRIGGER IF NOT EXISTS breadcrumb_events_no_delete
+BEFORE DELETE ON breadcrumb_events
+BEGIN
+    SELECT RAISE(ABORT, 'breadcrumb_events is append-only');
+END;
+`
+
+// Breadcrumb events written before Lamport clocks existed
+const migrationBreadcrumbEventDevice = `
+ALTER TABLE breadcrumb_events ADD COLUMN device_id TEXT NOT NULL DEFAULT '';
+`
+
+const migrationBreadcrumbEventLamport = `
+ALTER TABLE breadcrumb_events ADD COLUMN lamport INTEGER NOT NULL DEFAULT 0;
+`
+
+const migrationBreadcrumbEventOrder = `
+CREATE INDEX IF NOT EXISTS idx_breadcrumb_events_order ON breadcrumb_events(lamport, device_id, id);
+`
+
+// migrationMeta holds per-database settings such as the device ID
+const migrationMeta = `
+CREATE TABLE IF NOT EXISTS meta (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+);
+`
+
+// breadcrumbReplayOrder is the deterministic order every database replays events in
+const breadcrumbReplayOrder = `ORDER BY lamport ASC, device_id ASC, id ASC`
+
+// Read model columns. Each table holds its entities' whole state; tags and relations
+// are JSON arrays.
+const (
+	findingColumns = `id, project_id, session_id, goal_id, subtask_id, finding,
+		created_timestamp, subject, impact, last_verified_timestamp, subject_git_hash,
+		verify_check, verification_evidence, file_changed_detected_at, worktree, git_branch,
+		updated_at, deleted_at, version, tags, relations, snippet, subject_kind`
+	unknownColumns = `id, project_id, session_id, goal_id, subtask_id, unknown, is_resolved,
+		resolved_by, created_timestamp, resolved_timestamp, subject, impact, updated_at, deleted_at,
+		version, tags, relations`
+	deadEndColumns = `id, project_id, session_id, goal_id, subtask_id, approach, why_failed,
+		created_timestamp, subject, impact, updated_at, deleted_at, version, tags, relations`
+)
+
+// newBreadcrumbEvent is an event waiting to be appended and projected
+type newBreadcrumbEvent struct {
+	entityType    string
+	entityID      string
+	kind          models.BreadcrumbEventKind
+	payload       interface{}
+	expectVersion int // When > 0, the entity must be at this version or the append fails
+}
+
+// appendBreadcrumbEvents appends events to the stream and projects them into the
+// read models in one transaction, so the stream and the tables never disagree.
+// Inside InTx the events join the caller's transaction.
+func (d *DB) appendBreadcrumbEvents(ctx context.Context, events ...newBreadcrumbEvent) error {
+	return d.InTx(ctx, func(txd *DB) error {
+		return txd.appendBreadcrumbEventsTx(ctx, txd.tx, events)
+	})
+}
+
+// appendBreadcrumbEventsTx appends and projects events within tx
+func (d *DB) appendBreadcrumbEventsTx(ctx context.Context, tx *sqlx.Tx, events []newBreadcrumbEvent) error {
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	for _, e := range events {
+		payload, err := json.Marshal(e.payload)
+		if err != nil {
+			return err
+		}
+		if payload, err = d.scrubJSON(payload); err != nil {
+			return err
+		}
+		ev := &models.BreadcrumbEvent{
+			ID:         uuid.New().String(),
+			EntityType: e.entityType,
+			EntityID:   e.entityID,
+			Kind:       e.kind,
+			Payload:    string(payload),
+			Timestamp:  now,
+		}
+		if err := d.insertBreadcrumbEvent(ctx, tx, ev); err != nil {
+			return err
+		}
+		if err := projectBreadcrumbEvent(ctx, tx, ev, e.expectVersion); err != nil {
+			if err == sql.ErrNoRows {
+				return notFound(e.entityType, e.entityID)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// insertBreadcrumbEvent writes an event to the stream, stamping it with this device
+// and a Lamport clock past every event already in the stream, merged ones included
+func (d *DB) insertBreadcrumbEvent(ctx context.Context, tx *sqlx.Tx, ev *models.BreadcrumbEvent) error {
+	if err := tx.GetContext(ctx, &ev.Lamport, `SELECT COALESCE(MAX(lamport), 0) + 1 FROM breadcrumb_events`); err != nil {
+		return err
+	}
+	ev.DeviceID = d.deviceID
+
+	query := `
+		INSERT INTO breadcrumb_events (id, entity_type, entity_id, kind, payload, timestamp, device_id, lamport)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.ExecContext(ctx, query, ev.ID, ev.EntityType, ev.EntityID, ev.Kind, ev.Payload, ev.Timestamp, ev.DeviceID, ev.Lamport)
+	if err != nil {
+		return err
+	}
+	ev.Seq, err = result.LastInsertId()
+	return err
+}
+
+// checkVersion fails with ErrVersionConflict when an entity moved past the expected version
+func checkVersion(ev *models.BreadcrumbEvent, have, expect int) error {
+	if expect > 0 && have != expect {
+		return fmt.Errorf("%w: %s %s is at version %d, expected %d", ErrVersionConflict, ev.EntityType, ev.EntityID, have, expect)
+	}
+	return nil
+}
+
+// projectBreadcrumbEvent folds an event into the current state of its entity and saves it.
+// expectVersion > 0 guards against concurrent updates; replays pass 0.
+func projectBreadcrumbEvent(ctx context.Context, tx *sqlx.Tx, ev *models.BreadcrumbEvent, expectVersion int) error {
+	created := ev.Kind == models.EventFindingCreated ||
+		ev.Kind == models.EventUnknownCreated ||
+		ev.Kind == models.EventDeadEndCreated
+
+	switch ev.EntityType {
+	case models.EntityFinding:
+		f := &models.Finding{}
+		if !created {
+			var err error
+			if f, err = scanFinding(tx.QueryRowContext(ctx, `SELECT `+findingColumns+` FROM project_findings WHERE id = ?`, ev.EntityID)); err != nil {
+				return err
+			}
+			if err := checkVersion(ev, f.Version, expectVersion); err != nil {
+				return err
+			}
+		}
+		if err := models.ApplyFindingEvent(f, ev); err != nil {
+			return err
+		}
+		return saveFinding(ctx, tx, f)
+	case models.EntityUnknown:
+		u := &models.Unknown{}
+		if !created {
+			var err error
+			if u, err = scanUnknown(tx.QueryRowContext(ctx, `SELECT `+unknownColumns+` FROM project_unknowns WHERE id = ?`, ev.EntityID)); err != nil {
+				return err
+			}
+			if err := checkVersion(ev, u.Version, expectVersion); err != nil {
+				return err
+			}
+		}
+		if err := models.ApplyUnknownEvent(u, ev); err != nil {
+			return err
+		}
+		return saveUnknown(ctx, tx, u)
+	case models.EntityDeadEnd:
+		de := &models.DeadEnd{}
+		if !created {
+			var err error
+			if de, err = scanDeadEnd(tx.QueryRowContext(ctx, `SELECT `+deadEndColumns+` FROM project_dead_ends WHERE id = ?`, ev.EntityID)); err != nil {
+				return err
+			}
+			if err := checkVersion(ev, de.Version, expectVersion); err != nil {
+				return err
+			}
+		}
+		if err := models.ApplyDeadEndEvent(de, ev); err != nil {
+			return err
+		}
+		return saveDeadEnd(ctx, tx, de)
+	}
+	return fmt.Errorf("unknown breadcrumb entity type %q", ev.EntityType)
+}
+
+// saveFinding writes a finding's state to its read model
+func saveFinding(ctx context.Context, tx *sqlx.Tx, f *models.Finding) error {
+	query := `
+		INSERT INTO project_findings (` + findingColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			project_id = excluded.project_id,
+			finding = excluded.finding,
+			subject = excluded.subject,
+			subject_kind = excluded.subject_kind,
+			impact = excluded.impact,
+			last_verified_timestamp = excluded.last_verified_timestamp,
+			subject_git_hash = excluded.subject_git_hash,
+			verify_check = excluded.verify_check,
+			verification_evidence = excluded.verification_evidence,
+			file_changed_detected_at = excluded.file_changed_detected_at,
+			updated_at = excluded.updated_at,
+			deleted_at = excluded.deleted_at,
+			version = excluded.version,
+			tags = excluded.tags,
+			relations = excluded.relations
+	`
+	_, err := tx.ExecContext(ctx, query,
+		f.ID,
+		f.ProjectID,
+		f.SessionID,
+		f.GoalID,
+		f.SubtaskID,
+		f.Finding,
+		f.CreatedTimestamp,
+		f.Subject,
+		f.Impact,
+		f.LastVerifiedTimestamp,
+		f.SubjectGitHash,
+		f.VerifyCheck,
+		f.VerificationEvidence,
+		f.FileChangedDetectedAt,
+		f.Worktree,
+		f.GitBranch,
+		f.UpdatedAt,
+		f.DeletedAt,
+		f.Version,
+		f.Tags,
+		f.Relations,
+		f.Snippet,
+		f.SubjectKind,
+	)
+	return err
+}
+
+// saveUnknown writes an unknown's state to its read model
+func saveUnknown(ctx context.Context, tx *sqlx.Tx, u *models.Unknown) error {
+	query := `
+		INSERT INTO project_unknowns (` + unknownColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			project_id = excluded.project_id,
+			unknown = excluded.unknown,
+			is_resolved = excluded.is_resolved,
+			resolved_by = excluded.resolved_by,
+			resolved_timestamp = excluded.resolved_timestamp,
+			subject = excluded.subject,
+			impact = excluded.impact,
+			updated_at = excluded.updated_at,
+			deleted_at = excluded.deleted_at,
+			version = excluded.version,
+			tags = excluded.tags,
+			relations = excluded.relations
+	`
+	_, err := tx.ExecContext(ctx, query,
+		u.ID,
+		u.ProjectID,
+		u.SessionID,
+		u.GoalID,
+		u.SubtaskID,
+		u.Unknown,
+		u.IsResolved,
+		u.ResolvedBy,
+		u.CreatedTimestamp,
+		u.ResolvedTimestamp,
+		u.Subject,
+		u.Impact,
+		u.UpdatedAt,
+		u.DeletedAt,
+		u.Version,
+		u.Tags,
+		u.Relations,
+	)
+	return err
+}
+
+// saveDeadEnd writes a dead end's state to its read model
+func saveDeadEnd(ctx context.Context, tx *sqlx.Tx, de *models.DeadEnd) error {
+	query := `
+		INSERT INTO project_dead_ends (` + deadEndColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			project_id = excluded.project_id,
+			approach = excluded.approach,
+			why_failed = excluded.why_failed,
+			subject = excluded.subject,
+			impact = excluded.impact,
+			updated_at = excluded.updated_at,
+			deleted_at = excluded.deleted_at,
+			version = excluded.version,
+			tags = excluded.tags,
+			relations = excluded.relations
+	`
+	_, err := tx.ExecContext(ctx, query,
+		de.ID,
+		de.ProjectID,
+		de.SessionID,
+		de.GoalID,
+		de.SubtaskID,
+		de.Approach,
+		de.WhyFailed,
+		de.CreatedTimestamp,
+		de.Subject,
+		de.Impact,
+		de.UpdatedAt,
+		de.DeletedAt,
+		de.Version,
+		de.Tags,
+		de.Relations,
+	)
+	return err
+}
+
+// breadcrumbBlobs are the JSON copies of each breadcrumb's state that older databases
+// kept next to the columns, keyed by table
+var breadcrumbBlobs = []struct{ table, column string }{
+	{"project_findings", "finding_data"},
+	{"project_unknowns", "unknown_data"},
+	{"project_dead_ends", "dead_end_data"},
+}
+
+// dropBreadcrumbBlobs removes the *_data blobs from databases created before the columns
+// became the only copy of a breadcrumb's state. Tags and relations, which only the blob
+// held, are moved into their columns first. Reports whether anything was dropped; the
+// caller then rebuilds the read models so every column agrees with the event stream.
+func (d *DB) dropBreadcrumbBlobs(ctx context.Context) (bool, error) {
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	dropped := false
+	for _, b := range breadcrumbBlobs {
+		var present int
+		if err := tx.GetContext(ctx, &present, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, b.table, b.column); err != nil {
+			return false, err
+		}
+		if present == 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE `+b.table+` SET
+			tags = COALESCE(tags, json_extract(`+b.column+`, '$.tags')),
+			relations = COALESCE(relations, json_extract(`+b.column+`, '$.relations'))
+			WHERE json_valid(`+b.column+`)`); err != nil {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE `+b.table+` DROP COLUMN `+b.column); err != nil {
+			return false, err
+		}
+		dropped = true
+	}
+	if !dropped {
+		return false, nil
+	}
+	return true, tx.Commit()
+}
+
+// backfillBreadcrumbEvents gives breadcrumbs written before the event stream existed a
+// created event holding their current state
+func (d *DB) backfillBreadcrumbEvents(ctx context.Context) error {
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var findings []*models.Finding
+	if err := tx.SelectContext(ctx, &findings, `SELECT `+findingColumns+` FROM project_findings
+		WHERE id NOT IN (SELECT entity_id FROM breadcrumb_events WHERE entity_type = 'finding')`); err != nil {
+		return err
+	}
+	var unknowns []*models.Unknown
+	if err := tx.SelectContext(ctx, &unknowns, `SELECT `+unknownColumns+` FROM project_unknowns
+		WHERE id NOT IN (SELECT entity_id FROM breadcrumb_events WHERE entity_type = 'unknown')`); err != nil {
+		return err
+	}
+	var deadEnds []*models.DeadEnd
+	if err := tx.SelectContext(ctx, &deadEnds, `SELECT `+deadEndColumns+` FROM project_dead_ends
+		WHERE id NOT IN (SELECT entity_id FROM breadcrumb_events WHERE entity_type = 'dead_end')`); err != nil {
+		return err
+	}
+	if len(findings)+len(unknowns)+len(deadEnds) == 0 {
+		return nil
+	}
+
+	snapshot := func(entityType, entityID string, kind models.BreadcrumbEventKind, createdAt float64, state interface{}) error {
+		payload, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		ev := &models.BreadcrumbEvent{
+			ID:         uuid.New().String(),
+			EntityType: entityType,
+			EntityID:   entityID,
+			Kind:       kind,
+			Payload:    string(payload),
+			Timestamp:  createdAt,
+		}
+		if err := d.insertBreadcrumbEvent(ctx, tx, ev); err != nil {
+			return err
+		}
+		return projectBreadcrumbEvent(ctx, tx, ev, 0)
+	}
+	for _, f := range findings {
+		if err := snapshot(models.EntityFinding, f.ID, models.EventFindingCreated, f.CreatedTimestamp, f); err != nil {
+			return err
+		}
+	}
+	for _, u := range unknowns {
+		if err := snapshot(models.EntityUnknown, u.ID, models.EventUnknownCreated, u.CreatedTimestamp, u); err != nil {
+			return err
+		}
+	}
+	for _, de := range deadEnds {
+		if err := snapshot(models.EntityDeadEnd, de.ID, models.EventDeadEndCreated, de.CreatedTimestamp, de); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// loadDeviceID reads this database's device ID, generating one on first use
+func (d *DB) loadDeviceID(ctx context.Context) error {
+	err := d.GetContext(ctx, &d.deviceID, `SELECT value FROM meta WHERE key = 'device_id'`)
+	if err != sql.ErrNoRows {
+		return err
+	}
+	d.deviceID = uuid.New().String()
+	_, err = d.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES ('device_id', ?)`, d.deviceID)
+	return err
+}
+
+// DeviceID identifies this database in the event streams it shares with others
+func (d *DB) DeviceID() string {
+	return d.deviceID
+}
+
+// stampLegacyEvents gives events recorded before Lamport clocks existed a clock from
+// their insertion order and attributes them to this device. The stream is otherwise
+// append-only, so the update trigger is lifted for the duration.
+func (d *DB) stampLegacyEvents(ctx context.Context) error {
+	var legacy int
+	if err := d.GetContext(ctx, &legacy, `SELECT COUNT(*) FROM breadcrumb_events WHERE lamport = 0`); err != nil || legacy == 0 {
+		return err
+	}
+
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DROP TRIGGER IF EXISTS breadcrumb_events_no_update`); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE breadcrumb_events SET lamport = seq, device_id = ? WHERE lamport = 0`, d.deviceID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, migrationBreadcrumbEvents); err != nil { // Recreates the trigger
+		return err
+	}
+	return tx.Commit()
+}
+
+// MergeResult counts what a merge brought in from the other database
+type MergeResult struct {
+	Projects int `json:"projects"`
+	Sessions int `json:"sessions,omitempty"`
+	Events   int `json:"events"`
+	Remapped int `json:"remapped,omitempty"` // Breadcrumbs moved onto a mapped local project
+	Skipped  int `json:"skipped,omitempty"`  // Events left out because their breadcrumb was archived
+}
+
+// MergeBreadcrumbs merges the breadcrumb event stream and sessions of the database at
+// path into this one and rebuilds the read models. Events and sessions are matched by
+// ID, so merging is idempotent and two databases merged into each other end up with
+// identical breadcrumbs. Projects the events refer to are copied over when missing.
+//
+// A project in the other database is mapped onto the local project of the same name,
+// or onto the one projectMap names for it (other project name or ID → local project
+// name or ID). Mapped projects aren't copied: their sessions are filed under the local
+// project and their breadcrumbs moved to it with a moved event.
+//
+// The other database must already be on the current schema. With dryRun the counts
+// are computed and the merge rolled back.
+func (d *DB) MergeBreadcrumbs(ctx context.Context, path string, projectMap map[string]string, dryRun bool) (*MergeResult, error) {
+	conn, err := d.Connx(ctx) // ATTACH is per connection
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS other`, path); err != nil {
+		return nil, err
+	}
+	defer conn.ExecContext(ctx, `DETACH DATABASE other`)
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Parent projects may be copied after their children
+	if _, err := tx.ExecContext(ctx, `PRAGMA defer_foreign_keys = ON`); err != nil {
+		return nil, err
+	}
+
+	mapped, err := mergeProjectMap(ctx, tx, projectMap)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE merge_project_map (other_id TEXT PRIMARY KEY, local_id TEXT NOT NULL)`); err != nil {
+		return nil, err
+	}
+	for otherID, localID := range mapped {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO merge_project_map (other_id, local_id) VALUES (?, ?)`, otherID, localID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &MergeResult{}
+	const projectColumns = `id, name, description, repos, created_timestamp, last_activity_timestamp,
+		status, metadata, total_sessions, total_goals, total_epistemic_deltas, project_data,
+		parent_id, root_path`
+	res, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO projects (`+projectColumns+`)
+		SELECT `+projectColumns+` FROM other.projects
+		WHERE id NOT IN (SELECT other_id FROM merge_project_map)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge projects: %w", err)
+	}
+	projects, _ := res.RowsAffected()
+	result.Projects = int(projects)
+
+	// Sub-projects copied over keep pointing at their parent once it's mapped
+	if _, err := tx.ExecContext(ctx, `UPDATE projects SET
+		parent_id = (SELECT local_id FROM merge_project_map WHERE other_id = projects.parent_id),
+		project_data = json_set(project_data, '$.parent_id', (SELECT local_id FROM merge_project_map WHERE other_id = projects.parent_id))
+		WHERE parent_id IN (SELECT other_id FROM merge_project_map) AND id IN (SELECT id FROM other.projects)`); err != nil {
+		return nil, fmt.Errorf("failed to map parent projects: %w", err)
+	}
+
+	const sessionColumns = `session_id, ai_id, user_id, start_time, end_time, components_loaded,
+		total_turns, total_cascades, avg_confidence, drift_detected, session_notes, bootstrap_level,
+		project_id, subject, created_at`
+	res, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO sessions (`+sessionColumns+`)
+		SELECT `+sessionColumns+` FROM other.sessions`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge sessions: %w", err)
+	}
+	sessions, _ := res.RowsAffected()
+	result.Sessions = int(sessions)
+	if _, err := tx.ExecContext(ctx, `UPDATE sessions SET
+		project_id = (SELECT local_id FROM merge_project_map WHERE other_id = sessions.project_id)
+		WHERE project_id IN (SELECT other_id FROM merge_project_map) AND session_id IN (SELECT session_id FROM other.sessions)`); err != nil {
+		return nil, fmt.Errorf("failed to map sessions: %w", err)
+	}
+
+	// Merging an archive back into the database it came from restores its breadcrumbs;
+	// from anywhere else, archived breadcrumbs stay archived
+	var archivedFrom string
+	err = tx.GetContext(ctx, &archivedFrom, `SELECT value FROM other.meta WHERE key = ?`, archivedFromKey)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if archivedFrom == d.DeviceID() {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM main.archived_breadcrumbs
+			WHERE (entity_type, entity_id) IN (SELECT entity_type, entity_id FROM other.breadcrumb_events)`); err != nil {
+			return nil, fmt.Errorf("failed to restore archived breadcrumbs: %w", err)
+		}
+	}
+	if err := tx.GetContext(ctx, &result.Skipped, `SELECT COUNT(*) FROM other.breadcrumb_events WHERE NOT `+notArchived); err != nil {
+		return nil, err
+	}
+
+	const eventColumns = `id, entity_type, entity_id, kind, payload, timestamp, device_id, lamport`
+	res, err = tx.ExecContext(ctx, `INSERT OR IGNORE INTO breadcrumb_events (`+eventColumns+`)
+		SELECT `+eventColumns+` FROM other.breadcrumb_events WHERE `+notArchived+` `+breadcrumbReplayOrder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge events: %w", err)
+	}
+	events, _ := res.RowsAffected()
+	result.Events = int(events)
+
+	if len(mapped) > 0 {
+		if result.Remapped, err = d.remapMergedBreadcrumbs(ctx, tx, mapped); err != nil {
+			return nil, fmt.Errorf("failed to map breadcrumbs: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DROP TABLE temp.merge_project_map`); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return result, nil
+	}
+	// Rebuilt in the same transaction, so a stream that can't be replayed isn't kept
+	if result.Events+result.Remapped > 0 {
+		if _, err := rebuildBreadcrumbsTx(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	return result, tx.Commit()
+}
+
+// mergeProjectMap pairs the other database's projects with local ones: by name, then
+// as projectMap asks. Returns other project ID → local project ID, leaving out projects
+// that already share an ID.
+func mergeProjectMap(ctx context.Context, tx *sqlx.Tx, projectMap map[string]string) (map[string]string, error) {
+	var pairs []struct {
+		OtherID string `db:"other_id"`
+		LocalID string `db:"local_id"`
+	}
+	if err := tx.SelectContext(ctx, &pairs, `SELECT o.id AS other_id, l.id AS local_id
+		FROM other.projects o JOIN main.projects l ON l.name = o.name AND l.id != o.id`); err != nil {
+		return nil, err
+	}
+	mapped := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		mapped[p.OtherID] = p.LocalID
+	}
+
+	for from, to := range projectMap {
+		var otherID, localID string
+		err := tx.GetContext(ctx, &otherID, `SELECT id FROM other.projects WHERE name = ? OR id = ? LIMIT 1`, from, from)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: the other database has no project %s", ErrNotFound, from)
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = tx.GetContext(ctx, &localID, `SELECT id FROM main.projects WHERE name = ? OR id = ? LIMIT 1`, to, to)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: no local project %s", ErrNotFound, to)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if otherID == localID {
+			delete(mapped, otherID)
+			continue
+		}
+		mapped[otherID] = localID
+	}
+	return mapped, nil
+}
+
+// remapMergedBreadcrumbs appends a moved event for every breadcrumb whose latest
+// created or moved event files it under a mapped project. Both payloads carry the
+// project ID at the top level. Returns the number of breadcrumbs moved.
+func (d *DB) remapMergedBreadcrumbs(ctx context.Context, tx *sqlx.Tx, mapped map[string]string) (int, error) {
+	var filed []struct {
+		EntityType string         `db:"entity_type"`
+		EntityID   string         `db:"entity_id"`
+		ProjectID  sql.NullString `db:"project_id"`
+	}
+	if err := tx.SelectContext(ctx, &filed, `SELECT entity_type, entity_id, json_extract(payload, '$.project_id') AS project_id
+		FROM breadcrumb_events WHERE kind IN (?, ?, ?, ?) `+breadcrumbReplayOrder,
+		models.EventFindingCreated, models.EventUnknownCreated, models.EventDeadEndCreated, models.EventBreadcrumbMoved); err != nil {
+		return 0, err
+	}
+	type entity struct{ entityType, id string }
+	latest := map[entity]string{}
+	var order []entity
+	for _, f := range filed {
+		e := entity{f.EntityType, f.EntityID}
+		if _, seen := latest[e]; !seen {
+			order = append(order, e)
+		}
+		latest[e] = f.ProjectID.String
+	}
+
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	moved := 0
+	for _, e := range order {
+		localID, ok := mapped[latest[e]]
+		if !ok {
+			continue
+		}
+		payload, err := json.Marshal(models.BreadcrumbMovedPayload{ProjectID: localID, FromProjectID: latest[e]})
+		if err != nil {
+			return 0, err
+		}
+		ev := &models.BreadcrumbEvent{
+			ID:         uuid.New().String(),
+			EntityType: e.entityType,
+			EntityID:   e.id,
+			Kind:       models.EventBreadcrumbMoved,
+			Payload:    string(payload),
+			Timestamp:  now,
+		}
+		if err := d.insertBreadcrumbEvent(ctx, tx, ev); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// RebuildBreadcrumbs discards the breadcrumb read models and replays the event stream
+// into them. Returns the number of events replayed.
+func (d *DB) RebuildBreadcrumbs(ctx context.Context) (int, error) {
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	n, err := rebuildBreadcrumbsTx(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// rebuildBreadcrumbsTx replays the event stream into the read models within tx.
+// Events of archived breadcrumbs are left out: their created event left with them.
+func rebuildBreadcrumbsTx(ctx context.Context, tx *sqlx.Tx) (int, error) {
+	// Commit links reference findings; they're valid again once the replay finishes
+	if _, err := tx.ExecContext(ctx, `PRAGMA defer_foreign_keys = ON`); err != nil {
+		return 0, err
+	}
+	// Scope changes aren't events, so they're carried over the replay
+	if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE rebuild_file_changes AS
+		SELECT id, file_changed_detected_at FROM main.project_findings WHERE file_changed_detected_at IS NOT NULL`); err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"project_findings", "project_unknowns", "project_dead_ends"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table); err != nil {
+			return 0, err
+		}
+	}
+
+	var events []*models.BreadcrumbEvent
+	if err := tx.SelectContext(ctx, &events, `SELECT * FROM main.breadcrumb_events WHERE `+notArchived+` `+breadcrumbReplayOrder); err != nil {
+		return 0, err
+	}
+	for _, ev := range events {
+		if err := projectBreadcrumbEvent(ctx, tx, ev, 0); err != nil {
+			if err == sql.ErrNoRows {
+				return 0, fmt.Errorf("event %d (%s) has no created event for %s", ev.Seq, ev.Kind, ev.EntityID)
+			}
+			return 0, err
+		}
+	}
+
+	// A verification replayed since the change was seen covers it
+	if _, err := tx.ExecContext(ctx, `UPDATE main.project_findings SET file_changed_detected_at = c.file_changed_detected_at
+		FROM temp.rebuild_file_changes c
+		WHERE c.id = project_findings.id
+		AND (project_findings.last_verified_timestamp IS NULL OR project_findings.last_verified_timestamp < c.file_changed_detected_at)`); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DROP TABLE temp.rebuild_file_changes`); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+func TestMergeReplaysBothWays(t *testing.T) {
+	ctx := context.Background()
+	a := openTestDB(t, "a.db")
+	b := openTestDB(t, "b.db")
+	project, session := seedSession(t, a, "shared")
+	repoA, repoB := NewBreadcrumbRepository(a), NewBreadcrumbRepository(b)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "Retries back off", 0.6)
+	unknown := models.NewUnknown(project.ID, session.SessionID, "What caps the backoff?", 0.5)
+	if err := repoA.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	if err := repoA.CreateUnknown(ctx, unknown); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := repoA.GetFinding(ctx, finding.ID)
+	updated := "Retries back off exponentially"
+	if err := repoA.VerifyFinding(ctx, finding.ID, f.Version, nil, &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := b.MergeBreadcrumbs(ctx, a.Path(), nil, false)
+	if err != nil {
+		t.Fatalf("merge a into b: %v", err)
+	}
+	if result.Events != 3 || result.Projects != 1 || result.Sessions != 1 {
+		t.Errorf("merge a into b = %+v, want 3 events, 1 project, 1 session", result)
+	}
+	fb, err := repoB.GetFinding(ctx, finding.ID)
+	if err != nil {
+		t.Fatalf("merged finding: %v", err)
+	}
+	if fb.Finding != updated {
+		t.Errorf("merged finding text = %q, want %q", fb.Finding, updated)
+	}
+
+	// A change made on b comes back to a
+	ub, _ := repoB.GetUnknown(ctx, unknown.ID)
+	if err := repoB.ResolveUnknown(ctx, unknown.ID, "A 30s ceiling", ub.Version); err != nil {
+		t.Fatal(err)
+	}
+	result, err = a.MergeBreadcrumbs(ctx, b.Path(), nil, false)
+	if err != nil {
+		t.Fatalf("merge b into a: %v", err)
+	}
+	if result.Events != 1 {
+		t.Errorf("merge b into a brought %d events, want 1", result.Events)
+	}
+	ua, _ := repoA.GetUnknown(ctx, unknown.ID)
+	if !ua.IsResolved || ua.Version != ub.Version+1 {
+		t.Errorf("unknown on a = resolved %v version %d, want resolved at version %d", ua.IsResolved, ua.Version, ub.Version+1)
+	}
+
+	// Merging again is a no-op
+	result, err = a.MergeBreadcrumbs(ctx, b.Path(), nil, false)
+	if err != nil || result.Events != 0 || result.Sessions != 0 {
+		t.Errorf("second merge = %+v, %v, want nothing new", result, err)
+	}
+
+	// Replaying the merged stream gives the same state on both sides
+	for _, d := range []*DB{a, b} {
+		if _, err := d.RebuildBreadcrumbs(ctx); err != nil {
+			t.Fatalf("rebuild %s: %v", d.Path(), err)
+		}
+	}
+	fa, _ := repoA.GetFinding(ctx, finding.ID)
+	fb, _ = repoB.GetFinding(ctx, finding.ID)
+	ua, _ = repoA.GetUnknown(ctx, unknown.ID)
+	ub, _ = repoB.GetUnknown(ctx, unknown.ID)
+	if fa.Finding != fb.Finding || fa.Version != fb.Version || ua.IsResolved != ub.IsResolved || ua.Version != ub.Version {
+		t.Errorf("replicas differ after rebuild: finding %+v vs %+v, unknown %+v vs %+v", fa, fb, ua, ub)
+	}
+}
+
+func TestApplySyncBatchReplay(t *testing.T) {
+	ctx := context.Background()
+	a := openTestDB(t, "a.db")
+	b := openTestDB(t, "b.db")
+	project, session := seedSession(t, a, "synced")
+	repoA, repoB := NewBreadcrumbRepository(a), NewBreadcrumbRepository(b)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "Cache keys include the tenant", 0.5)
+	if err := repoA.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	batch, err := a.SyncBatchAfter(ctx, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := b.ApplySyncBatch(ctx, batch)
+	if err != nil {
+		t.Fatalf("apply batch: %v", err)
+	}
+	if result.Events != 1 || result.Projects != 1 {
+		t.Errorf("apply = %+v, want 1 event and 1 project", result)
+	}
+
+	// A batch delivered twice, as after a lost acknowledgement, changes nothing
+	result, err = b.ApplySyncBatch(ctx, batch)
+	if err != nil || result.Events != 0 {
+		t.Errorf("reapply = %+v, %v, want no new events", result, err)
+	}
+
+	cursor, _ := a.LatestEventSeq(ctx)
+	f, _ := repoA.GetFinding(ctx, finding.ID)
+	if err := repoA.VerifyFinding(ctx, finding.ID, f.Version, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if batch, err = a.SyncBatchAfter(ctx, cursor, 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ApplySyncBatch(ctx, batch); err != nil {
+		t.Fatalf("apply follow-up batch: %v", err)
+	}
+	fa, _ := repoA.GetFinding(ctx, finding.ID)
+	fb, err := repoB.GetFinding(ctx, finding.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fb.Version != fa.Version || fb.LastVerifiedTimestamp == nil {
+		t.Errorf("synced finding at version %d (verified %v), want version %d and verified", fb.Version, fb.LastVerifiedTimestamp, fa.Version)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	d := openTestDB(t, "memory.db")
+	project, session := seedSession(t, d, "conflicts")
+	repo := NewBreadcrumbRepository(d)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "Workers share one queue", 0.5)
+	if err := repo.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	read, _ := repo.GetFinding(ctx, finding.ID)
+
+	// Two agents read the same version; the second write loses
+	first := "Workers share one queue per region"
+	if err := repo.VerifyFinding(ctx, finding.ID, read.Version, nil, &first); err != nil {
+		t.Fatalf("first verify: %v", err)
+	}
+	second := "Workers each have a queue"
+	err := repo.VerifyFinding(ctx, finding.ID, read.Version, nil, &second)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("stale verify = %v, want ErrVersionConflict", err)
+	}
+	current, _ := repo.GetFinding(ctx, finding.ID)
+	if current.Finding != first || current.Version != read.Version+1 {
+		t.Errorf("finding after conflict = %q at version %d, want %q at version %d", current.Finding, current.Version, first, read.Version+1)
+	}
+	if n := countEvents(t, d, models.EntityFinding, finding.ID); n != 2 {
+		t.Errorf("conflicting write left %d events, want 2", n)
+	}
+
+	// Without an expected version the write goes through
+	if err := repo.VerifyFinding(ctx, finding.ID, 0, nil, nil); err != nil {
+		t.Errorf("unguarded verify: %v", err)
+	}
+
+	unknown := models.NewUnknown(project.ID, session.SessionID, "Who drains the queue?", 0.5)
+	if err := repo.CreateUnknown(ctx, unknown); err != nil {
+		t.Fatal(err)
+	}
+	readUnknown, _ := repo.GetUnknown(ctx, unknown.ID)
+	if err := repo.ResolveUnknown(ctx, unknown.ID, "The scheduler", readUnknown.Version+1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("resolve at a future version = %v, want ErrVersionConflict", err)
+	}
+}
+
+func TestFileChangedStaysLocal(t *testing.T) {
+	ctx := context.Background()
+	a := openTestDB(t, "a.db")
+	b := openTestDB(t, "b.db")
+	project, session := seedSession(t, a, "worktrees")
+	repoA, repoB := NewBreadcrumbRepository(a), NewBreadcrumbRepository(b)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "The parser is recursive", 0.5)
+	if err := repoA.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := repoA.MarkFindingsFileChanged(ctx, []string{finding.ID}, float64(time.Now().UnixMilli())/1000.0); err != nil || n != 1 {
+		t.Fatalf("mark = %d, %v, want 1 flagged", n, err)
+	}
+	if n := countEvents(t, a, models.EntityFinding, finding.ID); n != 1 {
+		t.Errorf("flagging recorded %d events, want only the created one", n)
+	}
+
+	// Another machine's worktree hasn't changed
+	if _, err := b.MergeBreadcrumbs(ctx, a.Path(), nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if fb, _ := repoB.GetFinding(ctx, finding.ID); fb.FileChangedDetectedAt != nil {
+		t.Errorf("merge carried the file change to b")
+	}
+
+	// Rebuilding keeps it, and a later verification clears it
+	if _, err := a.RebuildBreadcrumbs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	fa, _ := repoA.GetFinding(ctx, finding.ID)
+	if fa.FileChangedDetectedAt == nil {
+		t.Fatalf("rebuild dropped the file change")
+	}
+	if err := repoA.VerifyFinding(ctx, finding.ID, fa.Version, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fa, _ = repoA.GetFinding(ctx, finding.ID); fa.FileChangedDetectedAt != nil {
+		t.Errorf("verification left the file change")
+	}
+}
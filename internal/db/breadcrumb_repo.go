@@ -1,8 +1,11 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AbdouB/memory/internal/models"
@@ -19,152 +22,165 @@ func NewBreadcrumbRepository(db *DB) *BreadcrumbRepository {
 }
 
 // CreateFinding creates a new finding
-func (r *BreadcrumbRepository) CreateFinding(finding *models.Finding) error {
-	findingData, err := json.Marshal(finding)
-	if err != nil {
+func (r *BreadcrumbRepository) CreateFinding(ctx context.Context, finding *models.Finding) error {
+	if err := r.db.scrubValue(finding); err != nil {
 		return err
 	}
-
-	query := `
-		INSERT INTO project_findings (
-			id, project_id, session_id, goal_id, subtask_id,
-			finding, created_timestamp, finding_data, subject, impact,
-			last_verified_timestamp, subject_git_hash
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err = r.db.Exec(query,
-		finding.ID,
-		finding.ProjectID,
-		finding.SessionID,
-		finding.GoalID,
-		finding.SubtaskID,
-		finding.Finding,
-		finding.CreatedTimestamp,
-		string(findingData),
-		finding.Subject,
-		finding.Impact,
-		finding.LastVerifiedTimestamp,
-		finding.SubjectGitHash,
-	)
-	return err
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: models.EntityFinding,
+		entityID:   finding.ID,
+		kind:       models.EventFindingCreated,
+		payload:    finding,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityFinding, finding.ID, &finding.ProjectID, finding)
 }
 
 // GetFinding retrieves a finding by ID
-func (r *BreadcrumbRepository) GetFinding(findingID string) (*models.Finding, error) {
-	var findingData string
-	query := `SELECT finding_data FROM project_findings WHERE id = ?`
-	err := r.db.QueryRow(query, findingID).Scan(&findingData)
+func (r *BreadcrumbRepository) GetFinding(ctx context.Context, findingID string) (*models.Finding, error) {
+	query := `SELECT ` + findingColumns + ` FROM project_findings WHERE deleted_at IS NULL AND id = ?`
+	finding, err := scanFinding(r.db.QueryRowContext(ctx, query, findingID))
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound(models.EntityFinding, findingID)
 	}
-	if err != nil {
-		return nil, err
-	}
-
-	var finding models.Finding
-	if err := json.Unmarshal([]byte(findingData), &finding); err != nil {
-		return nil, err
-	}
-	return &finding, nil
+	return finding, err
 }
 
 // ListFindingsWithStaleness lists findings with their staleness metadata loaded from db columns
-func (r *BreadcrumbRepository) ListFindingsWithStaleness(projectID, sessionID string, limit int) ([]*models.Finding, error) {
-	var findings []*models.Finding
-	var query string
-	var args []interface{}
+func (r *BreadcrumbRepository) ListFindingsWithStaleness(ctx context.Context, projectID, sessionID string, limit int) ([]*models.Finding, error) {
+	findings, _, err := r.ListFindingsPage(ctx, projectID, sessionID, Page{Size: limit})
+	return findings, err
+}
 
-	// Select individual columns including staleness fields
-	selectCols := `id, project_id, session_id, goal_id, subtask_id, finding,
-		created_timestamp, subject, impact, last_verified_timestamp, subject_git_hash`
+// ListFindingsPage lists one page of findings with their staleness metadata, newest first.
+// The returned cursor fetches the next page and is nil on the last one.
+func (r *BreadcrumbRepository) ListFindingsPage(ctx context.Context, projectID, sessionID string, page Page) ([]*models.Finding, *Cursor, error) {
+	after, err := page.afterTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if projectID != "" && sessionID != "" {
-		query = `SELECT ` + selectCols + ` FROM project_findings WHERE project_id = ? AND session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, sessionID, limit}
-	} else if projectID != "" {
-		query = `SELECT ` + selectCols + ` FROM project_findings WHERE project_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, limit}
-	} else if sessionID != "" {
-		query = `SELECT ` + selectCols + ` FROM project_findings WHERE session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{sessionID, limit}
-	} else {
-		query = `SELECT ` + selectCols + ` FROM project_findings ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{limit}
+	query := `SELECT ` + findingColumns + ` FROM project_findings WHERE deleted_at IS NULL`
+	var args []interface{}
+	if projectID != "" {
+		query += ` AND project_id = ?`
+		args = append(args, projectID)
+	}
+	if sessionID != "" {
+		query += ` AND session_id = ?`
+		args = append(args, sessionID)
 	}
+	query, args = page.byAgent(query, args)
+	query, args = page.keyset(query, args, "created_timestamp", "id", after)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	findings, err := scanAll(rows, err, scanFinding)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var f models.Finding
-		if err := rows.Scan(
-			&f.ID,
-			&f.ProjectID,
-			&f.SessionID,
-			&f.GoalID,
-			&f.SubtaskID,
-			&f.Finding,
-			&f.CreatedTimestamp,
-			&f.Subject,
-			&f.Impact,
-			&f.LastVerifiedTimestamp,
-			&f.SubjectGitHash,
-		); err != nil {
-			return nil, err
-		}
-		findings = append(findings, &f)
+	findings, more := trim(findings, page)
+	if !more {
+		return findings, nil, nil
 	}
-
-	return findings, rows.Err()
+	last := findings[len(findings)-1]
+	return findings, timestampCursor(last.CreatedTimestamp, last.ID), nil
 }
 
-// VerifyFinding refreshes the verification timestamp and optionally updates the text and git hash
-func (r *BreadcrumbRepository) VerifyFinding(findingID string, newGitHash, updatedText *string) error {
-	now := float64(time.Now().UnixMilli()) / 1000.0
-
-	// Build update query based on what needs updating
-	query := `UPDATE project_findings SET last_verified_timestamp = ?`
-	args := []interface{}{now}
+// VerifyFinding refreshes the verification timestamp and optionally updates the text and git hash.
+// Verifying clears any file change flag. With expectVersion > 0 the finding must still be at
+// that version, otherwise ErrVersionConflict is returned and nothing is written.
+func (r *BreadcrumbRepository) VerifyFinding(ctx context.Context, findingID string, expectVersion int, newGitHash, updatedText *string) error {
+	payload := models.FindingVerifiedPayload{
+		VerifiedAt: float64(time.Now().UnixMilli()) / 1000.0,
+		GitHash:    newGitHash,
+		Finding:    updatedText,
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType:    models.EntityFinding,
+		entityID:      findingID,
+		kind:          models.EventFindingVerified,
+		payload:       payload,
+		expectVersion: expectVersion,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditVerify, models.EntityFinding, findingID, r.findingProjectID(ctx, findingID), payload)
+}
 
-	if newGitHash != nil {
-		query += `, subject_git_hash = ?`
-		args = append(args, *newGitHash)
+// FindingHistory returns the texts a finding has had, folded from its events. Deleted
+// findings keep their history.
+func (r *BreadcrumbRepository) FindingHistory(ctx context.Context, findingID string) (*models.FindingHistory, error) {
+	var events []*models.BreadcrumbEvent
+	if err := r.db.SelectContext(ctx, &events, `SELECT * FROM breadcrumb_events WHERE entity_type = ? AND entity_id = ? `+breadcrumbReplayOrder,
+		models.EntityFinding, findingID); err != nil {
+		return nil, err
 	}
-	if updatedText != nil {
-		query += `, finding = ?`
-		args = append(args, *updatedText)
+	if len(events) == 0 {
+		return nil, notFound(models.EntityFinding, findingID)
 	}
+	return models.FoldFindingHistory(findingID, events)
+}
 
-	query += ` WHERE id = ?`
-	args = append(args, findingID)
+// MarkFindingFileChanged records when a finding's scoped file was first detected as changed
+func (r *BreadcrumbRepository) MarkFindingFileChanged(ctx context.Context, findingID string, detectedAt float64) error {
+	_, err := r.MarkFindingsFileChanged(ctx, []string{findingID}, detectedAt)
+	return err
+}
 
-	result, err := r.db.Exec(query, args...)
+// MarkFindingsFileChanged flags many findings as file-changed in a single statement.
+// Findings already flagged keep their original detection time. Whether a scope changed
+// depends on this machine's worktree, so the flag lives in the read model only: it's
+// neither an event, which sync and merge would carry to other machines, nor audited.
+// A verification after the detection clears it.
+func (r *BreadcrumbRepository) MarkFindingsFileChanged(ctx context.Context, findingIDs []string, detectedAt float64) (int64, error) {
+	if len(findingIDs) == 0 {
+		return 0, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(findingIDs)), ",")
+	args := make([]interface{}, 0, len(findingIDs)+2)
+	args = append(args, detectedAt, detectedAt)
+	for _, id := range findingIDs {
+		args = append(args, id)
+	}
+	res, err := r.db.ExecContext(ctx, `UPDATE project_findings SET file_changed_detected_at = ?
+		WHERE deleted_at IS NULL AND file_changed_detected_at IS NULL
+		AND (last_verified_timestamp IS NULL OR last_verified_timestamp < ?)
+		AND id IN (`+placeholders+`)`, args...)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return res.RowsAffected()
+}
 
-	rows, err := result.RowsAffected()
-	if err != nil {
+// RecordVerificationEvidence stores the output of a finding's verification check
+func (r *BreadcrumbRepository) RecordVerificationEvidence(ctx context.Context, findingID, evidence string) error {
+	payload := models.FindingEvidencePayload{Evidence: evidence}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: models.EntityFinding,
+		entityID:   findingID,
+		kind:       models.EventFindingEvidenceRecorded,
+		payload:    payload,
+	}); err != nil {
 		return err
 	}
-	if rows == 0 {
-		return sql.ErrNoRows
-	}
+	return r.db.audit(ctx, models.AuditVerify, models.EntityFinding, findingID, r.findingProjectID(ctx, findingID), payload)
+}
 
-	return nil
+// findingProjectID looks up a finding's project for audit entries
+func (r *BreadcrumbRepository) findingProjectID(ctx context.Context, findingID string) *string {
+	var projectID string
+	if err := r.db.GetContext(ctx, &projectID, `SELECT project_id FROM project_findings WHERE id = ?`, findingID); err != nil {
+		return nil
+	}
+	return &projectID
 }
 
 // FindFindingByText searches for findings containing the given text
-func (r *BreadcrumbRepository) FindFindingByText(projectID, searchText string) ([]*models.Finding, error) {
-	var findings []*models.Finding
-
-	selectCols := `id, project_id, session_id, goal_id, subtask_id, finding,
-		created_timestamp, subject, impact, last_verified_timestamp, subject_git_hash`
-
-	query := `SELECT ` + selectCols + ` FROM project_findings WHERE finding LIKE ?`
+func (r *BreadcrumbRepository) FindFindingByText(ctx context.Context, projectID, searchText string) ([]*models.Finding, error) {
+	query := `SELECT ` + findingColumns + ` FROM project_findings WHERE deleted_at IS NULL AND finding LIKE ?`
 	args := []interface{}{"%" + searchText + "%"}
 
 	if projectID != "" {
@@ -174,274 +190,588 @@ func (r *BreadcrumbRepository) FindFindingByText(projectID, searchText string) (
 
 	query += ` ORDER BY created_timestamp DESC LIMIT 10`
 
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	return scanAll(rows, err, scanFinding)
+}
 
-	for rows.Next() {
-		var f models.Finding
-		if err := rows.Scan(
-			&f.ID,
-			&f.ProjectID,
-			&f.SessionID,
-			&f.GoalID,
-			&f.SubtaskID,
-			&f.Finding,
-			&f.CreatedTimestamp,
-			&f.Subject,
-			&f.Impact,
-			&f.LastVerifiedTimestamp,
-			&f.SubjectGitHash,
-		); err != nil {
-			return nil, err
-		}
-		findings = append(findings, &f)
-	}
+// FindFindingsByIDPrefix lists the live findings whose ID starts with prefix, so the short
+// IDs shown in text output can be used to select a finding
+func (r *BreadcrumbRepository) FindFindingsByIDPrefix(ctx context.Context, prefix string) ([]*models.Finding, error) {
+	query := `SELECT ` + findingColumns + ` FROM project_findings
+		WHERE deleted_at IS NULL AND substr(id, 1, ?) = ?
+		ORDER BY created_timestamp DESC LIMIT 10`
+
+	rows, err := r.db.QueryContext(ctx, query, len(prefix), prefix)
+	return scanAll(rows, err, scanFinding)
+}
 
-	return findings, rows.Err()
+// FindFindingsTouching lists findings with a scope or whose text mentions the needle.
+// Every scoped finding is a candidate, since its scope may be a directory containing
+// the file; callers match scopes against the file.
+func (r *BreadcrumbRepository) FindFindingsTouching(ctx context.Context, projectID, needle string) ([]*models.Finding, error) {
+	query := `SELECT ` + findingColumns + ` FROM project_findings
+		WHERE deleted_at IS NULL AND project_id = ? AND (COALESCE(subject, '') != '' OR finding LIKE ?)
+		ORDER BY created_timestamp DESC`
+	pattern := "%" + needle + "%"
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, pattern)
+	return scanAll(rows, err, scanFinding)
 }
 
 // ListFindings lists findings with filtering
-func (r *BreadcrumbRepository) ListFindings(projectID, sessionID string, limit int) ([]*models.Finding, error) {
-	var findings []*models.Finding
-	var query string
+func (r *BreadcrumbRepository) ListFindings(ctx context.Context, projectID, sessionID string, limit int) ([]*models.Finding, error) {
+	query := `SELECT ` + findingColumns + ` FROM project_findings WHERE deleted_at IS NULL`
 	var args []interface{}
+	if projectID != "" {
+		query += ` AND project_id = ?`
+		args = append(args, projectID)
+	}
+	if sessionID != "" {
+		query += ` AND session_id = ?`
+		args = append(args, sessionID)
+	}
+	query += ` ORDER BY created_timestamp DESC LIMIT ?`
+	args = append(args, limit)
 
-	if projectID != "" && sessionID != "" {
-		query = `SELECT finding_data FROM project_findings WHERE project_id = ? AND session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, sessionID, limit}
-	} else if projectID != "" {
-		query = `SELECT finding_data FROM project_findings WHERE project_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, limit}
-	} else if sessionID != "" {
-		query = `SELECT finding_data FROM project_findings WHERE session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{sessionID, limit}
-	} else {
-		query = `SELECT finding_data FROM project_findings ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{limit}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	return scanAll(rows, err, scanFinding)
+}
+
+// CreateUnknown creates a new unknown
+func (r *BreadcrumbRepository) CreateUnknown(ctx context.Context, unknown *models.Unknown) error {
+	if err := r.db.scrubValue(unknown); err != nil {
+		return err
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: models.EntityUnknown,
+		entityID:   unknown.ID,
+		kind:       models.EventUnknownCreated,
+		payload:    unknown,
+	}); err != nil {
+		return err
 	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityUnknown, unknown.ID, &unknown.ProjectID, unknown)
+}
 
-	rows, err := r.db.Query(query, args...)
+// GetUnknown retrieves an unknown by ID
+func (r *BreadcrumbRepository) GetUnknown(ctx context.Context, unknownID string) (*models.Unknown, error) {
+	query := `SELECT ` + unknownColumns + ` FROM project_unknowns WHERE deleted_at IS NULL AND id = ?`
+	unknown, err := scanUnknown(r.db.QueryRowContext(ctx, query, unknownID))
+	if err == sql.ErrNoRows {
+		return nil, notFound(models.EntityUnknown, unknownID)
+	}
+	return unknown, err
+}
+
+// ListUnknowns lists unknowns with filtering
+func (r *BreadcrumbRepository) ListUnknowns(ctx context.Context, projectID, sessionID string, resolved *bool, limit int) ([]*models.Unknown, error) {
+	unknowns, _, err := r.ListUnknownsPage(ctx, projectID, sessionID, resolved, Page{Size: limit})
+	return unknowns, err
+}
+
+// ListUnknownsPage lists one page of unknowns, newest first. The returned cursor
+// fetches the next page and is nil on the last one.
+func (r *BreadcrumbRepository) ListUnknownsPage(ctx context.Context, projectID, sessionID string, resolved *bool, page Page) ([]*models.Unknown, *Cursor, error) {
+	after, err := page.afterTimestamp()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var findingData string
-		if err := rows.Scan(&findingData); err != nil {
-			return nil, err
-		}
+	query := `SELECT ` + unknownColumns + ` FROM project_unknowns WHERE deleted_at IS NULL`
+	var args []interface{}
+	if projectID != "" {
+		query += ` AND project_id = ?`
+		args = append(args, projectID)
+	}
+	if sessionID != "" {
+		query += ` AND session_id = ?`
+		args = append(args, sessionID)
+	}
+	if resolved != nil {
+		query += ` AND is_resolved = ?`
+		args = append(args, *resolved)
+	}
+	query, args = page.byAgent(query, args)
+	query, args = page.keyset(query, args, "created_timestamp", "id", after)
 
-		var finding models.Finding
-		if err := json.Unmarshal([]byte(findingData), &finding); err != nil {
-			return nil, err
-		}
-		findings = append(findings, &finding)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	unknowns, err := scanAll(rows, err, scanUnknown)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return findings, rows.Err()
+	unknowns, more := trim(unknowns, page)
+	if !more {
+		return unknowns, nil, nil
+	}
+	last := unknowns[len(unknowns)-1]
+	return unknowns, timestampCursor(last.CreatedTimestamp, last.ID), nil
 }
 
-// CreateUnknown creates a new unknown
-func (r *BreadcrumbRepository) CreateUnknown(unknown *models.Unknown) error {
-	unknownData, err := json.Marshal(unknown)
+// FindUnknownsTouching lists unknowns with a scope or whose text mentions the needle,
+// leaving callers to match scopes as FindFindingsTouching does
+func (r *BreadcrumbRepository) FindUnknownsTouching(ctx context.Context, projectID, needle string, resolved *bool) ([]*models.Unknown, error) {
+	query := `SELECT ` + unknownColumns + ` FROM project_unknowns
+		WHERE deleted_at IS NULL AND project_id = ? AND (COALESCE(subject, '') != '' OR unknown LIKE ?)`
+	pattern := "%" + needle + "%"
+	args := []interface{}{projectID, pattern}
+	if resolved != nil {
+		query += ` AND is_resolved = ?`
+		args = append(args, *resolved)
+	}
+	query += ` ORDER BY created_timestamp DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	return scanAll(rows, err, scanUnknown)
+}
+
+// ResolveUnknown marks an unknown as resolved. With expectVersion > 0 the unknown must still
+// be at that version, otherwise ErrVersionConflict is returned and nothing is written.
+func (r *BreadcrumbRepository) ResolveUnknown(ctx context.Context, unknownID, resolvedBy string, expectVersion int) error {
+	unknown, err := r.GetUnknown(ctx, unknownID)
 	if err != nil {
 		return err
 	}
 
-	query := `
-		INSERT INTO project_unknowns (
-			id, project_id, session_id, goal_id, subtask_id,
-			unknown, is_resolved, created_timestamp, unknown_data, subject, impact
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err = r.db.Exec(query,
-		unknown.ID,
-		unknown.ProjectID,
-		unknown.SessionID,
-		unknown.GoalID,
-		unknown.SubtaskID,
-		unknown.Unknown,
-		unknown.IsResolved,
-		unknown.CreatedTimestamp,
-		string(unknownData),
-		unknown.Subject,
-		unknown.Impact,
-	)
-	return err
+	payload := models.UnknownResolvedPayload{
+		ResolvedBy: resolvedBy,
+		ResolvedAt: float64(time.Now().UnixMilli()) / 1000.0,
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType:    models.EntityUnknown,
+		entityID:      unknownID,
+		kind:          models.EventUnknownResolved,
+		payload:       payload,
+		expectVersion: expectVersion,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditResolve, models.EntityUnknown, unknownID, &unknown.ProjectID, payload)
 }
 
-// GetUnknown retrieves an unknown by ID
-func (r *BreadcrumbRepository) GetUnknown(unknownID string) (*models.Unknown, error) {
-	var unknownData string
-	query := `SELECT unknown_data FROM project_unknowns WHERE id = ?`
-	err := r.db.QueryRow(query, unknownID).Scan(&unknownData)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
+// SetUnknownImpact changes how much an unknown matters
+func (r *BreadcrumbRepository) SetUnknownImpact(ctx context.Context, unknownID string, impact float64) error {
+	unknown, err := r.GetUnknown(ctx, unknownID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var unknown models.Unknown
-	if err := json.Unmarshal([]byte(unknownData), &unknown); err != nil {
-		return nil, err
+	payload := models.UnknownReprioritizedPayload{Impact: impact}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: models.EntityUnknown,
+		entityID:   unknownID,
+		kind:       models.EventUnknownReprioritized,
+		payload:    payload,
+	}); err != nil {
+		return err
 	}
-	return &unknown, nil
+	return r.db.audit(ctx, models.AuditEdit, models.EntityUnknown, unknownID, &unknown.ProjectID, payload)
 }
 
-// ListUnknowns lists unknowns with filtering
-func (r *BreadcrumbRepository) ListUnknowns(projectID, sessionID string, resolved *bool, limit int) ([]*models.Unknown, error) {
-	var unknowns []*models.Unknown
-	var query string
-	var args []interface{}
+// CreateDeadEnd creates a new dead end
+func (r *BreadcrumbRepository) CreateDeadEnd(ctx context.Context, deadEnd *models.DeadEnd) error {
+	if err := r.db.scrubValue(deadEnd); err != nil {
+		return err
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: models.EntityDeadEnd,
+		entityID:   deadEnd.ID,
+		kind:       models.EventDeadEndCreated,
+		payload:    deadEnd,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityDeadEnd, deadEnd.ID, &deadEnd.ProjectID, deadEnd)
+}
+
+// ListDeadEnds lists dead ends with filtering
+func (r *BreadcrumbRepository) ListDeadEnds(ctx context.Context, projectID, sessionID string, limit int) ([]*models.DeadEnd, error) {
+	deadEnds, _, err := r.ListDeadEndsPage(ctx, projectID, sessionID, Page{Size: limit})
+	return deadEnds, err
+}
 
-	baseQuery := `SELECT unknown_data FROM project_unknowns WHERE 1=1`
+// ListDeadEndsPage lists one page of dead ends, newest first. The returned cursor
+// fetches the next page and is nil on the last one.
+func (r *BreadcrumbRepository) ListDeadEndsPage(ctx context.Context, projectID, sessionID string, page Page) ([]*models.DeadEnd, *Cursor, error) {
+	after, err := page.afterTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
 
+	query := `SELECT ` + deadEndColumns + ` FROM project_dead_ends WHERE deleted_at IS NULL`
+	var args []interface{}
 	if projectID != "" {
-		baseQuery += ` AND project_id = ?`
+		query += ` AND project_id = ?`
 		args = append(args, projectID)
 	}
 	if sessionID != "" {
-		baseQuery += ` AND session_id = ?`
+		query += ` AND session_id = ?`
 		args = append(args, sessionID)
 	}
-	if resolved != nil {
-		baseQuery += ` AND is_resolved = ?`
-		args = append(args, *resolved)
+	query, args = page.byAgent(query, args)
+	query, args = page.keyset(query, args, "created_timestamp", "id", after)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	deadEnds, err := scanAll(rows, err, scanDeadEnd)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	query = baseQuery + ` ORDER BY created_timestamp DESC LIMIT ?`
-	args = append(args, limit)
+	deadEnds, more := trim(deadEnds, page)
+	if !more {
+		return deadEnds, nil, nil
+	}
+	last := deadEnds[len(deadEnds)-1]
+	return deadEnds, timestampCursor(last.CreatedTimestamp, last.ID), nil
+}
 
-	rows, err := r.db.Query(query, args...)
-	if err != nil {
-		return nil, err
+// FindDeadEndsTouching lists dead ends with a scope or whose approach or reason mentions
+// the needle, leaving callers to match scopes as FindFindingsTouching does
+func (r *BreadcrumbRepository) FindDeadEndsTouching(ctx context.Context, projectID, needle string) ([]*models.DeadEnd, error) {
+	query := `SELECT ` + deadEndColumns + ` FROM project_dead_ends
+		WHERE deleted_at IS NULL AND project_id = ? AND (COALESCE(subject, '') != '' OR approach LIKE ? OR why_failed LIKE ?)
+		ORDER BY created_timestamp DESC`
+	pattern := "%" + needle + "%"
+
+	rows, err := r.db.QueryContext(ctx, query, projectID, pattern, pattern)
+	return scanAll(rows, err, scanDeadEnd)
+}
+
+// breadcrumbTables maps breadcrumb entity types to their read model tables
+var breadcrumbTables = map[string]string{
+	models.EntityFinding: "project_findings",
+	models.EntityUnknown: "project_unknowns",
+	models.EntityDeadEnd: "project_dead_ends",
+}
+
+// breadcrumbTombstoneKinds maps breadcrumb entity types to their deleted and restored events
+var breadcrumbTombstoneKinds = map[string][2]models.BreadcrumbEventKind{
+	models.EntityFinding: {models.EventFindingDeleted, models.EventFindingRestored},
+	models.EntityUnknown: {models.EventUnknownDeleted, models.EventUnknownRestored},
+	models.EntityDeadEnd: {models.EventDeadEndDeleted, models.EventDeadEndRestored},
+}
+
+// TombstoneKind returns the event DeleteBreadcrumb, or RestoreBreadcrumb with restore,
+// records for an entity type
+func TombstoneKind(entityType string, restore bool) (models.BreadcrumbEventKind, bool) {
+	kinds, ok := breadcrumbTombstoneKinds[entityType]
+	if restore {
+		return kinds[1], ok
 	}
-	defer rows.Close()
+	return kinds[0], ok
+}
 
-	for rows.Next() {
-		var unknownData string
-		if err := rows.Scan(&unknownData); err != nil {
-			return nil, err
+// BreadcrumbType returns whether an ID is a finding, unknown or dead end, including deleted ones.
+// Returns ErrNotFound if no breadcrumb has the ID.
+func (r *BreadcrumbRepository) BreadcrumbType(ctx context.Context, id string) (string, error) {
+	for _, entityType := range []string{models.EntityFinding, models.EntityUnknown, models.EntityDeadEnd} {
+		var count int
+		query := `SELECT COUNT(*) FROM ` + breadcrumbTables[entityType] + ` WHERE id = ?`
+		if err := r.db.GetContext(ctx, &count, query, id); err != nil {
+			return "", err
 		}
-
-		var unknown models.Unknown
-		if err := json.Unmarshal([]byte(unknownData), &unknown); err != nil {
-			return nil, err
+		if count > 0 {
+			return entityType, nil
 		}
-		unknowns = append(unknowns, &unknown)
 	}
+	return "", notFound("breadcrumb", id)
+}
 
-	return unknowns, rows.Err()
+// breadcrumbState returns a breadcrumb's project and tombstone, or ErrNotFound
+func (r *BreadcrumbRepository) breadcrumbState(ctx context.Context, entityType, id string) (string, *float64, error) {
+	var row struct {
+		ProjectID string   `db:"project_id"`
+		DeletedAt *float64 `db:"deleted_at"`
+	}
+	query := `SELECT project_id, deleted_at FROM ` + breadcrumbTables[entityType] + ` WHERE id = ?`
+	err := r.db.GetContext(ctx, &row, query, id)
+	if err == sql.ErrNoRows {
+		return "", nil, notFound(entityType, id)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return row.ProjectID, row.DeletedAt, nil
 }
 
-// ResolveUnknown marks an unknown as resolved
-func (r *BreadcrumbRepository) ResolveUnknown(unknownID, resolvedBy string) error {
-	now := float64(time.Now().UnixMilli()) / 1000.0
+// DeleteBreadcrumb tombstones a finding, unknown or dead end. Its history is kept and
+// it can be restored; deleting an already deleted breadcrumb is a no-op.
+func (r *BreadcrumbRepository) DeleteBreadcrumb(ctx context.Context, entityType, id, reason string) error {
+	kinds, ok := breadcrumbTombstoneKinds[entityType]
+	if !ok {
+		return fmt.Errorf("%w: cannot delete %s", ErrInvalid, entityType)
+	}
+	projectID, deletedAt, err := r.breadcrumbState(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if deletedAt != nil {
+		return nil
+	}
+
+	payload := models.BreadcrumbDeletedPayload{
+		DeletedAt: float64(time.Now().UnixMilli()) / 1000.0,
+		Reason:    reason,
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: entityType,
+		entityID:   id,
+		kind:       kinds[0],
+		payload:    payload,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditDelete, entityType, id, &projectID, payload)
+}
 
-	// Get current unknown
-	unknown, err := r.GetUnknown(unknownID)
+// RestoreBreadcrumb removes a breadcrumb's tombstone; restoring a live breadcrumb is a no-op
+func (r *BreadcrumbRepository) RestoreBreadcrumb(ctx context.Context, entityType, id string) error {
+	kinds, ok := breadcrumbTombstoneKinds[entityType]
+	if !ok {
+		return fmt.Errorf("%w: cannot restore %s", ErrInvalid, entityType)
+	}
+	projectID, deletedAt, err := r.breadcrumbState(ctx, entityType, id)
 	if err != nil {
 		return err
 	}
-	if unknown == nil {
-		return sql.ErrNoRows
+	if deletedAt == nil {
+		return nil
 	}
 
-	unknown.IsResolved = true
-	unknown.ResolvedBy = &resolvedBy
-	unknown.ResolvedTimestamp = &now
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: entityType,
+		entityID:   id,
+		kind:       kinds[1],
+		payload:    struct{}{},
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditRestore, entityType, id, &projectID, nil)
+}
 
-	unknownData, err := json.Marshal(unknown)
+// TagBreadcrumb adds tags to a live finding, unknown or dead end. Tags are a grow-only
+// set, so tagging twice or on two machines converges to the union.
+func (r *BreadcrumbRepository) TagBreadcrumb(ctx context.Context, entityType, id string, tags []string) error {
+	projectID, deletedAt, err := r.breadcrumbState(ctx, entityType, id)
 	if err != nil {
 		return err
 	}
+	if deletedAt != nil {
+		return notFound(entityType, id)
+	}
 
-	query := `
-		UPDATE project_unknowns SET 
-			is_resolved = 1,
-			resolved_by = ?,
-			resolved_timestamp = ?,
-			unknown_data = ?
-		WHERE id = ?
-	`
-	_, err = r.db.Exec(query, resolvedBy, now, string(unknownData), unknownID)
-	return err
+	events := make([]newBreadcrumbEvent, 0, len(tags))
+	for _, tag := range tags {
+		events = append(events, newBreadcrumbEvent{
+			entityType: entityType,
+			entityID:   id,
+			kind:       models.EventBreadcrumbTagged,
+			payload:    models.BreadcrumbTaggedPayload{Tag: tag},
+		})
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, events...); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, entityType, id, &projectID, map[string]interface{}{"tags": tags})
 }
 
-// CreateDeadEnd creates a new dead end
-func (r *BreadcrumbRepository) CreateDeadEnd(deadEnd *models.DeadEnd) error {
-	deadEndData, err := json.Marshal(deadEnd)
+// RelateBreadcrumb links a live finding, unknown or dead end to another breadcrumb.
+// Relations are a grow-only set like tags.
+func (r *BreadcrumbRepository) RelateBreadcrumb(ctx context.Context, entityType, id string, relation models.BreadcrumbRelation) error {
+	projectID, deletedAt, err := r.breadcrumbState(ctx, entityType, id)
 	if err != nil {
 		return err
 	}
+	if deletedAt != nil {
+		return notFound(entityType, id)
+	}
 
-	query := `
-		INSERT INTO project_dead_ends (
-			id, project_id, session_id, goal_id, subtask_id,
-			approach, why_failed, created_timestamp, dead_end_data, subject, impact
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err = r.db.Exec(query,
-		deadEnd.ID,
-		deadEnd.ProjectID,
-		deadEnd.SessionID,
-		deadEnd.GoalID,
-		deadEnd.SubtaskID,
-		deadEnd.Approach,
-		deadEnd.WhyFailed,
-		deadEnd.CreatedTimestamp,
-		string(deadEndData),
-		deadEnd.Subject,
-		deadEnd.Impact,
-	)
-	return err
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: entityType,
+		entityID:   id,
+		kind:       models.EventBreadcrumbRelated,
+		payload:    relation,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, entityType, id, &projectID, relation)
 }
 
-// ListDeadEnds lists dead ends with filtering
-func (r *BreadcrumbRepository) ListDeadEnds(projectID, sessionID string, limit int) ([]*models.DeadEnd, error) {
-	var deadEnds []*models.DeadEnd
-	var query string
-	var args []interface{}
+// MoveBreadcrumb files a live finding, unknown or dead end under another project. The
+// breadcrumb keeps its ID, session and history; moving it to its own project is a no-op.
+func (r *BreadcrumbRepository) MoveBreadcrumb(ctx context.Context, entityType, id, projectID string) error {
+	fromProjectID, deletedAt, err := r.breadcrumbState(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	if deletedAt != nil {
+		return notFound(entityType, id)
+	}
+	if fromProjectID == projectID {
+		return nil
+	}
 
-	if projectID != "" && sessionID != "" {
-		query = `SELECT dead_end_data FROM project_dead_ends WHERE project_id = ? AND session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, sessionID, limit}
-	} else if projectID != "" {
-		query = `SELECT dead_end_data FROM project_dead_ends WHERE project_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{projectID, limit}
-	} else if sessionID != "" {
-		query = `SELECT dead_end_data FROM project_dead_ends WHERE session_id = ? ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{sessionID, limit}
-	} else {
-		query = `SELECT dead_end_data FROM project_dead_ends ORDER BY created_timestamp DESC LIMIT ?`
-		args = []interface{}{limit}
+	payload := models.BreadcrumbMovedPayload{ProjectID: projectID, FromProjectID: fromProjectID}
+	if err := r.db.appendBreadcrumbEvents(ctx, newBreadcrumbEvent{
+		entityType: entityType,
+		entityID:   id,
+		kind:       models.EventBreadcrumbMoved,
+		payload:    payload,
+	}); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, entityType, id, &projectID, payload)
+}
+
+// CopyBreadcrumb files a copy of a live finding, unknown or dead end under another
+// project and returns the copy's ID. The copy keeps the original's timestamps and
+// session and records a copied_from relation back to it.
+func (r *BreadcrumbRepository) CopyBreadcrumb(ctx context.Context, entityType, id, projectID string) (string, error) {
+	switch entityType {
+	case models.EntityFinding:
+		f, err := r.GetFinding(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		c := f.CopyTo(projectID)
+		return c.ID, r.CreateFinding(ctx, c)
+	case models.EntityUnknown:
+		u, err := r.GetUnknown(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		c := u.CopyTo(projectID)
+		return c.ID, r.CreateUnknown(ctx, c)
+	case models.EntityDeadEnd:
+		de, err := scanDeadEnd(r.db.QueryRowContext(ctx, `SELECT `+deadEndColumns+` FROM project_dead_ends WHERE deleted_at IS NULL AND id = ?`, id))
+		if err == sql.ErrNoRows {
+			return "", notFound(entityType, id)
+		}
+		if err != nil {
+			return "", err
+		}
+		c := de.CopyTo(projectID)
+		return c.ID, r.CreateDeadEnd(ctx, c)
 	}
+	return "", fmt.Errorf("%w: cannot copy %s", ErrInvalid, entityType)
+}
 
-	rows, err := r.db.Query(query, args...)
+// ImportFinding merges a finding from another source, such as a teammate's shared
+// export. Missing findings are created as given; existing ones pick up a newer
+// verification (with its text and git hash) and any tags or relations they lack.
+// Deleted findings stay deleted. Returns AuditCreate or AuditEdit for what was (or with
+// dryRun, would be) written, or "" if the finding is already up to date.
+func (r *BreadcrumbRepository) ImportFinding(ctx context.Context, f *models.Finding, dryRun bool) (models.AuditAction, error) {
+	local, err := scanFinding(r.db.QueryRowContext(ctx, `SELECT `+findingColumns+` FROM project_findings WHERE id = ?`, f.ID))
+	if err == sql.ErrNoRows {
+		if dryRun {
+			return models.AuditCreate, nil
+		}
+		return models.AuditCreate, r.CreateFinding(ctx, f)
+	}
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if local.DeletedAt != nil {
+		return "", nil
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var deadEndData string
-		if err := rows.Scan(&deadEndData); err != nil {
-			return nil, err
+	events := setEventsFor(models.EntityFinding, f.ID, local.Tags, local.Relations, f.Tags, f.Relations)
+	if f.LastVerifiedTimestamp != nil &&
+		(local.LastVerifiedTimestamp == nil || *f.LastVerifiedTimestamp > *local.LastVerifiedTimestamp) {
+		payload := models.FindingVerifiedPayload{
+			VerifiedAt: *f.LastVerifiedTimestamp,
+			GitHash:    f.SubjectGitHash,
 		}
+		if f.Finding != local.Finding {
+			payload.Finding = &f.Finding
+		}
+		events = append(events, newBreadcrumbEvent{
+			entityType: models.EntityFinding,
+			entityID:   f.ID,
+			kind:       models.EventFindingVerified,
+			payload:    payload,
+		})
+	}
+	if len(events) == 0 {
+		return "", nil
+	}
+	if dryRun {
+		return models.AuditEdit, nil
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, events...); err != nil {
+		return "", err
+	}
+	return models.AuditEdit, r.db.audit(ctx, models.AuditEdit, models.EntityFinding, f.ID, &local.ProjectID, f)
+}
 
-		var deadEnd models.DeadEnd
-		if err := json.Unmarshal([]byte(deadEndData), &deadEnd); err != nil {
-			return nil, err
+// ImportDeadEnd merges a dead end from another source. Missing dead ends are created;
+// existing ones pick up any tags or relations they lack. Returns the action written (or
+// with dryRun, that would be), like ImportFinding.
+func (r *BreadcrumbRepository) ImportDeadEnd(ctx context.Context, de *models.DeadEnd, dryRun bool) (models.AuditAction, error) {
+	local, err := scanDeadEnd(r.db.QueryRowContext(ctx, `SELECT `+deadEndColumns+` FROM project_dead_ends WHERE id = ?`, de.ID))
+	if err == sql.ErrNoRows {
+		if dryRun {
+			return models.AuditCreate, nil
 		}
-		deadEnds = append(deadEnds, &deadEnd)
+		return models.AuditCreate, r.CreateDeadEnd(ctx, de)
+	}
+	if err != nil {
+		return "", err
 	}
+	if local.DeletedAt != nil {
+		return "", nil
+	}
+
+	events := setEventsFor(models.EntityDeadEnd, de.ID, local.Tags, local.Relations, de.Tags, de.Relations)
+	if len(events) == 0 {
+		return "", nil
+	}
+	if dryRun {
+		return models.AuditEdit, nil
+	}
+	if err := r.db.appendBreadcrumbEvents(ctx, events...); err != nil {
+		return "", err
+	}
+	return models.AuditEdit, r.db.audit(ctx, models.AuditEdit, models.EntityDeadEnd, de.ID, &local.ProjectID, de)
+}
 
-	return deadEnds, rows.Err()
+// setEventsFor returns the tagged and related events that add the incoming tags and
+// relations missing from the local sets
+func setEventsFor(entityType, id string, haveTags []string, haveRelations []models.BreadcrumbRelation,
+	tags []string, relations []models.BreadcrumbRelation) []newBreadcrumbEvent {
+	var events []newBreadcrumbEvent
+	known := make(map[string]bool, len(haveTags))
+	for _, t := range haveTags {
+		known[t] = true
+	}
+	for _, t := range tags {
+		if !known[t] {
+			known[t] = true
+			events = append(events, newBreadcrumbEvent{
+				entityType: entityType,
+				entityID:   id,
+				kind:       models.EventBreadcrumbTagged,
+				payload:    models.BreadcrumbTaggedPayload{Tag: t},
+			})
+		}
+	}
+	knownRelations := make(map[models.BreadcrumbRelation]bool, len(haveRelations))
+	for _, rel := range haveRelations {
+		knownRelations[rel] = true
+	}
+	for _, rel := range relations {
+		if !knownRelations[rel] {
+			knownRelations[rel] = true
+			events = append(events, newBreadcrumbEvent{
+				entityType: entityType,
+				entityID:   id,
+				kind:       models.EventBreadcrumbRelated,
+				payload:    rel,
+			})
+		}
+	}
+	return events
 }
 
 // MistakeRepository handles mistake database operations
@@ -455,7 +785,10 @@ func NewMistakeRepository(db *DB) *MistakeRepository {
 }
 
 // Create creates a new mistake
-func (r *MistakeRepository) Create(mistake *models.Mistake) error {
+func (r *MistakeRepository) Create(ctx context.Context, mistake *models.Mistake) error {
+	if err := r.db.scrubValue(mistake); err != nil {
+		return err
+	}
 	mistakeData, err := json.Marshal(mistake)
 	if err != nil {
 		return err
@@ -467,7 +800,7 @@ func (r *MistakeRepository) Create(mistake *models.Mistake) error {
 			cost_estimate, root_cause_vector, prevention, created_timestamp, mistake_data
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		mistake.ID,
 		mistake.SessionID,
 		mistake.GoalID,
@@ -480,11 +813,14 @@ func (r *MistakeRepository) Create(mistake *models.Mistake) error {
 		mistake.CreatedTimestamp,
 		string(mistakeData),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityMistake, mistake.ID, mistake.ProjectID, mistake)
 }
 
 // List lists mistakes with filtering
-func (r *MistakeRepository) List(sessionID string, goalID *string, limit int) ([]*models.Mistake, error) {
+func (r *MistakeRepository) List(ctx context.Context, sessionID string, goalID *string, limit int) ([]*models.Mistake, error) {
 	var mistakes []*models.Mistake
 	var query string
 	var args []interface{}
@@ -500,7 +836,7 @@ func (r *MistakeRepository) List(sessionID string, goalID *string, limit int) ([
 		args = []interface{}{limit}
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
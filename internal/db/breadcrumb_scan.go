@@ -0,0 +1,118 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanFinding reads a row selected with findingColumns
+func scanFinding(row rowScanner) (*models.Finding, error) {
+	var f models.Finding
+	err := row.Scan(
+		&f.ID,
+		&f.ProjectID,
+		&f.SessionID,
+		&f.GoalID,
+		&f.SubtaskID,
+		&f.Finding,
+		&f.CreatedTimestamp,
+		&f.Subject,
+		&f.Impact,
+		&f.LastVerifiedTimestamp,
+		&f.SubjectGitHash,
+		&f.VerifyCheck,
+		&f.VerificationEvidence,
+		&f.FileChangedDetectedAt,
+		&f.Worktree,
+		&f.GitBranch,
+		&f.UpdatedAt,
+		&f.DeletedAt,
+		&f.Version,
+		&f.Tags,
+		&f.Relations,
+		&f.Snippet,
+		&f.SubjectKind,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// scanUnknown reads a row selected with unknownColumns
+func scanUnknown(row rowScanner) (*models.Unknown, error) {
+	var u models.Unknown
+	err := row.Scan(
+		&u.ID,
+		&u.ProjectID,
+		&u.SessionID,
+		&u.GoalID,
+		&u.SubtaskID,
+		&u.Unknown,
+		&u.IsResolved,
+		&u.ResolvedBy,
+		&u.CreatedTimestamp,
+		&u.ResolvedTimestamp,
+		&u.Subject,
+		&u.Impact,
+		&u.UpdatedAt,
+		&u.DeletedAt,
+		&u.Version,
+		&u.Tags,
+		&u.Relations,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// scanDeadEnd reads a row selected with deadEndColumns
+func scanDeadEnd(row rowScanner) (*models.DeadEnd, error) {
+	var de models.DeadEnd
+	err := row.Scan(
+		&de.ID,
+		&de.ProjectID,
+		&de.SessionID,
+		&de.GoalID,
+		&de.SubtaskID,
+		&de.Approach,
+		&de.WhyFailed,
+		&de.CreatedTimestamp,
+		&de.Subject,
+		&de.Impact,
+		&de.UpdatedAt,
+		&de.DeletedAt,
+		&de.Version,
+		&de.Tags,
+		&de.Relations,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &de, nil
+}
+
+// scanAll reads every row of a query with scan and closes the rows
+func scanAll[T any](rows *sql.Rows, err error, scan func(rowScanner) (T, error)) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// CalibrationSamples pairs the preflight confidence of every finished session with its
+// outcome. Sessions count as finished once done ends them, or once their agent has
+// started a later session without ending them, which marks them abandoned.
+func (r *ReflexRepository) CalibrationSamples(ctx context.Context) ([]*models.CalibrationSample, error) {
+	var samples []*models.CalibrationSample
+	query := `
+		SELECT
+			s.session_id,
+			s.ai_id,
+			r.confidence AS predicted,
+			h.session_id IS NOT NULL AS completed,
+			(SELECT COUNT(*) FROM project_findings f WHERE f.session_id = s.session_id) AS findings,
+			(SELECT COUNT(*) FROM project_findings f WHERE f.session_id = s.session_id AND f.deleted_at IS NOT NULL) AS invalidated
+		FROM sessions s
+		JOIN reflexes r ON r.id = (
+			SELECT MAX(id) FROM reflexes
+			WHERE session_id = s.session_id AND phase = ? AND confidence IS NOT NULL
+		)
+		LEFT JOIN handoff_reports h ON h.session_id = s.session_id
+		WHERE s.end_time IS NOT NULL
+		   OR EXISTS (SELECT 1 FROM sessions later WHERE later.ai_id = s.ai_id AND later.start_time > s.start_time)
+		ORDER BY s.start_time`
+	if err := r.db.SelectContext(ctx, &samples, query, string(models.PhasePreflight)); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// SetCalibrationStatus records how a session's predicted confidence compared to its outcome
+func (r *HandoffRepository) SetCalibrationStatus(ctx context.Context, sessionID, status string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE handoff_reports SET calibration_status = ? WHERE session_id = ? AND calibration_status IS NOT ?`,
+		status, sessionID, status)
+	return err
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// CommitLinkRepository handles finding-to-commit link database operations
+type CommitLinkRepository struct {
+	db *DB
+}
+
+// NewCommitLinkRepository creates a new commit link repository
+func NewCommitLinkRepository(db *DB) *CommitLinkRepository {
+	return &CommitLinkRepository{db: db}
+}
+
+// Create links a finding to a commit (duplicate links are ignored)
+func (r *CommitLinkRepository) Create(ctx context.Context, link *models.CommitLink) error {
+	query := `
+		INSERT OR IGNORE INTO finding_commits (
+			id, finding_id, commit_sha, relation, linked_timestamp
+		) VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		link.ID,
+		link.FindingID,
+		link.CommitSHA,
+		link.Relation,
+		link.LinkedTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil // Already linked
+	}
+	var projectID *string
+	var findingProject string
+	if r.db.GetContext(ctx, &findingProject, `SELECT project_id FROM project_findings WHERE id = ?`, link.FindingID) == nil {
+		projectID = &findingProject
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityCommitLink, link.ID, projectID, link)
+}
+
+// ListByFinding lists commits linked to a finding
+func (r *CommitLinkRepository) ListByFinding(ctx context.Context, findingID string) ([]*models.CommitLink, error) {
+	var links []*models.CommitLink
+	query := `SELECT * FROM finding_commits WHERE finding_id = ? ORDER BY linked_timestamp ASC`
+	err := r.db.SelectContext(ctx, &links, query, findingID)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// ListByFindings lists commits for many findings at once, keyed by finding ID
+func (r *CommitLinkRepository) ListByFindings(ctx context.Context, findingIDs []string) (map[string][]*models.CommitLink, error) {
+	result := make(map[string][]*models.CommitLink)
+	if len(findingIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(findingIDs)), ",")
+	query := `SELECT * FROM finding_commits WHERE finding_id IN (` + placeholders + `) ORDER BY linked_timestamp ASC`
+	args := make([]interface{}, len(findingIDs))
+	for i, id := range findingIDs {
+		args[i] = id
+	}
+
+	var links []*models.CommitLink
+	if err := r.db.SelectContext(ctx, &links, query, args...); err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		result[l.FindingID] = append(result[l.FindingID], l)
+	}
+	return result, nil
+}
@@ -2,18 +2,24 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 // DB wraps the database connection
 type DB struct {
 	*sqlx.DB
-	path string
+	path     string
+	actor    string   // Attributed to mutations in the audit trail
+	deviceID string   // Stamped on breadcrumb events recorded here
+	scrubber Scrubber // Removes personal data before writes
+	tx       *sqlx.Tx // Set on the copy InTx hands out
+	stmts    *stmtCache
 }
 
 // DefaultDBPath returns the default database path
@@ -33,7 +39,7 @@ func DefaultDBPath() string {
 }
 
 // Open opens or creates the database
-func Open(path string) (*DB, error) {
+func Open(ctx context.Context, path string) (*DB, error) {
 	if path == "" {
 		path = DefaultDBPath()
 	}
@@ -44,21 +50,23 @@ func Open(path string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open database
-	db, err := sqlx.Open("sqlite3", path+"?_journal_mode=WAL&_foreign_keys=on")
+	// Open database; statements are timed for -v/-vv diagnostics. Other agent processes
+	// may hold the lock, so SQLite waits busyTimeout before giving up.
+	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=%d", path, busyTimeout.Milliseconds())
+	db, err := sqlx.Open(tracedDriverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Test connection
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	d := &DB{DB: db, path: path}
+	d := &DB{DB: db, path: path, stmts: newStmtCache()}
 
 	// Run migrations
-	if err := d.migrate(); err != nil {
+	if err := d.migrate(ctx); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
@@ -71,7 +79,7 @@ func (d *DB) Path() string {
 }
 
 // migrate runs database migrations
-func (d *DB) migrate() error {
+func (d *DB) migrate(ctx context.Context) error {
 	migrations := []string{
 		migrationSessions,
 		migrationCascades,
@@ -85,11 +93,21 @@ func (d *DB) migrate() error {
 		migrationMistakes,
 		migrationHandoffs,
 		migrationBranches,
+		migrationCommitLinks,
+		migrationIssueLinks,
+		migrationSources,
+		migrationReferenceDocs,
+		migrationProjectHandoffs,
+		migrationAuditEvents,
+		migrationBreadcrumbEvents,
+		migrationMeta,
+		migrationArchivedBreadcrumbs,
+		migrationCarriedBlobs,
 		migrationIndexes,
 	}
 
 	for _, m := range migrations {
-		if _, err := d.Exec(m); err != nil {
+		if _, err := d.ExecContext(ctx, m); err != nil {
 			return fmt.Errorf("migration failed: %w", err)
 		}
 	}
@@ -99,9 +117,64 @@ func (d *DB) migrate() error {
 		migrationFindingStaleness,
 		migrationFindingStaleness2,
 		migrationHandoffProjectID,
+		migrationFindingVerifyCheck,
+		migrationFindingVerifyEvidence,
+		migrationFindingFileChangedAt,
+		migrationFindingWorktree,
+		migrationFindingGitBranch,
+		migrationProjectParent,
+		migrationProjectRootPath,
+		migrationFindingUpdatedAt,
+		migrationFindingDeletedAt,
+		migrationUnknownUpdatedAt,
+		migrationUnknownDeletedAt,
+		migrationDeadEndUpdatedAt,
+		migrationDeadEndDeletedAt,
+		migrationFindingVersion,
+		migrationUnknownVersion,
+		migrationDeadEndVersion,
+		migrationBreadcrumbEventDevice,
+		migrationBreadcrumbEventLamport,
+		migrationBreadcrumbEventOrder,
+		migrationFindingTags,
+		migrationFindingRelations,
+		migrationUnknownTags,
+		migrationUnknownRelations,
+		migrationDeadEndTags,
+		migrationDeadEndRelations,
+		migrationReflexConfidence,
+		migrationFindingSnippet,
+		migrationFindingSubjectKind,
 	}
 	for _, m := range alterMigrations {
-		d.Exec(m) // Ignore errors - column may already exist
+		d.ExecContext(ctx, m) // Ignore errors - column may already exist
+	}
+
+	if err := d.loadDeviceID(ctx); err != nil {
+		return fmt.Errorf("failed to load device ID: %w", err)
+	}
+	if err := d.stampLegacyEvents(ctx); err != nil {
+		return fmt.Errorf("failed to stamp breadcrumb events: %w", err)
+	}
+	dropped, err := d.dropBreadcrumbBlobs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to drop breadcrumb blobs: %w", err)
+	}
+	if err := d.backfillBreadcrumbEvents(ctx); err != nil {
+		return fmt.Errorf("failed to backfill breadcrumb events: %w", err)
+	}
+
+	// Rows projected before versions existed are replayed once to count their events,
+	// and rows that had a blob are replayed so every column matches the event stream
+	var unversioned int
+	d.GetContext(ctx, &unversioned, `SELECT
+		(SELECT COUNT(*) FROM project_findings WHERE version = 0) +
+		(SELECT COUNT(*) FROM project_unknowns WHERE version = 0) +
+		(SELECT COUNT(*) FROM project_dead_ends WHERE version = 0)`)
+	if unversioned > 0 || dropped {
+		if _, err := d.RebuildBreadcrumbs(ctx); err != nil {
+			return fmt.Errorf("failed to version breadcrumbs: %w", err)
+		}
 	}
 
 	return nil
@@ -244,7 +317,6 @@ CREATE TABLE IF NOT EXISTS project_findings (
     subtask_id TEXT,
     finding TEXT NOT NULL,
     created_timestamp REAL NOT NULL,
-    finding_data TEXT NOT NULL,
     subject TEXT,
     impact REAL DEFAULT 0.5,
     FOREIGN KEY (project_id) REFERENCES projects(id)
@@ -263,7 +335,6 @@ CREATE TABLE IF NOT EXISTS project_unknowns (
     resolved_by TEXT,
     created_timestamp REAL NOT NULL,
     resolved_timestamp REAL,
-    unknown_data TEXT NOT NULL,
     subject TEXT,
     impact REAL DEFAULT 0.5,
     FOREIGN KEY (project_id) REFERENCES projects(id)
@@ -280,7 +351,6 @@ CREATE TABLE IF NOT EXISTS project_dead_ends (
     approach TEXT NOT NULL,
     why_failed TEXT NOT NULL,
     created_timestamp REAL NOT NULL,
-    dead_end_data TEXT NOT NULL,
     subject TEXT,
     impact REAL DEFAULT 0.5,
     FOREIGN KEY (project_id) REFERENCES projects(id)
@@ -328,6 +398,32 @@ CREATE TABLE IF NOT EXISTS handoff_reports (
 );
 `
 
+// migrationAuditEvents creates the append-only audit trail; triggers reject edits to history
+const migrationAuditEvents = `
+CREATE TABLE IF NOT EXISTS audit_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp REAL NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    project_id TEXT,
+    payload TEXT
+);
+
+CREATE TRIGGER IF NOT EXISTS audit_events_no_update
+BEFORE UPDATE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_events_no_delete
+BEFORE DELETE ON audit_events
+BEGIN
+    SELECT RAISE(ABORT, 'audit_events is append-only');
+END;
+`
+
 const migrationBranches = `
 CREATE TABLE IF NOT EXISTS investigation_branches (
     id TEXT PRIMARY KEY,
@@ -366,12 +462,101 @@ CREATE TABLE IF NOT EXISTS merge_decisions (
 );
 `
 
+const migrationCommitLinks = `
+CREATE TABLE IF NOT EXISTS finding_commits (
+    id TEXT PRIMARY KEY,
+    finding_id TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    relation TEXT NOT NULL DEFAULT 'validated',
+    linked_timestamp REAL NOT NULL,
+    UNIQUE (finding_id, commit_sha, relation),
+    FOREIGN KEY (finding_id) REFERENCES project_findings(id)
+);
+`
+
+// migrationSources holds docs, URLs and code that findings rest on. related_findings
+// is a JSON array of finding IDs.
+const migrationSources = `
+CREATE TABLE IF NOT EXISTS epistemic_sources (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    session_id TEXT,
+    source_type TEXT NOT NULL,
+    source_url TEXT,
+    title TEXT NOT NULL,
+    description TEXT,
+    confidence REAL NOT NULL DEFAULT 0.5,
+    epistemic_layer TEXT,
+    supports_vectors TEXT,
+    related_findings TEXT,
+    discovered_by_ai TEXT,
+    discovered_at TEXT NOT NULL,
+    source_metadata TEXT,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_epistemic_sources_project ON epistemic_sources(project_id, discovered_at);
+`
+
+// migrationReferenceDocs holds documents agents should consult for a project.
+// doc_path is a repository-relative path or a URL.
+const migrationReferenceDocs = `
+CREATE TABLE IF NOT EXISTS reference_docs (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    doc_path TEXT NOT NULL,
+    doc_type TEXT,
+    description TEXT,
+    created_timestamp REAL NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    UNIQUE (project_id, doc_path)
+);
+`
+
+// migrationProjectHandoffs holds project summaries synthesized from session handoffs.
+// The list columns are JSON arrays.
+const migrationProjectHandoffs = `
+CREATE TABLE IF NOT EXISTS project_handoffs (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    created_timestamp REAL NOT NULL,
+    project_summary TEXT NOT NULL,
+    sessions_included TEXT NOT NULL,
+    total_learning_deltas TEXT,
+    key_decisions TEXT,
+    patterns_discovered TEXT,
+    mistakes_summary TEXT,
+    remaining_work TEXT,
+    repos_touched TEXT,
+    next_session_bootstrap TEXT,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_handoffs_project ON project_handoffs(project_id, created_timestamp);
+`
+
+const migrationIssueLinks = `
+CREATE TABLE IF NOT EXISTS issue_links (
+    id TEXT PRIMARY KEY,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    provider TEXT NOT NULL DEFAULT 'github',
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    title TEXT,
+    url TEXT NOT NULL,
+    linked_timestamp REAL NOT NULL,
+    UNIQUE (target_type, target_id, provider, repo, number)
+);
+`
+
 const migrationIndexes = `
 CREATE INDEX IF NOT EXISTS idx_sessions_ai_id ON sessions(ai_id);
 CREATE INDEX IF NOT EXISTS idx_sessions_project_id ON sessions(project_id);
 CREATE INDEX IF NOT EXISTS idx_cascades_session_id ON cascades(session_id);
 CREATE INDEX IF NOT EXISTS idx_reflexes_session_id ON reflexes(session_id);
 CREATE INDEX IF NOT EXISTS idx_reflexes_phase ON reflexes(phase);
+CREATE INDEX IF NOT EXISTS idx_reflexes_timestamp ON reflexes(timestamp);
 CREATE INDEX IF NOT EXISTS idx_goals_session_id ON goals(session_id);
 CREATE INDEX IF NOT EXISTS idx_subtasks_goal_id ON subtasks(goal_id);
 CREATE INDEX IF NOT EXISTS idx_findings_project_id ON project_findings(project_id);
@@ -381,6 +566,11 @@ CREATE INDEX IF NOT EXISTS idx_unknowns_resolved ON project_unknowns(is_resolved
 CREATE INDEX IF NOT EXISTS idx_dead_ends_project_id ON project_dead_ends(project_id);
 CREATE INDEX IF NOT EXISTS idx_mistakes_session_id ON mistakes_made(session_id);
 CREATE INDEX IF NOT EXISTS idx_branches_session_id ON investigation_branches(session_id);
+CREATE INDEX IF NOT EXISTS idx_finding_commits_finding_id ON finding_commits(finding_id);
+CREATE INDEX IF NOT EXISTS idx_finding_commits_sha ON finding_commits(commit_sha);
+CREATE INDEX IF NOT EXISTS idx_issue_links_target ON issue_links(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_project ON audit_events(project_id, timestamp);
 `
 
 // migrationFindingStaleness adds staleness tracking columns to findings
@@ -397,3 +587,135 @@ ALTER TABLE project_findings ADD COLUMN subject_git_hash TEXT;
 const migrationHandoffProjectID = `
 ALTER TABLE handoff_reports ADD COLUMN project_id TEXT;
 `
+
+// migrationFindingVerifyCheck adds an executable verification command to findings
+const migrationFindingVerifyCheck = `
+ALTER TABLE project_findings ADD COLUMN verify_check TEXT;
+`
+
+const migrationFindingVerifyEvidence = `
+ALTER TABLE project_findings ADD COLUMN verification_evidence TEXT;
+`
+
+// migrationFindingFileChangedAt persists when a scoped file was first seen changed,
+// so later status calls can skip re-hashing findings already known to be stale
+const migrationFindingFileChangedAt = `
+ALTER TABLE project_findings ADD COLUMN file_changed_detected_at REAL;
+`
+
+// migrationFindingWorktree records which worktree and branch a finding was made on
+const migrationFindingWorktree = `
+ALTER TABLE project_findings ADD COLUMN worktree TEXT;
+`
+
+const migrationFindingGitBranch = `
+ALTER TABLE project_findings ADD COLUMN git_branch TEXT;
+`
+
+// migrationProjectParent links monorepo sub-projects to their parent project
+const migrationProjectParent = `
+ALTER TABLE projects ADD COLUMN parent_id TEXT REFERENCES projects(id);
+`
+
+const migrationProjectRootPath = `
+ALTER TABLE projects ADD COLUMN root_path TEXT;
+`
+
+// Breadcrumb updated_at/deleted_at columns; deleted_at is a tombstone hidden from reads
+const migrationFindingUpdatedAt = `
+ALTER TABLE project_findings ADD COLUMN updated_at REAL;
+`
+
+const migrationFindingDeletedAt = `
+ALTER TABLE project_findings ADD COLUMN deleted_at REAL;
+`
+
+const migrationUnknownUpdatedAt = `
+ALTER TABLE project_unknowns ADD COLUMN updated_at REAL;
+`
+
+const migrationUnknownDeletedAt = `
+ALTER TABLE project_unknowns ADD COLUMN deleted_at REAL;
+`
+
+const migrationDeadEndUpdatedAt = `
+ALTER TABLE project_dead_ends ADD COLUMN updated_at REAL;
+`
+
+const migrationDeadEndDeletedAt = `
+ALTER TABLE project_dead_ends ADD COLUMN deleted_at REAL;
+`
+
+// Breadcrumb version columns count applied events for optimistic concurrency
+const migrationFindingVersion = `
+ALTER TABLE project_findings ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+`
+
+const migrationUnknownVersion = `
+ALTER TABLE project_unknowns ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+`
+
+const migrationDeadEndVersion = `
+ALTER TABLE project_dead_ends ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+`
+
+// Tags and relations as JSON arrays, previously only kept in the *_data blobs
+const migrationFindingTags = `
+ALTER TABLE project_findings ADD COLUMN tags TEXT;
+`
+
+const migrationFindingRelations = `
+ALTER TABLE project_findings ADD COLUMN relations TEXT;
+`
+
+const migrationUnknownTags = `
+ALTER TABLE project_unknowns ADD COLUMN tags TEXT;
+`
+
+const migrationUnknownRelations = `
+ALTER TABLE project_unknowns ADD COLUMN relations TEXT;
+`
+
+const migrationDeadEndTags = `
+ALTER TABLE project_dead_ends ADD COLUMN tags TEXT;
+`
+
+const migrationDeadEndRelations = `
+ALTER TABLE project_dead_ends ADD COLUMN relations TEXT;
+`
+
+// Overall confidence of the epistemic snapshots start, status and done record
+const migrationReflexConfidence = `
+ALTER TABLE reflexes ADD COLUMN confidence REAL;
+`
+
+// migrationFindingSnippet keeps the code a finding was logged with --snippet about,
+// as a JSON object
+const migrationFindingSnippet = `
+ALTER TABLE project_findings ADD COLUMN snippet TEXT;
+`
+
+// migrationFindingSubjectKind records what a finding's subject names: file, dir, url,
+// command or external-api. Older rows have none and are inferred from the subject.
+const migrationFindingSubjectKind = `
+ALTER TABLE project_findings ADD COLUMN subject_kind TEXT;
+`
+
+// BackupTo writes a consistent snapshot of the database to path, which must not exist.
+// The snapshot is taken online; other connections keep reading and writing.
+func (d *DB) BackupTo(ctx context.Context, path string) error {
+	_, err := d.ExecContext(ctx, `VACUUM INTO ?`, path)
+	return err
+}
+
+// IntegrityCheck runs SQLite's integrity check, returning an error describing any damage
+func (d *DB) IntegrityCheck(ctx context.Context) error {
+	var results []string
+	if err := d.SelectContext(ctx, &results, `PRAGMA integrity_check`); err != nil {
+		return err
+	}
+	if len(results) == 1 && results[0] == "ok" {
+		return nil
+	}
+	return fmt.Errorf("integrity check failed: %s", strings.Join(results, "; "))
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// openTestDB opens a fresh database under the test's temp directory
+func openTestDB(t *testing.T, name string) *DB {
+	t.Helper()
+	d, err := Open(context.Background(), filepath.Join(t.TempDir(), name))
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	t.Cleanup(func() { d.Close() })
+	return d
+}
+
+// seedSession creates a project and a session in it
+func seedSession(t *testing.T, d *DB, projectName string) (*models.Project, *models.Session) {
+	t.Helper()
+	ctx := context.Background()
+	stores := NewStores(d)
+	project := models.NewProject(projectName, nil)
+	if err := stores.Projects.Create(ctx, project); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	session := models.NewSession("test-agent")
+	session.ProjectID = &project.ID
+	if err := stores.Sessions.Create(ctx, session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return project, session
+}
+
+// countEvents counts the breadcrumb events recorded for one entity
+func countEvents(t *testing.T, d *DB, entityType, entityID string) int {
+	t.Helper()
+	var n int
+	if err := d.GetContext(context.Background(), &n, `SELECT COUNT(*) FROM breadcrumb_events WHERE entity_type = ? AND entity_id = ?`,
+		entityType, entityID); err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	return n
+}
+
+// baselineSchema is the breadcrumb schema databases had before the event stream: each
+// row kept its whole state in a *_data JSON blob next to the columns
+const baselineSchema = `
+CREATE TABLE sessions (
+    session_id TEXT PRIMARY KEY,
+    ai_id TEXT NOT NULL,
+    user_id TEXT,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP,
+    components_loaded INTEGER NOT NULL DEFAULT 0,
+    total_turns INTEGER DEFAULT 0,
+    total_cascades INTEGER DEFAULT 0,
+    avg_confidence REAL,
+    drift_detected BOOLEAN DEFAULT 0,
+    session_notes TEXT,
+    bootstrap_level INTEGER DEFAULT 1,
+    project_id TEXT,
+    subject TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE projects (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    repos TEXT,
+    created_timestamp REAL NOT NULL,
+    last_activity_timestamp REAL,
+    status TEXT DEFAULT 'active',
+    metadata TEXT,
+    total_sessions INTEGER DEFAULT 0,
+    total_goals INTEGER DEFAULT 0,
+    total_epistemic_deltas TEXT,
+    project_data TEXT NOT NULL
+);
+CREATE TABLE project_findings (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    goal_id TEXT,
+    subtask_id TEXT,
+    finding TEXT NOT NULL,
+    created_timestamp REAL NOT NULL,
+    finding_data TEXT NOT NULL,
+    subject TEXT,
+    impact REAL DEFAULT 0.5,
+    last_verified_timestamp REAL,
+    subject_git_hash TEXT,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+CREATE TABLE project_unknowns (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    goal_id TEXT,
+    subtask_id TEXT,
+    unknown TEXT NOT NULL,
+    is_resolved BOOLEAN DEFAULT FALSE,
+    resolved_by TEXT,
+    created_timestamp REAL NOT NULL,
+    resolved_timestamp REAL,
+    unknown_data TEXT NOT NULL,
+    subject TEXT,
+    impact REAL DEFAULT 0.5,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+CREATE TABLE project_dead_ends (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    goal_id TEXT,
+    subtask_id TEXT,
+    approach TEXT NOT NULL,
+    why_failed TEXT NOT NULL,
+    created_timestamp REAL NOT NULL,
+    dead_end_data TEXT NOT NULL,
+    subject TEXT,
+    impact REAL DEFAULT 0.5,
+    FOREIGN KEY (project_id) REFERENCES projects(id)
+);
+
+INSERT INTO projects (id, name, created_timestamp, project_data)
+VALUES ('p1', 'legacy', 1700000000, '{"id":"p1","name":"legacy"}');
+INSERT INTO sessions (session_id, ai_id, start_time, project_id)
+VALUES ('s1', 'test-agent', '2023-11-14 22:13:20', 'p1');
+INSERT INTO project_findings (id, project_id, session_id, finding, created_timestamp, finding_data, subject, impact)
+VALUES ('f1', 'p1', 's1', 'Pool size is 10', 1700000000, '{"id":"f1","tags":["db"]}', 'config/db.go', 0.7);
+INSERT INTO project_unknowns (id, project_id, session_id, unknown, is_resolved, resolved_by, created_timestamp, resolved_timestamp, unknown_data)
+VALUES ('u1', 'p1', 's1', 'Why does the pool drain?', 1, 'Idle timeout', 1700000000, 1700000100, '{"id":"u1"}');
+INSERT INTO project_dead_ends (id, project_id, session_id, approach, why_failed, created_timestamp, dead_end_data)
+VALUES ('d1', 'p1', 's1', 'Raise the pool size', 'Drained anyway', 1700000000, '{"id":"d1"}');
+`
+
+func TestOpenMigratesBaselineDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	legacy, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Exec(baselineSchema); err != nil {
+		t.Fatalf("create baseline database: %v", err)
+	}
+	legacy.Close()
+
+	d, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("migrate baseline database: %v", err)
+	}
+	repo := NewBreadcrumbRepository(d)
+
+	f, err := repo.GetFinding(ctx, "f1")
+	if err != nil {
+		t.Fatalf("get finding: %v", err)
+	}
+	if f.Finding != "Pool size is 10" || f.Subject == nil || *f.Subject != "config/db.go" || f.Impact != 0.7 {
+		t.Errorf("finding columns not kept: %+v", f)
+	}
+	if len(f.Tags) != 1 || f.Tags[0] != "db" {
+		t.Errorf("tags from finding_data = %v, want [db]", f.Tags)
+	}
+	u, err := repo.GetUnknown(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get unknown: %v", err)
+	}
+	if !u.IsResolved || u.ResolvedBy == nil || *u.ResolvedBy != "Idle timeout" {
+		t.Errorf("unknown resolution not kept: %+v", u)
+	}
+	deadEnds, err := repo.ListDeadEnds(ctx, "p1", "", 10)
+	if err != nil || len(deadEnds) != 1 || deadEnds[0].WhyFailed != "Drained anyway" {
+		t.Errorf("dead ends = %v, %v", deadEnds, err)
+	}
+
+	// Each breadcrumb gets one created event, and the blobs are gone
+	for _, e := range []struct{ entityType, id string }{{"finding", "f1"}, {"unknown", "u1"}, {"dead_end", "d1"}} {
+		if n := countEvents(t, d, e.entityType, e.id); n != 1 {
+			t.Errorf("%s %s has %d events, want 1", e.entityType, e.id, n)
+		}
+	}
+	for _, b := range breadcrumbBlobs {
+		var present int
+		if err := d.GetContext(ctx, &present, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, b.table, b.column); err != nil {
+			t.Fatal(err)
+		}
+		if present != 0 {
+			t.Errorf("%s.%s was not dropped", b.table, b.column)
+		}
+	}
+	d.Close()
+
+	// Opening again migrates nothing twice
+	d, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer d.Close()
+	if n := countEvents(t, d, "finding", "f1"); n != 1 {
+		t.Errorf("reopening backfilled again: %d events", n)
+	}
+	if _, err := d.RebuildBreadcrumbs(ctx); err != nil {
+		t.Fatalf("rebuild migrated database: %v", err)
+	}
+	rebuilt, err := NewBreadcrumbRepository(d).GetFinding(ctx, "f1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt.Finding != f.Finding || len(rebuilt.Tags) != 1 {
+		t.Errorf("rebuild changed the finding: %+v", rebuilt)
+	}
+}
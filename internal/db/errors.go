@@ -0,0 +1,26 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by repositories, wrapped with the record involved; match them with errors.Is
+var (
+	// ErrNotFound is returned when a record doesn't exist, or is deleted for writes that need a live one
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a write clashes with the record's current state
+	ErrConflict = errors.New("conflict")
+
+	// ErrInvalid is returned for input a repository refuses to store
+	ErrInvalid = errors.New("invalid")
+)
+
+// ErrVersionConflict is returned when a breadcrumb changed after the caller read it
+var ErrVersionConflict = fmt.Errorf("version %w", ErrConflict)
+
+// notFound wraps ErrNotFound with the kind and key of the missing record
+func notFound(kind, key string) error {
+	return fmt.Errorf("%s %s: %w", kind, key, ErrNotFound)
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"time"
@@ -19,7 +20,10 @@ func NewGoalRepository(db *DB) *GoalRepository {
 }
 
 // Create creates a new goal
-func (r *GoalRepository) Create(goal *models.Goal) error {
+func (r *GoalRepository) Create(ctx context.Context, goal *models.Goal) error {
+	if err := r.db.scrubValue(goal); err != nil {
+		return err
+	}
 	// Serialize scope and full goal data
 	scopeJSON, err := json.Marshal(goal.Scope)
 	if err != nil {
@@ -37,7 +41,7 @@ func (r *GoalRepository) Create(goal *models.Goal) error {
 			created_timestamp, is_completed, goal_data, status, beads_issue_id
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		goal.ID,
 		goal.SessionID,
 		goal.Objective,
@@ -49,11 +53,14 @@ func (r *GoalRepository) Create(goal *models.Goal) error {
 		goal.Status,
 		goal.BeadsIssueID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityGoal, goal.ID, r.db.sessionProjectID(ctx, goal.SessionID), goal)
 }
 
 // Get retrieves a goal by ID
-func (r *GoalRepository) Get(goalID string) (*models.Goal, error) {
+func (r *GoalRepository) Get(ctx context.Context, goalID string) (*models.Goal, error) {
 	var goal models.Goal
 	var goalData string
 
@@ -61,7 +68,7 @@ func (r *GoalRepository) Get(goalID string) (*models.Goal, error) {
 	          created_timestamp, completed_timestamp, is_completed, goal_data, 
 	          status, beads_issue_id FROM goals WHERE id = ?`
 
-	row := r.db.QueryRow(query, goalID)
+	row := r.db.QueryRowContext(ctx, query, goalID)
 	err := row.Scan(
 		&goal.ID,
 		&goal.SessionID,
@@ -76,7 +83,7 @@ func (r *GoalRepository) Get(goalID string) (*models.Goal, error) {
 		&goal.BeadsIssueID,
 	)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("goal", goalID)
 	}
 	if err != nil {
 		return nil, err
@@ -96,7 +103,7 @@ func (r *GoalRepository) Get(goalID string) (*models.Goal, error) {
 }
 
 // List lists goals with optional filtering
-func (r *GoalRepository) List(sessionID string, completed *bool, limit int) ([]*models.Goal, error) {
+func (r *GoalRepository) List(ctx context.Context, sessionID string, completed *bool, limit int) ([]*models.Goal, error) {
 	var goals []*models.Goal
 	var query string
 	var args []interface{}
@@ -115,7 +122,7 @@ func (r *GoalRepository) List(sessionID string, completed *bool, limit int) ([]*
 		args = []interface{}{limit}
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -138,24 +145,42 @@ func (r *GoalRepository) List(sessionID string, completed *bool, limit int) ([]*
 }
 
 // Complete marks a goal as completed
-func (r *GoalRepository) Complete(goalID string, reason string) error {
+func (r *GoalRepository) Complete(ctx context.Context, goalID string, reason string) error {
+	reason = r.db.scrubText(reason)
 	now := float64(time.Now().UnixMilli()) / 1000.0
+	// goal_data is what Get and List return, so keep it in step with the columns
 	query := `
 		UPDATE goals SET 
 			is_completed = 1,
 			completed_timestamp = ?,
-			status = 'complete'
+			status = 'complete',
+			goal_data = json_set(goal_data,
+				'$.is_completed', json('true'),
+				'$.completed_timestamp', ?,
+				'$.status', 'complete')
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, now, goalID)
-	return err
+	_, err := r.db.ExecContext(ctx, query, now, now, goalID)
+	if err != nil {
+		return err
+	}
+	var payload map[string]interface{}
+	if reason != "" {
+		payload = map[string]interface{}{"reason": reason}
+	}
+	return r.db.audit(ctx, models.AuditComplete, models.EntityGoal, goalID, r.db.goalProjectID(ctx, goalID), payload)
 }
 
 // UpdateStatus updates a goal's status
-func (r *GoalRepository) UpdateStatus(goalID string, status models.GoalStatus) error {
+func (r *GoalRepository) UpdateStatus(ctx context.Context, goalID string, status models.GoalStatus) error {
 	query := `UPDATE goals SET status = ? WHERE id = ?`
-	_, err := r.db.Exec(query, status, goalID)
-	return err
+	_, err := r.db.ExecContext(ctx, query, status, goalID)
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, models.EntityGoal, goalID, r.db.goalProjectID(ctx, goalID), map[string]interface{}{
+		"status": status,
+	})
 }
 
 // SubtaskRepository handles subtask database operations
@@ -169,7 +194,10 @@ func NewSubtaskRepository(db *DB) *SubtaskRepository {
 }
 
 // Create creates a new subtask
-func (r *SubtaskRepository) Create(subtask *models.SubTask) error {
+func (r *SubtaskRepository) Create(ctx context.Context, subtask *models.SubTask) error {
+	if err := r.db.scrubValue(subtask); err != nil {
+		return err
+	}
 	subtaskData, err := json.Marshal(subtask)
 	if err != nil {
 		return err
@@ -181,7 +209,7 @@ func (r *SubtaskRepository) Create(subtask *models.SubTask) error {
 			estimated_tokens, notes, created_timestamp, subtask_data
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		subtask.ID,
 		subtask.GoalID,
 		subtask.Description,
@@ -192,16 +220,19 @@ func (r *SubtaskRepository) Create(subtask *models.SubTask) error {
 		subtask.CreatedTimestamp,
 		string(subtaskData),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntitySubtask, subtask.ID, r.db.goalProjectID(ctx, subtask.GoalID), subtask)
 }
 
 // Get retrieves a subtask by ID
-func (r *SubtaskRepository) Get(subtaskID string) (*models.SubTask, error) {
+func (r *SubtaskRepository) Get(ctx context.Context, subtaskID string) (*models.SubTask, error) {
 	var subtaskData string
 	query := `SELECT subtask_data FROM subtasks WHERE id = ?`
-	err := r.db.QueryRow(query, subtaskID).Scan(&subtaskData)
+	err := r.db.QueryRowContext(ctx, query, subtaskID).Scan(&subtaskData)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("subtask", subtaskID)
 	}
 	if err != nil {
 		return nil, err
@@ -215,11 +246,11 @@ func (r *SubtaskRepository) Get(subtaskID string) (*models.SubTask, error) {
 }
 
 // ListByGoal lists subtasks for a goal
-func (r *SubtaskRepository) ListByGoal(goalID string) ([]*models.SubTask, error) {
+func (r *SubtaskRepository) ListByGoal(ctx context.Context, goalID string) ([]*models.SubTask, error) {
 	var subtasks []*models.SubTask
 	query := `SELECT subtask_data FROM subtasks WHERE goal_id = ? ORDER BY created_timestamp ASC`
 
-	rows, err := r.db.Query(query, goalID)
+	rows, err := r.db.QueryContext(ctx, query, goalID)
 	if err != nil {
 		return nil, err
 	}
@@ -242,17 +273,15 @@ func (r *SubtaskRepository) ListByGoal(goalID string) ([]*models.SubTask, error)
 }
 
 // Complete marks a subtask as completed
-func (r *SubtaskRepository) Complete(subtaskID string, evidence string) error {
+func (r *SubtaskRepository) Complete(ctx context.Context, subtaskID string, evidence string) error {
+	evidence = r.db.scrubText(evidence)
 	now := float64(time.Now().UnixMilli()) / 1000.0
 
 	// First get the current subtask data
-	subtask, err := r.Get(subtaskID)
+	subtask, err := r.Get(ctx, subtaskID)
 	if err != nil {
 		return err
 	}
-	if subtask == nil {
-		return sql.ErrNoRows
-	}
 
 	subtask.Status = models.TaskStatusCompleted
 	subtask.CompletedTimestamp = &now
@@ -271,19 +300,31 @@ func (r *SubtaskRepository) Complete(subtaskID string, evidence string) error {
 			subtask_data = ?
 		WHERE id = ?
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		subtask.Status,
 		now,
 		evidence,
 		string(subtaskData),
 		subtaskID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditComplete, models.EntitySubtask, subtaskID, r.db.goalProjectID(ctx, subtask.GoalID), map[string]interface{}{
+		"evidence": evidence,
+	})
 }
 
-// UpdateStatus updates a subtask's status
-func (r *SubtaskRepository) UpdateStatus(subtaskID string, status models.TaskStatus) error {
-	query := `UPDATE subtasks SET status = ? WHERE id = ?`
-	_, err := r.db.Exec(query, status, subtaskID)
-	return err
+// UpdateStatus updates a subtask's status, in its column and its stored JSON
+func (r *SubtaskRepository) UpdateStatus(ctx context.Context, subtaskID string, status models.TaskStatus) error {
+	query := `UPDATE subtasks SET status = ?, subtask_data = json_set(subtask_data, '$.status', ?) WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, status, status, subtaskID)
+	if err != nil {
+		return err
+	}
+	var goalID string
+	r.db.GetContext(ctx, &goalID, `SELECT goal_id FROM subtasks WHERE id = ?`, subtaskID)
+	return r.db.audit(ctx, models.AuditEdit, models.EntitySubtask, subtaskID, r.db.goalProjectID(ctx, goalID), map[string]interface{}{
+		"status": status,
+	})
 }
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// IssueLinkRepository handles session/goal-to-issue link database operations
+type IssueLinkRepository struct {
+	db *DB
+}
+
+// NewIssueLinkRepository creates a new issue link repository
+func NewIssueLinkRepository(db *DB) *IssueLinkRepository {
+	return &IssueLinkRepository{db: db}
+}
+
+// Create links a session or goal to an issue (re-linking refreshes the title)
+func (r *IssueLinkRepository) Create(ctx context.Context, link *models.IssueLink) error {
+	query := `
+		INSERT INTO issue_links (
+			id, target_type, target_id, provider, repo, number, title, url, linked_timestamp
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (target_type, target_id, provider, repo, number)
+		DO UPDATE SET title = COALESCE(excluded.title, issue_links.title)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		link.ID,
+		link.TargetType,
+		link.TargetID,
+		link.Provider,
+		link.Repo,
+		link.Number,
+		link.Title,
+		link.URL,
+		link.LinkedTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	projectID := r.db.sessionProjectID(ctx, link.TargetID)
+	if link.TargetType == models.IssueTargetGoal {
+		projectID = r.db.goalProjectID(ctx, link.TargetID)
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityIssueLink, link.ID, projectID, link)
+}
+
+// ListByTarget lists issues linked to a session or goal
+func (r *IssueLinkRepository) ListByTarget(ctx context.Context, targetType models.IssueTargetType, targetID string) ([]*models.IssueLink, error) {
+	var links []*models.IssueLink
+	query := `SELECT * FROM issue_links WHERE target_type = ? AND target_id = ? ORDER BY linked_timestamp ASC`
+	err := r.db.SelectContext(ctx, &links, query, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
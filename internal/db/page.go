@@ -0,0 +1,118 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Page selects one page of a list sorted newest first. Lists resume after the cursor
+// by comparing (creation time, ID) rather than skipping rows with OFFSET, so each page
+// costs the same however deep it is and rows added meanwhile don't shift it.
+type Page struct {
+	Size  int      // Maximum number of rows
+	After *Cursor  // Last row of the previous page; nil starts at the newest
+	AIIDs []string // Keep only rows logged in sessions run by these AI IDs or agent families
+}
+
+// Cursor identifies the last row of a page
+type Cursor struct {
+	Created string `json:"c"` // Creation time as stored: Unix seconds or RFC 3339
+	ID      string `json:"id"`
+}
+
+// String encodes the cursor as an opaque token for --cursor
+func (c *Cursor) String() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor decodes a token produced by Cursor.String
+func ParseCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w cursor %q", ErrInvalid, token)
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, fmt.Errorf("%w cursor %q", ErrInvalid, token)
+	}
+	return &c, nil
+}
+
+func timestampCursor(created float64, id string) *Cursor {
+	return &Cursor{Created: strconv.FormatFloat(created, 'f', -1, 64), ID: id}
+}
+
+func timeCursor(created time.Time, id string) *Cursor {
+	return &Cursor{Created: created.Format(time.RFC3339Nano), ID: id}
+}
+
+// keyset appends the condition selecting rows after the page's cursor and the
+// ordering and limit that go with it. It fetches one row more than the page size
+// so the caller can tell whether another page follows.
+func (p Page) keyset(query string, args []interface{}, createdCol, idCol string, created interface{}) (string, []interface{}) {
+	if p.After != nil {
+		query += ` AND (` + createdCol + ` < ? OR (` + createdCol + ` = ? AND ` + idCol + ` < ?))`
+		args = append(args, created, created, p.After.ID)
+	}
+	query += ` ORDER BY ` + createdCol + ` DESC, ` + idCol + ` DESC LIMIT ?`
+	return query, append(args, p.Size+1)
+}
+
+// byAgent appends the condition keeping rows logged in sessions run by the page's AI
+// IDs. Each matches its AI ID, case-insensitively, or when it has no separator an
+// agent family: "claude" matches claude-code, claude:x and claude_x.
+func (p Page) byAgent(query string, args []interface{}) (string, []interface{}) {
+	if len(p.AIIDs) == 0 {
+		return query, args
+	}
+	var conds []string
+	for _, id := range p.AIIDs {
+		id = strings.ToLower(id)
+		conds = append(conds, `LOWER(ai_id) = ?`)
+		args = append(args, id)
+		if !strings.ContainsAny(id, "-:_") {
+			conds = append(conds, `substr(LOWER(ai_id), 1, ?) IN (?, ?, ?)`)
+			args = append(args, utf8.RuneCountInString(id)+1, id+"-", id+":", id+"_")
+		}
+	}
+	query += ` AND session_id IN (SELECT session_id FROM sessions WHERE ` + strings.Join(conds, " OR ") + `)`
+	return query, args
+}
+
+// afterTimestamp parses the cursor of a list keyed by a Unix timestamp column
+func (p Page) afterTimestamp() (float64, error) {
+	if p.After == nil {
+		return 0, nil
+	}
+	created, err := strconv.ParseFloat(p.After.Created, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w cursor: bad timestamp %q", ErrInvalid, p.After.Created)
+	}
+	return created, nil
+}
+
+// afterTime parses the cursor of a list keyed by a time column
+func (p Page) afterTime() (time.Time, error) {
+	if p.After == nil {
+		return time.Time{}, nil
+	}
+	created, err := time.Parse(time.RFC3339Nano, p.After.Created)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w cursor: bad time %q", ErrInvalid, p.After.Created)
+	}
+	return created, nil
+}
+
+// trim drops the extra row keyset fetched, returning the page and whether more follow
+func trim[T any](rows []T, p Page) ([]T, bool) {
+	if len(rows) > p.Size {
+		return rows[:p.Size], true
+	}
+	return rows, false
+}
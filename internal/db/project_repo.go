@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/AbdouB/memory/internal/models"
@@ -19,7 +21,7 @@ func NewProjectRepository(db *DB) *ProjectRepository {
 }
 
 // Create creates a new project
-func (r *ProjectRepository) Create(project *models.Project) error {
+func (r *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
 	reposJSON, err := json.Marshal(project.Repos)
 	if err != nil {
 		return err
@@ -33,10 +35,11 @@ func (r *ProjectRepository) Create(project *models.Project) error {
 	query := `
 		INSERT INTO projects (
 			id, name, description, repos, created_timestamp,
-			status, total_sessions, total_goals, project_data
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			status, total_sessions, total_goals, project_data,
+			parent_id, root_path
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		project.ID,
 		project.Name,
 		project.Description,
@@ -46,17 +49,22 @@ func (r *ProjectRepository) Create(project *models.Project) error {
 		project.TotalSessions,
 		project.TotalGoals,
 		string(projectData),
+		project.ParentID,
+		project.RootPath,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityProject, project.ID, &project.ID, project)
 }
 
 // Get retrieves a project by ID
-func (r *ProjectRepository) Get(projectID string) (*models.Project, error) {
+func (r *ProjectRepository) Get(ctx context.Context, projectID string) (*models.Project, error) {
 	var projectData string
 	query := `SELECT project_data FROM projects WHERE id = ?`
-	err := r.db.QueryRow(query, projectID).Scan(&projectData)
+	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&projectData)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("project", projectID)
 	}
 	if err != nil {
 		return nil, err
@@ -70,12 +78,12 @@ func (r *ProjectRepository) Get(projectID string) (*models.Project, error) {
 }
 
 // GetByName retrieves a project by name
-func (r *ProjectRepository) GetByName(name string) (*models.Project, error) {
+func (r *ProjectRepository) GetByName(ctx context.Context, name string) (*models.Project, error) {
 	var projectData string
 	query := `SELECT project_data FROM projects WHERE name = ?`
-	err := r.db.QueryRow(query, name).Scan(&projectData)
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&projectData)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("project", name)
 	}
 	if err != nil {
 		return nil, err
@@ -89,7 +97,7 @@ func (r *ProjectRepository) GetByName(name string) (*models.Project, error) {
 }
 
 // List lists all projects
-func (r *ProjectRepository) List(status *models.ProjectStatus, limit int) ([]*models.Project, error) {
+func (r *ProjectRepository) List(ctx context.Context, status *models.ProjectStatus, limit int) ([]*models.Project, error) {
 	var projects []*models.Project
 	var query string
 	var args []interface{}
@@ -102,7 +110,34 @@ func (r *ProjectRepository) List(status *models.ProjectStatus, limit int) ([]*mo
 		args = []interface{}{limit}
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var projectData string
+		if err := rows.Scan(&projectData); err != nil {
+			return nil, err
+		}
+
+		var project models.Project
+		if err := json.Unmarshal([]byte(projectData), &project); err != nil {
+			return nil, err
+		}
+		projects = append(projects, &project)
+	}
+
+	return projects, rows.Err()
+}
+
+// ListChildren lists the sub-projects registered under a parent project
+func (r *ProjectRepository) ListChildren(ctx context.Context, parentID string) ([]*models.Project, error) {
+	var projects []*models.Project
+	query := `SELECT project_data FROM projects WHERE parent_id = ? ORDER BY root_path`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +160,7 @@ func (r *ProjectRepository) List(status *models.ProjectStatus, limit int) ([]*mo
 }
 
 // Update updates a project
-func (r *ProjectRepository) Update(project *models.Project) error {
+func (r *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
 	now := float64(time.Now().UnixMilli()) / 1000.0
 	project.LastActivityTimestamp = &now
 
@@ -152,7 +187,7 @@ func (r *ProjectRepository) Update(project *models.Project) error {
 			project_data = ?
 		WHERE id = ?
 	`
-	_, err = r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		project.Name,
 		project.Description,
 		string(reposJSON),
@@ -164,29 +199,37 @@ func (r *ProjectRepository) Update(project *models.Project) error {
 		string(projectData),
 		project.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, models.EntityProject, project.ID, &project.ID, project)
 }
 
 // UpdateStatus updates a project's status
-func (r *ProjectRepository) UpdateStatus(projectID string, status models.ProjectStatus) error {
+func (r *ProjectRepository) UpdateStatus(ctx context.Context, projectID string, status models.ProjectStatus) error {
 	query := `UPDATE projects SET status = ? WHERE id = ?`
-	_, err := r.db.Exec(query, status, projectID)
-	return err
+	_, err := r.db.ExecContext(ctx, query, status, projectID)
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, models.EntityProject, projectID, &projectID, map[string]interface{}{
+		"status": status,
+	})
 }
 
 // IncrementSessions increments the session count for a project
-func (r *ProjectRepository) IncrementSessions(projectID string) error {
+func (r *ProjectRepository) IncrementSessions(ctx context.Context, projectID string) error {
 	now := float64(time.Now().UnixMilli()) / 1000.0
 	query := `UPDATE projects SET total_sessions = total_sessions + 1, last_activity_timestamp = ? WHERE id = ?`
-	_, err := r.db.Exec(query, now, projectID)
+	_, err := r.db.ExecContext(ctx, query, now, projectID)
 	return err
 }
 
 // IncrementGoals increments the goal count for a project
-func (r *ProjectRepository) IncrementGoals(projectID string) error {
+func (r *ProjectRepository) IncrementGoals(ctx context.Context, projectID string) error {
 	now := float64(time.Now().UnixMilli()) / 1000.0
 	query := `UPDATE projects SET total_goals = total_goals + 1, last_activity_timestamp = ? WHERE id = ?`
-	_, err := r.db.Exec(query, now, projectID)
+	_, err := r.db.ExecContext(ctx, query, now, projectID)
 	return err
 }
 
@@ -200,6 +243,171 @@ func NewReferenceDocRepository(db *DB) *ReferenceDocRepository {
 	return &ReferenceDocRepository{db: db}
 }
 
+// Create registers a reference doc. Registering the same path twice in a project is a conflict.
+func (r *ReferenceDocRepository) Create(ctx context.Context, doc *models.ReferenceDoc) error {
+	if err := r.db.scrubValue(doc); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO reference_docs (
+			id, project_id, doc_path, doc_type, description, created_timestamp
+		) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project_id, doc_path) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		doc.ID,
+		doc.ProjectID,
+		doc.DocPath,
+		doc.DocType,
+		doc.Description,
+		doc.CreatedTimestamp,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("doc %s: already registered: %w", doc.DocPath, ErrConflict)
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityDoc, doc.ID, &doc.ProjectID, doc)
+}
+
+// Get retrieves a reference doc by ID
+func (r *ReferenceDocRepository) Get(ctx context.Context, docID string) (*models.ReferenceDoc, error) {
+	var doc models.ReferenceDoc
+	err := r.db.GetContext(ctx, &doc, `SELECT * FROM reference_docs WHERE id = ?`, docID)
+	if err == sql.ErrNoRows {
+		return nil, notFound(models.EntityDoc, docID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// GetByPath retrieves a project's reference doc by its path or URL
+func (r *ReferenceDocRepository) GetByPath(ctx context.Context, projectID, docPath string) (*models.ReferenceDoc, error) {
+	var doc models.ReferenceDoc
+	err := r.db.GetContext(ctx, &doc, `SELECT * FROM reference_docs WHERE project_id = ? AND doc_path = ?`, projectID, docPath)
+	if err == sql.ErrNoRows {
+		return nil, notFound(models.EntityDoc, docPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// List lists a project's reference docs by path, optionally of one type
+func (r *ReferenceDocRepository) List(ctx context.Context, projectID, docType string) ([]*models.ReferenceDoc, error) {
+	var docs []*models.ReferenceDoc
+	query := `SELECT * FROM reference_docs WHERE project_id = ?`
+	args := []interface{}{projectID}
+	if docType != "" {
+		query += ` AND doc_type = ?`
+		args = append(args, docType)
+	}
+	query += ` ORDER BY doc_path`
+
+	if err := r.db.SelectContext(ctx, &docs, query, args...); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Update changes a reference doc's type and description
+func (r *ReferenceDocRepository) Update(ctx context.Context, doc *models.ReferenceDoc) error {
+	if err := r.db.scrubValue(doc); err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `UPDATE reference_docs SET doc_type = ?, description = ? WHERE id = ?`,
+		doc.DocType, doc.Description, doc.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return notFound(models.EntityDoc, doc.ID)
+	}
+	return r.db.audit(ctx, models.AuditEdit, models.EntityDoc, doc.ID, &doc.ProjectID, doc)
+}
+
+// Delete removes a reference doc
+func (r *ReferenceDocRepository) Delete(ctx context.Context, docID string) error {
+	doc, err := r.Get(ctx, docID)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM reference_docs WHERE id = ?`, docID); err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditDelete, models.EntityDoc, docID, &doc.ProjectID, doc)
+}
+
+// ProjectHandoffRepository handles project handoff database operations
+type ProjectHandoffRepository struct {
+	db *DB
+}
+
+// NewProjectHandoffRepository creates a new project handoff repository
+func NewProjectHandoffRepository(db *DB) *ProjectHandoffRepository {
+	return &ProjectHandoffRepository{db: db}
+}
+
+// Create stores a project handoff
+func (r *ProjectHandoffRepository) Create(ctx context.Context, handoff *models.ProjectHandoff) error {
+	if err := r.db.scrubValue(handoff); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO project_handoffs (
+			id, project_id, created_timestamp, project_summary, sessions_included,
+			total_learning_deltas, key_decisions, patterns_discovered, mistakes_summary,
+			remaining_work, repos_touched, next_session_bootstrap
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		handoff.ID,
+		handoff.ProjectID,
+		handoff.CreatedTimestamp,
+		handoff.ProjectSummary,
+		handoff.SessionsIncluded,
+		handoff.TotalLearningDeltas,
+		handoff.KeyDecisions,
+		handoff.PatternsDiscovered,
+		handoff.MistakesSummary,
+		handoff.RemainingWork,
+		handoff.ReposTouched,
+		handoff.NextSessionBootstrap,
+	)
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntityProjectHandoff, handoff.ID, &handoff.ProjectID, handoff)
+}
+
+// Latest retrieves the most recent handoff of a project
+func (r *ProjectHandoffRepository) Latest(ctx context.Context, projectID string) (*models.ProjectHandoff, error) {
+	var handoff models.ProjectHandoff
+	query := `SELECT * FROM project_handoffs WHERE project_id = ? ORDER BY created_timestamp DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &handoff, query, projectID)
+	if err == sql.ErrNoRows {
+		return nil, notFound("project handoff for project", projectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &handoff, nil
+}
+
+// List lists a project's handoffs, newest first
+func (r *ProjectHandoffRepository) List(ctx context.Context, projectID string, limit int) ([]*models.ProjectHandoff, error) {
+	var handoffs []*models.ProjectHandoff
+	query := `SELECT * FROM project_handoffs WHERE project_id = ? ORDER BY created_timestamp DESC LIMIT ?`
+	if err := r.db.SelectContext(ctx, &handoffs, query, projectID, limit); err != nil {
+		return nil, err
+	}
+	return handoffs, nil
+}
+
 // BranchRepository handles investigation branch database operations
 type BranchRepository struct {
 	db *DB
@@ -211,7 +419,7 @@ func NewBranchRepository(db *DB) *BranchRepository {
 }
 
 // Create creates a new investigation branch
-func (r *BranchRepository) Create(branch *models.InvestigationBranch) error {
+func (r *BranchRepository) Create(ctx context.Context, branch *models.InvestigationBranch) error {
 	query := `
 		INSERT INTO investigation_branches (
 			id, session_id, branch_name, investigation_path, git_branch_name,
@@ -219,7 +427,7 @@ func (r *BranchRepository) Create(branch *models.InvestigationBranch) error {
 			created_timestamp, status
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		branch.ID,
 		branch.SessionID,
 		branch.BranchName,
@@ -236,12 +444,12 @@ func (r *BranchRepository) Create(branch *models.InvestigationBranch) error {
 }
 
 // Get retrieves a branch by ID
-func (r *BranchRepository) Get(branchID string) (*models.InvestigationBranch, error) {
+func (r *BranchRepository) Get(ctx context.Context, branchID string) (*models.InvestigationBranch, error) {
 	var branch models.InvestigationBranch
 	query := `SELECT * FROM investigation_branches WHERE id = ?`
-	err := r.db.Get(&branch, query, branchID)
+	err := r.db.GetContext(ctx, &branch, query, branchID)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("branch", branchID)
 	}
 	if err != nil {
 		return nil, err
@@ -250,10 +458,10 @@ func (r *BranchRepository) Get(branchID string) (*models.InvestigationBranch, er
 }
 
 // ListBySession lists branches for a session
-func (r *BranchRepository) ListBySession(sessionID string) ([]*models.InvestigationBranch, error) {
+func (r *BranchRepository) ListBySession(ctx context.Context, sessionID string) ([]*models.InvestigationBranch, error) {
 	var branches []*models.InvestigationBranch
 	query := `SELECT * FROM investigation_branches WHERE session_id = ? ORDER BY created_timestamp DESC`
-	err := r.db.Select(&branches, query, sessionID)
+	err := r.db.SelectContext(ctx, &branches, query, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +469,7 @@ func (r *BranchRepository) ListBySession(sessionID string) ([]*models.Investigat
 }
 
 // Checkpoint updates a branch with postflight data
-func (r *BranchRepository) Checkpoint(branchID string, postflightVectors string, tokensSpent, timeSpent int) error {
+func (r *BranchRepository) Checkpoint(ctx context.Context, branchID string, postflightVectors string, tokensSpent, timeSpent int) error {
 	now := float64(time.Now().UnixMilli()) / 1000.0
 	query := `
 		UPDATE investigation_branches SET
@@ -271,12 +479,12 @@ func (r *BranchRepository) Checkpoint(branchID string, postflightVectors string,
 			checkpoint_timestamp = ?
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, postflightVectors, tokensSpent, timeSpent, now, branchID)
+	_, err := r.db.ExecContext(ctx, query, postflightVectors, tokensSpent, timeSpent, now, branchID)
 	return err
 }
 
 // MarkWinner marks a branch as the winner
-func (r *BranchRepository) MarkWinner(branchID string, score float64) error {
+func (r *BranchRepository) MarkWinner(ctx context.Context, branchID string, score float64) error {
 	now := float64(time.Now().UnixMilli()) / 1000.0
 	query := `
 		UPDATE investigation_branches SET
@@ -286,6 +494,6 @@ func (r *BranchRepository) MarkWinner(branchID string, score float64) error {
 			status = 'merged'
 		WHERE id = ?
 	`
-	_, err := r.db.Exec(query, score, now, branchID)
+	_, err := r.db.ExecContext(ctx, query, score, now, branchID)
 	return err
 }
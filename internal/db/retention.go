@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionCutoffs are the oldest timestamps kept for each kind of breadcrumb. A zero
+// time keeps that kind however old it gets.
+type RetentionCutoffs struct {
+	Findings         time.Time // Compared with the last verification
+	OpenUnknowns     time.Time
+	ResolvedUnknowns time.Time // Compared with the resolution
+	DeadEnds         time.Time
+	AdHocSessions    time.Time // Any breadcrumb of a session whose subject starts with AdHocPrefix
+	AdHocPrefix      string
+}
+
+// ExpiredBreadcrumb is a live breadcrumb older than its retention period
+type ExpiredBreadcrumb struct {
+	EntityType string `db:"entity_type" json:"type"`
+	ID         string `db:"id" json:"id"`
+	ProjectID  string `db:"project_id" json:"project_id"`
+	Text       string `db:"text" json:"text"`
+	Rule       string `db:"-" json:"rule"` // The retention period that expired it
+}
+
+// ExpiredBreadcrumbs lists live breadcrumbs older than their retention period, across
+// every project. A breadcrumb matching several rules is listed once.
+func (r *BreadcrumbRepository) ExpiredBreadcrumbs(ctx context.Context, cutoffs RetentionCutoffs) ([]*ExpiredBreadcrumb, error) {
+	const adHoc = ` AND session_id IN (SELECT session_id FROM sessions WHERE substr(subject, 1, length(?)) = ?)`
+	rules := []struct {
+		rule   string
+		cutoff time.Time
+		query  string
+		adHoc  bool // The query ends by matching the ad-hoc prefix
+	}{
+		{"findings", cutoffs.Findings, `SELECT 'finding' AS entity_type, id, project_id, finding AS text
+			FROM project_findings WHERE deleted_at IS NULL
+			AND COALESCE(last_verified_timestamp, created_timestamp) < ?`, false},
+		{"open_unknowns", cutoffs.OpenUnknowns, `SELECT 'unknown' AS entity_type, id, project_id, unknown AS text
+			FROM project_unknowns WHERE deleted_at IS NULL AND NOT is_resolved AND created_timestamp < ?`, false},
+		{"resolved_unknowns", cutoffs.ResolvedUnknowns, `SELECT 'unknown' AS entity_type, id, project_id, unknown AS text
+			FROM project_unknowns WHERE deleted_at IS NULL AND is_resolved
+			AND COALESCE(resolved_timestamp, created_timestamp) < ?`, false},
+		{"dead_ends", cutoffs.DeadEnds, `SELECT 'dead_end' AS entity_type, id, project_id, approach AS text
+			FROM project_dead_ends WHERE deleted_at IS NULL AND created_timestamp < ?`, false},
+		{"adhoc_sessions", cutoffs.AdHocSessions, `SELECT 'finding' AS entity_type, id, project_id, finding AS text
+			FROM project_findings WHERE deleted_at IS NULL AND created_timestamp < ?` + adHoc, true},
+		{"adhoc_sessions", cutoffs.AdHocSessions, `SELECT 'unknown' AS entity_type, id, project_id, unknown AS text
+			FROM project_unknowns WHERE deleted_at IS NULL AND created_timestamp < ?` + adHoc, true},
+		{"adhoc_sessions", cutoffs.AdHocSessions, `SELECT 'dead_end' AS entity_type, id, project_id, approach AS text
+			FROM project_dead_ends WHERE deleted_at IS NULL AND created_timestamp < ?` + adHoc, true},
+	}
+
+	var expired []*ExpiredBreadcrumb
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.cutoff.IsZero() || (rule.adHoc && cutoffs.AdHocPrefix == "") {
+			continue
+		}
+		args := []interface{}{float64(rule.cutoff.UnixMilli()) / 1000.0}
+		if rule.adHoc {
+			args = append(args, cutoffs.AdHocPrefix, cutoffs.AdHocPrefix)
+		}
+		var matched []*ExpiredBreadcrumb
+		if err := r.db.SelectContext(ctx, &matched, rule.query+` ORDER BY created_timestamp`, args...); err != nil {
+			return nil, err
+		}
+		for _, b := range matched {
+			if seen[b.ID] {
+				continue
+			}
+			seen[b.ID] = true
+			b.Rule = rule.rule
+			expired = append(expired, b)
+		}
+	}
+	return expired, nil
+}
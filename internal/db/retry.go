@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// busyTimeout is how long SQLite itself waits on a locked database before returning
+// SQLITE_BUSY; it is passed as _busy_timeout in milliseconds
+const busyTimeout = 5 * time.Second
+
+// Retries after SQLite gives up: lock upgrades inside a transaction fail immediately
+// whatever the busy timeout, so they are retried from the start with backoff
+const (
+	maxBusyRetries = 5
+	busyRetryBase  = 25 * time.Millisecond
+)
+
+// isBusy reports whether err means another process holds the database lock
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// retryBusy runs fn, retrying with jittered exponential backoff while the database
+// is locked by another process. Other errors and context cancellation stop it.
+func retryBusy(ctx context.Context, fn func() error) error {
+	err := fn()
+	for attempt := 0; attempt < maxBusyRetries && isBusy(err); attempt++ {
+		backoff := busyRetryBase << attempt
+		wait := backoff/2 + rand.N(backoff)
+		slog.Info("database busy, retrying", "attempt", attempt+1, "wait", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		err = fn()
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package db
+
+import "encoding/json"
+
+// Scrubber removes personal data from text before it is stored
+type Scrubber interface {
+	String(text string) string
+	JSON(data []byte) ([]byte, error)
+}
+
+// SetScrubber sets the filter applied to text before it is written. Nil disables scrubbing.
+func (d *DB) SetScrubber(s Scrubber) {
+	d.scrubber = s
+}
+
+// scrubText filters a single string
+func (d *DB) scrubText(text string) string {
+	if d.scrubber == nil {
+		return text
+	}
+	return d.scrubber.String(text)
+}
+
+// scrubJSON filters the string values of a JSON document
+func (d *DB) scrubJSON(data []byte) ([]byte, error) {
+	if d.scrubber == nil {
+		return data, nil
+	}
+	return d.scrubber.JSON(data)
+}
+
+// scrubValue filters the exported string fields of v in place, so the caller's copy
+// (printed, sent to webhooks) matches what is stored
+func (d *DB) scrubValue(v interface{}) error {
+	if d.scrubber == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	scrubbed, err := d.scrubber.JSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(scrubbed, v)
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -20,7 +21,10 @@ func NewSessionRepository(db *DB) *SessionRepository {
 }
 
 // Create creates a new session
-func (r *SessionRepository) Create(session *models.Session) error {
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	if err := r.db.scrubValue(session); err != nil {
+		return err
+	}
 	query := `
 		INSERT INTO sessions (
 			session_id, ai_id, user_id, start_time, components_loaded,
@@ -28,7 +32,7 @@ func (r *SessionRepository) Create(session *models.Session) error {
 			project_id, subject, created_at
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		session.SessionID,
 		session.AIID,
 		session.UserID,
@@ -42,16 +46,19 @@ func (r *SessionRepository) Create(session *models.Session) error {
 		session.Subject,
 		session.CreatedAt,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntitySession, session.SessionID, session.ProjectID, session)
 }
 
 // Get retrieves a session by ID
-func (r *SessionRepository) Get(sessionID string) (*models.Session, error) {
+func (r *SessionRepository) Get(ctx context.Context, sessionID string) (*models.Session, error) {
 	var session models.Session
 	query := `SELECT * FROM sessions WHERE session_id = ?`
-	err := r.db.Get(&session, query, sessionID)
+	err := r.db.GetContext(ctx, &session, query, sessionID)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound(models.EntitySession, sessionID)
 	}
 	if err != nil {
 		return nil, err
@@ -60,33 +67,62 @@ func (r *SessionRepository) Get(sessionID string) (*models.Session, error) {
 }
 
 // List lists sessions with optional filtering
-func (r *SessionRepository) List(aiID string, limit int) ([]*models.Session, error) {
+func (r *SessionRepository) List(ctx context.Context, aiID string, limit int) ([]*models.Session, error) {
+	sessions, _, err := r.ListPage(ctx, aiID, Page{Size: limit})
+	return sessions, err
+}
+
+// ListPage lists one page of sessions, newest first. The returned cursor fetches the
+// next page and is nil on the last one.
+func (r *SessionRepository) ListPage(ctx context.Context, aiID string, page Page) ([]*models.Session, *Cursor, error) {
 	var sessions []*models.Session
-	var query string
-	var args []interface{}
+	after, err := page.afterTime()
+	if err != nil {
+		return nil, nil, err
+	}
 
+	query := `SELECT * FROM sessions WHERE 1 = 1`
+	var args []interface{}
 	if aiID != "" {
-		query = `SELECT * FROM sessions WHERE ai_id = ? ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{aiID, limit}
-	} else {
-		query = `SELECT * FROM sessions ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{limit}
+		query += ` AND ai_id = ?`
+		args = append(args, aiID)
 	}
+	query, args = page.keyset(query, args, "created_at", "session_id", after)
 
-	err := r.db.Select(&sessions, query, args...)
-	if err != nil {
-		return nil, err
+	if err := r.db.SelectContext(ctx, &sessions, query, args...); err != nil {
+		return nil, nil, err
+	}
+
+	sessions, more := trim(sessions, page)
+	if !more {
+		return sessions, nil, nil
 	}
-	return sessions, nil
+	last := sessions[len(sessions)-1]
+	return sessions, timeCursor(last.CreatedAt, last.SessionID), nil
 }
 
 // GetLatest gets the most recent session for an AI
-func (r *SessionRepository) GetLatest(aiID string) (*models.Session, error) {
+func (r *SessionRepository) GetLatest(ctx context.Context, aiID string) (*models.Session, error) {
 	var session models.Session
 	query := `SELECT * FROM sessions WHERE ai_id = ? ORDER BY created_at DESC LIMIT 1`
-	err := r.db.Get(&session, query, aiID)
+	err := r.db.GetContext(ctx, &session, query, aiID)
+	if err == sql.ErrNoRows {
+		return nil, notFound("session for", aiID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetOpenBySubject retrieves the newest unended session in a project with this subject
+func (r *SessionRepository) GetOpenBySubject(ctx context.Context, projectID, subject string) (*models.Session, error) {
+	var session models.Session
+	query := `SELECT * FROM sessions WHERE project_id = ? AND subject = ? AND end_time IS NULL
+		ORDER BY created_at DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &session, query, projectID, subject)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("open session for", subject)
 	}
 	if err != nil {
 		return nil, err
@@ -95,7 +131,10 @@ func (r *SessionRepository) GetLatest(aiID string) (*models.Session, error) {
 }
 
 // Update updates a session
-func (r *SessionRepository) Update(session *models.Session) error {
+func (r *SessionRepository) Update(ctx context.Context, session *models.Session) error {
+	if err := r.db.scrubValue(session); err != nil {
+		return err
+	}
 	query := `
 		UPDATE sessions SET
 			end_time = ?,
@@ -107,7 +146,7 @@ func (r *SessionRepository) Update(session *models.Session) error {
 			bootstrap_level = ?
 		WHERE session_id = ?
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		session.EndTime,
 		session.TotalTurns,
 		session.TotalCascades,
@@ -117,15 +156,26 @@ func (r *SessionRepository) Update(session *models.Session) error {
 		session.BootstrapLevel,
 		session.SessionID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditEdit, models.EntitySession, session.SessionID, r.db.sessionProjectID(ctx, session.SessionID), session)
 }
 
-// End marks a session as ended
-func (r *SessionRepository) End(sessionID string) error {
+// End marks a session as ended; ErrNotFound if it doesn't exist or has already ended
+func (r *SessionRepository) End(ctx context.Context, sessionID string) error {
 	now := time.Now()
-	query := `UPDATE sessions SET end_time = ? WHERE session_id = ?`
-	_, err := r.db.Exec(query, now, sessionID)
-	return err
+	query := `UPDATE sessions SET end_time = ? WHERE session_id = ? AND end_time IS NULL`
+	res, err := r.db.ExecContext(ctx, query, now, sessionID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return notFound("open session", sessionID)
+	}
+	return r.db.audit(ctx, models.AuditComplete, models.EntitySession, sessionID, r.db.sessionProjectID(ctx, sessionID), nil)
 }
 
 // ReflexRepository handles reflex (epistemic checkpoint) database operations
@@ -139,16 +189,16 @@ func NewReflexRepository(db *DB) *ReflexRepository {
 }
 
 // Create creates a new reflex
-func (r *ReflexRepository) Create(reflex *models.Reflex) error {
+func (r *ReflexRepository) Create(ctx context.Context, reflex *models.Reflex) error {
 	query := `
 		INSERT INTO reflexes (
 			session_id, cascade_id, phase, round, timestamp,
 			engagement, know, do_vec, context, clarity, coherence,
 			signal, density, state, change, completion, impact, uncertainty,
-			reflex_data, reasoning, evidence
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			confidence, reflex_data, reasoning, evidence
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	result, err := r.db.Exec(query,
+	result, err := r.db.ExecContext(ctx, query,
 		reflex.SessionID,
 		reflex.CascadeID,
 		reflex.Phase,
@@ -167,6 +217,7 @@ func (r *ReflexRepository) Create(reflex *models.Reflex) error {
 		reflex.Completion,
 		reflex.Impact,
 		reflex.Uncertainty,
+		reflex.Confidence,
 		reflex.ReflexData,
 		reflex.Reasoning,
 		reflex.Evidence,
@@ -184,16 +235,16 @@ func (r *ReflexRepository) Create(reflex *models.Reflex) error {
 }
 
 // GetLatestByPhase gets the most recent reflex for a session and phase
-func (r *ReflexRepository) GetLatestByPhase(sessionID, phase string) (*models.Reflex, error) {
+func (r *ReflexRepository) GetLatestByPhase(ctx context.Context, sessionID, phase string) (*models.Reflex, error) {
 	var reflex models.Reflex
 	query := `
 		SELECT * FROM reflexes 
 		WHERE session_id = ? AND phase = ? 
 		ORDER BY timestamp DESC LIMIT 1
 	`
-	err := r.db.Get(&reflex, query, sessionID, phase)
+	err := r.db.GetContext(ctx, &reflex, query, sessionID, phase)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("reflex", sessionID+"/"+phase)
 	}
 	if err != nil {
 		return nil, err
@@ -202,24 +253,75 @@ func (r *ReflexRepository) GetLatestByPhase(sessionID, phase string) (*models.Re
 }
 
 // ListBySession lists all reflexes for a session
-func (r *ReflexRepository) ListBySession(sessionID string, limit int) ([]*models.Reflex, error) {
+func (r *ReflexRepository) ListBySession(ctx context.Context, sessionID string, limit int) ([]*models.Reflex, error) {
 	var reflexes []*models.Reflex
 	query := `SELECT * FROM reflexes WHERE session_id = ? ORDER BY timestamp DESC LIMIT ?`
-	err := r.db.Select(&reflexes, query, sessionID, limit)
+	err := r.db.SelectContext(ctx, &reflexes, query, sessionID, limit)
 	if err != nil {
 		return nil, err
 	}
 	return reflexes, nil
 }
 
+// ListLatestPerSession returns the latest reflex of each of the project's most recent
+// sessions that recorded one, newest session first
+func (r *ReflexRepository) ListLatestPerSession(ctx context.Context, projectID string, sessions int) ([]*models.Reflex, error) {
+	var reflexes []*models.Reflex
+	query := `
+		SELECT r.* FROM reflexes r
+		JOIN sessions s ON s.session_id = r.session_id
+		WHERE s.project_id = ?
+		  AND r.id = (SELECT MAX(id) FROM reflexes WHERE session_id = r.session_id)
+		ORDER BY r.timestamp DESC LIMIT ?`
+	if err := r.db.SelectContext(ctx, &reflexes, query, projectID, sessions); err != nil {
+		return nil, err
+	}
+	return reflexes, nil
+}
+
+// ReflexFilter narrows an epistemic history listing
+type ReflexFilter struct {
+	ProjectID string
+	SessionID string
+	Since     float64
+}
+
+// ListHistory lists recorded reflexes matching the filter, newest first
+func (r *ReflexRepository) ListHistory(ctx context.Context, filter ReflexFilter, limit int) ([]*models.Reflex, error) {
+	query := `SELECT r.* FROM reflexes r JOIN sessions s ON s.session_id = r.session_id WHERE 1=1`
+	var args []interface{}
+
+	if filter.ProjectID != "" {
+		query += ` AND s.project_id = ?`
+		args = append(args, filter.ProjectID)
+	}
+	if filter.SessionID != "" {
+		query += ` AND r.session_id = ?`
+		args = append(args, filter.SessionID)
+	}
+	if filter.Since > 0 {
+		query += ` AND r.timestamp >= ?`
+		args = append(args, filter.Since)
+	}
+
+	query += ` ORDER BY r.timestamp DESC, r.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	var reflexes []*models.Reflex
+	if err := r.db.SelectContext(ctx, &reflexes, query, args...); err != nil {
+		return nil, err
+	}
+	return reflexes, nil
+}
+
 // GetDelta calculates the epistemic delta between two reflexes
-func (r *ReflexRepository) GetDelta(sessionID string) (*models.EpistemicVectors, error) {
-	preflight, err := r.GetLatestByPhase(sessionID, "PREFLIGHT")
+func (r *ReflexRepository) GetDelta(ctx context.Context, sessionID string) (*models.EpistemicVectors, error) {
+	preflight, err := r.GetLatestByPhase(ctx, sessionID, "PREFLIGHT")
 	if err != nil || preflight == nil {
 		return nil, err
 	}
 
-	postflight, err := r.GetLatestByPhase(sessionID, "POSTFLIGHT")
+	postflight, err := r.GetLatestByPhase(ctx, sessionID, "POSTFLIGHT")
 	if err != nil || postflight == nil {
 		return nil, err
 	}
@@ -241,7 +343,7 @@ func NewCascadeRepository(db *DB) *CascadeRepository {
 }
 
 // Create creates a new cascade
-func (r *CascadeRepository) Create(cascade *models.Cascade) error {
+func (r *CascadeRepository) Create(ctx context.Context, cascade *models.Cascade) error {
 	query := `
 		INSERT INTO cascades (
 			cascade_id, session_id, task, context_json, goal_id, goal_json,
@@ -250,7 +352,7 @@ func (r *CascadeRepository) Create(cascade *models.Cascade) error {
 			investigation_rounds, started_at, bayesian_active, drift_monitored
 		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		cascade.CascadeID,
 		cascade.SessionID,
 		cascade.Task,
@@ -273,12 +375,12 @@ func (r *CascadeRepository) Create(cascade *models.Cascade) error {
 }
 
 // Get retrieves a cascade by ID
-func (r *CascadeRepository) Get(cascadeID string) (*models.Cascade, error) {
+func (r *CascadeRepository) Get(ctx context.Context, cascadeID string) (*models.Cascade, error) {
 	var cascade models.Cascade
 	query := `SELECT * FROM cascades WHERE cascade_id = ?`
-	err := r.db.Get(&cascade, query, cascadeID)
+	err := r.db.GetContext(ctx, &cascade, query, cascadeID)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("cascade", cascadeID)
 	}
 	if err != nil {
 		return nil, err
@@ -287,7 +389,7 @@ func (r *CascadeRepository) Get(cascadeID string) (*models.Cascade, error) {
 }
 
 // UpdatePhase updates a cascade phase completion status
-func (r *CascadeRepository) UpdatePhase(cascadeID, phase string, completed bool) error {
+func (r *CascadeRepository) UpdatePhase(ctx context.Context, cascadeID, phase string, completed bool) error {
 	var column string
 	switch phase {
 	case "PREFLIGHT":
@@ -309,12 +411,12 @@ func (r *CascadeRepository) UpdatePhase(cascadeID, phase string, completed bool)
 	}
 
 	query := fmt.Sprintf("UPDATE cascades SET %s = ? WHERE cascade_id = ?", column)
-	_, err := r.db.Exec(query, completed, cascadeID)
+	_, err := r.db.ExecContext(ctx, query, completed, cascadeID)
 	return err
 }
 
 // Complete marks a cascade as completed
-func (r *CascadeRepository) Complete(cascadeID string, action string, confidence float64) error {
+func (r *CascadeRepository) Complete(ctx context.Context, cascadeID string, action string, confidence float64) error {
 	now := time.Now()
 	query := `
 		UPDATE cascades SET 
@@ -323,7 +425,7 @@ func (r *CascadeRepository) Complete(cascadeID string, action string, confidence
 			final_confidence = ?
 		WHERE cascade_id = ?
 	`
-	_, err := r.db.Exec(query, now, action, confidence, cascadeID)
+	_, err := r.db.ExecContext(ctx, query, now, action, confidence, cascadeID)
 	return err
 }
 
@@ -338,13 +440,26 @@ func NewHandoffRepository(db *DB) *HandoffRepository {
 }
 
 // Create creates a new handoff report
-func (r *HandoffRepository) Create(input *models.HandoffCreateInput, aiID string) (*models.HandoffReport, error) {
+func (r *HandoffRepository) Create(ctx context.Context, input *models.HandoffCreateInput, aiID string) (*models.HandoffReport, error) {
+	if err := r.db.scrubValue(input); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 
 	keyFindingsJSON, _ := json.Marshal(input.KeyFindings)
 	unknownsJSON, _ := json.Marshal(input.RemainingUnknowns)
 	artifactsJSON, _ := json.Marshal(input.Artifacts)
 
+	var deltas *string
+	if len(input.EpistemicDeltas) > 0 {
+		deltasJSON, _ := json.Marshal(input.EpistemicDeltas)
+		deltas = strPtr(string(deltasJSON))
+	}
+	var duration *float64
+	if input.DurationSeconds > 0 {
+		duration = &input.DurationSeconds
+	}
+
 	var projectID *string
 	if input.ProjectID != "" {
 		projectID = &input.ProjectID
@@ -356,46 +471,58 @@ func (r *HandoffRepository) Create(input *models.HandoffCreateInput, aiID string
 		ProjectID:          projectID,
 		Timestamp:          now.Format(time.RFC3339),
 		TaskSummary:        &input.TaskSummary,
+		DurationSeconds:    duration,
+		EpistemicDeltas:    deltas,
 		KeyFindings:        strPtr(string(keyFindingsJSON)),
 		RemainingUnknowns:  strPtr(string(unknownsJSON)),
 		NextSessionContext: strPtr(input.NextSessionContext),
 		ArtifactsCreated:   strPtr(string(artifactsJSON)),
 		CreatedAt:          float64(now.UnixMilli()) / 1000.0,
 	}
+	if input.CalibrationStatus != "" {
+		report.CalibrationStatus = &input.CalibrationStatus
+	}
 
 	query := `
 		INSERT INTO handoff_reports (
 			session_id, ai_id, project_id, timestamp, task_summary,
+			duration_seconds, epistemic_deltas,
 			key_findings, remaining_unknowns, next_session_context,
-			artifacts_created, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			artifacts_created, calibration_status, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.Exec(query,
+	_, err := r.db.ExecContext(ctx, query,
 		report.SessionID,
 		report.AIID,
 		report.ProjectID,
 		report.Timestamp,
 		report.TaskSummary,
+		report.DurationSeconds,
+		report.EpistemicDeltas,
 		report.KeyFindings,
 		report.RemainingUnknowns,
 		report.NextSessionContext,
 		report.ArtifactsCreated,
+		report.CalibrationStatus,
 		report.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if err := r.db.audit(ctx, models.AuditCreate, models.EntityHandoff, report.SessionID, report.ProjectID, report); err != nil {
+		return nil, err
+	}
 
 	return report, nil
 }
 
 // Get retrieves a handoff report by session ID
-func (r *HandoffRepository) Get(sessionID string) (*models.HandoffReport, error) {
+func (r *HandoffRepository) Get(ctx context.Context, sessionID string) (*models.HandoffReport, error) {
 	var report models.HandoffReport
 	query := `SELECT * FROM handoff_reports WHERE session_id = ?`
-	err := r.db.Get(&report, query, sessionID)
+	err := r.db.GetContext(ctx, &report, query, sessionID)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, notFound("handoff for session", sessionID)
 	}
 	if err != nil {
 		return nil, err
@@ -404,30 +531,42 @@ func (r *HandoffRepository) Get(sessionID string) (*models.HandoffReport, error)
 }
 
 // List lists handoff reports filtered by project and/or AI ID
-func (r *HandoffRepository) List(projectID, aiID string, limit int) ([]*models.HandoffReport, error) {
+func (r *HandoffRepository) List(ctx context.Context, projectID, aiID string, limit int) ([]*models.HandoffReport, error) {
+	reports, _, err := r.ListPage(ctx, projectID, aiID, Page{Size: limit})
+	return reports, err
+}
+
+// ListPage lists one page of handoff reports, newest first. The returned cursor
+// fetches the next page and is nil on the last one.
+func (r *HandoffRepository) ListPage(ctx context.Context, projectID, aiID string, page Page) ([]*models.HandoffReport, *Cursor, error) {
 	var reports []*models.HandoffReport
-	var query string
+	after, err := page.afterTimestamp()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `SELECT * FROM handoff_reports WHERE 1 = 1`
 	var args []interface{}
+	if projectID != "" {
+		query += ` AND project_id = ?`
+		args = append(args, projectID)
+	}
+	if aiID != "" {
+		query += ` AND ai_id = ?`
+		args = append(args, aiID)
+	}
+	query, args = page.keyset(query, args, "created_at", "session_id", after)
 
-	if projectID != "" && aiID != "" {
-		query = `SELECT * FROM handoff_reports WHERE project_id = ? AND ai_id = ? ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{projectID, aiID, limit}
-	} else if projectID != "" {
-		query = `SELECT * FROM handoff_reports WHERE project_id = ? ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{projectID, limit}
-	} else if aiID != "" {
-		query = `SELECT * FROM handoff_reports WHERE ai_id = ? ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{aiID, limit}
-	} else {
-		query = `SELECT * FROM handoff_reports ORDER BY created_at DESC LIMIT ?`
-		args = []interface{}{limit}
-	}
-
-	err := r.db.Select(&reports, query, args...)
-	if err != nil {
-		return nil, err
+	if err := r.db.SelectContext(ctx, &reports, query, args...); err != nil {
+		return nil, nil, err
+	}
+
+	reports, more := trim(reports, page)
+	if !more {
+		return reports, nil, nil
 	}
-	return reports, nil
+	last := reports[len(reports)-1]
+	return reports, timestampCursor(last.CreatedAt, last.SessionID), nil
 }
 
 func strPtr(s string) *string {
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// SourceRepository handles epistemic source database operations
+type SourceRepository struct {
+	db *DB
+}
+
+// NewSourceRepository creates a new source repository
+func NewSourceRepository(db *DB) *SourceRepository {
+	return &SourceRepository{db: db}
+}
+
+// Create records a source
+func (r *SourceRepository) Create(ctx context.Context, source *models.EpistemicSource) error {
+	if err := r.db.scrubValue(source); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO epistemic_sources (
+			id, project_id, session_id, source_type, source_url, title, description,
+			confidence, epistemic_layer, supports_vectors, related_findings,
+			discovered_by_ai, discovered_at, source_metadata
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		source.ID,
+		source.ProjectID,
+		source.SessionID,
+		source.SourceType,
+		source.SourceURL,
+		source.Title,
+		source.Description,
+		source.Confidence,
+		source.EpistemicLayer,
+		source.SupportsVectors,
+		source.RelatedFindings,
+		source.DiscoveredByAI,
+		source.DiscoveredAt,
+		source.SourceMetadata,
+	)
+	if err != nil {
+		return err
+	}
+	return r.db.audit(ctx, models.AuditCreate, models.EntitySource, source.ID, &source.ProjectID, source)
+}
+
+// Get retrieves a source by ID
+func (r *SourceRepository) Get(ctx context.Context, sourceID string) (*models.EpistemicSource, error) {
+	var source models.EpistemicSource
+	err := r.db.GetContext(ctx, &source, `SELECT * FROM epistemic_sources WHERE id = ?`, sourceID)
+	if err == sql.ErrNoRows {
+		return nil, notFound(models.EntitySource, sourceID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// List lists a project's sources, newest first, optionally of one type
+func (r *SourceRepository) List(ctx context.Context, projectID, sourceType string, limit int) ([]*models.EpistemicSource, error) {
+	var sources []*models.EpistemicSource
+	query := `SELECT * FROM epistemic_sources WHERE project_id = ?`
+	args := []interface{}{projectID}
+	if sourceType != "" {
+		query += ` AND source_type = ?`
+		args = append(args, sourceType)
+	}
+	query += ` ORDER BY discovered_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	if err := r.db.SelectContext(ctx, &sources, query, args...); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// ListByFinding lists the sources a finding is linked to, most confident first
+func (r *SourceRepository) ListByFinding(ctx context.Context, findingID string) ([]*models.EpistemicSource, error) {
+	var sources []*models.EpistemicSource
+	query := `SELECT * FROM epistemic_sources
+		WHERE EXISTS (SELECT 1 FROM json_each(COALESCE(related_findings, '[]')) WHERE value = ?)
+		ORDER BY confidence DESC`
+	if err := r.db.SelectContext(ctx, &sources, query, findingID); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// LinkFinding adds a finding to the source's related findings. Returns false if it
+// was already linked.
+func (r *SourceRepository) LinkFinding(ctx context.Context, sourceID, findingID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE epistemic_sources
+		SET related_findings = json_insert(COALESCE(related_findings, '[]'), '$[#]', ?)
+		WHERE id = ? AND NOT EXISTS (
+			SELECT 1 FROM json_each(COALESCE(related_findings, '[]')) WHERE value = ?
+		)`, findingID, sourceID, findingID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := r.Get(ctx, sourceID); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+	projectID := r.sourceProjectID(ctx, sourceID)
+	return true, r.db.audit(ctx, models.AuditEdit, models.EntitySource, sourceID, projectID,
+		map[string]string{"linked_finding": findingID})
+}
+
+// sourceProjectID looks up a source's project for audit entries
+func (r *SourceRepository) sourceProjectID(ctx context.Context, sourceID string) *string {
+	var projectID string
+	if err := r.db.GetContext(ctx, &projectID, `SELECT project_id FROM epistemic_sources WHERE id = ?`, sourceID); err != nil {
+		return nil
+	}
+	return &projectID
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// maxStatsScopes caps the scope rankings in project stats
+const maxStatsScopes = 10
+
+// ProjectStats aggregates a project's breadcrumbs in SQL. Staleness uses the file
+// changes already recorded on findings rather than re-hashing files.
+func (r *BreadcrumbRepository) ProjectStats(ctx context.Context, projectID string) (*models.ProjectStats, error) {
+	now := float64(time.Now().UnixMilli()) / 1000.0
+	stats := &models.ProjectStats{}
+
+	agingAfter, staleAfter := models.StalenessAges(false)
+	changedAgingAfter, changedStaleAfter := models.StalenessAges(true)
+	findingsQuery := `
+		SELECT
+			COALESCE(SUM(deleted_at IS NULL), 0) AS total,
+			COALESCE(SUM(deleted_at IS NULL AND age < CASE WHEN file_changed_detected_at IS NULL THEN ? ELSE ? END), 0) AS fresh,
+			COALESCE(SUM(deleted_at IS NULL AND age >= CASE WHEN file_changed_detected_at IS NULL THEN ? ELSE ? END), 0) AS stale,
+			COALESCE(SUM(deleted_at IS NULL AND last_verified_timestamp > created_timestamp), 0) AS verified,
+			COALESCE(SUM(deleted_at IS NULL AND verify_check IS NOT NULL), 0) AS with_check,
+			COALESCE(SUM(deleted_at IS NULL AND file_changed_detected_at IS NOT NULL), 0) AS file_changed,
+			COALESCE(SUM(deleted_at IS NOT NULL), 0) AS deleted,
+			COALESCE(AVG(CASE WHEN deleted_at IS NULL THEN ? - created_timestamp END), 0) / 86400 AS avg_age_days,
+			COALESCE(AVG(deleted_at - created_timestamp), 0) / 86400 AS avg_lifetime_days
+		FROM (
+			SELECT *, ? - COALESCE(last_verified_timestamp, created_timestamp) AS age
+			FROM project_findings WHERE project_id = ?
+		)`
+	err := r.db.GetContext(ctx, &stats.Findings, findingsQuery,
+		agingAfter, changedAgingAfter, staleAfter, changedStaleAfter, now, now, projectID)
+	if err != nil {
+		return nil, err
+	}
+	f := &stats.Findings
+	f.Aging = f.Total - f.Fresh - f.Stale
+	if f.Total > 0 {
+		f.VerificationRate = float64(f.Verified) / float64(f.Total)
+	}
+
+	unknownsQuery := `
+		SELECT
+			COALESCE(SUM(deleted_at IS NULL AND NOT is_resolved), 0) AS open,
+			COALESCE(SUM(deleted_at IS NULL AND is_resolved), 0) AS resolved,
+			COALESCE(SUM(deleted_at IS NOT NULL), 0) AS deleted,
+			COALESCE(AVG(CASE WHEN deleted_at IS NULL AND is_resolved THEN resolved_timestamp - created_timestamp END), 0) / 86400 AS avg_days_to_resolve
+		FROM project_unknowns WHERE project_id = ?`
+	if err := r.db.GetContext(ctx, &stats.Unknowns, unknownsQuery, projectID); err != nil {
+		return nil, err
+	}
+	u := &stats.Unknowns
+	u.Total = u.Open + u.Resolved
+	if u.Total > 0 {
+		u.ResolutionRate = float64(u.Resolved) / float64(u.Total)
+	}
+
+	deadEndsQuery := `
+		SELECT
+			COALESCE(SUM(deleted_at IS NULL), 0) AS total,
+			COALESCE(SUM(deleted_at IS NOT NULL), 0) AS deleted
+		FROM project_dead_ends WHERE project_id = ?`
+	if err := r.db.GetContext(ctx, &stats.DeadEnds, deadEndsQuery, projectID); err != nil {
+		return nil, err
+	}
+
+	scopesQuery := `
+		SELECT subject AS scope, COUNT(*) AS count FROM (
+			SELECT subject FROM project_findings WHERE project_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT subject FROM project_unknowns WHERE project_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT subject FROM project_dead_ends WHERE project_id = ? AND deleted_at IS NULL
+		)
+		WHERE subject IS NOT NULL AND subject != ''
+		GROUP BY subject ORDER BY count DESC, scope LIMIT ?`
+	if err := r.db.SelectContext(ctx, &stats.TopScopes, scopesQuery, projectID, projectID, projectID, maxStatsScopes); err != nil {
+		return nil, err
+	}
+
+	hotspotsQuery := `
+		SELECT subject AS scope, COUNT(*) AS count FROM project_dead_ends
+		WHERE project_id = ? AND deleted_at IS NULL AND subject IS NOT NULL AND subject != ''
+		GROUP BY subject ORDER BY count DESC, scope LIMIT ?`
+	if err := r.db.SelectContext(ctx, &stats.DeadEndHotspots, hotspotsQuery, projectID, maxStatsScopes); err != nil {
+		return nil, err
+	}
+
+	if stats.TopScopes == nil {
+		stats.TopScopes = []models.ScopeCount{}
+	}
+	if stats.DeadEndHotspots == nil {
+		stats.DeadEndHotspots = []models.ScopeCount{}
+	}
+	return stats, nil
+}
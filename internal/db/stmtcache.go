@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// maxCachedStmts bounds the statement cache; queries built with a variable number of
+// placeholders would otherwise grow it without limit
+const maxCachedStmts = 256
+
+// stmtCache keeps prepared statements by query text so repeated writes skip
+// SQLite's parse and plan step. It is shared by a DB and the copies InTx hands out.
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[string]*sqlx.Stmt
+}
+
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: make(map[string]*sqlx.Stmt)}
+}
+
+// stmt returns a prepared statement for query, bound to the current transaction if
+// there is one. It returns nil when the query should run unprepared: statements
+// without arguments (migrations, pragmas) and anything past the cache limit.
+func (d *DB) stmt(ctx context.Context, query string, args []interface{}) (*sqlx.Stmt, error) {
+	if d.stmts == nil || len(args) == 0 {
+		return nil, nil
+	}
+
+	c := d.stmts
+	c.mu.Lock()
+	s, ok := c.stmts[query]
+	if !ok {
+		if len(c.stmts) >= maxCachedStmts {
+			c.mu.Unlock()
+			return nil, nil
+		}
+		var err error
+		s, err = d.DB.PreparexContext(ctx, query)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.stmts[query] = s
+	}
+	c.mu.Unlock()
+
+	if d.tx != nil {
+		return d.tx.StmtxContext(ctx, s), nil
+	}
+	return s, nil
+}
+
+// close releases every cached statement
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for query, s := range c.stmts {
+		s.Close()
+		delete(c.stmts, query)
+	}
+}
+
+// Close releases cached statements and closes the database
+func (d *DB) Close() error {
+	if d.stmts != nil {
+		d.stmts.close()
+	}
+	return d.DB.Close()
+}
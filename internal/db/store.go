@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// FindingStore reads and writes findings
+type FindingStore interface {
+	CreateFinding(ctx context.Context, finding *models.Finding) error
+	GetFinding(ctx context.Context, findingID string) (*models.Finding, error)
+	ListFindings(ctx context.Context, projectID, sessionID string, limit int) ([]*models.Finding, error)
+	ListFindingsWithStaleness(ctx context.Context, projectID, sessionID string, limit int) ([]*models.Finding, error)
+	ListFindingsPage(ctx context.Context, projectID, sessionID string, page Page) ([]*models.Finding, *Cursor, error)
+	FindFindingByText(ctx context.Context, projectID, searchText string) ([]*models.Finding, error)
+	FindFindingsByIDPrefix(ctx context.Context, prefix string) ([]*models.Finding, error)
+	FindFindingsTouching(ctx context.Context, projectID, needle string) ([]*models.Finding, error)
+	VerifyFinding(ctx context.Context, findingID string, expectVersion int, newGitHash, updatedText *string) error
+	MarkFindingFileChanged(ctx context.Context, findingID string, detectedAt float64) error
+	MarkFindingsFileChanged(ctx context.Context, findingIDs []string, detectedAt float64) (int64, error)
+	RecordVerificationEvidence(ctx context.Context, findingID, evidence string) error
+	ImportFinding(ctx context.Context, f *models.Finding, dryRun bool) (models.AuditAction, error)
+	FindingHistory(ctx context.Context, findingID string) (*models.FindingHistory, error)
+}
+
+// UnknownStore reads and writes unknowns
+type UnknownStore interface {
+	CreateUnknown(ctx context.Context, unknown *models.Unknown) error
+	GetUnknown(ctx context.Context, unknownID string) (*models.Unknown, error)
+	ListUnknowns(ctx context.Context, projectID, sessionID string, resolved *bool, limit int) ([]*models.Unknown, error)
+	ListUnknownsPage(ctx context.Context, projectID, sessionID string, resolved *bool, page Page) ([]*models.Unknown, *Cursor, error)
+	FindUnknownsTouching(ctx context.Context, projectID, needle string, resolved *bool) ([]*models.Unknown, error)
+	ResolveUnknown(ctx context.Context, unknownID, resolvedBy string, expectVersion int) error
+	SetUnknownImpact(ctx context.Context, unknownID string, impact float64) error
+}
+
+// DeadEndStore reads and writes dead ends
+type DeadEndStore interface {
+	CreateDeadEnd(ctx context.Context, deadEnd *models.DeadEnd) error
+	ListDeadEnds(ctx context.Context, projectID, sessionID string, limit int) ([]*models.DeadEnd, error)
+	ListDeadEndsPage(ctx context.Context, projectID, sessionID string, page Page) ([]*models.DeadEnd, *Cursor, error)
+	FindDeadEndsTouching(ctx context.Context, projectID, needle string) ([]*models.DeadEnd, error)
+	ImportDeadEnd(ctx context.Context, de *models.DeadEnd, dryRun bool) (models.AuditAction, error)
+}
+
+// BreadcrumbStore covers findings, unknowns and dead ends plus the operations shared by all three
+type BreadcrumbStore interface {
+	FindingStore
+	UnknownStore
+	DeadEndStore
+	BreadcrumbType(ctx context.Context, id string) (string, error)
+	DeleteBreadcrumb(ctx context.Context, entityType, id, reason string) error
+	RestoreBreadcrumb(ctx context.Context, entityType, id string) error
+	TagBreadcrumb(ctx context.Context, entityType, id string, tags []string) error
+	RelateBreadcrumb(ctx context.Context, entityType, id string, relation models.BreadcrumbRelation) error
+	MoveBreadcrumb(ctx context.Context, entityType, id, projectID string) error
+	CopyBreadcrumb(ctx context.Context, entityType, id, projectID string) (string, error)
+	ProjectStats(ctx context.Context, projectID string) (*models.ProjectStats, error)
+	ExpiredBreadcrumbs(ctx context.Context, cutoffs RetentionCutoffs) ([]*ExpiredBreadcrumb, error)
+}
+
+// SessionStore reads and writes sessions
+type SessionStore interface {
+	Create(ctx context.Context, session *models.Session) error
+	Get(ctx context.Context, sessionID string) (*models.Session, error)
+	GetLatest(ctx context.Context, aiID string) (*models.Session, error)
+	GetOpenBySubject(ctx context.Context, projectID, subject string) (*models.Session, error)
+	List(ctx context.Context, aiID string, limit int) ([]*models.Session, error)
+	ListPage(ctx context.Context, aiID string, page Page) ([]*models.Session, *Cursor, error)
+	Update(ctx context.Context, session *models.Session) error
+	End(ctx context.Context, sessionID string) error
+}
+
+// HandoffStore reads and writes session handoffs
+type HandoffStore interface {
+	Create(ctx context.Context, input *models.HandoffCreateInput, aiID string) (*models.HandoffReport, error)
+	Get(ctx context.Context, sessionID string) (*models.HandoffReport, error)
+	List(ctx context.Context, projectID, aiID string, limit int) ([]*models.HandoffReport, error)
+	ListPage(ctx context.Context, projectID, aiID string, page Page) ([]*models.HandoffReport, *Cursor, error)
+	SetCalibrationStatus(ctx context.Context, sessionID, status string) error
+}
+
+// ProjectStore reads and writes projects
+type ProjectStore interface {
+	Create(ctx context.Context, project *models.Project) error
+	Get(ctx context.Context, projectID string) (*models.Project, error)
+	GetByName(ctx context.Context, name string) (*models.Project, error)
+	List(ctx context.Context, status *models.ProjectStatus, limit int) ([]*models.Project, error)
+	ListChildren(ctx context.Context, parentID string) ([]*models.Project, error)
+	Update(ctx context.Context, project *models.Project) error
+	UpdateStatus(ctx context.Context, projectID string, status models.ProjectStatus) error
+	IncrementSessions(ctx context.Context, projectID string) error
+	IncrementGoals(ctx context.Context, projectID string) error
+}
+
+// GoalStore reads and writes goals
+type GoalStore interface {
+	Create(ctx context.Context, goal *models.Goal) error
+	Get(ctx context.Context, goalID string) (*models.Goal, error)
+	List(ctx context.Context, sessionID string, completed *bool, limit int) ([]*models.Goal, error)
+	Complete(ctx context.Context, goalID string, reason string) error
+	UpdateStatus(ctx context.Context, goalID string, status models.GoalStatus) error
+}
+
+// SubtaskStore reads and writes goal subtasks
+type SubtaskStore interface {
+	Create(ctx context.Context, subtask *models.SubTask) error
+	Get(ctx context.Context, subtaskID string) (*models.SubTask, error)
+	ListByGoal(ctx context.Context, goalID string) ([]*models.SubTask, error)
+	Complete(ctx context.Context, subtaskID string, evidence string) error
+	UpdateStatus(ctx context.Context, subtaskID string, status models.TaskStatus) error
+}
+
+// CommitLinkStore reads and writes links between findings and commits
+type CommitLinkStore interface {
+	Create(ctx context.Context, link *models.CommitLink) error
+	ListByFinding(ctx context.Context, findingID string) ([]*models.CommitLink, error)
+	ListByFindings(ctx context.Context, findingIDs []string) (map[string][]*models.CommitLink, error)
+}
+
+// IssueLinkStore reads and writes links between sessions or goals and tracker issues
+type IssueLinkStore interface {
+	Create(ctx context.Context, link *models.IssueLink) error
+	ListByTarget(ctx context.Context, targetType models.IssueTargetType, targetID string) ([]*models.IssueLink, error)
+}
+
+// SourceStore reads and writes epistemic sources and their links to findings
+type SourceStore interface {
+	Create(ctx context.Context, source *models.EpistemicSource) error
+	Get(ctx context.Context, sourceID string) (*models.EpistemicSource, error)
+	List(ctx context.Context, projectID, sourceType string, limit int) ([]*models.EpistemicSource, error)
+	ListByFinding(ctx context.Context, findingID string) ([]*models.EpistemicSource, error)
+	LinkFinding(ctx context.Context, sourceID, findingID string) (bool, error)
+}
+
+// ReferenceDocStore reads and writes the documents registered for a project
+type ReferenceDocStore interface {
+	Create(ctx context.Context, doc *models.ReferenceDoc) error
+	Get(ctx context.Context, docID string) (*models.ReferenceDoc, error)
+	GetByPath(ctx context.Context, projectID, docPath string) (*models.ReferenceDoc, error)
+	List(ctx context.Context, projectID, docType string) ([]*models.ReferenceDoc, error)
+	Update(ctx context.Context, doc *models.ReferenceDoc) error
+	Delete(ctx context.Context, docID string) error
+}
+
+// ProjectHandoffStore reads and writes project handoffs
+type ProjectHandoffStore interface {
+	Create(ctx context.Context, handoff *models.ProjectHandoff) error
+	Latest(ctx context.Context, projectID string) (*models.ProjectHandoff, error)
+	List(ctx context.Context, projectID string, limit int) ([]*models.ProjectHandoff, error)
+}
+
+// AuditStore reads the audit trail
+type AuditStore interface {
+	List(ctx context.Context, filter AuditFilter, limit int) ([]*models.AuditEvent, error)
+}
+
+// SyncStore exchanges breadcrumb event batches with sync peers and keeps their cursors
+type SyncStore interface {
+	GetMeta(ctx context.Context, key string) (string, error)
+	SetMeta(ctx context.Context, key, value string) error
+	LatestEventSeq(ctx context.Context) (int64, error)
+	SyncBatchAfter(ctx context.Context, afterSeq int64, limit int) (*models.SyncBatch, error)
+	ApplySyncBatch(ctx context.Context, batch *models.SyncBatch) (*MergeResult, error)
+	EventProjectIDs(ctx context.Context, events []*models.BreadcrumbEvent) (map[string]string, error)
+	NewSyncEvents(ctx context.Context, events []*models.BreadcrumbEvent) ([]*models.BreadcrumbEvent, error)
+}
+
+// ReflexStore records the epistemic snapshots taken during sessions
+type ReflexStore interface {
+	Create(ctx context.Context, reflex *models.Reflex) error
+	GetLatestByPhase(ctx context.Context, sessionID, phase string) (*models.Reflex, error)
+	ListBySession(ctx context.Context, sessionID string, limit int) ([]*models.Reflex, error)
+	ListLatestPerSession(ctx context.Context, projectID string, sessions int) ([]*models.Reflex, error)
+	ListHistory(ctx context.Context, filter ReflexFilter, limit int) ([]*models.Reflex, error)
+	CalibrationSamples(ctx context.Context) ([]*models.CalibrationSample, error)
+}
+
+// TimelineStore reads a project's history in time order
+type TimelineStore interface {
+	List(ctx context.Context, filter TimelineFilter, limit int) ([]*models.TimelineEntry, error)
+}
+
+// Stores bundles the stores the CLI works with, so another backend or a test double
+// can stand in for SQLite
+type Stores struct {
+	// Transact runs fn against stores that commit or roll back together. Backends
+	// without transactions, such as test doubles, can leave it nil to run fn directly.
+	Transact func(ctx context.Context, fn func(tx *Stores) error) error
+
+	Breadcrumbs     BreadcrumbStore
+	Sessions        SessionStore
+	Handoffs        HandoffStore
+	Projects        ProjectStore
+	Goals           GoalStore
+	Subtasks        SubtaskStore
+	CommitLinks     CommitLinkStore
+	IssueLinks      IssueLinkStore
+	Sources         SourceStore
+	Docs            ReferenceDocStore
+	ProjectHandoffs ProjectHandoffStore
+	Reflexes        ReflexStore
+	Audit           AuditStore
+	Timeline        TimelineStore
+	Sync            SyncStore
+}
+
+// NewStores returns the SQLite-backed stores for a database
+func NewStores(d *DB) *Stores {
+	return &Stores{
+		Transact: func(ctx context.Context, fn func(tx *Stores) error) error {
+			return d.InTx(ctx, func(tx *DB) error { return fn(NewStores(tx)) })
+		},
+		Breadcrumbs:     NewBreadcrumbRepository(d),
+		Sessions:        NewSessionRepository(d),
+		Handoffs:        NewHandoffRepository(d),
+		Projects:        NewProjectRepository(d),
+		Goals:           NewGoalRepository(d),
+		Subtasks:        NewSubtaskRepository(d),
+		CommitLinks:     NewCommitLinkRepository(d),
+		IssueLinks:      NewIssueLinkRepository(d),
+		Sources:         NewSourceRepository(d),
+		Docs:            NewReferenceDocRepository(d),
+		ProjectHandoffs: NewProjectHandoffRepository(d),
+		Reflexes:        NewReflexRepository(d),
+		Audit:           NewAuditRepository(d),
+		Timeline:        NewTimelineRepository(d),
+		Sync:            d,
+	}
+}
+
+// InTx runs fn against stores whose writes are committed together if fn returns nil
+func (s *Stores) InTx(ctx context.Context, fn func(tx *Stores) error) error {
+	if s.Transact == nil {
+		return fn(s)
+	}
+	return s.Transact(ctx, fn)
+}
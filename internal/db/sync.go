@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// GetMeta reads a per-database setting; empty string if unset
+func (d *DB) GetMeta(ctx context.Context, key string) (string, error) {
+	var value string
+	err := d.GetContext(ctx, &value, `SELECT value FROM meta WHERE key = ?`, key)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+// SetMeta writes a per-database setting
+func (d *DB) SetMeta(ctx context.Context, key, value string) error {
+	_, err := d.ExecContext(ctx, `INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+// LatestEventSeq returns the seq of the last event recorded, 0 when there are none
+func (d *DB) LatestEventSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := d.GetContext(ctx, &seq, `SELECT COALESCE(MAX(seq), 0) FROM breadcrumb_events`)
+	return seq, err
+}
+
+// SyncBatchAfter returns up to limit events recorded locally after the given seq,
+// in insertion order, together with every project
+func (d *DB) SyncBatchAfter(ctx context.Context, afterSeq int64, limit int) (*models.SyncBatch, error) {
+	batch := &models.SyncBatch{Cursor: afterSeq}
+	if err := d.SelectContext(ctx, &batch.Events, `SELECT * FROM breadcrumb_events WHERE seq > ? ORDER BY seq ASC LIMIT ?`,
+		afterSeq, limit+1); err != nil {
+		return nil, err
+	}
+	if len(batch.Events) > limit {
+		batch.Events = batch.Events[:limit]
+		batch.More = true
+	}
+	if len(batch.Events) == 0 {
+		return batch, nil
+	}
+	batch.Cursor = batch.Events[len(batch.Events)-1].Seq
+
+	var blobs []string
+	if err := d.SelectContext(ctx, &blobs, `SELECT project_data FROM projects ORDER BY created_timestamp ASC`); err != nil {
+		return nil, err
+	}
+	for _, data := range blobs {
+		var p models.Project
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			return nil, err
+		}
+		batch.Projects = append(batch.Projects, &p)
+	}
+	return batch, nil
+}
+
+// ApplySyncBatch merges a batch received from a sync peer, keeping each event's device
+// and Lamport clock, and rebuilds the read models if anything new arrived.
+// Events and projects already present are skipped, so batches can be replayed safely,
+// and so are events of breadcrumbs archived out of this database.
+func (d *DB) ApplySyncBatch(ctx context.Context, batch *models.SyncBatch) (*MergeResult, error) {
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Parent projects may arrive after their children
+	if _, err := tx.ExecContext(ctx, `PRAGMA defer_foreign_keys = ON`); err != nil {
+		return nil, err
+	}
+
+	result := &MergeResult{}
+	for _, p := range batch.Projects {
+		reposJSON, err := json.Marshal(p.Repos)
+		if err != nil {
+			return nil, err
+		}
+		projectData, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO projects (
+				id, name, description, repos, created_timestamp, last_activity_timestamp,
+				status, total_sessions, total_goals, project_data, parent_id, root_path
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			p.ID, p.Name, p.Description, string(reposJSON), p.CreatedTimestamp, p.LastActivityTimestamp,
+			p.Status, p.TotalSessions, p.TotalGoals, string(projectData), p.ParentID, p.RootPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge project %s: %w", p.ID, err)
+		}
+		n, _ := res.RowsAffected()
+		result.Projects += int(n)
+	}
+
+	for _, ev := range batch.Events {
+		if ev.ID == "" || ev.DeviceID == "" || ev.Lamport <= 0 {
+			return nil, fmt.Errorf("%w event %q: missing id, device or clock", ErrInvalid, ev.ID)
+		}
+		if _, ok := breadcrumbTables[ev.EntityType]; !ok {
+			return nil, fmt.Errorf("%w event %s: unknown entity type %q", ErrInvalid, ev.ID, ev.EntityType)
+		}
+		archived, err := isArchived(ctx, tx, ev.EntityType, ev.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if archived {
+			result.Skipped++
+			continue
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO breadcrumb_events (id, entity_type, entity_id, kind, payload, timestamp, device_id, lamport)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			ev.ID, ev.EntityType, ev.EntityID, ev.Kind, ev.Payload, ev.Timestamp, ev.DeviceID, ev.Lamport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge event %s: %w", ev.ID, err)
+		}
+		n, _ := res.RowsAffected()
+		result.Events += int(n)
+	}
+
+	// Rebuilt in the same transaction, so a batch that can't be replayed isn't kept
+	if result.Events > 0 {
+		if _, err := rebuildBreadcrumbsTx(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	return result, tx.Commit()
+}
+
+// EventProjectIDs maps the breadcrumbs the events touch to their projects: from the
+// breadcrumb's current state, else from a created event in the list, so a pushed event
+// can't claim another project for an existing breadcrumb. Breadcrumbs found in neither
+// are left out.
+func (d *DB) EventProjectIDs(ctx context.Context, events []*models.BreadcrumbEvent) (map[string]string, error) {
+	projects := map[string]string{}
+	for _, ev := range events {
+		if _, ok := projects[ev.EntityID]; ok {
+			continue
+		}
+		table, ok := breadcrumbTables[ev.EntityType]
+		if !ok {
+			continue
+		}
+		var projectID string
+		err := d.GetContext(ctx, &projectID, `SELECT project_id FROM `+table+` WHERE id = ?`, ev.EntityID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		projects[ev.EntityID] = projectID
+	}
+
+	for _, ev := range events {
+		if _, ok := projects[ev.EntityID]; ok {
+			continue
+		}
+		switch ev.Kind {
+		case models.EventFindingCreated, models.EventUnknownCreated, models.EventDeadEndCreated:
+			var created struct {
+				ProjectID string `json:"project_id"`
+			}
+			if err := json.Unmarshal([]byte(ev.Payload), &created); err == nil && created.ProjectID != "" {
+				projects[ev.EntityID] = created.ProjectID
+			}
+		}
+	}
+	return projects, nil
+}
+
+// NewSyncEvents returns the events this database doesn't hold yet, the ones merging a
+// batch would actually add
+func (d *DB) NewSyncEvents(ctx context.Context, events []*models.BreadcrumbEvent) ([]*models.BreadcrumbEvent, error) {
+	var fresh []*models.BreadcrumbEvent
+	for _, ev := range events {
+		var n int
+		if err := d.GetContext(ctx, &n, `SELECT COUNT(*) FROM breadcrumb_events WHERE id = ?`, ev.ID); err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			fresh = append(fresh, ev)
+		}
+	}
+	return fresh, nil
+}
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+// TimelineRepository reads the history of a project across breadcrumbs, goals and sessions
+type TimelineRepository struct {
+	db *DB
+}
+
+// NewTimelineRepository creates a new timeline repository
+func NewTimelineRepository(db *DB) *TimelineRepository {
+	return &TimelineRepository{db: db}
+}
+
+// TimelineFilter narrows a timeline
+type TimelineFilter struct {
+	ProjectID string
+	SessionID string  // Only this session's breadcrumbs and goals, plus changes made while it ran
+	Since     float64 // Only entries at or after this time
+	Until     float64 // Only entries at or before this time, when set
+}
+
+// timelineSession is the part of a session the timeline needs
+type timelineSession struct {
+	SessionID string     `db:"session_id"`
+	AIID      string     `db:"ai_id"`
+	Subject   *string    `db:"subject"`
+	StartTime time.Time  `db:"start_time"`
+	EndTime   *time.Time `db:"end_time"`
+}
+
+// breadcrumbTimelineQuery joins breadcrumb events to the breadcrumbs they change.
+// Only the creation of a breadcrumb belongs to the session that recorded it; later
+// events belong to whichever session was running, so a session filter also takes
+// events inside its time window. Each row shows the text the breadcrumb had when the
+// event happened, taken from the latest event before it in replay order that set one;
+// a verification that rewrote the text shows the new text as its detail.
+const breadcrumbTimelineQuery = `
+	SELECT timestamp, kind, entity_type, entity_id, session_id, text, detail, payload FROM (
+		SELECT
+			e.timestamp,
+			CAST(e.kind AS TEXT) AS kind,
+			e.entity_type,
+			e.entity_id,
+			COALESCE(f.session_id, u.session_id, d.session_id) AS session_id,
+			COALESCE((
+				SELECT COALESCE(json_extract(t.payload, '$.finding'), json_extract(t.payload, '$.unknown'), json_extract(t.payload, '$.approach'))
+				FROM breadcrumb_events t
+				WHERE t.entity_type = e.entity_type AND t.entity_id = e.entity_id
+				AND t.kind IN ('finding_created', 'finding_verified', 'unknown_created', 'dead_end_created')
+				AND COALESCE(json_extract(t.payload, '$.finding'), json_extract(t.payload, '$.unknown'), json_extract(t.payload, '$.approach')) IS NOT NULL
+				AND ((t.lamport, t.device_id, t.id) < (e.lamport, e.device_id, e.id) OR (t.id = e.id AND t.kind != 'finding_verified'))
+				ORDER BY t.lamport DESC, t.device_id DESC, t.id DESC
+				LIMIT 1
+			), f.finding, u.unknown, d.approach, '') AS text,
+			CASE WHEN e.kind = 'dead_end_created' THEN COALESCE(json_extract(e.payload, '$.why_failed'), '') ELSE '' END AS detail,
+			e.payload,
+			COALESCE(f.project_id, u.project_id, d.project_id) AS project_id
+		FROM breadcrumb_events e
+		LEFT JOIN project_findings f ON e.entity_type = 'finding' AND f.id = e.entity_id
+		LEFT JOIN project_unknowns u ON e.entity_type = 'unknown' AND u.id = e.entity_id
+		LEFT JOIN project_dead_ends d ON e.entity_type = 'dead_end' AND d.id = e.entity_id
+	)
+	WHERE project_id = ? AND timestamp >= ?`
+
+// goalTimelineQuery reads goal and subtask changes from the audit trail
+const goalTimelineQuery = `
+	SELECT timestamp, kind, entity_type, entity_id, session_id, text, detail, payload FROM (
+		SELECT
+			a.timestamp,
+			a.entity_type || '_' || CASE a.action
+				WHEN 'create' THEN 'created'
+				WHEN 'complete' THEN 'completed'
+				ELSE 'updated'
+			END AS kind,
+			a.entity_type,
+			a.entity_id,
+			COALESCE(g.session_id, sg.session_id) AS session_id,
+			COALESCE(g.objective, s.description, '') AS text,
+			'' AS detail,
+			a.payload,
+			a.project_id
+		FROM audit_events a
+		LEFT JOIN goals g ON a.entity_type = 'goal' AND g.id = a.entity_id
+		LEFT JOIN subtasks s ON a.entity_type = 'subtask' AND s.id = a.entity_id
+		LEFT JOIN goals sg ON sg.id = s.goal_id
+		WHERE a.entity_type IN ('goal', 'subtask')
+	)
+	WHERE project_id = ? AND timestamp >= ?`
+
+// List returns the timeline in chronological order. With a limit, the most recent
+// entries are kept.
+func (r *TimelineRepository) List(ctx context.Context, filter TimelineFilter, limit int) ([]*models.TimelineEntry, error) {
+	var sessions []timelineSession
+	sessionQuery := `SELECT session_id, ai_id, subject, start_time, end_time FROM sessions WHERE project_id = ?`
+	sessionArgs := []interface{}{filter.ProjectID}
+	if filter.SessionID != "" {
+		sessionQuery += ` AND session_id = ?`
+		sessionArgs = append(sessionArgs, filter.SessionID)
+	}
+	if err := r.db.SelectContext(ctx, &sessions, sessionQuery, sessionArgs...); err != nil {
+		return nil, err
+	}
+	if filter.SessionID != "" && len(sessions) == 0 {
+		return nil, notFound("session", filter.SessionID)
+	}
+
+	var entries []*models.TimelineEntry
+	for _, query := range []string{breadcrumbTimelineQuery, goalTimelineQuery} {
+		args := []interface{}{filter.ProjectID, filter.Since}
+		if filter.Until > 0 {
+			query += ` AND timestamp <= ?`
+			args = append(args, filter.Until)
+		}
+		if filter.SessionID != "" {
+			s := sessions[0]
+			end := time.Now()
+			if s.EndTime != nil {
+				end = *s.EndTime
+			}
+			query += ` AND (session_id = ? OR (kind NOT LIKE '%_created' AND timestamp BETWEEN ? AND ?))`
+			args = append(args, s.SessionID, unixSeconds(s.StartTime), unixSeconds(end))
+		}
+		if limit > 0 {
+			query += ` ORDER BY timestamp DESC LIMIT ?`
+			args = append(args, limit)
+		}
+		var rows []*models.TimelineEntry
+		if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+			return nil, err
+		}
+		entries = append(entries, rows...)
+	}
+
+	for _, s := range sessions {
+		s := s
+		text := ""
+		if s.Subject != nil {
+			text = *s.Subject
+		}
+		entries = append(entries, &models.TimelineEntry{
+			Timestamp:  unixSeconds(s.StartTime),
+			Kind:       "session_started",
+			EntityType: "session",
+			EntityID:   s.SessionID,
+			SessionID:  &s.SessionID,
+			Text:       text,
+			Detail:     s.AIID,
+		})
+		if s.EndTime != nil {
+			entries = append(entries, &models.TimelineEntry{
+				Timestamp:  unixSeconds(*s.EndTime),
+				Kind:       "session_ended",
+				EntityType: "session",
+				EntityID:   s.SessionID,
+				SessionID:  &s.SessionID,
+				Text:       text,
+			})
+		}
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Timestamp >= filter.Since && (filter.Until == 0 || e.Timestamp <= filter.Until) {
+			describeTimelineEntry(e)
+			kept = append(kept, e)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Timestamp < kept[j].Timestamp })
+	if limit > 0 && len(kept) > limit {
+		kept = kept[len(kept)-limit:]
+	}
+	return kept, nil
+}
+
+// describeTimelineEntry fills in an entry's detail from its event payload
+func describeTimelineEntry(e *models.TimelineEntry) {
+	if e.Detail != "" || e.Payload == nil {
+		return
+	}
+	var p struct {
+		Finding    *string `json:"finding"`
+		Evidence   string  `json:"evidence"`
+		ResolvedBy string  `json:"resolved_by"`
+		Reason     string  `json:"reason"`
+		Tag        string  `json:"tag"`
+		TargetID   string  `json:"target_id"`
+		Kind       string  `json:"kind"`
+		Status     string  `json:"status"`
+		Impact     float64 `json:"impact"`
+	}
+	if json.Unmarshal([]byte(*e.Payload), &p) != nil {
+		return
+	}
+
+	switch e.Kind {
+	case string(models.EventFindingVerified):
+		if p.Finding != nil {
+			e.Detail = "text updated: " + *p.Finding
+		}
+	case string(models.EventFindingFileChanged):
+		e.Detail = "scoped file changed"
+	case string(models.EventFindingEvidenceRecorded):
+		e.Detail = p.Evidence
+	case string(models.EventUnknownResolved):
+		e.Detail = p.ResolvedBy
+	case string(models.EventUnknownReprioritized):
+		e.Detail = fmt.Sprintf("impact %.1f", p.Impact)
+	case string(models.EventBreadcrumbTagged):
+		e.Detail = "#" + p.Tag
+	case string(models.EventBreadcrumbRelated):
+		e.Detail = fmt.Sprintf("%s %s", p.Kind, p.TargetID)
+	case "goal_updated", "subtask_updated":
+		e.Detail = p.Status
+	case "goal_completed":
+		e.Detail = p.Reason
+	case "subtask_completed":
+		e.Detail = p.Evidence
+	default:
+		e.Detail = p.Reason // Deletions
+	}
+}
+
+// unixSeconds converts a time to the fractional Unix seconds breadcrumbs use
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000.0
+}
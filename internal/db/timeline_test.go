@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AbdouB/memory/internal/models"
+)
+
+func TestTimelineShowsTextAtEachEvent(t *testing.T) {
+	ctx := context.Background()
+	d := openTestDB(t, "memory.db")
+	project, session := seedSession(t, d, "history")
+	repo := NewBreadcrumbRepository(d)
+
+	finding := models.NewFinding(project.ID, session.SessionID, "Tokens expire after 1h", 0.5)
+	if err := repo.CreateFinding(ctx, finding); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := repo.GetFinding(ctx, finding.ID)
+	if err := repo.VerifyFinding(ctx, finding.ID, f.Version, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	updated := "Tokens expire after 3h"
+	if err := repo.VerifyFinding(ctx, finding.ID, f.Version+1, nil, &updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.VerifyFinding(ctx, finding.ID, f.Version+2, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := NewTimelineRepository(d).List(ctx, TimelineFilter{ProjectID: project.ID}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		if e.EntityID == finding.ID {
+			got = append(got, e.Kind+": "+e.Text)
+		}
+	}
+	want := []string{
+		"finding_created: Tokens expire after 1h",
+		"finding_verified: Tokens expire after 1h",
+		"finding_verified: Tokens expire after 1h", // The rewrite; its detail has the new text
+		"finding_verified: Tokens expire after 3h",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("timeline = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDriverName is the SQLite driver wrapped with query timing
+const tracedDriverName = "sqlite3_traced"
+
+// slowQueryThreshold is the duration above which queries are logged at info level
+const slowQueryThreshold = 100 * time.Millisecond
+
+func init() {
+	sql.Register(tracedDriverName, tracedDriver{&sqlite3.SQLiteDriver{}})
+	sqlx.BindDriver(tracedDriverName, sqlx.QUESTION)
+}
+
+// tracerName names the spans recorded for statements
+const tracerName = "github.com/AbdouB/memory/internal/db"
+
+// logQuery records how long a statement took: every statement at debug level,
+// slow or failing ones at info level, and as a span of the caller's trace
+func logQuery(ctx context.Context, kind, query string, start time.Time, err error) {
+	traceQuery(ctx, kind, query, start, err)
+	elapsed := time.Since(start)
+	level := slog.LevelDebug
+	if elapsed >= slowQueryThreshold || (err != nil && err != driver.ErrSkip) {
+		level = slog.LevelInfo
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+	attrs := []any{"kind", kind, "sql", compactSQL(query), "duration", elapsed}
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	}
+	slog.Log(ctx, level, "db query", attrs...)
+}
+
+// traceQuery records a finished statement as a span under the one in ctx. A statement
+// the driver skips is retried as a prepared statement, which records its own span.
+func traceQuery(ctx context.Context, kind, query string, start time.Time, err error) {
+	parent := trace.SpanFromContext(ctx)
+	if err == driver.ErrSkip || !parent.IsRecording() {
+		return
+	}
+	_, span := otel.Tracer(tracerName).Start(ctx, "db."+kind,
+		trace.WithTimestamp(start),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.statement", compactSQL(query)),
+		),
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// compactSQL collapses whitespace and shortens a statement for logging
+func compactSQL(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > 200 {
+		query = query[:197] + "..."
+	}
+	return query
+}
+
+// tracedDriver wraps the SQLite driver so every connection is timed
+type tracedDriver struct {
+	driver.Driver
+}
+
+// Open opens a timed connection
+func (d tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+// tracedConn times statements run on a SQLite connection
+type tracedConn struct {
+	*sqlite3.SQLiteConn
+}
+
+// ExecContext runs a statement and logs its duration
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := c.SQLiteConn.ExecContext(ctx, query, args)
+	logQuery(ctx, "exec", query, start, err)
+	return result, err
+}
+
+// QueryContext runs a query and logs the time until the first row is ready
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.SQLiteConn.QueryContext(ctx, query, args)
+	logQuery(ctx, "query", query, start, err)
+	return rows, err
+}
+
+// Prepare prepares a timed statement
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext prepares a timed statement
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.SQLiteConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{stmt.(*sqlite3.SQLiteStmt), query}, nil
+}
+
+// tracedStmt times executions of a prepared statement
+type tracedStmt struct {
+	*sqlite3.SQLiteStmt
+	query string
+}
+
+// ExecContext executes the statement and logs its duration
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := s.SQLiteStmt.ExecContext(ctx, args)
+	logQuery(ctx, "exec", s.query, start, err)
+	return result, err
+}
+
+// QueryContext runs the statement and logs the time until the first row is ready
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.SQLiteStmt.QueryContext(ctx, args)
+	logQuery(ctx, "query", s.query, start, err)
+	return rows, err
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// InTx runs fn with a DB whose repositories all read and write through one transaction,
+// committing if fn returns nil and rolling back otherwise. Calling InTx on a DB that is
+// already in a transaction runs fn in that transaction, so batches of breadcrumb writes
+// made inside fn are committed once. A transaction that loses a lock race to another
+// process is rolled back and fn runs again, so fn must only touch the database.
+func (d *DB) InTx(ctx context.Context, fn func(tx *DB) error) error {
+	if d.tx != nil {
+		return fn(d)
+	}
+	return retryBusy(ctx, func() error { return d.runTx(ctx, fn) })
+}
+
+// runTx makes one attempt at running fn in a transaction
+func (d *DB) runTx(ctx context.Context, fn func(tx *DB) error) error {
+	tx, err := d.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	inTx := *d
+	inTx.tx = tx
+	if err := fn(&inTx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// BeginTxx starts a transaction; it fails inside InTx because SQLite can't nest them
+func (d *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	if d.tx != nil {
+		return nil, fmt.Errorf("nested transactions are not supported")
+	}
+	return d.DB.BeginTxx(ctx, opts)
+}
+
+// ExecContext runs a statement, inside the current transaction if there is one
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := d.retryBusy(ctx, func() (err error) {
+		result, err = d.execContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (d *DB) execContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if s, err := d.stmt(ctx, query, args); err != nil || s != nil {
+		if err != nil {
+			return nil, err
+		}
+		return s.ExecContext(ctx, args...)
+	}
+	if d.tx != nil {
+		return d.tx.ExecContext(ctx, query, args...)
+	}
+	return d.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a query, inside the current transaction if there is one
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var result *sql.Rows
+	err := d.retryBusy(ctx, func() (err error) {
+		result, err = d.queryContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (d *DB) queryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if s, err := d.stmt(ctx, query, args); err != nil || s != nil {
+		if err != nil {
+			return nil, err
+		}
+		return s.QueryContext(ctx, args...)
+	}
+	if d.tx != nil {
+		return d.tx.QueryContext(ctx, query, args...)
+	}
+	return d.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a single-row query, inside the current transaction if there is one
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	// A failed prepare falls through so the error surfaces from Scan
+	if s, err := d.stmt(ctx, query, args); err == nil && s != nil {
+		return s.Stmt.QueryRowContext(ctx, args...)
+	}
+	if d.tx != nil {
+		return d.tx.QueryRowContext(ctx, query, args...)
+	}
+	return d.DB.QueryRowContext(ctx, query, args...)
+}
+
+// GetContext scans a single row into dest, inside the current transaction if there is one
+func (d *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.retryBusy(ctx, func() error { return d.getContext(ctx, dest, query, args...) })
+}
+
+func (d *DB) getContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if s, err := d.stmt(ctx, query, args); err != nil || s != nil {
+		if err != nil {
+			return err
+		}
+		return s.GetContext(ctx, dest, args...)
+	}
+	if d.tx != nil {
+		return d.tx.GetContext(ctx, dest, query, args...)
+	}
+	return d.DB.GetContext(ctx, dest, query, args...)
+}
+
+// SelectContext scans rows into dest, inside the current transaction if there is one
+func (d *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return d.retryBusy(ctx, func() error { return d.selectContext(ctx, dest, query, args...) })
+}
+
+func (d *DB) selectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if s, err := d.stmt(ctx, query, args); err != nil || s != nil {
+		if err != nil {
+			return err
+		}
+		return s.SelectContext(ctx, dest, args...)
+	}
+	if d.tx != nil {
+		return d.tx.SelectContext(ctx, dest, query, args...)
+	}
+	return d.DB.SelectContext(ctx, dest, query, args...)
+}
+
+// retryBusy retries fn while the database is locked, unless this is a transaction:
+// its locks are held until it ends, so InTx retries the whole transaction instead
+func (d *DB) retryBusy(ctx context.Context, fn func() error) error {
+	if d.tx != nil {
+		return fn()
+	}
+	return retryBusy(ctx, fn)
+}
@@ -0,0 +1,44 @@
+package models
+
+// AuditAction is the kind of mutation recorded in the audit trail
+type AuditAction string
+
+const (
+	AuditCreate   AuditAction = "create"
+	AuditEdit     AuditAction = "edit"
+	AuditVerify   AuditAction = "verify"
+	AuditResolve  AuditAction = "resolve"
+	AuditComplete AuditAction = "complete"
+	AuditDelete   AuditAction = "delete"
+	AuditRestore  AuditAction = "restore"
+)
+
+// Audited entity types
+const (
+	EntityProject        = "project"
+	EntitySession        = "session"
+	EntityFinding        = "finding"
+	EntityUnknown        = "unknown"
+	EntityDeadEnd        = "dead_end"
+	EntityMistake        = "mistake"
+	EntityGoal           = "goal"
+	EntitySubtask        = "subtask"
+	EntityHandoff        = "handoff"
+	EntityCommitLink     = "commit_link"
+	EntityIssueLink      = "issue_link"
+	EntitySource         = "source"
+	EntityDoc            = "doc"
+	EntityProjectHandoff = "project_handoff"
+)
+
+// AuditEvent is one entry in the append-only audit trail
+type AuditEvent struct {
+	ID         int64       `json:"id" db:"id"`
+	Timestamp  float64     `json:"timestamp" db:"timestamp"`
+	Actor      string      `json:"actor" db:"actor"` // ai_id of the active session, or user:<name>
+	Action     AuditAction `json:"action" db:"action"`
+	EntityType string      `json:"entity_type" db:"entity_type"`
+	EntityID   string      `json:"entity_id" db:"entity_id"`
+	ProjectID  *string     `json:"project_id,omitempty" db:"project_id"`
+	Payload    *string     `json:"payload,omitempty" db:"payload"` // JSON snapshot of what changed
+}
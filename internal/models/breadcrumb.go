@@ -1,7 +1,12 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +27,64 @@ const DecayHalfLifeDays = 14.0
 // FileChangeConfidenceMultiplier is applied when referenced file changes
 const FileChangeConfidenceMultiplier = 0.5
 
+// ScopeKind says what a finding's subject names, which decides how a change to it is
+// detected
+type ScopeKind string
+
+const (
+	ScopeFile        ScopeKind = "file"         // Git blob hash of the file
+	ScopeDir         ScopeKind = "dir"          // Blob hashes of every file under the directory
+	ScopeURL         ScopeKind = "url"          // ETag or Last-Modified of the response
+	ScopeCommand     ScopeKind = "command"      // Path, size and modification time of the executable
+	ScopeExternalAPI ScopeKind = "external-api" // Not fingerprinted; only ages
+)
+
+// ScopeKinds lists every scope kind
+var ScopeKinds = []ScopeKind{ScopeFile, ScopeDir, ScopeURL, ScopeCommand, ScopeExternalAPI}
+
+// ParseScopeKind returns the scope kind named s
+func ParseScopeKind(s string) (ScopeKind, bool) {
+	for _, k := range ScopeKinds {
+		if string(k) == s {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// InferScopeKind returns the kind of a subject recorded without one. URLs are told
+// apart by their scheme and anything else is taken for a file, as every subject was
+// before kinds were recorded.
+func InferScopeKind(subject string) ScopeKind {
+	if strings.HasPrefix(subject, "http://") || strings.HasPrefix(subject, "https://") {
+		return ScopeURL
+	}
+	return ScopeFile
+}
+
+// Value stores the kind as text, or NULL when there is none
+func (k ScopeKind) Value() (driver.Value, error) {
+	if k == "" {
+		return nil, nil
+	}
+	return string(k), nil
+}
+
+// Scan reads a kind stored by Value
+func (k *ScopeKind) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*k = ""
+	case string:
+		*k = ScopeKind(src)
+	case []byte:
+		*k = ScopeKind(src)
+	default:
+		return fmt.Errorf("unsupported scope kind column type %T", src)
+	}
+	return nil
+}
+
 // BreadcrumbScope determines where breadcrumbs are stored
 type BreadcrumbScope string
 
@@ -31,20 +94,146 @@ const (
 	ScopeBoth    BreadcrumbScope = "both"    // Dual-log for important discoveries
 )
 
+// Relation kinds between breadcrumbs
+const (
+	RelationRelated     = "related"
+	RelationSupersedes  = "supersedes"
+	RelationContradicts = "contradicts"
+	RelationCopiedFrom  = "copied_from" // Recorded on copies made by 'memory cp'
+)
+
+// BreadcrumbRelation links a breadcrumb to another one
+type BreadcrumbRelation struct {
+	TargetID string `json:"target_id"`
+	Kind     string `json:"kind"` // related, supersedes, contradicts or copied_from
+}
+
+// Tags that assign an unknown to a future session
+const (
+	TagNextSession     = "next-session"
+	TagObjectivePrefix = "objective:" // Followed by the lowercased objective
+)
+
+// Tags marking findings that export renders as rules rather than plain knowledge
+const (
+	TagConvention = "convention"
+	TagDecision   = "decision"
+	TagConstraint = "constraint"
+)
+
+// copiedRelations returns a copy's relations: the original's, plus one back to it
+func copiedRelations(relations RelationSet, originalID string) RelationSet {
+	copied := append(RelationSet{}, relations...)
+	copied = append(copied, BreadcrumbRelation{TargetID: originalID, Kind: RelationCopiedFrom})
+	sort.Slice(copied, func(i, j int) bool {
+		if copied[i].TargetID != copied[j].TargetID {
+			return copied[i].TargetID < copied[j].TargetID
+		}
+		return copied[i].Kind < copied[j].Kind
+	})
+	return copied
+}
+
+// TagSet is a breadcrumb's sorted tags, stored as a JSON array column
+type TagSet []string
+
+// Value stores the set as a JSON array, or NULL when empty
+func (t TagSet) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]string(t))
+	return string(data), err
+}
+
+// Scan reads a set stored by Value
+func (t *TagSet) Scan(src interface{}) error {
+	return scanJSONColumn(src, (*[]string)(t))
+}
+
+// RelationSet is a breadcrumb's sorted relations, stored as a JSON array column
+type RelationSet []BreadcrumbRelation
+
+// Value stores the set as a JSON array, or NULL when empty
+func (r RelationSet) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]BreadcrumbRelation(r))
+	return string(data), err
+}
+
+// Scan reads a set stored by Value
+func (r *RelationSet) Scan(src interface{}) error {
+	return scanJSONColumn(src, (*[]BreadcrumbRelation)(r))
+}
+
+// CodeSnippet is the code a finding is about, as it read when the finding was logged
+type CodeSnippet struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Language  string `json:"language,omitempty"`
+	Code      string `json:"code"`
+}
+
+// Location returns the snippet's file and line range, e.g. auth/jwt.go:20-45
+func (s *CodeSnippet) Location() string {
+	return fmt.Sprintf("%s:%d-%d", s.Path, s.StartLine, s.EndLine)
+}
+
+// Value stores the snippet as a JSON object, or NULL when there is none
+func (s *CodeSnippet) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// Scan reads a snippet stored by Value
+func (s *CodeSnippet) Scan(src interface{}) error {
+	return scanJSONColumn(src, s)
+}
+
+// scanJSONColumn decodes a nullable JSON column into v
+func scanJSONColumn(src interface{}, v interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), v)
+	case []byte:
+		return json.Unmarshal(src, v)
+	}
+	return fmt.Errorf("cannot scan %T into a JSON column", src)
+}
+
 // Finding represents a discovered fact or insight
 type Finding struct {
-	ID                    string   `json:"id" db:"id"`
-	ProjectID             string   `json:"project_id" db:"project_id"`
-	SessionID             string   `json:"session_id" db:"session_id"`
-	GoalID                *string  `json:"goal_id,omitempty" db:"goal_id"`
-	SubtaskID             *string  `json:"subtask_id,omitempty" db:"subtask_id"`
-	Finding               string   `json:"finding" db:"finding"`
-	CreatedTimestamp      float64  `json:"created_timestamp" db:"created_timestamp"`
-	Subject               *string  `json:"subject,omitempty" db:"subject"`
-	Impact                float64  `json:"impact" db:"impact"` // 0.0-1.0
-	FindingData           string   `json:"-" db:"finding_data"`
-	LastVerifiedTimestamp *float64 `json:"last_verified_timestamp,omitempty" db:"last_verified_timestamp"`
-	SubjectGitHash        *string  `json:"subject_git_hash,omitempty" db:"subject_git_hash"`
+	ID                    string       `json:"id" db:"id"`
+	ProjectID             string       `json:"project_id" db:"project_id"`
+	SessionID             string       `json:"session_id" db:"session_id"`
+	GoalID                *string      `json:"goal_id,omitempty" db:"goal_id"`
+	SubtaskID             *string      `json:"subtask_id,omitempty" db:"subtask_id"`
+	Finding               string       `json:"finding" db:"finding"`
+	CreatedTimestamp      float64      `json:"created_timestamp" db:"created_timestamp"`
+	Subject               *string      `json:"subject,omitempty" db:"subject"`
+	SubjectKind           ScopeKind    `json:"subject_kind,omitempty" db:"subject_kind"` // Empty on findings recorded before kinds
+	Impact                float64      `json:"impact" db:"impact"`                       // 0.0-1.0
+	LastVerifiedTimestamp *float64     `json:"last_verified_timestamp,omitempty" db:"last_verified_timestamp"`
+	SubjectGitHash        *string      `json:"subject_git_hash,omitempty" db:"subject_git_hash"`
+	VerifyCheck           *string      `json:"verify_check,omitempty" db:"verify_check"`                   // Shell command whose exit status verifies the finding
+	VerificationEvidence  *string      `json:"verification_evidence,omitempty" db:"verification_evidence"` // Output of the last check run
+	FileChangedDetectedAt *float64     `json:"file_changed_detected_at,omitempty" db:"file_changed_detected_at"`
+	Worktree              *string      `json:"worktree,omitempty" db:"worktree"`     // Checkout directory the finding was made in
+	GitBranch             *string      `json:"git_branch,omitempty" db:"git_branch"` // Branch checked out when the finding was made
+	Snippet               *CodeSnippet `json:"snippet,omitempty" db:"snippet"`       // Code captured with --snippet
+	UpdatedAt             *float64     `json:"updated_at,omitempty" db:"updated_at"`
+	DeletedAt             *float64     `json:"deleted_at,omitempty" db:"deleted_at"` // Tombstone; deleted findings are hidden from reads
+	Version               int          `json:"version" db:"version"`                 // Number of events applied; guards concurrent updates
+	Tags                  TagSet       `json:"tags,omitempty" db:"tags"`             // Grow-only set
+	Relations             RelationSet  `json:"relations,omitempty" db:"relations"`   // Grow-only set
 }
 
 // CalculateConfidence returns the time-decayed confidence (0.0-1.0)
@@ -85,6 +274,41 @@ func (f *Finding) GetStalenessStatus(fileChanged bool) StalenessStatus {
 	return StatusStale
 }
 
+// StaleSince returns the Unix timestamp at which the finding became (or will become) stale,
+// accounting for the file-change penalty from the moment the change was detected
+func (f *Finding) StaleSince() float64 {
+	baseTime := f.CreatedTimestamp
+	if f.LastVerifiedTimestamp != nil {
+		baseTime = *f.LastVerifiedTimestamp
+	}
+	lambda := math.Log(2) / DecayHalfLifeDays
+	secondsUntil := func(confidence float64) float64 {
+		return math.Log(1/confidence) / lambda * 24 * 60 * 60
+	}
+
+	staleAt := baseTime + secondsUntil(0.40)
+	if f.FileChangedDetectedAt != nil {
+		// With the penalty applied, confidence drops below 0.40 once decay passes 0.40/multiplier
+		changedAt := math.Max(*f.FileChangedDetectedAt, baseTime+secondsUntil(0.40/FileChangeConfidenceMultiplier))
+		staleAt = math.Min(staleAt, changedAt)
+	}
+	return staleAt
+}
+
+// StalenessAges returns how many seconds after its last verification (or creation) a
+// finding turns aging and then stale, the thresholds GetStalenessStatus applies
+func StalenessAges(fileChanged bool) (agingAfter, staleAfter float64) {
+	lambda := math.Log(2) / DecayHalfLifeDays
+	secondsUntil := func(confidence float64) float64 {
+		if fileChanged {
+			confidence /= FileChangeConfidenceMultiplier
+		}
+		// A penalized finding can start below a threshold; it crosses it immediately
+		return math.Max(0, math.Log(1/confidence)/lambda*24*60*60)
+	}
+	return secondsUntil(0.70), secondsUntil(0.40)
+}
+
 // DaysSinceVerified returns the number of days since last verification (or creation)
 func (f *Finding) DaysSinceVerified() float64 {
 	baseTime := f.CreatedTimestamp
@@ -107,6 +331,30 @@ func NewFinding(projectID, sessionID, finding string, impact float64) *Finding {
 	}
 }
 
+// ScopeKind returns the kind of the finding's subject, inferred for findings recorded
+// without one, or "" when it has no subject
+func (f *Finding) ScopeKind() ScopeKind {
+	switch {
+	case f.Subject == nil || *f.Subject == "":
+		return ""
+	case f.SubjectKind != "":
+		return f.SubjectKind
+	}
+	return InferScopeKind(*f.Subject)
+}
+
+// CopyTo returns a copy of the finding filed under another project. It keeps the
+// session, timestamps and verification, and relates back to the original.
+func (f *Finding) CopyTo(projectID string) *Finding {
+	c := *f
+	c.ID = uuid.New().String()
+	c.ProjectID = projectID
+	c.UpdatedAt, c.DeletedAt, c.Version = nil, nil, 0
+	c.Tags = append(TagSet(nil), f.Tags...)
+	c.Relations = copiedRelations(f.Relations, f.ID)
+	return &c
+}
+
 // FindingLogInput represents input for logging a finding
 type FindingLogInput struct {
 	ProjectID string          `json:"project_id,omitempty"`
@@ -121,19 +369,23 @@ type FindingLogInput struct {
 
 // Unknown represents a knowledge gap or unanswered question
 type Unknown struct {
-	ID                string   `json:"id" db:"id"`
-	ProjectID         string   `json:"project_id" db:"project_id"`
-	SessionID         string   `json:"session_id" db:"session_id"`
-	GoalID            *string  `json:"goal_id,omitempty" db:"goal_id"`
-	SubtaskID         *string  `json:"subtask_id,omitempty" db:"subtask_id"`
-	Unknown           string   `json:"unknown" db:"unknown"`
-	IsResolved        bool     `json:"is_resolved" db:"is_resolved"`
-	ResolvedBy        *string  `json:"resolved_by,omitempty" db:"resolved_by"`
-	CreatedTimestamp  float64  `json:"created_timestamp" db:"created_timestamp"`
-	ResolvedTimestamp *float64 `json:"resolved_timestamp,omitempty" db:"resolved_timestamp"`
-	Subject           *string  `json:"subject,omitempty" db:"subject"`
-	Impact            float64  `json:"impact" db:"impact"`
-	UnknownData       string   `json:"-" db:"unknown_data"`
+	ID                string      `json:"id" db:"id"`
+	ProjectID         string      `json:"project_id" db:"project_id"`
+	SessionID         string      `json:"session_id" db:"session_id"`
+	GoalID            *string     `json:"goal_id,omitempty" db:"goal_id"`
+	SubtaskID         *string     `json:"subtask_id,omitempty" db:"subtask_id"`
+	Unknown           string      `json:"unknown" db:"unknown"`
+	IsResolved        bool        `json:"is_resolved" db:"is_resolved"`
+	ResolvedBy        *string     `json:"resolved_by,omitempty" db:"resolved_by"`
+	CreatedTimestamp  float64     `json:"created_timestamp" db:"created_timestamp"`
+	ResolvedTimestamp *float64    `json:"resolved_timestamp,omitempty" db:"resolved_timestamp"`
+	Subject           *string     `json:"subject,omitempty" db:"subject"`
+	Impact            float64     `json:"impact" db:"impact"`
+	UpdatedAt         *float64    `json:"updated_at,omitempty" db:"updated_at"`
+	DeletedAt         *float64    `json:"deleted_at,omitempty" db:"deleted_at"` // Tombstone; deleted unknowns are hidden from reads
+	Version           int         `json:"version" db:"version"`                 // Number of events applied; guards concurrent updates
+	Tags              TagSet      `json:"tags,omitempty" db:"tags"`             // Grow-only set
+	Relations         RelationSet `json:"relations,omitempty" db:"relations"`   // Grow-only set
 }
 
 // NewUnknown creates a new unknown
@@ -149,6 +401,18 @@ func NewUnknown(projectID, sessionID, unknown string, impact float64) *Unknown {
 	}
 }
 
+// CopyTo returns a copy of the unknown filed under another project, relating back to
+// the original
+func (u *Unknown) CopyTo(projectID string) *Unknown {
+	c := *u
+	c.ID = uuid.New().String()
+	c.ProjectID = projectID
+	c.UpdatedAt, c.DeletedAt, c.Version = nil, nil, 0
+	c.Tags = append(TagSet(nil), u.Tags...)
+	c.Relations = copiedRelations(u.Relations, u.ID)
+	return &c
+}
+
 // UnknownLogInput represents input for logging an unknown
 type UnknownLogInput struct {
 	ProjectID string          `json:"project_id,omitempty"`
@@ -163,17 +427,21 @@ type UnknownLogInput struct {
 
 // DeadEnd represents a failed approach that shouldn't be repeated
 type DeadEnd struct {
-	ID               string  `json:"id" db:"id"`
-	ProjectID        string  `json:"project_id" db:"project_id"`
-	SessionID        string  `json:"session_id" db:"session_id"`
-	GoalID           *string `json:"goal_id,omitempty" db:"goal_id"`
-	SubtaskID        *string `json:"subtask_id,omitempty" db:"subtask_id"`
-	Approach         string  `json:"approach" db:"approach"`
-	WhyFailed        string  `json:"why_failed" db:"why_failed"`
-	CreatedTimestamp float64 `json:"created_timestamp" db:"created_timestamp"`
-	Subject          *string `json:"subject,omitempty" db:"subject"`
-	Impact           float64 `json:"impact" db:"impact"`
-	DeadEndData      string  `json:"-" db:"dead_end_data"`
+	ID               string      `json:"id" db:"id"`
+	ProjectID        string      `json:"project_id" db:"project_id"`
+	SessionID        string      `json:"session_id" db:"session_id"`
+	GoalID           *string     `json:"goal_id,omitempty" db:"goal_id"`
+	SubtaskID        *string     `json:"subtask_id,omitempty" db:"subtask_id"`
+	Approach         string      `json:"approach" db:"approach"`
+	WhyFailed        string      `json:"why_failed" db:"why_failed"`
+	CreatedTimestamp float64     `json:"created_timestamp" db:"created_timestamp"`
+	Subject          *string     `json:"subject,omitempty" db:"subject"`
+	Impact           float64     `json:"impact" db:"impact"`
+	UpdatedAt        *float64    `json:"updated_at,omitempty" db:"updated_at"`
+	DeletedAt        *float64    `json:"deleted_at,omitempty" db:"deleted_at"` // Tombstone; deleted dead ends are hidden from reads
+	Version          int         `json:"version" db:"version"`                 // Number of events applied; guards concurrent updates
+	Tags             TagSet      `json:"tags,omitempty" db:"tags"`             // Grow-only set
+	Relations        RelationSet `json:"relations,omitempty" db:"relations"`   // Grow-only set
 }
 
 // NewDeadEnd creates a new dead end record
@@ -189,6 +457,18 @@ func NewDeadEnd(projectID, sessionID, approach, whyFailed string, impact float64
 	}
 }
 
+// CopyTo returns a copy of the dead end filed under another project, relating back to
+// the original
+func (d *DeadEnd) CopyTo(projectID string) *DeadEnd {
+	c := *d
+	c.ID = uuid.New().String()
+	c.ProjectID = projectID
+	c.UpdatedAt, c.DeletedAt, c.Version = nil, nil, 0
+	c.Tags = append(TagSet(nil), d.Tags...)
+	c.Relations = copiedRelations(d.Relations, d.ID)
+	return &c
+}
+
 // DeadEndLogInput represents input for logging a dead end
 type DeadEndLogInput struct {
 	ProjectID string          `json:"project_id,omitempty"`
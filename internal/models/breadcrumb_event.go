@@ -0,0 +1,346 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// BreadcrumbEventKind identifies a change in a breadcrumb's event stream
+type BreadcrumbEventKind string
+
+const (
+	EventFindingCreated          BreadcrumbEventKind = "finding_created"
+	EventFindingVerified         BreadcrumbEventKind = "finding_verified"
+	EventFindingFileChanged      BreadcrumbEventKind = "finding_file_changed" // No longer recorded; see ApplyFindingEvent
+	EventFindingEvidenceRecorded BreadcrumbEventKind = "finding_evidence_recorded"
+	EventUnknownCreated          BreadcrumbEventKind = "unknown_created"
+	EventUnknownResolved         BreadcrumbEventKind = "unknown_resolved"
+	EventUnknownReprioritized    BreadcrumbEventKind = "unknown_reprioritized"
+	EventDeadEndCreated          BreadcrumbEventKind = "dead_end_created"
+
+	// Deleted events leave a tombstone; restored events remove it
+	EventFindingDeleted  BreadcrumbEventKind = "finding_deleted"
+	EventFindingRestored BreadcrumbEventKind = "finding_restored"
+	EventUnknownDeleted  BreadcrumbEventKind = "unknown_deleted"
+	EventUnknownRestored BreadcrumbEventKind = "unknown_restored"
+	EventDeadEndDeleted  BreadcrumbEventKind = "dead_end_deleted"
+	EventDeadEndRestored BreadcrumbEventKind = "dead_end_restored"
+
+	// Grow-only sets shared by every breadcrumb type
+	EventBreadcrumbTagged  BreadcrumbEventKind = "tagged"
+	EventBreadcrumbRelated BreadcrumbEventKind = "related"
+
+	// Moved events file a breadcrumb under another project
+	EventBreadcrumbMoved BreadcrumbEventKind = "moved"
+)
+
+// BreadcrumbEvent is one entry in the append-only breadcrumb event stream.
+// Findings, unknowns and dead ends are read models folded from these events.
+//
+// Events are replayed in (Lamport, DeviceID, ID) order, which every database holding
+// the same events agrees on, so streams edited offline on two machines merge
+// deterministically by taking their union. Plain fields are last-writer-wins registers
+// under that order; verifications, tags and relations only ever grow.
+type BreadcrumbEvent struct {
+	Seq        int64               `json:"seq" db:"seq"` // Local insertion order only
+	ID         string              `json:"id" db:"id"`
+	EntityType string              `json:"entity_type" db:"entity_type"`
+	EntityID   string              `json:"entity_id" db:"entity_id"`
+	Kind       BreadcrumbEventKind `json:"kind" db:"kind"`
+	Payload    string              `json:"payload" db:"payload"`
+	Timestamp  float64             `json:"timestamp" db:"timestamp"`
+	DeviceID   string              `json:"device_id" db:"device_id"` // Database that recorded the event
+	Lamport    int64               `json:"lamport" db:"lamport"`     // Logical clock, greater than every event seen before it
+}
+
+// FindingVerifiedPayload records a verification, optionally rewriting the text or git hash
+type FindingVerifiedPayload struct {
+	VerifiedAt float64 `json:"verified_at"`
+	GitHash    *string `json:"git_hash,omitempty"`
+	Finding    *string `json:"finding,omitempty"`
+}
+
+// FindingEvidencePayload records the output of a finding's verification check
+type FindingEvidencePayload struct {
+	Evidence string `json:"evidence"`
+}
+
+// UnknownResolvedPayload records who resolved an unknown and when
+type UnknownResolvedPayload struct {
+	ResolvedBy string  `json:"resolved_by"`
+	ResolvedAt float64 `json:"resolved_at"`
+}
+
+// UnknownReprioritizedPayload sets an unknown's impact
+type UnknownReprioritizedPayload struct {
+	Impact float64 `json:"impact"`
+}
+
+// BreadcrumbTaggedPayload adds a tag to a breadcrumb
+type BreadcrumbTaggedPayload struct {
+	Tag string `json:"tag"`
+}
+
+// BreadcrumbDeletedPayload records why a breadcrumb was deleted
+type BreadcrumbDeletedPayload struct {
+	DeletedAt float64 `json:"deleted_at"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// BreadcrumbMovedPayload records a breadcrumb moving to another project
+type BreadcrumbMovedPayload struct {
+	ProjectID     string `json:"project_id"`
+	FromProjectID string `json:"from_project_id"`
+}
+
+// movedTo returns the project a moved event files the breadcrumb under
+func movedTo(ev *BreadcrumbEvent) (string, error) {
+	var p BreadcrumbMovedPayload
+	if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+		return "", err
+	}
+	return p.ProjectID, nil
+}
+
+// deletedAt returns the tombstone time from a deleted event
+func deletedAt(ev *BreadcrumbEvent) (*float64, error) {
+	var p BreadcrumbDeletedPayload
+	if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+		return nil, err
+	}
+	return &p.DeletedAt, nil
+}
+
+// ApplyFindingEvent folds an event into a finding. A created event replaces the state.
+func ApplyFindingEvent(f *Finding, ev *BreadcrumbEvent) error {
+	switch ev.Kind {
+	case EventFindingCreated:
+		if err := json.Unmarshal([]byte(ev.Payload), f); err != nil {
+			return err
+		}
+		f.FileChangedDetectedAt = nil // Local to the database that saw it
+	case EventFindingVerified:
+		var p FindingVerifiedPayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		// The latest verification wins regardless of merge order, and only clears
+		// file changes detected before it
+		if f.LastVerifiedTimestamp == nil || p.VerifiedAt > *f.LastVerifiedTimestamp {
+			f.LastVerifiedTimestamp = &p.VerifiedAt
+		}
+		if f.FileChangedDetectedAt != nil && *f.FileChangedDetectedAt <= p.VerifiedAt {
+			f.FileChangedDetectedAt = nil
+		}
+		if p.GitHash != nil {
+			f.SubjectGitHash = p.GitHash
+		}
+		if p.Finding != nil {
+			f.Finding = *p.Finding
+		}
+	case EventFindingFileChanged:
+		// Older databases recorded scope changes as events. A change is only true of the
+		// worktree it was seen in, so the flag is now kept locally and these are ignored.
+	case EventFindingEvidenceRecorded:
+		var p FindingEvidencePayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		f.VerificationEvidence = &p.Evidence
+	case EventFindingDeleted:
+		ts, err := deletedAt(ev)
+		if err != nil {
+			return err
+		}
+		f.DeletedAt = ts
+	case EventFindingRestored:
+		f.DeletedAt = nil
+	case EventBreadcrumbMoved:
+		projectID, err := movedTo(ev)
+		if err != nil {
+			return err
+		}
+		f.ProjectID = projectID
+	case EventBreadcrumbTagged, EventBreadcrumbRelated:
+		if err := applySetEvent(ev, &f.Tags, &f.Relations); err != nil {
+			return err
+		}
+	}
+	updatedAt := ev.Timestamp
+	f.UpdatedAt = &updatedAt
+	f.Version++
+	return nil
+}
+
+// FindingRevision is one text a finding had, with the verifications made while it held
+type FindingRevision struct {
+	Finding       string   `json:"finding"`
+	Since         float64  `json:"since"`     // When the text was written
+	DeviceID      string   `json:"device_id"` // Database that wrote it
+	Verifications int      `json:"verifications"`
+	LastVerified  *float64 `json:"last_verified,omitempty"`
+}
+
+// FindingHistory is a finding's texts over time. Verifications that kept the text are
+// counted on its revision rather than listed, so the history stays short.
+type FindingHistory struct {
+	FindingID    string            `json:"finding_id"`
+	Revisions    []FindingRevision `json:"revisions"` // Oldest first; the last is the current text
+	DeletedAt    *float64          `json:"deleted_at,omitempty"`
+	DeleteReason string            `json:"delete_reason,omitempty"`
+}
+
+// FoldFindingHistory folds a finding's events, in replay order, into its history
+func FoldFindingHistory(findingID string, events []*BreadcrumbEvent) (*FindingHistory, error) {
+	history := &FindingHistory{FindingID: findingID, Revisions: []FindingRevision{}}
+	f := &Finding{}
+	for _, ev := range events {
+		previous := f.Finding
+		if err := ApplyFindingEvent(f, ev); err != nil {
+			return nil, err
+		}
+		switch ev.Kind {
+		case EventFindingCreated:
+			history.Revisions = append(history.Revisions, FindingRevision{Finding: f.Finding, Since: ev.Timestamp, DeviceID: ev.DeviceID})
+		case EventFindingVerified:
+			if len(history.Revisions) == 0 {
+				continue
+			}
+			if f.Finding != previous {
+				history.Revisions = append(history.Revisions, FindingRevision{Finding: f.Finding, Since: ev.Timestamp, DeviceID: ev.DeviceID})
+			}
+			current := &history.Revisions[len(history.Revisions)-1]
+			current.Verifications++
+			if current.LastVerified == nil || ev.Timestamp > *current.LastVerified {
+				verified := ev.Timestamp
+				current.LastVerified = &verified
+			}
+		case EventFindingDeleted:
+			var p BreadcrumbDeletedPayload
+			if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+				return nil, err
+			}
+			history.DeleteReason = p.Reason
+		case EventFindingRestored:
+			history.DeleteReason = ""
+		}
+	}
+	history.DeletedAt = f.DeletedAt
+	return history, nil
+}
+
+// ApplyUnknownEvent folds an event into an unknown. A created event replaces the state.
+func ApplyUnknownEvent(u *Unknown, ev *BreadcrumbEvent) error {
+	switch ev.Kind {
+	case EventUnknownCreated:
+		if err := json.Unmarshal([]byte(ev.Payload), u); err != nil {
+			return err
+		}
+	case EventUnknownResolved:
+		var p UnknownResolvedPayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		u.IsResolved = true
+		u.ResolvedBy = &p.ResolvedBy
+		u.ResolvedTimestamp = &p.ResolvedAt
+	case EventUnknownReprioritized:
+		var p UnknownReprioritizedPayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		u.Impact = p.Impact
+	case EventUnknownDeleted:
+		ts, err := deletedAt(ev)
+		if err != nil {
+			return err
+		}
+		u.DeletedAt = ts
+	case EventUnknownRestored:
+		u.DeletedAt = nil
+	case EventBreadcrumbMoved:
+		projectID, err := movedTo(ev)
+		if err != nil {
+			return err
+		}
+		u.ProjectID = projectID
+	case EventBreadcrumbTagged, EventBreadcrumbRelated:
+		if err := applySetEvent(ev, &u.Tags, &u.Relations); err != nil {
+			return err
+		}
+	}
+	updatedAt := ev.Timestamp
+	u.UpdatedAt = &updatedAt
+	u.Version++
+	return nil
+}
+
+// ApplyDeadEndEvent folds an event into a dead end. A created event replaces the state.
+func ApplyDeadEndEvent(d *DeadEnd, ev *BreadcrumbEvent) error {
+	switch ev.Kind {
+	case EventDeadEndCreated:
+		if err := json.Unmarshal([]byte(ev.Payload), d); err != nil {
+			return err
+		}
+	case EventDeadEndDeleted:
+		ts, err := deletedAt(ev)
+		if err != nil {
+			return err
+		}
+		d.DeletedAt = ts
+	case EventDeadEndRestored:
+		d.DeletedAt = nil
+	case EventBreadcrumbMoved:
+		projectID, err := movedTo(ev)
+		if err != nil {
+			return err
+		}
+		d.ProjectID = projectID
+	case EventBreadcrumbTagged, EventBreadcrumbRelated:
+		if err := applySetEvent(ev, &d.Tags, &d.Relations); err != nil {
+			return err
+		}
+	}
+	updatedAt := ev.Timestamp
+	d.UpdatedAt = &updatedAt
+	d.Version++
+	return nil
+}
+
+// applySetEvent adds to a breadcrumb's tag or relation set. Sets are kept sorted so
+// the folded state doesn't depend on the order additions were merged in.
+func applySetEvent(ev *BreadcrumbEvent, tags *TagSet, relations *RelationSet) error {
+	switch ev.Kind {
+	case EventBreadcrumbTagged:
+		var p BreadcrumbTaggedPayload
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		for _, t := range *tags {
+			if t == p.Tag {
+				return nil
+			}
+		}
+		*tags = append(*tags, p.Tag)
+		sort.Strings(*tags)
+	case EventBreadcrumbRelated:
+		var p BreadcrumbRelation
+		if err := json.Unmarshal([]byte(ev.Payload), &p); err != nil {
+			return err
+		}
+		for _, r := range *relations {
+			if r == p {
+				return nil
+			}
+		}
+		*relations = append(*relations, p)
+		sort.Slice(*relations, func(i, j int) bool {
+			a, b := (*relations)[i], (*relations)[j]
+			if a.TargetID != b.TargetID {
+				return a.TargetID < b.TargetID
+			}
+			return a.Kind < b.Kind
+		})
+	}
+	return nil
+}
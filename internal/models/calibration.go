@@ -0,0 +1,60 @@
+package models
+
+// Calibration statuses compare the confidence an agent started a session with to how
+// the session turned out
+const (
+	CalibrationCalibrated     = "calibrated"
+	CalibrationOverconfident  = "overconfident"
+	CalibrationUnderconfident = "underconfident"
+)
+
+// CalibrationTolerance is how far predicted confidence may drift from outcomes before
+// an agent counts as over- or underconfident
+const CalibrationTolerance = 0.1
+
+// CalibrationSample pairs a finished session's predicted confidence with its outcome
+type CalibrationSample struct {
+	SessionID   string  `json:"session_id" db:"session_id"`
+	AIID        string  `json:"ai_id" db:"ai_id"`
+	Predicted   float64 `json:"predicted" db:"predicted"`     // Decision guidance confidence at start
+	Completed   bool    `json:"completed" db:"completed"`     // Ended with done rather than abandoned
+	Findings    int     `json:"findings" db:"findings"`       // Findings the session recorded
+	Invalidated int     `json:"invalidated" db:"invalidated"` // Of those, how many were later deleted
+}
+
+// Outcome scores how the session turned out from 0 to 1: abandoned sessions score 0,
+// completed ones lose the share of their findings that were later invalidated
+func (s *CalibrationSample) Outcome() float64 {
+	if !s.Completed {
+		return 0
+	}
+	if s.Findings == 0 {
+		return 1
+	}
+	return 1 - float64(s.Invalidated)/float64(s.Findings)
+}
+
+// CalibrationStatusFor classifies predicted confidence against an outcome
+func CalibrationStatusFor(predicted, outcome float64) string {
+	switch gap := predicted - outcome; {
+	case gap > CalibrationTolerance:
+		return CalibrationOverconfident
+	case gap < -CalibrationTolerance:
+		return CalibrationUnderconfident
+	default:
+		return CalibrationCalibrated
+	}
+}
+
+// AgentCalibration summarizes how well an agent's confidence predicted its outcomes
+type AgentCalibration struct {
+	AIID          string  `json:"ai_id"`
+	Sessions      int     `json:"sessions"`
+	Completed     int     `json:"completed"`
+	Findings      int     `json:"findings"`
+	Invalidated   int     `json:"invalidated"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	MeanOutcome   float64 `json:"mean_outcome"`
+	Gap           float64 `json:"gap"` // Positive when overconfident
+	Status        string  `json:"status"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// CheckpointData is stored with a checkpoint's reflex alongside its vectors
+type CheckpointData struct {
+	Label  string           `json:"label"`
+	Counts BreadcrumbCounts `json:"counts"`
+}
+
+// Checkpoint is a labeled snapshot of a session's epistemic state and breadcrumb counts
+type Checkpoint struct {
+	ID        int64             `json:"id"`
+	SessionID string            `json:"session_id"`
+	Label     string            `json:"label"`
+	Timestamp float64           `json:"timestamp"`
+	Vectors   EpistemicSnapshot `json:"vectors"`
+	Counts    BreadcrumbCounts  `json:"counts"`
+}
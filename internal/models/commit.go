@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommitRelation describes how a commit relates to a finding
+type CommitRelation string
+
+const (
+	CommitProduced  CommitRelation = "produced"  // Commit created the knowledge (e.g. HEAD when logged)
+	CommitValidated CommitRelation = "validated" // Commit confirmed the knowledge still holds
+)
+
+// CommitLink connects a finding to a git commit
+type CommitLink struct {
+	ID              string         `json:"id" db:"id"`
+	FindingID       string         `json:"finding_id" db:"finding_id"`
+	CommitSHA       string         `json:"commit_sha" db:"commit_sha"`
+	Relation        CommitRelation `json:"relation" db:"relation"`
+	LinkedTimestamp float64        `json:"linked_timestamp" db:"linked_timestamp"`
+}
+
+// NewCommitLink creates a new finding-to-commit link
+func NewCommitLink(findingID, sha string, relation CommitRelation) *CommitLink {
+	return &CommitLink{
+		ID:              uuid.New().String(),
+		FindingID:       findingID,
+		CommitSHA:       sha,
+		Relation:        relation,
+		LinkedTimestamp: float64(time.Now().UnixMilli()) / 1000.0,
+	}
+}
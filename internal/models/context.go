@@ -8,10 +8,26 @@ type SessionContext struct {
 	ProjectID string `json:"project_id"`
 	Objective string `json:"objective"`
 
+	// Tracker issues this session is working on
+	Issues []*IssueLink `json:"issues,omitempty"`
+
 	// === DECISION SUPPORT ===
 	// These fields tell the AI what to do RIGHT NOW
 	Decision *DecisionGuidance `json:"decision"`
 
+	// Why the decision was made, shown by `memory explain` rather than in every context
+	Explanation *DecisionExplanation `json:"-"`
+
+	// === CURRENT GOAL ===
+	// Only present while a goal is active: the breadcrumbs recorded under it. The
+	// lists below then leave out breadcrumbs recorded under other goals.
+	Goal *GoalContext `json:"goal,omitempty"`
+
+	// === ASSIGNED QUESTIONS ===
+	// Questions a previous session handed to this one, either to the next session or
+	// to sessions whose objective matches. Answer these first.
+	AssignedQuestions []AssignedQuestion `json:"assigned_questions,omitempty"`
+
 	// === CRITICAL: VERIFY BEFORE USING ===
 	// Stale knowledge that MUST be verified before relying on it
 	// Empty means nothing needs verification
@@ -31,6 +47,21 @@ type SessionContext struct {
 	// Consider investigating these if relevant to current objective
 	OpenQuestions []string `json:"open_questions,omitempty"`
 
+	// === AGING QUESTIONS ===
+	// Questions open longer than the configured threshold: escalate or close them
+	// rather than letting the list silently grow stale
+	AgingUnknowns []AgingUnknown `json:"aging_unknowns,omitempty"`
+
+	// === REFERENCE DOCS ===
+	// Registered documents relevant to the objective or the files it touches
+	// Consult these before relying on memory alone
+	ReferenceDocs []*ReferenceDoc `json:"reference_docs,omitempty"`
+
+	// === PROJECT BRIEFING ===
+	// Only present when the project has been idle for a while: the summary of its
+	// recent sessions, since the last session alone may not say enough
+	ProjectBriefing *ProjectBriefing `json:"project_briefing,omitempty"`
+
 	// === LAST SESSION HANDOFF ===
 	// Context from the previous session for continuity
 	Continuity *ContinuityContext `json:"continuity,omitempty"`
@@ -40,6 +71,50 @@ type SessionContext struct {
 	Vectors *EpistemicSnapshot `json:"vectors,omitempty"`
 }
 
+// AssignedQuestion is an open question assigned to the session being started
+type AssignedQuestion struct {
+	ID      string  `json:"id"`
+	Unknown string  `json:"unknown"`
+	Impact  float64 `json:"impact"`
+
+	// "next-session", or the objective the question was assigned to
+	AssignedTo string `json:"assigned_to"`
+}
+
+// Recommendations for aging questions
+const (
+	AgingEscalate = "escalate"
+	AgingClose    = "close"
+)
+
+// AgingUnknown is a question open longer than the aging threshold
+type AgingUnknown struct {
+	ID      string  `json:"id"`
+	Unknown string  `json:"unknown"`
+	AgeDays int     `json:"age_days"`
+	Impact  float64 `json:"impact"`
+
+	// "escalate" for questions that still matter, "close" for the rest
+	Recommendation string `json:"recommendation"`
+
+	// Command that carries out the recommendation
+	Command string `json:"command"`
+}
+
+// GoalContext groups the breadcrumbs recorded under the active goal
+type GoalContext struct {
+	GoalID    string `json:"goal_id"`
+	Objective string `json:"objective"`
+
+	// Breadcrumbs recorded under this goal
+	Findings      []string         `json:"findings,omitempty"`
+	OpenQuestions []string         `json:"open_questions,omitempty"`
+	DeadEnds      []DeadEndWarning `json:"dead_ends,omitempty"`
+
+	// Breadcrumbs from other goals left out of the context
+	Hidden int `json:"hidden"`
+}
+
 // DecisionGuidance provides immediate actionable guidance for the AI
 type DecisionGuidance struct {
 	// Can the AI proceed with confidence, or should it investigate first?
@@ -60,6 +135,79 @@ type DecisionGuidance struct {
 
 	// Numeric confidence 0.0-1.0 for programmatic use
 	Confidence float64 `json:"confidence"`
+
+	// The active goal's next subtask: the most important one not yet done whose
+	// dependencies are complete
+	NextSubtask *SubtaskRef `json:"next_subtask,omitempty"`
+
+	// Subtasks of the active goal marked blocked or waiting on unfinished dependencies
+	BlockedSubtasks []SubtaskRef `json:"blocked_subtasks,omitempty"`
+}
+
+// DecisionExplanation breaks the recommended action down into the checks and
+// breadcrumbs behind it, so the guidance can be contested or corrected
+type DecisionExplanation struct {
+	// The recommended action and the check that decided it
+	Action   string `json:"action"`
+	Deciding string `json:"deciding"`
+
+	// Every check in the order it's made, with the vector's value against its threshold
+	Checks []VectorCheck `json:"checks"`
+
+	// Findings that aren't fresh, which lower clarity
+	StaleFindings []StaleFindingCause `json:"stale_findings,omitempty"`
+
+	// Dead ends, which lower coherence
+	DeadEnds []DeadEndCause `json:"dead_ends,omitempty"`
+
+	// Open unknowns, which raise uncertainty
+	OpenUnknowns int `json:"open_unknowns"`
+}
+
+// VectorCheck is one threshold the recommended action is decided by
+type VectorCheck struct {
+	Vector    string  `json:"vector"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+
+	// How the value must compare to the threshold to pass: ">=" or "<="
+	Passes string `json:"passes"`
+	Passed bool   `json:"passed"`
+
+	// The action recommended when the check fails
+	OnFail string `json:"on_fail"`
+}
+
+// StaleFindingCause is a finding counted as not fresh when clarity was calculated
+type StaleFindingCause struct {
+	ID          string          `json:"id"`
+	Finding     string          `json:"finding"`
+	Status      StalenessStatus `json:"status"`
+	Confidence  float64         `json:"confidence"`
+	DaysStale   int             `json:"days_stale"`
+	FileChanged bool            `json:"file_changed,omitempty"`
+	Scope       string          `json:"scope,omitempty"`
+
+	// Its share of the clarity lost, by impact weight
+	Weight float64 `json:"weight"`
+}
+
+// DeadEndCause is a dead end counted against coherence
+type DeadEndCause struct {
+	ID        string `json:"id"`
+	Approach  string `json:"approach"`
+	WhyFailed string `json:"why_failed"`
+
+	// How much coherence it costs, by impact weight
+	Cost float64 `json:"cost"`
+}
+
+// SubtaskRef identifies a planned subtask in decision guidance
+type SubtaskRef struct {
+	ID          string              `json:"id"`
+	Description string              `json:"description"`
+	Importance  EpistemicImportance `json:"importance"`
+	Status      TaskStatus          `json:"status"`
 }
 
 // VerificationNeeded represents a piece of knowledge that should be verified
@@ -113,6 +261,27 @@ type KnowledgeItem struct {
 	Scope string `json:"scope,omitempty"`
 }
 
+// ProjectBriefing summarizes a project's recent sessions for an agent returning after a break
+type ProjectBriefing struct {
+	// How long since the last session ended
+	IdleFor string `json:"idle_for"`
+
+	// What the recent sessions covered
+	Summary string `json:"summary"`
+
+	// Outcomes of recent sessions, newest first
+	KeyDecisions []string `json:"key_decisions,omitempty"`
+
+	// Files worked on across several sessions
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Approaches that failed
+	Mistakes []string `json:"mistakes,omitempty"`
+
+	// Questions still open and suggested next steps
+	RemainingWork []string `json:"remaining_work,omitempty"`
+}
+
 // ContinuityContext provides handoff from previous session
 type ContinuityContext struct {
 	// What was accomplished in the last session
@@ -142,6 +311,25 @@ type EpistemicSnapshot struct {
 	Overall float64 `json:"overall"`
 }
 
+// FileRecall is the compact context for a single file, returned by `memory recall`.
+// Designed to be injected right before an agent edits the file.
+type FileRecall struct {
+	// The file this context is about
+	Path string `json:"path"`
+
+	// Stale knowledge about the file that should be re-checked before relying on it
+	RequiresVerification []VerificationNeeded `json:"requires_verification,omitempty"`
+
+	// Approaches that already failed for this file
+	DeadEnds []DeadEndWarning `json:"dead_ends,omitempty"`
+
+	// Fresh or aging findings about the file
+	Knowledge []KnowledgeItem `json:"knowledge,omitempty"`
+
+	// Unresolved questions about the file
+	OpenQuestions []string `json:"open_questions,omitempty"`
+}
+
 // StartResponse is the complete response from `memory start`
 type StartResponse struct {
 	// Status is always "started" on success
@@ -179,3 +367,17 @@ type BreadcrumbCounts struct {
 	UnknownsOpen     int `json:"unknowns_open"`
 	DeadEnds         int `json:"dead_ends"`
 }
+
+// SessionReport summarizes a session's outcome for publishing outside memory
+type SessionReport struct {
+	SessionID         string           `json:"session_id"`
+	Objective         string           `json:"objective"`
+	Summary           string           `json:"summary,omitempty"` // From the handoff, empty while the session is active
+	Active            bool             `json:"active"`
+	Issues            []*IssueLink     `json:"issues,omitempty"`
+	Findings          []string         `json:"findings"`
+	ResolvedQuestions []string         `json:"resolved_questions"`
+	OpenQuestions     []string         `json:"open_questions"`
+	DeadEnds          []DeadEndWarning `json:"dead_ends"`
+	Artifacts         []string         `json:"artifacts,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IssueTargetType identifies what an issue is linked to
+type IssueTargetType string
+
+const (
+	IssueTargetSession IssueTargetType = "session"
+	IssueTargetGoal    IssueTargetType = "goal"
+)
+
+// Issue tracker providers
+const (
+	IssueProviderGitHub = "github"
+	IssueProviderJira   = "jira"
+)
+
+// IssueLink connects a session or goal to a tracker issue
+type IssueLink struct {
+	ID              string          `json:"id" db:"id"`
+	TargetType      IssueTargetType `json:"target_type" db:"target_type"`
+	TargetID        string          `json:"target_id" db:"target_id"`
+	Provider        string          `json:"provider" db:"provider"` // github or jira
+	Repo            string          `json:"repo" db:"repo"`         // owner/name for GitHub, project key for Jira
+	Number          int             `json:"number" db:"number"`
+	Title           *string         `json:"title,omitempty" db:"title"`
+	URL             string          `json:"url" db:"url"`
+	LinkedTimestamp float64         `json:"linked_timestamp" db:"linked_timestamp"`
+}
+
+// NewIssueLink creates a new link from a session or goal to a GitHub issue
+func NewIssueLink(targetType IssueTargetType, targetID, repo string, number int) *IssueLink {
+	return &IssueLink{
+		ID:              uuid.New().String(),
+		TargetType:      targetType,
+		TargetID:        targetID,
+		Provider:        IssueProviderGitHub,
+		Repo:            repo,
+		Number:          number,
+		LinkedTimestamp: float64(time.Now().UnixMilli()) / 1000.0,
+	}
+}
+
+// Ref returns the tracker's short reference for the issue (owner/name#12 or PROJ-12)
+func (l *IssueLink) Ref() string {
+	if l.Provider == IssueProviderJira {
+		return fmt.Sprintf("%s-%d", l.Repo, l.Number)
+	}
+	return fmt.Sprintf("%s#%d", l.Repo, l.Number)
+}
@@ -30,6 +30,8 @@ type Project struct {
 	TotalGoals            int           `json:"total_goals" db:"total_goals"`
 	TotalEpistemicDeltas  *string       `json:"total_epistemic_deltas,omitempty" db:"total_epistemic_deltas"`
 	ProjectData           string        `json:"-" db:"project_data"`
+	ParentID              *string       `json:"parent_id,omitempty" db:"parent_id"` // Parent project for monorepo sub-projects
+	RootPath              *string       `json:"root_path,omitempty" db:"root_path"` // Sub-project directory relative to the repository root
 }
 
 // NewProject creates a new project
@@ -67,7 +69,17 @@ type ProjectHandoff struct {
 	RemainingWork        *string `json:"remaining_work,omitempty" db:"remaining_work"`
 	ReposTouched         *string `json:"repos_touched,omitempty" db:"repos_touched"`
 	NextSessionBootstrap *string `json:"next_session_bootstrap,omitempty" db:"next_session_bootstrap"`
-	HandoffData          string  `json:"-" db:"handoff_data"`
+}
+
+// NewProjectHandoff creates a project handoff covering the given sessions
+func NewProjectHandoff(projectID, summary, sessionsIncluded string) *ProjectHandoff {
+	return &ProjectHandoff{
+		ID:               uuid.New().String(),
+		ProjectID:        projectID,
+		CreatedTimestamp: float64(time.Now().UnixMilli()) / 1000.0,
+		ProjectSummary:   summary,
+		SessionsIncluded: sessionsIncluded,
+	}
 }
 
 // ReferenceDoc represents a reference document for a project
@@ -78,7 +90,6 @@ type ReferenceDoc struct {
 	DocType          *string `json:"doc_type,omitempty" db:"doc_type"`
 	Description      *string `json:"description,omitempty" db:"description"`
 	CreatedTimestamp float64 `json:"created_timestamp" db:"created_timestamp"`
-	DocData          string  `json:"-" db:"doc_data"`
 }
 
 // NewReferenceDoc creates a new reference document
@@ -111,6 +122,18 @@ type EpistemicSource struct {
 	SourceMetadata  *string `json:"source_metadata,omitempty" db:"source_metadata"` // JSON
 }
 
+// NewEpistemicSource creates a source discovered now
+func NewEpistemicSource(projectID, sourceType, title string, confidence float64) *EpistemicSource {
+	return &EpistemicSource{
+		ID:           uuid.New().String(),
+		ProjectID:    projectID,
+		SourceType:   sourceType,
+		Title:        title,
+		Confidence:   confidence,
+		DiscoveredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
 // InvestigationBranch represents a parallel investigation branch
 type InvestigationBranch struct {
 	ID                  string   `json:"id" db:"id"`
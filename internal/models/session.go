@@ -120,6 +120,7 @@ type Reflex struct {
 	Completion  *float64 `json:"completion,omitempty" db:"completion"`
 	Impact      *float64 `json:"impact,omitempty" db:"impact"`
 	Uncertainty *float64 `json:"uncertainty,omitempty" db:"uncertainty"`
+	Confidence  *float64 `json:"confidence,omitempty" db:"confidence"`
 	ReflexData  *string  `json:"reflex_data,omitempty" db:"reflex_data"`
 	Reasoning   *string  `json:"reasoning,omitempty" db:"reasoning"`
 	Evidence    *string  `json:"evidence,omitempty" db:"evidence"`
@@ -153,6 +154,24 @@ func NewReflex(sessionID, phase string, vectors *EpistemicVectors, round int) *R
 	return r
 }
 
+// NewSnapshotReflex records the vectors computed for a session: start records a
+// preflight, status a check and done a postflight
+func NewSnapshotReflex(sessionID string, phase CASCADEPhase, s *EpistemicSnapshot) *Reflex {
+	return &Reflex{
+		SessionID:   sessionID,
+		Phase:       string(phase),
+		Round:       1,
+		Timestamp:   float64(time.Now().UnixMilli()) / 1000.0,
+		Engagement:  &s.Engagement,
+		Know:        &s.Know,
+		Clarity:     &s.Clarity,
+		Coherence:   &s.Coherence,
+		Completion:  &s.Completion,
+		Uncertainty: &s.Uncertainty,
+		Confidence:  &s.Overall,
+	}
+}
+
 // ToVectors converts a reflex to EpistemicVectors
 func (r *Reflex) ToVectors() *EpistemicVectors {
 	v := &EpistemicVectors{}
@@ -209,6 +228,7 @@ const (
 	PhasePlan        CASCADEPhase = "PLAN"
 	PhaseInvestigate CASCADEPhase = "INVESTIGATE"
 	PhaseAct         CASCADEPhase = "ACT"
+	PhaseCheckpoint  CASCADEPhase = "CHECKPOINT" // Labeled snapshot taken by 'memory checkpoint'
 )
 
 // HandoffReport represents a session handoff report for continuity
@@ -236,12 +256,15 @@ type HandoffReport struct {
 
 // HandoffCreateInput represents input for creating a handoff
 type HandoffCreateInput struct {
-	SessionID          string   `json:"session_id"`
-	ProjectID          string   `json:"project_id,omitempty"`
-	TaskSummary        string   `json:"task_summary"`
-	KeyFindings        []string `json:"key_findings,omitempty"`
-	RemainingUnknowns  []string `json:"remaining_unknowns,omitempty"`
-	NextSessionContext string   `json:"next_session_context,omitempty"`
-	Artifacts          []string `json:"artifacts,omitempty"`
-	PlanningOnly       bool     `json:"planning_only,omitempty"`
+	SessionID          string             `json:"session_id"`
+	ProjectID          string             `json:"project_id,omitempty"`
+	TaskSummary        string             `json:"task_summary"`
+	KeyFindings        []string           `json:"key_findings,omitempty"`
+	RemainingUnknowns  []string           `json:"remaining_unknowns,omitempty"`
+	NextSessionContext string             `json:"next_session_context,omitempty"`
+	Artifacts          []string           `json:"artifacts,omitempty"`
+	EpistemicDeltas    map[string]float64 `json:"epistemic_deltas,omitempty"`
+	DurationSeconds    float64            `json:"duration_seconds,omitempty"`
+	CalibrationStatus  string             `json:"calibration_status,omitempty"`
+	PlanningOnly       bool               `json:"planning_only,omitempty"`
 }
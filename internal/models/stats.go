@@ -0,0 +1,47 @@
+package models
+
+// ProjectStats aggregates a project's knowledge base
+type ProjectStats struct {
+	Findings        FindingStats `json:"findings"`
+	Unknowns        UnknownStats `json:"unknowns"`
+	DeadEnds        DeadEndStats `json:"dead_ends"`
+	TopScopes       []ScopeCount `json:"top_scopes"`        // Files and URLs with the most breadcrumbs
+	DeadEndHotspots []ScopeCount `json:"dead_end_hotspots"` // Files and URLs with the most dead ends
+}
+
+// FindingStats counts findings by staleness and verification
+type FindingStats struct {
+	Total            int     `json:"total" db:"total"`
+	Fresh            int     `json:"fresh" db:"fresh"`
+	Aging            int     `json:"aging" db:"aging"`
+	Stale            int     `json:"stale" db:"stale"`
+	Verified         int     `json:"verified" db:"verified"` // Re-verified since they were recorded
+	WithCheck        int     `json:"with_check" db:"with_check"`
+	FileChanged      int     `json:"file_changed" db:"file_changed"`
+	Deleted          int     `json:"deleted" db:"deleted"`
+	VerificationRate float64 `json:"verification_rate"`
+	AvgAgeDays       float64 `json:"avg_age_days" db:"avg_age_days"`           // Live findings, since creation
+	AvgLifetimeDays  float64 `json:"avg_lifetime_days" db:"avg_lifetime_days"` // Deleted findings, from creation to deletion
+}
+
+// UnknownStats counts unknowns by resolution
+type UnknownStats struct {
+	Total            int     `json:"total"`
+	Open             int     `json:"open" db:"open"`
+	Resolved         int     `json:"resolved" db:"resolved"`
+	Deleted          int     `json:"deleted" db:"deleted"`
+	ResolutionRate   float64 `json:"resolution_rate"`
+	AvgDaysToResolve float64 `json:"avg_days_to_resolve" db:"avg_days_to_resolve"`
+}
+
+// DeadEndStats counts dead ends
+type DeadEndStats struct {
+	Total   int `json:"total" db:"total"`
+	Deleted int `json:"deleted" db:"deleted"`
+}
+
+// ScopeCount is the number of breadcrumbs scoped to one file or URL
+type ScopeCount struct {
+	Scope string `json:"scope" db:"scope"`
+	Count int    `json:"count" db:"count"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// SyncBatch is a page of the breadcrumb event stream exchanged with a sync server.
+// Projects travel with the events so breadcrumbs always land in a known project, and
+// the evidence blobs the events refer to so their evidence can be read.
+type SyncBatch struct {
+	Events   []*BreadcrumbEvent `json:"events"`
+	Projects []*Project         `json:"projects,omitempty"`
+	Blobs    map[string][]byte  `json:"blobs,omitempty"` // Content by hash
+	Cursor   int64              `json:"cursor"`          // Seq of the last event in the batch on the sending side
+	More     bool               `json:"more"`            // Another page follows
+}
@@ -0,0 +1,14 @@
+package models
+
+// TimelineEntry is one step in the history of a project or session: a breadcrumb
+// event, a goal or subtask change, or a session starting or ending
+type TimelineEntry struct {
+	Timestamp  float64 `json:"timestamp" db:"timestamp"`
+	Kind       string  `json:"kind" db:"kind"` // e.g. finding_created, unknown_resolved, goal_completed, session_started
+	EntityType string  `json:"entity_type" db:"entity_type"`
+	EntityID   string  `json:"entity_id" db:"entity_id"`
+	SessionID  *string `json:"session_id,omitempty" db:"session_id"`
+	Text       string  `json:"text" db:"text"`
+	Detail     string  `json:"detail,omitempty" db:"detail"`
+	Payload    *string `json:"-" db:"payload"`
+}
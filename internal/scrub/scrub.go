@@ -0,0 +1,127 @@
+// Package scrub removes personal data (emails, names, customer IDs) from text
+package scrub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AbdouB/memory/internal/config"
+)
+
+// emailPattern matches email addresses
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// rule is a compiled replacement
+type rule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// Scrubber applies the configured replacements
+type Scrubber struct {
+	rules []rule
+}
+
+// New compiles a scrub configuration. Returns nil if nothing is configured.
+func New(cfg *config.ScrubConfig) (*Scrubber, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	s := &Scrubber{}
+	if cfg.Emails {
+		s.rules = append(s.rules, rule{pattern: emailPattern, replacement: "[email]"})
+	}
+	if len(cfg.Names) > 0 {
+		quoted := make([]string, 0, len(cfg.Names))
+		for _, name := range cfg.Names {
+			if name = strings.TrimSpace(name); name != "" {
+				quoted = append(quoted, regexp.QuoteMeta(name))
+			}
+		}
+		if len(quoted) > 0 {
+			s.rules = append(s.rules, rule{
+				pattern:     regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`),
+				replacement: "[name]",
+			})
+		}
+	}
+	for _, r := range cfg.Rules {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrub rule %q: %w", r.Name, err)
+		}
+		s.rules = append(s.rules, rule{pattern: pattern, replacement: "[" + r.Name + "]"})
+	}
+	if len(s.rules) == 0 {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// String returns text with every configured match replaced
+func (s *Scrubber) String(text string) string {
+	if s == nil {
+		return text
+	}
+	for _, r := range s.rules {
+		text = r.pattern.ReplaceAllLiteralString(text, r.replacement)
+	}
+	return text
+}
+
+// JSON scrubs every string value in a JSON document. Identifiers (keys named id or
+// ending in _id or _hash) are left alone so references keep working. The document is
+// returned unchanged, byte for byte, when nothing matched.
+func (s *Scrubber) JSON(data []byte) ([]byte, error) {
+	if s == nil {
+		return data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	scrubbed, changed := s.walk(doc, "")
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(scrubbed)
+}
+
+// walk scrubs strings under a JSON value, reporting whether anything changed
+func (s *Scrubber) walk(v interface{}, key string) (interface{}, bool) {
+	switch value := v.(type) {
+	case string:
+		if isIdentifierKey(key) {
+			return value, false
+		}
+		scrubbed := s.String(value)
+		return scrubbed, scrubbed != value
+	case map[string]interface{}:
+		changed := false
+		for k, child := range value {
+			scrubbed, c := s.walk(child, k)
+			value[k] = scrubbed
+			changed = changed || c
+		}
+		return value, changed
+	case []interface{}:
+		changed := false
+		for i, child := range value {
+			scrubbed, c := s.walk(child, key)
+			value[i] = scrubbed
+			changed = changed || c
+		}
+		return value, changed
+	}
+	return v, false
+}
+
+// isIdentifierKey reports whether a JSON key holds an identifier rather than prose
+func isIdentifierKey(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_hash")
+}
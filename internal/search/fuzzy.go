@@ -8,13 +8,13 @@ import (
 
 // SearchResult represents a matched item with its score
 type SearchResult struct {
-	ID          string
-	Type        string // "finding", "unknown", "dead_end"
-	Text        string // Primary text (finding/unknown/approach)
+	ID            string
+	Type          string // "finding", "unknown", "dead_end"
+	Text          string // Primary text (finding/unknown/approach)
 	SecondaryText string // Secondary text (why_failed for dead ends)
-	Scope       string
-	Score       float64
-	Highlights  []int // Indices of matching characters (for UI highlighting)
+	Scope         string
+	Score         float64
+	Highlights    []int // Indices of matching characters (for UI highlighting)
 }
 
 // SearchItem represents an item to be searched